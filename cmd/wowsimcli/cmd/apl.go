@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core/apltext"
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var aplCmd = &cobra.Command{
+	Use:   "apl",
+	Short: "convert APL rotations between json and the text syntax",
+	Long:  "convert APL rotations between protojson (e.g. .apl.json files) and the compact text syntax",
+}
+
+var aplFmtCmd = &cobra.Command{
+	Use:   "fmt [file]",
+	Short: "reformat a text APL rotation",
+	Long:  "parse a text APL rotation and print it back in canonical form",
+	Args:  cobra.ExactArgs(1),
+	RunE:  aplFmtMain,
+}
+
+var aplParseCmd = &cobra.Command{
+	Use:   "parse [file]",
+	Short: "convert a text APL rotation to json",
+	Long:  "convert a text APL rotation to an APLRotation in protojson format",
+	Args:  cobra.ExactArgs(1),
+	RunE:  aplParseMain,
+}
+
+var aplPrintCmd = &cobra.Command{
+	Use:   "print [file]",
+	Short: "convert a json APL rotation to text",
+	Long:  "convert an APLRotation in protojson format, such as an .apl.json file, to the text syntax",
+	Args:  cobra.ExactArgs(1),
+	RunE:  aplPrintMain,
+}
+
+var aplWrite bool
+
+func init() {
+	aplFmtCmd.Flags().BoolVarP(&aplWrite, "write", "w", false, "write the result back to the input file instead of stdout")
+	for _, cmd := range []*cobra.Command{aplFmtCmd, aplParseCmd, aplPrintCmd} {
+		cmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+		aplCmd.AddCommand(cmd)
+	}
+	aplFmtCmd.MarkFlagsMutuallyExclusive("write", "outfile")
+}
+
+func aplFmtMain(cmd *cobra.Command, args []string) error {
+	rotation, err := loadTextRotation(args[0])
+	if err != nil {
+		return err
+	}
+
+	if aplWrite {
+		outfile = args[0]
+	}
+	return writeOutput([]byte(apltext.Format(rotation)))
+}
+
+func aplParseMain(cmd *cobra.Command, args []string) error {
+	rotation, err := loadTextRotation(args[0])
+	if err != nil {
+		return err
+	}
+
+	output, err := formatProto(rotation)
+	if err != nil {
+		return err
+	}
+	return writeOutput(output)
+}
+
+func aplPrintMain(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load apl json file %q: %w", args[0], err)
+	}
+
+	rotation := &proto.APLRotation{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, rotation); err != nil {
+		return fmt.Errorf("failed to load apl json file: %w", err)
+	}
+	return writeOutput([]byte(apltext.Format(rotation)))
+}
+
+func loadTextRotation(filename string) (*proto.APLRotation, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load apl file %q: %w", filename, err)
+	}
+
+	rotation, err := apltext.Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return rotation, nil
+}
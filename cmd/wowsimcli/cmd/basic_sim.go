@@ -8,6 +8,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wowsims/mop/sim/core"
 	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/distsim"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -21,6 +23,7 @@ func init() {
 	simCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
 	simCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
 	simCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	addWorkersFlag(simCmd)
 	simCmd.MarkFlagRequired("infile")
 }
 
@@ -36,17 +39,27 @@ func simMain(cmd *cobra.Command, args []string) {
 	}
 
 	var output []byte
-	reporter := make(chan *proto.ProgressMetrics, 10)
-	core.RunRaidSimConcurrentAsync(input, reporter, "cmd-raid-sim")
-
 	var finalResult *proto.RaidSimResult
-	for v := range reporter {
-		if v.FinalRaidResult != nil {
-			finalResult = v.FinalRaidResult
-			break
-		}
+	if len(workerURLs) > 0 {
+		coordinator := distsim.NewCoordinator(workerURLs)
 		if verbose {
-			fmt.Printf("Sim Progress: %d / %d\n", v.CompletedIterations, v.TotalIterations)
+			coordinator.OnProgress = func(completedIterations int32, totalIterations int32) {
+				fmt.Printf("Sim Progress: %d / %d\n", completedIterations, totalIterations)
+			}
+		}
+		finalResult = coordinator.RunRaidSim(input, simsignals.CreateSignals())
+	} else {
+		reporter := make(chan *proto.ProgressMetrics, 10)
+		core.RunRaidSimConcurrentAsync(input, reporter, "cmd-raid-sim")
+
+		for v := range reporter {
+			if v.FinalRaidResult != nil {
+				finalResult = v.FinalRaidResult
+				break
+			}
+			if verbose {
+				fmt.Printf("Sim Progress: %d / %d\n", v.CompletedIterations, v.TotalIterations)
+			}
 		}
 	}
 
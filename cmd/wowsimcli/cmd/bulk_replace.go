@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wowsims/mop/sim/core"
 	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/distsim"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -33,6 +34,7 @@ func init() {
 	bulkCmd.Flags().StringVar(&replacefile, "replacefile", "", "location of replacement items file. Writes a CSV result of the items replaced instead of JSON")
 	bulkCmd.Flags().StringVar(&outfile, "output", "", "location of output file, defaults to stdout")
 	bulkCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	addWorkersFlag(bulkCmd)
 	bulkCmd.MarkFlagRequired("infile")
 	bulkCmd.MarkFlagRequired("replacefile")
 }
@@ -97,7 +99,13 @@ func BulkSim(input *proto.RaidSimRequest, replaceFile string, verbose bool) stri
 		},
 	}
 	progress := make(chan *proto.ProgressMetrics, 100)
-	core.RunBulkSimAsync(bsr, progress, "cmd-bulk-sim")
+	if len(workerURLs) > 0 {
+		// Every combo is split across all workers, so a few at once keep them busy.
+		coordinator := distsim.NewCoordinator(workerURLs)
+		core.RunBulkSimRemoteAsync(bsr, progress, "cmd-bulk-sim", coordinator.RunRaidSim, len(workerURLs))
+	} else {
+		core.RunBulkSimAsync(bsr, progress, "cmd-bulk-sim")
+	}
 
 	startTime := time.Now()
 
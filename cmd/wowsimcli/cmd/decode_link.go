@@ -27,28 +27,40 @@ var decodeLinkCmd = &cobra.Command{
 var errInvalidLink = errors.New("invalid wowsims export link")
 
 func decodeLink(link string) error {
+	settings, err := decodeLinkSettings(link)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(protojson.Format(settings))
+	return nil
+}
+
+// decodeLinkSettings returns either a *proto.RaidSimSettings or a
+// *proto.IndividualSimSettings depending on the kind of link.
+func decodeLinkSettings(link string) (goproto.Message, error) {
 	parts := strings.Split(link, "#")
 	switch {
 	case len(parts) != 2:
-		return errInvalidLink
+		return nil, errInvalidLink
 	case parts[1] == "":
-		return errInvalidLink
+		return nil, errInvalidLink
 	}
 
 	raw, err := base64.StdEncoding.DecodeString(parts[1])
 	if err != nil {
-		return fmt.Errorf("cannot decode proto from link: %w", err)
+		return nil, fmt.Errorf("cannot decode proto from link: %w", err)
 	}
 
 	r, err := zlib.NewReader(bytes.NewReader(raw))
 	if err != nil {
-		return fmt.Errorf("cannot create zlib reader: %w", err)
+		return nil, fmt.Errorf("cannot create zlib reader: %w", err)
 	}
 	defer r.Close()
 
 	var buf bytes.Buffer
 	if _, err := buf.ReadFrom(r); err != nil {
-		return fmt.Errorf("reading zlib data failed: %w", err)
+		return nil, fmt.Errorf("reading zlib data failed: %w", err)
 	}
 
 	var settings goproto.Message
@@ -59,9 +71,8 @@ func decodeLink(link string) error {
 	}
 
 	if err := goproto.Unmarshal(buf.Bytes(), settings); err != nil {
-		return fmt.Errorf("cannot unmarshal raw proto: %w", err)
+		return nil, fmt.Errorf("cannot unmarshal raw proto: %w", err)
 	}
 
-	fmt.Println(protojson.Format(goproto.Message(settings)))
-	return nil
+	return settings, nil
 }
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// Shared by the stats and weights commands.
+var (
+	link   string
+	format string
+)
+
+const (
+	formatJSON  = "json"
+	formatCSV   = "csv"
+	formatTable = "table"
+)
+
+func validateFormat() error {
+	switch format {
+	case formatJSON, formatCSV, formatTable:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q, expected one of json, csv or table", format)
+	}
+}
+
+// formatRows renders a header plus rows as CSV or as an aligned text table.
+func formatRows(header []string, rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case formatCSV:
+		w := csv.NewWriter(&buf)
+		if err := w.Write(header); err != nil {
+			return nil, err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return nil, err
+		}
+	case formatTable:
+		w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(header, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		if err := w.Flush(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("format %q is not tabular", format)
+	}
+	return buf.Bytes(), nil
+}
+
+func formatProto(msg goproto.Message) ([]byte, error) {
+	return protojson.MarshalOptions{EmitUnpopulated: true, Multiline: true}.Marshal(msg)
+}
+
+func writeOutput(output []byte) error {
+	if outfile == "" {
+		_, err := os.Stdout.Write(output)
+		return err
+	}
+
+	if err := os.WriteFile(outfile, output, 0666); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Wrote output file: `%s` successfully.\n", outfile)
+	}
+	return nil
+}
+
+// loadInput fills msg from the --infile protojson file, or returns the decoded
+// --link settings when a link was given instead.
+func loadInput(msg goproto.Message) (goproto.Message, error) {
+	if link != "" {
+		return decodeLinkSettings(link)
+	}
+	if infile == "" {
+		return nil, fmt.Errorf("either --infile or --link is required")
+	}
+
+	data, err := os.ReadFile(infile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load input json file %q: %w", infile, err)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("failed to load input json file: %w", err)
+	}
+	return msg, nil
+}
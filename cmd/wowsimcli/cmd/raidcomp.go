@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+var (
+	raidCompIterations int32
+	raidCompSize       int32
+	raidCompMaxSims    int32
+	raidCompResults    int32
+)
+
+var raidCompCmd = &cobra.Command{
+	Use:   "raidcomp",
+	Short: "find the raid comps with the highest raid DPS out of a pool of players",
+	Long:  "search a pool of players from a RaidCompOptimizeRequest or a wowsims raid sim export link for the best 10 or 25 player comps, e.g. --size 10 --max-sims 100",
+	RunE:  raidCompMain,
+}
+
+func init() {
+	raidCompCmd.Flags().StringVar(&infile, "infile", "", "location of input file (RaidCompOptimizeRequest in protojson format)")
+	raidCompCmd.Flags().StringVar(&link, "link", "", "wowsims raid sim export link to use instead of an input file, every player in it joins the pool")
+	raidCompCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	raidCompCmd.Flags().StringVar(&format, "format", formatJSON, "output format: json, csv or table")
+	raidCompCmd.Flags().Int32Var(&raidCompIterations, "iterations", 0, "iterations per raid sim, overrides the value from the input")
+	raidCompCmd.Flags().Int32Var(&raidCompSize, "size", 0, "raid size, 10 or 25, overrides the value from the input")
+	raidCompCmd.Flags().Int32Var(&raidCompMaxSims, "max-sims", 0, "maximum number of raid sims, overrides the value from the input")
+	raidCompCmd.Flags().Int32Var(&raidCompResults, "results", 0, "number of comps to report, overrides the value from the input")
+	raidCompCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	raidCompCmd.MarkFlagsMutuallyExclusive("infile", "link")
+}
+
+const (
+	defaultRaidCompIterations = 1000
+	defaultRaidCompSize       = 25
+)
+
+func raidCompMain(cmd *cobra.Command, args []string) error {
+	if err := validateFormat(); err != nil {
+		return err
+	}
+
+	input, err := loadInput(&proto.RaidCompOptimizeRequest{})
+	if err != nil {
+		return err
+	}
+
+	var request *proto.RaidCompOptimizeRequest
+	switch settings := input.(type) {
+	case *proto.RaidCompOptimizeRequest:
+		request = settings
+	case *proto.RaidSimSettings:
+		request = raidCompRequestFromRaid(settings)
+	case *proto.IndividualSimSettings:
+		return errors.New("raid comp optimization requires a raid sim link, not an individual sim link")
+	}
+
+	if request.SimOptions == nil {
+		request.SimOptions = &proto.SimOptions{Iterations: defaultRaidCompIterations}
+	}
+	if raidCompIterations > 0 {
+		request.SimOptions.Iterations = raidCompIterations
+	}
+	if raidCompSize > 0 {
+		request.RaidSize = raidCompSize
+	}
+	if raidCompMaxSims > 0 {
+		request.MaxSims = raidCompMaxSims
+	}
+	if raidCompResults > 0 {
+		request.NumResults = raidCompResults
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	core.RaidCompOptimizeAsync(request, reporter, "cmd-raid-comp")
+
+	var result *proto.RaidCompOptimizeResult
+	for v := range reporter {
+		if v.FinalRaidCompResult != nil {
+			result = v.FinalRaidCompResult
+			break
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Raid Comp Progress: sim %d / %d, iterations %d / %d\n", v.CompletedSims, v.TotalSims, v.CompletedIterations, v.TotalIterations)
+		}
+	}
+
+	if result == nil {
+		return errors.New("raid comp optimizer finished without a result")
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to optimize raid comp: %s", result.Error.Message)
+	}
+
+	var output []byte
+	if format == formatJSON {
+		output, err = formatProto(result)
+	} else {
+		output, err = formatRows(raidCompTable(request, result))
+	}
+	if err != nil {
+		return err
+	}
+	return writeOutput(output)
+}
+
+// Turns every player of a raid sim into the pool, keeping its tanks.
+func raidCompRequestFromRaid(settings *proto.RaidSimSettings) *proto.RaidCompOptimizeRequest {
+	request := &proto.RaidCompOptimizeRequest{
+		RaidSize:   defaultRaidCompSize,
+		RaidBuffs:  settings.Raid.GetBuffs(),
+		Debuffs:    settings.Raid.GetDebuffs(),
+		Encounter:  settings.Encounter,
+		SimOptions: &proto.SimOptions{Iterations: defaultRaidCompIterations},
+	}
+	if settings.Settings != nil {
+		request.SimOptions.RandomSeed = settings.Settings.FixedRngSeed
+	}
+
+	for partyIdx, party := range settings.Raid.GetParties() {
+		for slot, player := range party.GetPlayers() {
+			if player == nil || player.GetSpec() == nil {
+				continue
+			}
+			raidIndex := int32(partyIdx*5 + slot)
+			for _, tank := range settings.Raid.Tanks {
+				if tank.Type == proto.UnitReference_Player && tank.Index == raidIndex {
+					request.TankPlayers = append(request.TankPlayers, int32(len(request.Players)))
+				}
+			}
+			request.Players = append(request.Players, player)
+		}
+	}
+	return request
+}
+
+// raidCompTable lists each comp with its raid DPS, 95% confidence interval,
+// players by party and the buff categories it misses.
+func raidCompTable(request *proto.RaidCompOptimizeRequest, result *proto.RaidCompOptimizeResult) ([]string, [][]string) {
+	header := []string{"Rank", "Raid DPS", "Raid DPS CI", "Parties", "Missing"}
+
+	playerName := func(idx int32) string {
+		if name := request.Players[idx].Name; name != "" {
+			return name
+		}
+		return "#" + strconv.Itoa(int(idx))
+	}
+	var rows [][]string
+	for i, comp := range result.Comps {
+		parties := make([]string, len(comp.Parties))
+		for j, party := range comp.Parties {
+			names := make([]string, len(party.Players))
+			for k, idx := range party.Players {
+				names[k] = playerName(idx)
+			}
+			parties[j] = strings.Join(names, ", ")
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			strconv.FormatFloat(comp.RaidDps, 'f', 2, 64),
+			strconv.FormatFloat(comp.RaidDpsCiHalfWidth, 'f', 2, 64),
+			strings.Join(parties, " / "),
+			strings.Join(comp.Missing, ", "),
+		})
+	}
+	return header, rows
+}
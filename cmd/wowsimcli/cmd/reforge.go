@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+var (
+	reforgeGems             []int32
+	reforgeKeepReforges     bool
+	reforgeVerifyIterations int32
+)
+
+var reforgeCmd = &cobra.Command{
+	Use:   "reforge",
+	Short: "optimize reforges and gems",
+	Long:  "pick the reforges and gems which maximize EP from a ReforgeOptimizeRequest or a wowsims export link, using the EP weights and stat caps of the link",
+	RunE:  reforgeMain,
+}
+
+func init() {
+	reforgeCmd.Flags().StringVar(&infile, "infile", "", "location of input file (ReforgeOptimizeRequest in protojson format)")
+	reforgeCmd.Flags().StringVar(&link, "link", "", "wowsims individual sim export link to use instead of an input file")
+	reforgeCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	reforgeCmd.Flags().StringVar(&format, "format", formatJSON, "output format: json, csv or table")
+	reforgeCmd.Flags().Int32SliceVar(&reforgeGems, "gems", nil, "ids of the gems to choose from, overrides the value from the input")
+	reforgeCmd.Flags().BoolVar(&reforgeKeepReforges, "keep-reforges", false, "only optimize gems")
+	reforgeCmd.Flags().Int32Var(&reforgeVerifyIterations, "verify-iterations", 0, "sim the original and optimized gear with this many iterations")
+	reforgeCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	reforgeCmd.MarkFlagsMutuallyExclusive("infile", "link")
+}
+
+func reforgeMain(cmd *cobra.Command, args []string) error {
+	if err := validateFormat(); err != nil {
+		return err
+	}
+
+	input, err := loadInput(&proto.ReforgeOptimizeRequest{})
+	if err != nil {
+		return err
+	}
+
+	var request *proto.ReforgeOptimizeRequest
+	switch settings := input.(type) {
+	case *proto.ReforgeOptimizeRequest:
+		request = settings
+	case *proto.IndividualSimSettings:
+		request = reforgeRequestFromSettings(settings)
+	case *proto.RaidSimSettings:
+		return errors.New("reforging requires an individual sim link, not a raid sim link")
+	}
+
+	if len(reforgeGems) > 0 {
+		request.GemIds = reforgeGems
+	}
+	if reforgeKeepReforges {
+		request.KeepReforges = true
+	}
+	if reforgeVerifyIterations > 0 {
+		request.VerifySimOptions = &proto.SimOptions{Iterations: reforgeVerifyIterations}
+	}
+	if request.StatWeights == nil {
+		return errors.New("no stat weights, set EP weights in the sim before exporting the link")
+	}
+
+	result := core.OptimizeReforges(request)
+	if result.ErrorResult != "" {
+		return fmt.Errorf("failed to optimize reforges: %s", result.ErrorResult)
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Score: %.2f EP (optimal: %t)\n", result.Score, result.Optimal)
+		if result.OriginalResult != nil && result.OptimizedResult != nil {
+			fmt.Fprintf(os.Stderr, "DPS: %.2f -> %.2f\n", playerDps(result.OriginalResult), playerDps(result.OptimizedResult))
+		}
+	}
+
+	var output []byte
+	if format == formatJSON {
+		output, err = formatProto(result)
+	} else {
+		output, err = formatRows(reforgeTable(result.Equipment))
+	}
+	if err != nil {
+		return err
+	}
+	return writeOutput(output)
+}
+
+// Uses the EP weights of the link, and its stat caps as hard caps.
+func reforgeRequestFromSettings(settings *proto.IndividualSimSettings) *proto.ReforgeOptimizeRequest {
+	request := &proto.ReforgeOptimizeRequest{
+		Player:      settings.Player,
+		RaidBuffs:   settings.RaidBuffs,
+		PartyBuffs:  settings.PartyBuffs,
+		Debuffs:     settings.Debuffs,
+		Encounter:   settings.Encounter,
+		StatWeights: settings.EpWeightsStats,
+	}
+
+	if settings.StatCaps != nil {
+		for i, value := range settings.StatCaps.Stats {
+			if value > 0 && i < stats.ProtoStatsLen {
+				request.StatCaps = append(request.StatCaps, &proto.ReforgeStatCap{
+					Stat:           proto.Stat(i),
+					Breakpoints:    []float64{value},
+					PostCapWeights: []float64{0},
+				})
+			}
+		}
+	}
+	return request
+}
+
+func playerDps(result *proto.RaidSimResult) float64 {
+	if result.Error != nil || result.RaidMetrics == nil || result.RaidMetrics.Dps == nil {
+		return 0
+	}
+	return result.RaidMetrics.Dps.Avg
+}
+
+// reforgeTable lists the reforge and gems of every item.
+func reforgeTable(equipment *proto.EquipmentSpec) ([]string, [][]string) {
+	header := []string{"Slot", "Item", "Reforge", "Gems"}
+
+	var rows [][]string
+	for slot, itemSpec := range equipment.Items {
+		item, ok := core.ItemsByID[itemSpec.Id]
+		if !ok {
+			continue
+		}
+
+		reforge := ""
+		if reforgeStat, ok := core.ReforgeStatsByID[itemSpec.Reforging]; ok {
+			reforge = stats.Stat(reforgeStat.FromStat).StatName() + " -> " + stats.Stat(reforgeStat.ToStat).StatName()
+		}
+
+		var gems []string
+		for _, gemID := range itemSpec.Gems {
+			if gem, ok := core.GemsByID[gemID]; ok {
+				gems = append(gems, gem.Name)
+			}
+		}
+
+		slotName := strings.TrimPrefix(proto.ItemSlot(slot).String(), "ItemSlot")
+		rows = append(rows, []string{slotName, item.Name, reforge, strings.Join(gems, ", ")})
+	}
+	return header, rows
+}
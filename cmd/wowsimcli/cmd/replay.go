@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/wowlog"
+)
+
+var (
+	replayLogFile    string
+	replayPlayer     string
+	replayEncounter  int
+	replayIterations int32
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "replay a fight from an in-game combat log and compare it with the sim",
+	Long:  "replay one player's casts from a WoWCombatLog.txt file with the character from an IndividualSimSettings file or a wowsims export link, and compare per spell damage, buff uptimes and resource gains, e.g. --log WoWCombatLog.txt --player Name --encounter 2",
+	RunE:  replayMain,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replayLogFile, "log", "", "location of the combat log file")
+	replayCmd.Flags().StringVar(&replayPlayer, "player", "", "name of the player to replay, with or without realm")
+	replayCmd.Flags().IntVar(&replayEncounter, "encounter", 0, "1-based index of the encounter to replay, may be omitted if the log contains at most one")
+	replayCmd.Flags().StringVar(&infile, "infile", "", "location of input file (IndividualSimSettings in protojson format)")
+	replayCmd.Flags().StringVar(&link, "link", "", "wowsims individual sim export link to use instead of an input file")
+	replayCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	replayCmd.Flags().StringVar(&format, "format", formatJSON, "output format: json, csv or table")
+	replayCmd.Flags().Int32Var(&replayIterations, "iterations", defaultReplayIterations, "number of iterations to sim the replay for")
+	replayCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	replayCmd.MarkFlagRequired("log")
+	replayCmd.MarkFlagRequired("player")
+	replayCmd.MarkFlagsMutuallyExclusive("infile", "link")
+}
+
+const defaultReplayIterations = 1000
+
+func replayMain(cmd *cobra.Command, args []string) error {
+	if err := validateFormat(); err != nil {
+		return err
+	}
+
+	input, err := loadInput(&proto.IndividualSimSettings{})
+	if err != nil {
+		return err
+	}
+	settings, ok := input.(*proto.IndividualSimSettings)
+	if !ok {
+		return errors.New("replays require an individual sim link, not a raid sim link")
+	}
+
+	logFile, err := os.Open(replayLogFile)
+	if err != nil {
+		return fmt.Errorf("failed to open combat log: %w", err)
+	}
+	combatLog, err := wowlog.Parse(logFile, replayPlayer)
+	logFile.Close()
+	if err != nil {
+		return err
+	}
+
+	summary, err := combatLog.Summarize(replayEncounter)
+	if err != nil {
+		return err
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Replaying %d casts over %s\n", len(summary.Casts), summary.Duration)
+	}
+
+	raid := core.SinglePlayerRaidProto(settings.Player, settings.PartyBuffs, settings.RaidBuffs, settings.Debuffs)
+	encounter := settings.Encounter
+	if encounter == nil {
+		encounter = &proto.Encounter{}
+	}
+	encounter.Duration = summary.Duration.Seconds()
+	encounter.DurationVariation = 0
+	encounter.UseHealth = false
+
+	statsResult := core.ComputeStats(&proto.ComputeStatsRequest{Raid: raid, Encounter: encounter})
+	if statsResult.ErrorResult != "" {
+		return fmt.Errorf("failed to compute stats: %s", statsResult.ErrorResult)
+	}
+	castable := make(map[int32]bool)
+	for _, spell := range statsResult.RaidStats.Parties[0].Players[0].Metadata.GetSpells() {
+		if spell.IsCastable {
+			castable[spell.Id.GetSpellId()] = true
+		}
+	}
+
+	rotation, skipped := summary.Rotation(func(spellID int32) bool {
+		return castable[spellID]
+	})
+	settings.Player.Rotation = rotation
+
+	request := &proto.RaidSimRequest{
+		Raid:      raid,
+		Encounter: encounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: replayIterations,
+		},
+	}
+	if settings.Settings != nil {
+		request.SimOptions.RandomSeed = settings.Settings.FixedRngSeed
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	core.RunRaidSimConcurrentAsync(request, reporter, "cmd-replay")
+
+	var result *proto.RaidSimResult
+	for v := range reporter {
+		if v.FinalRaidResult != nil {
+			result = v.FinalRaidResult
+			break
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Sim Progress: %d / %d\n", v.CompletedIterations, v.TotalIterations)
+		}
+	}
+	if result == nil {
+		return errors.New("replay sim finished without a result")
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to sim the replay: %s", result.Error.Message)
+	}
+
+	report := summary.Compare(result, result.RaidMetrics.Parties[0].Players[0], skipped)
+
+	var output []byte
+	if format == formatJSON {
+		output, err = json.MarshalIndent(report, "", "  ")
+	} else {
+		output, err = formatRows(replayTable(report))
+	}
+	if err != nil {
+		return err
+	}
+	return writeOutput(output)
+}
+
+// replayTable lists every compared value with the log and sim side by side,
+// and the relative difference.
+func replayTable(report *wowlog.Report) ([]string, [][]string) {
+	header := []string{"Section", "ID", "Name", "Metric", "Log", "Sim", "Diff %"}
+
+	formatValue := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+	var rows [][]string
+	addRow := func(section string, id string, name string, metric string, logValue float64, simValue float64) {
+		diff := ""
+		if logValue != 0 {
+			diff = formatValue(100 * (simValue - logValue) / logValue)
+		}
+		rows = append(rows, []string{section, id, name, metric, formatValue(logValue), formatValue(simValue), diff})
+	}
+
+	for _, spell := range report.Spells {
+		addRow("Spell", spell.ID, spell.Name, "Casts", spell.LogCasts, spell.SimCasts)
+		if spell.LogDamage > 0 || spell.SimDamage > 0 {
+			addRow("Spell", spell.ID, spell.Name, "Damage", spell.LogDamage, spell.SimDamage)
+		}
+		if spell.LogHealing > 0 || spell.SimHealing > 0 {
+			addRow("Spell", spell.ID, spell.Name, "Healing", spell.LogHealing, spell.SimHealing)
+		}
+		if spell.LogCritPercent > 0 || spell.SimCritPercent > 0 {
+			addRow("Spell", spell.ID, spell.Name, "Crit %", spell.LogCritPercent, spell.SimCritPercent)
+		}
+	}
+	for _, aura := range report.Auras {
+		addRow("Aura", aura.ID, aura.Name, "Uptime %", aura.LogUptimePercent, aura.SimUptimePercent)
+	}
+	for _, resource := range report.Resources {
+		addRow("Resource", resource.ID, resource.Name, resource.Type, resource.LogGain, resource.SimGain)
+	}
+	for _, cast := range report.Skipped {
+		rows = append(rows, []string{"Skipped", cast.ID, cast.Name, "Casts", strconv.Itoa(int(cast.Casts)), "", ""})
+	}
+	return header, rows
+}
@@ -18,6 +18,14 @@ func Execute(version string) {
 	rootCmd.AddCommand(simCmd)
 	rootCmd.AddCommand(bulkCmd)
 	rootCmd.AddCommand(decodeLinkCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(weightsCmd)
+	rootCmd.AddCommand(aplCmd)
+	rootCmd.AddCommand(workerCmd)
+	rootCmd.AddCommand(reforgeCmd)
+	rootCmd.AddCommand(scalingCmd)
+	rootCmd.AddCommand(raidCompCmd)
+	rootCmd.AddCommand(replayCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+var (
+	scalingIterations int32
+	scalingStats      []string
+	scalingMins       []float64
+	scalingMaxes      []float64
+	scalingSteps      []int32
+	scalingTotal      bool
+)
+
+var scalingCmd = &cobra.Command{
+	Use:   "scaling",
+	Short: "sim DPS across a range of one or two stats",
+	Long:  "sweep one or two stats over a grid from a StatScalingRequest or a wowsims export link, e.g. --stats HasteRating --min 0 --max 15000 --steps 31 --total",
+	RunE:  scalingMain,
+}
+
+func init() {
+	scalingCmd.Flags().StringVar(&infile, "infile", "", "location of input file (StatScalingRequest in protojson format)")
+	scalingCmd.Flags().StringVar(&link, "link", "", "wowsims individual sim export link to use instead of an input file")
+	scalingCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	scalingCmd.Flags().StringVar(&format, "format", formatJSON, "output format: json, csv or table")
+	scalingCmd.Flags().Int32Var(&scalingIterations, "iterations", 0, "iterations per point, overrides the value from the input")
+	scalingCmd.Flags().StringSliceVar(&scalingStats, "stats", nil, "one or two stats to sweep (e.g. HasteRating,MasteryRating), overrides the axes from the input")
+	scalingCmd.Flags().Float64SliceVar(&scalingMins, "min", nil, "lowest value of each stat")
+	scalingCmd.Flags().Float64SliceVar(&scalingMaxes, "max", nil, "highest value of each stat")
+	scalingCmd.Flags().Int32SliceVar(&scalingSteps, "steps", nil, "number of values of each stat")
+	scalingCmd.Flags().BoolVar(&scalingTotal, "total", false, "min and max are stat totals instead of amounts added")
+	scalingCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	scalingCmd.MarkFlagsMutuallyExclusive("infile", "link")
+}
+
+const defaultScalingIterations = 5000
+
+func scalingMain(cmd *cobra.Command, args []string) error {
+	if err := validateFormat(); err != nil {
+		return err
+	}
+
+	input, err := loadInput(&proto.StatScalingRequest{})
+	if err != nil {
+		return err
+	}
+
+	var request *proto.StatScalingRequest
+	switch settings := input.(type) {
+	case *proto.StatScalingRequest:
+		request = settings
+	case *proto.IndividualSimSettings:
+		request = &proto.StatScalingRequest{
+			Player:     settings.Player,
+			RaidBuffs:  settings.RaidBuffs,
+			PartyBuffs: settings.PartyBuffs,
+			Debuffs:    settings.Debuffs,
+			Encounter:  settings.Encounter,
+			Tanks:      settings.Tanks,
+			SimOptions: &proto.SimOptions{Iterations: defaultScalingIterations},
+		}
+		if settings.Settings != nil {
+			request.SimOptions.RandomSeed = settings.Settings.FixedRngSeed
+		}
+	case *proto.RaidSimSettings:
+		return errors.New("stat scaling requires an individual sim link, not a raid sim link")
+	}
+
+	if err := applyScalingFlags(request); err != nil {
+		return err
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	core.StatScalingAsync(request, reporter, "cmd-stat-scaling")
+
+	var result *proto.StatScalingResult
+	for v := range reporter {
+		if v.FinalScalingResult != nil {
+			result = v.FinalScalingResult
+			break
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Stat Scaling Progress: sim %d / %d, iterations %d / %d\n", v.CompletedSims, v.TotalSims, v.CompletedIterations, v.TotalIterations)
+		}
+	}
+
+	if result == nil {
+		return errors.New("stat scaling finished without a result")
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to compute stat scaling: %s", result.Error.Message)
+	}
+
+	var output []byte
+	if format == formatJSON {
+		output, err = formatProto(result)
+	} else {
+		output, err = formatRows(scalingTable(result))
+	}
+	if err != nil {
+		return err
+	}
+	return writeOutput(output)
+}
+
+func applyScalingFlags(request *proto.StatScalingRequest) error {
+	if request.SimOptions == nil {
+		request.SimOptions = &proto.SimOptions{Iterations: defaultScalingIterations}
+	}
+	if scalingIterations > 0 {
+		request.SimOptions.Iterations = scalingIterations
+	}
+
+	if len(scalingStats) == 0 {
+		if request.X == nil {
+			return errors.New("no stat to sweep, use --stats to select one")
+		}
+		return nil
+	}
+	if len(scalingStats) > 2 {
+		return errors.New("at most two stats can be swept")
+	}
+	if len(scalingMins) != len(scalingStats) || len(scalingMaxes) != len(scalingStats) || len(scalingSteps) != len(scalingStats) {
+		return errors.New("--min, --max and --steps need one value per stat")
+	}
+
+	axes := make([]*proto.StatScalingAxis, len(scalingStats))
+	for i, name := range scalingStats {
+		stat, err := parseStat(name)
+		if err != nil {
+			return err
+		}
+		axes[i] = &proto.StatScalingAxis{
+			Stat:  stat,
+			Min:   scalingMins[i],
+			Max:   scalingMaxes[i],
+			Steps: scalingSteps[i],
+			Total: scalingTotal,
+		}
+	}
+	request.X = axes[0]
+	request.Y = nil
+	if len(axes) > 1 {
+		request.Y = axes[1]
+	}
+	return nil
+}
+
+// scalingTable lists the DPS and HPS of every point, with 95% confidence
+// intervals.
+func scalingTable(result *proto.StatScalingResult) ([]string, [][]string) {
+	header := []string{"X", "Y", "DPS", "DPS CI", "DPS Delta", "DPS Delta CI", "HPS", "HPS CI"}
+
+	formatValue := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+	var rows [][]string
+	for _, point := range result.Points {
+		rows = append(rows, []string{
+			formatValue(point.X),
+			formatValue(point.Y),
+			formatValue(point.Dps.Avg),
+			formatValue(point.Dps.CiHalfWidth),
+			formatValue(point.Dps.Delta),
+			formatValue(point.Dps.DeltaCiHalfWidth),
+			formatValue(point.Hps.Avg),
+			formatValue(point.Hps.CiHalfWidth),
+		})
+	}
+	return header, rows
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "compute the character sheet for a raid",
+	Long:  "compute the character sheet for a raid from a ComputeStatsRequest or a wowsims export link",
+	RunE:  statsMain,
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&infile, "infile", "", "location of input file (ComputeStatsRequest in protojson format)")
+	statsCmd.Flags().StringVar(&link, "link", "", "wowsims export link to use instead of an input file")
+	statsCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	statsCmd.Flags().StringVar(&format, "format", formatJSON, "output format: json, csv or table")
+	statsCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	statsCmd.MarkFlagsMutuallyExclusive("infile", "link")
+}
+
+func statsMain(cmd *cobra.Command, args []string) error {
+	if err := validateFormat(); err != nil {
+		return err
+	}
+
+	input, err := loadInput(&proto.ComputeStatsRequest{})
+	if err != nil {
+		return err
+	}
+
+	var request *proto.ComputeStatsRequest
+	switch settings := input.(type) {
+	case *proto.ComputeStatsRequest:
+		request = settings
+	case *proto.IndividualSimSettings:
+		request = &proto.ComputeStatsRequest{
+			Raid:      core.SinglePlayerRaidProto(settings.Player, settings.PartyBuffs, settings.RaidBuffs, settings.Debuffs),
+			Encounter: settings.Encounter,
+		}
+	case *proto.RaidSimSettings:
+		request = &proto.ComputeStatsRequest{
+			Raid:      settings.Raid,
+			Encounter: settings.Encounter,
+		}
+	}
+
+	result := core.ComputeStats(request)
+	if result.ErrorResult != "" {
+		return fmt.Errorf("failed to compute stats: %s", result.ErrorResult)
+	}
+
+	var output []byte
+	if format == formatJSON {
+		output, err = formatProto(result)
+	} else {
+		output, err = formatRows(statsTable(request.Raid, result.RaidStats))
+	}
+	if err != nil {
+		return err
+	}
+	return writeOutput(output)
+}
+
+// statsTable lists the breakdown of every stat for every player in the raid.
+func statsTable(raid *proto.Raid, raidStats *proto.RaidStats) ([]string, [][]string) {
+	header := []string{"Player", "Stat", "Base", "Gear", "Talents", "Buffs", "Consumes", "Final"}
+
+	var rows [][]string
+	for partyIdx, party := range raidStats.Parties {
+		for playerIdx, playerStats := range party.Players {
+			if playerStats.FinalStats == nil {
+				continue
+			}
+
+			name := fmt.Sprintf("Player %d", partyIdx*5+playerIdx+1)
+			if partyIdx < len(raid.Parties) && playerIdx < len(raid.Parties[partyIdx].Players) {
+				if player := raid.Parties[partyIdx].Players[playerIdx]; player != nil && player.Name != "" {
+					name = player.Name
+				}
+			}
+
+			breakdown := []*proto.UnitStats{
+				playerStats.BaseStats,
+				playerStats.GearStats,
+				playerStats.TalentsStats,
+				playerStats.BuffsStats,
+				playerStats.ConsumesStats,
+				playerStats.FinalStats,
+			}
+			for i := range stats.ProtoStatsLen {
+				row := []string{name, stats.Stat(i).StatName()}
+				for _, unitStats := range breakdown {
+					row = append(row, formatStatValue(unitStats, i))
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	return header, rows
+}
+
+func formatStatValue(unitStats *proto.UnitStats, idx int) string {
+	if unitStats == nil || idx >= len(unitStats.Stats) {
+		return "0"
+	}
+	return strconv.FormatFloat(unitStats.Stats[idx], 'f', 2, 64)
+}
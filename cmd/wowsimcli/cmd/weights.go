@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+var (
+	weightsIterations int32
+	weightsStats      []string
+	weightsRefStat    string
+	weightsMethod     string
+	weightsSamples    int32
+	weightsQuadratic  bool
+)
+
+var weightsCmd = &cobra.Command{
+	Use:   "weights",
+	Short: "compute stat weights and EP values",
+	Long:  "compute stat weights and EP values from a StatWeightsRequest or a wowsims export link",
+	RunE:  weightsMain,
+}
+
+func init() {
+	weightsCmd.Flags().StringVar(&infile, "infile", "", "location of input file (StatWeightsRequest in protojson format)")
+	weightsCmd.Flags().StringVar(&link, "link", "", "wowsims individual sim export link to use instead of an input file")
+	weightsCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	weightsCmd.Flags().StringVar(&format, "format", formatJSON, "output format: json, csv or table")
+	weightsCmd.Flags().Int32Var(&weightsIterations, "iterations", 0, "iterations per sim, overrides the value from the input")
+	weightsCmd.Flags().StringSliceVar(&weightsStats, "stats", nil, "stats to weigh (e.g. Agility,HitRating), overrides the value from the input")
+	weightsCmd.Flags().StringVar(&weightsRefStat, "ref-stat", "", "EP reference stat, overrides the value from the input")
+	weightsCmd.Flags().StringVar(&weightsMethod, "method", "", "stat weights method: finite-difference or regression, overrides the value from the input")
+	weightsCmd.Flags().Int32Var(&weightsSamples, "samples", 0, "number of sims for the regression method, overrides the value from the input")
+	weightsCmd.Flags().BoolVar(&weightsQuadratic, "quadratic", false, "fit a quadratic model with the regression method, which also returns cross terms between stats")
+	weightsCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	weightsCmd.MarkFlagsMutuallyExclusive("infile", "link")
+}
+
+const defaultWeightsIterations = 10000
+
+func weightsMain(cmd *cobra.Command, args []string) error {
+	if err := validateFormat(); err != nil {
+		return err
+	}
+
+	input, err := loadInput(&proto.StatWeightsRequest{})
+	if err != nil {
+		return err
+	}
+
+	var request *proto.StatWeightsRequest
+	switch settings := input.(type) {
+	case *proto.StatWeightsRequest:
+		request = settings
+	case *proto.IndividualSimSettings:
+		request = statWeightsRequestFromSettings(settings)
+	case *proto.RaidSimSettings:
+		return errors.New("stat weights require an individual sim link, not a raid sim link")
+	}
+
+	if err := applyWeightsFlags(request); err != nil {
+		return err
+	}
+	if len(request.StatsToWeigh) == 0 && len(request.PseudoStatsToWeigh) == 0 {
+		return errors.New("no stats to weigh, use --stats to select some")
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	core.StatWeightsAsync(request, reporter, "cmd-stat-weights")
+
+	var result *proto.StatWeightsResult
+	for v := range reporter {
+		if v.FinalWeightResult != nil {
+			result = v.FinalWeightResult
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Stat Weights Progress: sim %d / %d, iterations %d / %d\n", v.CompletedSims, v.TotalSims, v.CompletedIterations, v.TotalIterations)
+	}
+
+	if result == nil {
+		return errors.New("stat weights finished without a result")
+	}
+	if result.Error != nil {
+		return fmt.Errorf("failed to compute stat weights: %s", result.Error.Message)
+	}
+
+	var output []byte
+	if format == formatJSON {
+		output, err = formatProto(result)
+	} else {
+		output, err = formatRows(weightsTable(request, result))
+	}
+	if err != nil {
+		return err
+	}
+	return writeOutput(output)
+}
+
+func statWeightsRequestFromSettings(settings *proto.IndividualSimSettings) *proto.StatWeightsRequest {
+	request := &proto.StatWeightsRequest{
+		Player:          settings.Player,
+		RaidBuffs:       settings.RaidBuffs,
+		PartyBuffs:      settings.PartyBuffs,
+		Debuffs:         settings.Debuffs,
+		Encounter:       settings.Encounter,
+		Tanks:           settings.Tanks,
+		SimOptions:      &proto.SimOptions{Iterations: defaultWeightsIterations},
+		EpReferenceStat: settings.DpsRefStat,
+	}
+
+	if settings.Settings != nil {
+		if settings.Settings.Iterations > 0 {
+			request.SimOptions.Iterations = settings.Settings.Iterations
+		}
+		request.SimOptions.RandomSeed = settings.Settings.FixedRngSeed
+	}
+
+	// Weigh the stats the user has custom EP values for.
+	if settings.EpWeightsStats != nil {
+		for i, value := range settings.EpWeightsStats.Stats {
+			if value != 0 && i < stats.ProtoStatsLen {
+				request.StatsToWeigh = append(request.StatsToWeigh, proto.Stat(i))
+			}
+		}
+	}
+	return request
+}
+
+func applyWeightsFlags(request *proto.StatWeightsRequest) error {
+	if request.SimOptions == nil {
+		request.SimOptions = &proto.SimOptions{Iterations: defaultWeightsIterations}
+	}
+	if weightsIterations > 0 {
+		request.SimOptions.Iterations = weightsIterations
+	}
+
+	if len(weightsStats) > 0 {
+		request.StatsToWeigh = request.StatsToWeigh[:0]
+		for _, name := range weightsStats {
+			stat, err := parseStat(name)
+			if err != nil {
+				return err
+			}
+			request.StatsToWeigh = append(request.StatsToWeigh, stat)
+		}
+	}
+
+	switch weightsMethod {
+	case "":
+	case "finite-difference":
+		request.Method = proto.StatWeightsMethod_StatWeightsMethodFiniteDifference
+	case "regression":
+		request.Method = proto.StatWeightsMethod_StatWeightsMethodRegression
+	default:
+		return fmt.Errorf("unknown stat weights method %q", weightsMethod)
+	}
+	if weightsSamples > 0 {
+		request.RegressionSamples = weightsSamples
+	}
+	if weightsQuadratic {
+		request.RegressionQuadratic = true
+	}
+
+	if weightsRefStat != "" {
+		stat, err := parseStat(weightsRefStat)
+		if err != nil {
+			return err
+		}
+		request.EpReferenceStat = stat
+	}
+	return nil
+}
+
+// parseStat accepts either the proto enum name (StatAgility) or the short
+// name (Agility), case-insensitively.
+func parseStat(name string) (proto.Stat, error) {
+	name = strings.TrimSpace(name)
+	for i := range stats.ProtoStatsLen {
+		if strings.EqualFold(name, stats.Stat(i).StatName()) || strings.EqualFold(name, proto.Stat_name[int32(i)]) {
+			return proto.Stat(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown stat %q", name)
+}
+
+// weightsTable lists the weight and EP of every weighed stat for each metric
+// that produced any values.
+func weightsTable(request *proto.StatWeightsRequest, result *proto.StatWeightsResult) ([]string, [][]string) {
+	metrics := []struct {
+		name   string
+		values *proto.StatWeightValues
+	}{
+		{"DPS", result.Dps},
+		{"HPS", result.Hps},
+		{"TPS", result.Tps},
+		{"DTPS", result.Dtps},
+		{"TMI", result.Tmi},
+		{"pDeath", result.PDeath},
+	}
+
+	type column struct {
+		name  string
+		value func(idx int, pseudo bool) float64
+	}
+	var columns []column
+	for _, metric := range metrics {
+		if metric.values == nil || !hasNonZero(metric.values.Weights) {
+			continue
+		}
+		values := metric.values
+		columns = append(columns,
+			column{metric.name + " Weight", unitStatsGetter(values.Weights)},
+			column{metric.name + " Weight StDev", unitStatsGetter(values.WeightsStdev)},
+			column{metric.name + " EP", unitStatsGetter(values.EpValues)},
+			column{metric.name + " EP StDev", unitStatsGetter(values.EpValuesStdev)},
+		)
+	}
+
+	header := []string{"Stat"}
+	for _, col := range columns {
+		header = append(header, col.name)
+	}
+
+	var rows [][]string
+	addRow := func(name string, idx int, pseudo bool) {
+		row := []string{name}
+		for _, col := range columns {
+			row = append(row, strconv.FormatFloat(col.value(idx, pseudo), 'f', 4, 64))
+		}
+		rows = append(rows, row)
+	}
+
+	weighed := append([]proto.Stat{request.EpReferenceStat}, request.StatsToWeigh...)
+	seen := make(map[proto.Stat]bool, len(weighed))
+	for _, stat := range weighed {
+		if seen[stat] {
+			continue
+		}
+		seen[stat] = true
+		addRow(stats.Stat(stat).StatName(), int(stat), false)
+	}
+	for _, pseudoStat := range request.PseudoStatsToWeigh {
+		addRow(strings.TrimPrefix(pseudoStat.String(), "PseudoStat"), int(pseudoStat), true)
+	}
+
+	return header, rows
+}
+
+func unitStatsGetter(unitStats *proto.UnitStats) func(int, bool) float64 {
+	return func(idx int, pseudo bool) float64 {
+		if unitStats == nil {
+			return 0
+		}
+		values := unitStats.Stats
+		if pseudo {
+			values = unitStats.PseudoStats
+		}
+		if idx >= len(values) {
+			return 0
+		}
+		return values[idx]
+	}
+}
+
+func hasNonZero(unitStats *proto.UnitStats) bool {
+	if unitStats == nil {
+		return false
+	}
+	for _, v := range unitStats.Stats {
+		if v != 0 {
+			return true
+		}
+	}
+	for _, v := range unitStats.PseudoStats {
+		if v != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"log"
+	"net/http"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/distsim"
+)
+
+var workerHost string
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "run sims for a coordinator on other machines",
+	Long:  "serve sim shards over HTTP for sim and bulk commands run with --workers",
+	RunE:  workerMain,
+}
+
+// Worker URLs used by the sim and bulk commands, runs locally if empty.
+var workerURLs []string
+
+func init() {
+	workerCmd.Flags().StringVar(&workerHost, "host", "localhost:3334", "address to listen on, e.g. :3334 to accept connections from other machines")
+}
+
+func workerMain(cmd *cobra.Command, args []string) error {
+	// Keeps several shards received at once from oversubscribing the CPUs.
+	core.SetWorkerBudget(runtime.NumCPU())
+
+	log.Printf("Worker listening on %s", workerHost)
+	return http.ListenAndServe(workerHost, distsim.NewWorkerHandler())
+}
+
+func addWorkersFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&workerURLs, "workers", nil, "comma separated worker URLs (see the worker command) to run the sim on instead of locally")
+}
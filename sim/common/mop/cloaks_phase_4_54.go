@@ -206,6 +206,7 @@ func init() {
 					shieldAura.Activate(sim)
 					absorbedDamage := result.Damage
 					result.Damage = 0
+					result.Absorbed += absorbedDamage
 					shieldAura.SetStacks(sim, int32(absorbedDamage))
 					shieldAura.Deactivate(sim)
 					icd.Use(sim)
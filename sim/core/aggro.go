@@ -0,0 +1,112 @@
+package core
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Share of the current victim's threat needed to pull aggro from them.
+const (
+	meleeAggroThreshold  = 1.1
+	rangedAggroThreshold = 1.3
+)
+
+type aggroModel struct {
+	// Threat of every unit on each target, indexed by target Index and then
+	// by UnitIndex.
+	threat [][]float64
+}
+
+// Sets up the encounter's aggro model, which lets targets switch to whoever
+// pulls aggro from their current victim instead of always attacking their
+// tank.
+func (env *Environment) applyAggroModel(options *proto.AggroModel) {
+	if options == nil || !options.Enabled || len(env.Encounter.AllTargets) == 0 {
+		return
+	}
+
+	model := &aggroModel{
+		threat: make([][]float64, len(env.Encounter.AllTargets)),
+	}
+	for _, target := range env.Encounter.AllTargets {
+		threatTable := make([]float64, len(env.AllUnits))
+		model.threat[target.Index] = threatTable
+
+		target.RegisterResetEffect(func(sim *Simulation) {
+			clear(threatTable)
+			if target.defaultTarget != nil {
+				threatTable[target.defaultTarget.UnitIndex] = options.TankInitialThreat
+			}
+		})
+	}
+
+	env.Encounter.aggro = model
+}
+
+// Threat the given unit currently has on this target.
+func (target *Unit) ThreatFrom(unit *Unit) float64 {
+	aggro := target.Env.Encounter.aggro
+	if aggro == nil || target.Type != EnemyUnit {
+		return 0
+	}
+	return aggro.threat[target.Index][unit.UnitIndex]
+}
+
+// Adds threat generated by attacker to this target's threat table, crediting
+// the attacker's redirect target if there is one.
+func (target *Unit) addThreat(sim *Simulation, attacker *Unit, threat float64) {
+	if threat == 0 || target.Type != EnemyUnit || attacker.Type == EnemyUnit {
+		return
+	}
+
+	if attacker.ThreatRedirectTarget != nil {
+		attacker = attacker.ThreatRedirectTarget
+	}
+
+	threatTable := sim.Encounter.aggro.threat[target.Index]
+	threatTable[attacker.UnitIndex] += threat
+
+	if !target.IsEnabled() || target.CurrentTarget == attacker {
+		return
+	}
+
+	victim := target.CurrentTarget
+	if victim != nil && victim.IsActive() {
+		// The tank is assumed to open the pull, so a victim without any threat
+		// yet keeps aggro until there is threat of their own to compare against.
+		if threatTable[victim.UnitIndex] == 0 {
+			return
+		}
+
+		threshold := TernaryFloat64(attacker.DistanceTo(sim, target) <= MaxMeleeRange, meleeAggroThreshold, rangedAggroThreshold)
+		if threatTable[attacker.UnitIndex] <= threatTable[victim.UnitIndex]*threshold {
+			return
+		}
+		attacker.Metrics.AggroPulls++
+	}
+
+	target.switchAggro(sim, attacker)
+}
+
+// Healing threat is split evenly between all active targets.
+func (unit *Unit) addHealingThreat(sim *Simulation, threat float64) {
+	targets := sim.Encounter.ActiveTargetUnits
+	for _, target := range targets {
+		target.addThreat(sim, unit, threat/float64(len(targets)))
+	}
+}
+
+// Makes this target attack a new victim. Positions are left alone, the target
+// is assumed to reach its new victim right away.
+func (target *Unit) switchAggro(sim *Simulation, victim *Unit) {
+	if sim.Log != nil {
+		target.Log(sim, "Aggro switched to %s (Threat: %0.3f)", victim.Label, target.ThreatFrom(victim))
+	}
+
+	target.CurrentTarget = victim
+	target.FaceUnit(sim, victim)
+	if sim.CombatLog != nil {
+		sim.CombatLog.AddTargetChange(sim, target, victim)
+	}
+
+	target.AutoAttacks.EnableAutoSwing(sim)
+}
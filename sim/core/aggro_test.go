@@ -0,0 +1,138 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+// Sets up a sim with an aggro model, a tank, a DPS player and the given
+// number of targets, all tanked by the first player.
+func setupAggroSim(tankInitialThreat float64, numTargets int) *Simulation {
+	player := func(name string) *proto.Player {
+		return &proto.Player{
+			Name:      name,
+			Class:     proto.Class_ClassShaman,
+			Buffs:     &proto.IndividualBuffs{},
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: &proto.EquipmentSpec{},
+		}
+	}
+
+	encounter := &proto.Encounter{
+		Duration: 180,
+		Aggro: &proto.AggroModel{
+			Enabled:           true,
+			TankInitialThreat: tankInitialThreat,
+		},
+	}
+	for range numTargets {
+		encounter.Targets = append(encounter.Targets, &proto.Target{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon})
+	}
+
+	sim := NewSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{RandomSeed: 101},
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				{
+					Players: []*proto.Player{player("Tank"), player("DPS")},
+					Buffs:   &proto.PartyBuffs{},
+				},
+			},
+			Tanks: []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}},
+		},
+		Encounter: encounter,
+	}, simsignals.CreateSignals())
+	sim.Reset()
+
+	return sim
+}
+
+func TestAggroFirstHitKeepsTank(t *testing.T) {
+	sim := setupAggroSim(0, 1)
+	target := sim.Encounter.AllTargetUnits[0]
+	tank, dps := sim.Raid.AllPlayerUnits[0], sim.Raid.AllPlayerUnits[1]
+
+	target.addThreat(sim, dps, 100)
+	if target.CurrentTarget != tank {
+		t.Fatalf("Expected the tank to keep aggro before generating any threat")
+	}
+	if dps.Metrics.AggroPulls != 0 {
+		t.Fatalf("Expected no aggro pulls, got %d", dps.Metrics.AggroPulls)
+	}
+
+	// Once the tank has threat, the DPS needs to beat it by the threshold.
+	target.addThreat(sim, tank, 50)
+	target.addThreat(sim, dps, 1)
+	if target.CurrentTarget != dps || dps.Metrics.AggroPulls != 1 {
+		t.Fatalf("Expected the DPS to pull aggro with twice the tank's threat")
+	}
+}
+
+func TestAggroThresholds(t *testing.T) {
+	testCases := []struct {
+		label     string
+		distance  float64
+		threshold float64
+	}{
+		{"melee", 5, meleeAggroThreshold},
+		{"ranged", 30, rangedAggroThreshold},
+	}
+
+	for _, testCase := range testCases {
+		sim := setupAggroSim(1000, 1)
+		target := sim.Encounter.AllTargetUnits[0]
+		tank, dps := sim.Raid.AllPlayerUnits[0], sim.Raid.AllPlayerUnits[1]
+		dps.DistanceFromTarget = testCase.distance
+
+		if threat := target.ThreatFrom(tank); threat != 1000 {
+			t.Fatalf("Expected the tank to start with 1000 threat, got %f", threat)
+		}
+
+		target.addThreat(sim, dps, 1000*testCase.threshold-1)
+		if target.CurrentTarget != tank {
+			t.Fatalf("%s: expected the tank to keep aggro below %0.0f%% of their threat", testCase.label, testCase.threshold*100)
+		}
+
+		target.addThreat(sim, dps, 2)
+		if target.CurrentTarget != dps {
+			t.Fatalf("%s: expected the DPS to pull aggro above %0.0f%% of the tank's threat", testCase.label, testCase.threshold*100)
+		}
+	}
+}
+
+func TestAggroThreatRedirect(t *testing.T) {
+	sim := setupAggroSim(1000, 1)
+	target := sim.Encounter.AllTargetUnits[0]
+	tank, dps := sim.Raid.AllPlayerUnits[0], sim.Raid.AllPlayerUnits[1]
+
+	dps.ThreatRedirectTarget = tank
+	target.addThreat(sim, dps, 5000)
+	if target.ThreatFrom(tank) != 6000 || target.ThreatFrom(dps) != 0 {
+		t.Fatalf("Expected redirected threat to go to the tank, got %f and %f", target.ThreatFrom(tank), target.ThreatFrom(dps))
+	}
+	if target.CurrentTarget != tank {
+		t.Fatalf("Expected the tank to keep aggro")
+	}
+}
+
+func TestAggroHealingThreatSplit(t *testing.T) {
+	sim := setupAggroSim(1000, 2)
+	dps := sim.Raid.AllPlayerUnits[1]
+
+	dps.addHealingThreat(sim, 100)
+	for _, target := range sim.Encounter.AllTargetUnits {
+		if threat := target.ThreatFrom(dps); threat != 50 {
+			t.Fatalf("Expected healing threat to be split between targets, got %f", threat)
+		}
+	}
+
+	sim.Cleanup()
+	sim.Reset()
+	for _, target := range sim.Encounter.AllTargetUnits {
+		if target.ThreatFrom(dps) != 0 || target.ThreatFrom(sim.Raid.AllPlayerUnits[0]) != 1000 {
+			t.Fatalf("Expected threat tables to be reset")
+		}
+	}
+}
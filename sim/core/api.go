@@ -60,6 +60,63 @@ func StatWeightCompute(request *proto.StatWeightsCalcRequest) *proto.StatWeights
 	return computeStatWeights(request)
 }
 
+/**
+ * Sims a grid of values for one or two stats, returning how DPS and other
+ * metrics change across it.
+ */
+func StatScaling(request *proto.StatScalingRequest) *proto.StatScalingResult {
+	return runStatScaling(request, nil, simsignals.CreateSignals())
+}
+
+func StatScalingAsync(request *proto.StatScalingRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalScalingResult: &proto.StatScalingResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := runStatScaling(request, progress, signals)
+		progress <- &proto.ProgressMetrics{
+			FinalScalingResult: result,
+		}
+	}()
+}
+
+/**
+ * Searches a pool of players for the raid comps with the highest raid DPS.
+ */
+func RaidCompOptimize(request *proto.RaidCompOptimizeRequest) *proto.RaidCompOptimizeResult {
+	return runRaidCompOptimize(request, nil, simsignals.CreateSignals())
+}
+
+func RaidCompOptimizeAsync(request *proto.RaidCompOptimizeRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalRaidCompResult: &proto.RaidCompOptimizeResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := runRaidCompOptimize(request, progress, signals)
+		progress <- &proto.ProgressMetrics{
+			FinalRaidCompResult: result,
+		}
+	}()
+}
+
 /**
  * Runs multiple iterations of the sim with a full raid.
  */
@@ -81,6 +138,8 @@ func RunRaidSimAsync(request *proto.RaidSimRequest, progress chan *proto.Progres
 	}
 	go func() {
 		defer simsignals.UnregisterId(requestId)
+		release := acquireWorker()
+		defer release()
 		RunSim(request, progress, signals)
 	}()
 }
@@ -131,6 +190,26 @@ func RunBulkSimAsync(request *proto.BulkSimRequest, progress chan *proto.Progres
 	}()
 }
 
+// RunBulkSimRemoteAsync is RunBulkSimAsync with every simulation of the bulk
+// run by runner, e.g. on other machines.
+func RunBulkSimRemoteAsync(request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, requestId string, runner RemoteRaidSimRunner, concurrency int) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalBulkResult: &proto.BulkSimResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		BulkSimRemote(signals, request, progress, runner, concurrency)
+	}()
+}
+
 var runningInWasm = false
 
 func SetRunningInWasm() {
@@ -148,3 +227,11 @@ func RunBulkCombos(request *proto.BulkSimCombosRequest) *proto.BulkSimCombosResu
 	}
 	return BulkSimCombos(simsignals.CreateSignals(), bulkSimReq)
 }
+
+/**
+ * Picks the reforges and gems which maximize the EP of the player's gear,
+ * optionally simming the original and optimized gear to compare them.
+ */
+func OptimizeReforges(request *proto.ReforgeOptimizeRequest) *proto.ReforgeOptimizeResult {
+	return optimizeReforges(request)
+}
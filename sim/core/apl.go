@@ -32,12 +32,21 @@ type APLRotation struct {
 	// Used to override MCD restrictions within sequences.
 	inSequence bool
 
+	// User-defined variables in definition order, so that initial values
+	// which read earlier variables are always reset after them.
+	variables []*aplVariable
+
+	// User-defined variables and named values, by name.
+	variablesByName map[string]*aplVariable
+	namedValues     map[string]*aplNamedValue
+
 	// Validation warnings that occur during proto parsing.
 	// We return these back to the user for display in the UI.
 	curValidations          []*proto.APLValidation
 	prepullValidations      [][]*proto.APLValidation
 	priorityListValidations [][]*proto.APLValidation
 	uuidValidations         map[*proto.UUID][]*proto.APLValidation
+	definitionValidations   []*proto.APLValidation
 
 	// Maps indices in filtered sim lists to indices in configs.
 	prepullIdxMap      []int
@@ -99,6 +108,8 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 		uuidValidations:         make(map[*proto.UUID][]*proto.APLValidation),
 	}
 
+	rotation.parseDefinitions(config)
+
 	// Parse prepull actions
 	for i, prepullItem := range config.PrepullActions {
 		prepullIdx := i // Save to local variable for correct lambda capture behavior
@@ -152,6 +163,7 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 			action.Finalize(rotation)
 		})
 	}
+	rotation.validateDefinitions()
 
 	agent := unit.Env.GetAgentFromUnit(unit)
 	if agent != nil {
@@ -237,7 +249,8 @@ func (rot *APLRotation) getStats() *proto.APLStats {
 		PriorityList: MapSlice(rot.priorityListValidations, func(validations []*proto.APLValidation) *proto.APLActionStats {
 			return &proto.APLActionStats{Validations: validations}
 		}),
-		UuidValidations: uuidValidationsArr,
+		UuidValidations:       uuidValidationsArr,
+		DefinitionValidations: rot.definitionValidations,
 	}
 }
 
@@ -268,6 +281,7 @@ func (rot *APLRotation) reset(sim *Simulation) {
 	for _, action := range rot.allAPLActions() {
 		action.impl.Reset(sim)
 	}
+	rot.resetVariables(sim)
 }
 
 // We intentionally try to mimic the behavior of simc APL to avoid confusion
@@ -293,6 +307,7 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 	i := 0
 	apl.inLoop = true
 
+	apl.unit.sampleReactionTime(sim)
 	apl.unit.UpdatePosition(sim)
 	for nextAction := apl.getNextAction(sim); nextAction != nil; i, nextAction = i+1, apl.getNextAction(sim) {
 		if i > 1000 {
@@ -30,6 +30,9 @@ func (action *APLActionChangeTarget) Execute(sim *Simulation) {
 	if sim.Log != nil {
 		action.unit.Log(sim, "Changing target to %s", action.newTarget.Get().Label)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.AddTargetChange(sim, action.unit, action.newTarget.Get())
+	}
 	action.unit.CurrentTarget = action.newTarget.Get()
 }
 func (action *APLActionChangeTarget) String() string {
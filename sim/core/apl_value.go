@@ -83,6 +83,12 @@ func (rot *APLRotation) newAPLValue(config *proto.APLValue) APLValue {
 	case *proto.APLValue_Min:
 		value = rot.newValueMin(config.GetMin(), config.Uuid)
 
+	// Variables
+	case *proto.APLValue_Variable:
+		value = rot.newValueVariable(config.GetVariable(), config.Uuid)
+	case *proto.APLValue_NamedValue:
+		value = rot.newValueNamedValue(config.GetNamedValue(), config.Uuid)
+
 	// Encounter
 	case *proto.APLValue_CurrentTime:
 		value = rot.newValueCurrentTime(config.GetCurrentTime(), config.Uuid)
@@ -166,6 +172,10 @@ func (rot *APLRotation) newAPLValue(config *proto.APLValue) APLValue {
 		value = rot.newValueUnitIsMoving(config.GetUnitIsMoving(), config.Uuid)
 	case *proto.APLValue_UnitDistance:
 		value = rot.newValueUnitDistance(config.GetUnitDistance(), config.Uuid)
+	case *proto.APLValue_NumTargetsInRange:
+		value = rot.newValueNumTargetsInRange(config.GetNumTargetsInRange(), config.Uuid)
+	case *proto.APLValue_UnitInFrontArc:
+		value = rot.newValueUnitInFrontArc(config.GetUnitInFrontArc(), config.Uuid)
 
 	// GCD
 	case *proto.APLValue_GcdIsReady:
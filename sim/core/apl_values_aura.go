@@ -57,8 +57,8 @@ func (value *APLValueAuraIsActive) String() string {
 
 type APLValueAuraIsActiveWithReactionTime struct {
 	DefaultAPLValueImpl
-	aura         AuraReference
-	reactionTime time.Duration
+	unit *Unit
+	aura AuraReference
 }
 
 func (rot *APLRotation) newValueAuraIsActiveWithReactionTime(config *proto.APLValueAuraIsActiveWithReactionTime, _ *proto.UUID) APLValue {
@@ -70,8 +70,8 @@ func (rot *APLRotation) newValueAuraIsActiveWithReactionTime(config *proto.APLVa
 		return nil
 	}
 	return &APLValueAuraIsActiveWithReactionTime{
-		aura:         aura,
-		reactionTime: rot.unit.ReactionTime,
+		unit: rot.unit,
+		aura: aura,
 	}
 }
 func (value *APLValueAuraIsActiveWithReactionTime) Type() proto.APLValueType {
@@ -79,7 +79,7 @@ func (value *APLValueAuraIsActiveWithReactionTime) Type() proto.APLValueType {
 }
 func (value *APLValueAuraIsActiveWithReactionTime) GetBool(sim *Simulation) bool {
 	aura := value.aura.Get()
-	return aura.IsActive() && aura.TimeActive(sim) >= value.reactionTime
+	return aura.IsActive() && aura.TimeActive(sim) >= value.unit.ProcReactionTime(sim, aura)
 }
 func (value *APLValueAuraIsActiveWithReactionTime) String() string {
 	return fmt.Sprintf("Aura Active With Reaction Time(%s)", value.aura.String())
@@ -87,8 +87,8 @@ func (value *APLValueAuraIsActiveWithReactionTime) String() string {
 
 type APLValueAuraIsInactiveWithReactionTime struct {
 	DefaultAPLValueImpl
-	aura         AuraReference
-	reactionTime time.Duration
+	unit *Unit
+	aura AuraReference
 }
 
 func (rot *APLRotation) newValueAuraIsInactiveWithReactionTime(config *proto.APLValueAuraIsInactiveWithReactionTime, _ *proto.UUID) APLValue {
@@ -100,8 +100,8 @@ func (rot *APLRotation) newValueAuraIsInactiveWithReactionTime(config *proto.APL
 		return nil
 	}
 	return &APLValueAuraIsInactiveWithReactionTime{
-		aura:         aura,
-		reactionTime: rot.unit.ReactionTime,
+		unit: rot.unit,
+		aura: aura,
 	}
 }
 func (value *APLValueAuraIsInactiveWithReactionTime) Type() proto.APLValueType {
@@ -109,7 +109,7 @@ func (value *APLValueAuraIsInactiveWithReactionTime) Type() proto.APLValueType {
 }
 func (value *APLValueAuraIsInactiveWithReactionTime) GetBool(sim *Simulation) bool {
 	aura := value.aura.Get()
-	return !aura.IsActive() && aura.TimeInactive(sim) >= value.reactionTime
+	return !aura.IsActive() && aura.TimeInactive(sim) >= value.unit.ReactionTime
 }
 func (value *APLValueAuraIsInactiveWithReactionTime) String() string {
 	return fmt.Sprintf("Aura Inactive With Reaction Time(%s)", value.aura.String())
@@ -203,8 +203,8 @@ func (value *APLValueAuraInternalCooldown) String() string {
 
 type APLValueAuraICDIsReadyWithReactionTime struct {
 	DefaultAPLValueImpl
-	aura         AuraReference
-	reactionTime time.Duration
+	unit *Unit
+	aura AuraReference
 }
 
 func (rot *APLRotation) newValueAuraICDIsReadyWithReactionTime(config *proto.APLValueAuraICDIsReadyWithReactionTime, _ *proto.UUID) APLValue {
@@ -216,8 +216,8 @@ func (rot *APLRotation) newValueAuraICDIsReadyWithReactionTime(config *proto.APL
 		return nil
 	}
 	return &APLValueAuraICDIsReadyWithReactionTime{
-		aura:         aura,
-		reactionTime: rot.unit.ReactionTime,
+		unit: rot.unit,
+		aura: aura,
 	}
 }
 func (value *APLValueAuraICDIsReadyWithReactionTime) Type() proto.APLValueType {
@@ -225,7 +225,7 @@ func (value *APLValueAuraICDIsReadyWithReactionTime) Type() proto.APLValueType {
 }
 func (value *APLValueAuraICDIsReadyWithReactionTime) GetBool(sim *Simulation) bool {
 	aura := value.aura.Get()
-	return aura.Icd.IsReady(sim) || (aura.IsActive() && aura.TimeActive(sim) < value.reactionTime)
+	return aura.Icd.IsReady(sim) || (aura.IsActive() && aura.TimeActive(sim) < value.unit.ProcReactionTime(sim, aura))
 }
 func (value *APLValueAuraICDIsReadyWithReactionTime) String() string {
 	return fmt.Sprintf("Aura ICD Is Ready with Reaction Time(%s)", value.aura.String())
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"fmt"
+
 	"github.com/wowsims/mop/sim/core/proto"
 )
 
@@ -30,20 +32,87 @@ func (value *APLValueUnitIsMoving) String() string {
 
 type APLValueUnitDistance struct {
 	DefaultAPLValueImpl
-	unit *Unit
+	sourceUnit UnitReference
+	targetUnit UnitReference
 }
 
 func (rot *APLRotation) newValueUnitDistance(config *proto.APLValueUnitDistance, _ *proto.UUID) APLValue {
+	sourceUnit := rot.GetSourceUnit(config.SourceUnit)
+	if sourceUnit.Get() == nil {
+		return nil
+	}
+
+	// Without a target unit this is the distance to the source unit's own target.
+	targetUnit := NewUnitReference(&proto.UnitReference{Type: proto.UnitReference_CurrentTarget}, sourceUnit.Get())
+	if config.TargetUnit != nil && config.TargetUnit.Type != proto.UnitReference_Unknown {
+		targetUnit = rot.GetTargetUnit(config.TargetUnit)
+	}
 	return &APLValueUnitDistance{
-		unit: rot.unit,
+		sourceUnit: sourceUnit,
+		targetUnit: targetUnit,
 	}
 }
 func (value *APLValueUnitDistance) Type() proto.APLValueType {
 	return proto.APLValueType_ValueTypeFloat
 }
 func (value *APLValueUnitDistance) GetFloat(sim *Simulation) float64 {
-	return value.unit.DistanceFromTarget
+	return value.sourceUnit.Get().DistanceTo(sim, value.targetUnit.Get())
 }
 func (value *APLValueUnitDistance) String() string {
 	return "Unit Distance From Target"
 }
+
+type APLValueNumTargetsInRange struct {
+	DefaultAPLValueImpl
+	unit     UnitReference
+	maxRange float64
+}
+
+func (rot *APLRotation) newValueNumTargetsInRange(config *proto.APLValueNumTargetsInRange, _ *proto.UUID) APLValue {
+	unit := rot.GetSourceUnit(config.SourceUnit)
+	if unit.Get() == nil {
+		return nil
+	}
+	return &APLValueNumTargetsInRange{
+		unit:     unit,
+		maxRange: config.Range,
+	}
+}
+func (value *APLValueNumTargetsInRange) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeInt
+}
+func (value *APLValueNumTargetsInRange) GetInt(sim *Simulation) int32 {
+	return value.unit.Get().NumEnemiesInRange(sim, value.maxRange)
+}
+func (value *APLValueNumTargetsInRange) String() string {
+	return fmt.Sprintf("Num Targets In Range(%.1f)", value.maxRange)
+}
+
+type APLValueUnitInFrontArc struct {
+	DefaultAPLValueImpl
+	sourceUnit UnitReference
+	targetUnit UnitReference
+	arcDegrees float64
+}
+
+func (rot *APLRotation) newValueUnitInFrontArc(config *proto.APLValueUnitInFrontArc, _ *proto.UUID) APLValue {
+	sourceUnit := rot.GetSourceUnit(config.SourceUnit)
+	targetUnit := rot.GetTargetUnit(config.TargetUnit)
+	if sourceUnit.Get() == nil || targetUnit.Get() == nil {
+		return nil
+	}
+	return &APLValueUnitInFrontArc{
+		sourceUnit: sourceUnit,
+		targetUnit: targetUnit,
+		arcDegrees: config.ArcDegrees,
+	}
+}
+func (value *APLValueUnitInFrontArc) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeBool
+}
+func (value *APLValueUnitInFrontArc) GetBool(sim *Simulation) bool {
+	return value.sourceUnit.Get().IsInFrontArc(sim, value.targetUnit.Get(), value.arcDegrees)
+}
+func (value *APLValueUnitInFrontArc) String() string {
+	return fmt.Sprintf("In Front Arc(%.0f)", value.arcDegrees)
+}
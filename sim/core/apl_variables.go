@@ -0,0 +1,261 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// A user-defined piece of rotation state, read with APLValueVariable and
+// written with APLActionSetVariable.
+type aplVariable struct {
+	name               string
+	valueType          proto.APLValueType
+	initialValue       APLValue
+	resetOnCombatStart bool
+
+	value float64 // 1 or 0 for boolean variables.
+	read  bool
+}
+
+func (variable *aplVariable) reset(sim *Simulation) {
+	if variable.initialValue == nil {
+		variable.value = 0
+	} else {
+		variable.set(sim, variable.initialValue)
+	}
+}
+
+func (variable *aplVariable) set(sim *Simulation, value APLValue) {
+	variable.value = variable.evaluate(sim, value)
+}
+
+func (variable *aplVariable) evaluate(sim *Simulation, value APLValue) float64 {
+	if variable.valueType == proto.APLValueType_ValueTypeBool {
+		if value.GetBool(sim) {
+			return 1
+		}
+		return 0
+	}
+	return value.GetFloat(sim)
+}
+
+// A value defined once at the root of the rotation and expanded in place
+// wherever it is referenced by name.
+type aplNamedValue struct {
+	config     *proto.APLValue
+	referenced bool
+	expanding  bool // Used to detect named values which reference themselves.
+}
+
+// Parses the variables and named values of the rotation. Must be done before
+// any actions are parsed, so references can be resolved.
+func (rot *APLRotation) parseDefinitions(config *proto.APLRotation) {
+	rot.variables = make([]*aplVariable, 0, len(config.Variables))
+	rot.variablesByName = make(map[string]*aplVariable, len(config.Variables))
+	rot.namedValues = make(map[string]*aplNamedValue, len(config.NamedValues))
+
+	rot.doAndRecordWarnings(&rot.definitionValidations, false, func() {
+		for _, namedValueConfig := range config.NamedValues {
+			if namedValueConfig.Name == "" {
+				rot.ValidationMessage(proto.LogLevel_Warning, "Named values must have a name")
+				continue
+			}
+			if _, ok := rot.namedValues[namedValueConfig.Name]; ok {
+				rot.ValidationMessage(proto.LogLevel_Warning, "Duplicate named value: '%s'", namedValueConfig.Name)
+				continue
+			}
+			rot.namedValues[namedValueConfig.Name] = &aplNamedValue{
+				config: namedValueConfig.Value,
+			}
+		}
+
+		for _, variableConfig := range config.Variables {
+			if variableConfig.Name == "" {
+				rot.ValidationMessage(proto.LogLevel_Warning, "Variables must have a name")
+				continue
+			}
+			if _, ok := rot.variablesByName[variableConfig.Name]; ok {
+				rot.ValidationMessage(proto.LogLevel_Warning, "Duplicate variable: '%s'", variableConfig.Name)
+				continue
+			}
+
+			variable := &aplVariable{
+				name:               variableConfig.Name,
+				valueType:          proto.APLValueType_ValueTypeFloat,
+				resetOnCombatStart: variableConfig.ResetOnCombatStart,
+			}
+			if initialValue := rot.newAPLValue(variableConfig.InitialValue); initialValue != nil {
+				if initialValue.Type() == proto.APLValueType_ValueTypeBool {
+					variable.valueType = proto.APLValueType_ValueTypeBool
+				}
+				variable.initialValue = rot.coerceTo(initialValue, variable.valueType)
+			}
+			rot.variables = append(rot.variables, variable)
+			rot.variablesByName[variable.name] = variable
+		}
+	})
+}
+
+// Warns about variables and named values that are never used. Must be done
+// after all actions are parsed.
+func (rot *APLRotation) validateDefinitions() {
+	rot.doAndRecordWarnings(&rot.definitionValidations, false, func() {
+		for _, variable := range rot.variables {
+			if !variable.read {
+				rot.ValidationMessage(proto.LogLevel_Warning, "Variable '%s' is never read", variable.name)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(rot.namedValues)) {
+			if !rot.namedValues[name].referenced {
+				rot.ValidationMessage(proto.LogLevel_Warning, "Named value '%s' is never used", name)
+			}
+		}
+	})
+}
+
+func (rot *APLRotation) resetVariables(sim *Simulation) {
+	for _, variable := range rot.variables {
+		variable.reset(sim)
+	}
+}
+
+func (rot *APLRotation) onEncounterStart(sim *Simulation) {
+	for _, variable := range rot.variables {
+		if variable.resetOnCombatStart {
+			variable.reset(sim)
+		}
+	}
+}
+
+type APLValueVariable struct {
+	DefaultAPLValueImpl
+	variable *aplVariable
+}
+
+func (rot *APLRotation) newValueVariable(config *proto.APLValueVariable, uuid *proto.UUID) APLValue {
+	variable, ok := rot.variablesByName[config.Name]
+	if !ok {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "Undefined variable: '%s'", config.Name)
+		return nil
+	}
+	variable.read = true
+	return &APLValueVariable{
+		variable: variable,
+	}
+}
+func (value *APLValueVariable) Type() proto.APLValueType {
+	return value.variable.valueType
+}
+func (value *APLValueVariable) GetBool(_ *Simulation) bool {
+	return value.variable.value != 0
+}
+func (value *APLValueVariable) GetFloat(_ *Simulation) float64 {
+	return value.variable.value
+}
+func (value *APLValueVariable) String() string {
+	return fmt.Sprintf("Variable(%s)", value.variable.name)
+}
+
+type APLValueNamedValue struct {
+	DefaultAPLValueImpl
+	name  string
+	inner APLValue
+}
+
+func (rot *APLRotation) newValueNamedValue(config *proto.APLValueNamedValue, uuid *proto.UUID) APLValue {
+	namedValue, ok := rot.namedValues[config.Name]
+	if !ok {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "Undefined named value: '%s'", config.Name)
+		return nil
+	}
+	if namedValue.expanding {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "Named value '%s' references itself", config.Name)
+		return nil
+	}
+	namedValue.referenced = true
+
+	// Each reference gets its own copy, so values with internal state are
+	// not shared between the places they are used.
+	namedValue.expanding = true
+	inner := rot.newAPLValue(namedValue.config)
+	namedValue.expanding = false
+	if inner == nil {
+		return nil
+	}
+
+	return &APLValueNamedValue{
+		name:  config.Name,
+		inner: inner,
+	}
+}
+func (value *APLValueNamedValue) GetInnerValues() []APLValue {
+	return []APLValue{value.inner}
+}
+func (value *APLValueNamedValue) Type() proto.APLValueType {
+	return value.inner.Type()
+}
+func (value *APLValueNamedValue) GetBool(sim *Simulation) bool {
+	return value.inner.GetBool(sim)
+}
+func (value *APLValueNamedValue) GetInt(sim *Simulation) int32 {
+	return value.inner.GetInt(sim)
+}
+func (value *APLValueNamedValue) GetFloat(sim *Simulation) float64 {
+	return value.inner.GetFloat(sim)
+}
+func (value *APLValueNamedValue) GetDuration(sim *Simulation) time.Duration {
+	return value.inner.GetDuration(sim)
+}
+func (value *APLValueNamedValue) GetString(sim *Simulation) string {
+	return value.inner.GetString(sim)
+}
+func (value *APLValueNamedValue) String() string {
+	return fmt.Sprintf("Named Value(%s)", value.name)
+}
+
+type APLActionSetVariable struct {
+	defaultAPLActionImpl
+	unit     *Unit
+	variable *aplVariable
+	value    APLValue
+}
+
+func (rot *APLRotation) newActionSetVariable(config *proto.APLActionSetVariable) APLActionImpl {
+	variable, ok := rot.variablesByName[config.Name]
+	if !ok {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Undefined variable: '%s'", config.Name)
+		return nil
+	}
+	value := rot.coerceTo(rot.newAPLValue(config.Value), variable.valueType)
+	if value == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Set Variable must provide a value")
+		return nil
+	}
+	return &APLActionSetVariable{
+		unit:     rot.unit,
+		variable: variable,
+		value:    value,
+	}
+}
+func (action *APLActionSetVariable) GetAPLValues() []APLValue {
+	return []APLValue{action.value}
+}
+
+// Only ready when the variable would change, otherwise an unconditional set
+// would be picked by the rotation forever.
+func (action *APLActionSetVariable) IsReady(sim *Simulation) bool {
+	return action.variable.evaluate(sim, action.value) != action.variable.value
+}
+func (action *APLActionSetVariable) Execute(sim *Simulation) {
+	action.variable.set(sim, action.value)
+	if sim.Log != nil {
+		action.unit.Log(sim, "Set variable '%s' to %0.3f", action.variable.name, action.variable.value)
+	}
+}
+func (action *APLActionSetVariable) String() string {
+	return fmt.Sprintf("Set Variable(%s = %s)", action.variable.name, action.value)
+}
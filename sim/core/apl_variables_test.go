@@ -0,0 +1,125 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func aplConst(val string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+}
+
+func aplVariableRef(name string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Variable{Variable: &proto.APLValueVariable{Name: name}}}
+}
+
+func aplNamedValueRef(name string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_NamedValue{NamedValue: &proto.APLValueNamedValue{Name: name}}}
+}
+
+func aplAdd(lhs *proto.APLValue, rhs *proto.APLValue) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{Op: proto.APLValueMath_OpAdd, Lhs: lhs, Rhs: rhs}}}
+}
+
+func TestAPLVariablesResetInDefinitionOrder(t *testing.T) {
+	sim := SetupFakeSim()
+	unit := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+
+	// Each variable starts one higher than the one before it.
+	const numVariables = 20
+	config := &proto.APLRotation{
+		Variables: []*proto.APLVariable{{Name: "v0", InitialValue: aplConst("1")}},
+	}
+	for i := 1; i < numVariables; i++ {
+		config.Variables = append(config.Variables, &proto.APLVariable{
+			Name:         fmt.Sprintf("v%d", i),
+			InitialValue: aplAdd(aplVariableRef(fmt.Sprintf("v%d", i-1)), aplConst("1")),
+		})
+	}
+	rot := unit.newAPLRotation(config)
+
+	for range 10 {
+		for _, variable := range rot.variables {
+			variable.value = 0
+		}
+		rot.resetVariables(sim)
+		for i, variable := range rot.variables {
+			if variable.value != float64(i+1) {
+				t.Fatalf("Expected %s to be reset to %d, got %f", variable.name, i+1, variable.value)
+			}
+		}
+	}
+}
+
+func TestAPLVariableSet(t *testing.T) {
+	sim := SetupFakeSim()
+	unit := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+
+	rot := unit.newAPLRotation(&proto.APLRotation{
+		Variables: []*proto.APLVariable{
+			{Name: "count", InitialValue: aplConst("2")},
+			{Name: "flag", InitialValue: aplConst("true"), ResetOnCombatStart: true},
+		},
+	})
+	rot.resetVariables(sim)
+
+	count := rot.newValueVariable(&proto.APLValueVariable{Name: "count"}, nil)
+	if count.Type() != proto.APLValueType_ValueTypeFloat || count.GetFloat(sim) != 2 {
+		t.Fatalf("Unexpected numeric variable %s = %f", count.Type(), count.GetFloat(sim))
+	}
+	flag := rot.newValueVariable(&proto.APLValueVariable{Name: "flag"}, nil)
+	if flag.Type() != proto.APLValueType_ValueTypeBool || !flag.GetBool(sim) {
+		t.Fatalf("Unexpected boolean variable %s = %t", flag.Type(), flag.GetBool(sim))
+	}
+
+	increment := rot.newActionSetVariable(&proto.APLActionSetVariable{Name: "count", Value: aplAdd(aplVariableRef("count"), aplConst("1"))})
+	if !increment.IsReady(sim) {
+		t.Fatalf("Expected a set which changes the variable to be ready")
+	}
+	increment.Execute(sim)
+	if count.GetFloat(sim) != 3 {
+		t.Fatalf("Expected count to be 3 after the set, got %f", count.GetFloat(sim))
+	}
+
+	setSame := rot.newActionSetVariable(&proto.APLActionSetVariable{Name: "count", Value: aplConst("3")})
+	if setSame.IsReady(sim) {
+		t.Fatalf("Expected a set which doesn't change the variable not to be ready")
+	}
+
+	clearFlag := rot.newActionSetVariable(&proto.APLActionSetVariable{Name: "flag", Value: aplConst("false")})
+	clearFlag.Execute(sim)
+	rot.onEncounterStart(sim)
+	if !flag.GetBool(sim) || count.GetFloat(sim) != 3 {
+		t.Fatalf("Expected only flag to be reset when combat starts")
+	}
+
+	if rot.newActionSetVariable(&proto.APLActionSetVariable{Name: "missing", Value: aplConst("1")}) != nil {
+		t.Fatalf("Expected a set of an undefined variable to be rejected")
+	}
+}
+
+func TestAPLNamedValues(t *testing.T) {
+	sim := SetupFakeSim()
+	unit := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+
+	rot := unit.newAPLRotation(&proto.APLRotation{
+		NamedValues: []*proto.APLNamedValue{
+			{Name: "five", Value: aplConst("5")},
+			{Name: "six", Value: aplAdd(aplNamedValueRef("five"), aplConst("1"))},
+			{Name: "loop", Value: aplAdd(aplNamedValueRef("loop"), aplConst("1"))},
+		},
+	})
+
+	six := rot.newValueNamedValue(&proto.APLValueNamedValue{Name: "six"}, nil)
+	if six == nil || six.GetFloat(sim) != 6 {
+		t.Fatalf("Expected named value six to expand to 6")
+	}
+	if rot.newValueNamedValue(&proto.APLValueNamedValue{Name: "missing"}, nil) != nil {
+		t.Fatalf("Expected an undefined named value to be rejected")
+	}
+	if rot.newValueNamedValue(&proto.APLValueNamedValue{Name: "loop"}, nil) != nil {
+		t.Fatalf("Expected a named value which references itself to be rejected")
+	}
+}
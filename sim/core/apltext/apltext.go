@@ -0,0 +1,75 @@
+// Package apltext implements a compact text syntax for APL rotations, as an
+// alternative to writing proto.APLRotation as nested protojson.
+//
+// A rotation is a list of lines. Blank lines and lines starting with '#' are
+// ignored. Repeated fields of the rotation get one line per entry, and other
+// fields a single assignment:
+//
+//	type=TypeAPL
+//	variables+=/name="burst",initial_value=false
+//	named_values+=/name="execute",value=is_execute_phase(threshold=E20)
+//	prepull+=/cast_spell,spell_id=spell:1234,do_at=-1s
+//	actions+=/cast_spell,spell_id=spell:5678,if=named_value(name="execute") && current_mana_percent() > 20%
+//
+// Actions are written as the name of the action field in APLAction followed
+// by the fields of that action. 'if' sets the condition of the action, and
+// the list item fields (hide, notes, do_at) may be given alongside. Nested
+// actions are wrapped in parentheses.
+//
+// Values are expressions. Constants, &&, ||, !, comparisons and + - * / use
+// the usual operator syntax, every other value is written as the name of the
+// value field in APLValue with its fields as arguments, e.g.
+// dot_remaining_time(spell_id=spell:1234). Action IDs without a tag may be
+// written as spell:ID, item:ID or other:OtherActionName, any other message as
+// {field=value,...} and repeated fields as [a, b].
+//
+// Every action and value type round-trips through Parse and Format, with the
+// exception of value UUIDs which are UI-only and are dropped.
+package apltext
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	actionDescriptor   = (&proto.APLAction{}).ProtoReflect().Descriptor()
+	valueDescriptor    = (&proto.APLValue{}).ProtoReflect().Descriptor()
+	actionIDDescriptor = (&proto.ActionID{}).ProtoReflect().Descriptor()
+	uuidDescriptor     = (&proto.UUID{}).ProtoReflect().Descriptor()
+
+	actionOneof = actionDescriptor.Oneofs().ByName("action")
+	valueOneof  = valueDescriptor.Oneofs().ByName("value")
+)
+
+// Shorter names for rotation fields.
+var sectionAliases = map[string]protoreflect.Name{
+	"actions": "priority_list",
+	"prepull": "prepull_actions",
+}
+
+const (
+	conditionParam = "if"
+	doAtParam      = "do_at"
+
+	// Used for an action or value without any type set.
+	noneKind = "none"
+)
+
+// Returns the field holding the action of a list item, e.g. APLListItem.action.
+func itemActionField(desc protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	fd := desc.Fields().ByName("action")
+	if fd == nil || fd.Message() == nil || fd.Message().FullName() != actionDescriptor.FullName() {
+		return nil
+	}
+	return fd
+}
+
+// Whether messages of this type are written as an action followed by params.
+func isActionItem(desc protoreflect.MessageDescriptor) bool {
+	return desc.FullName() == actionDescriptor.FullName() || itemActionField(desc) != nil
+}
+
+func isUUID(fd protoreflect.FieldDescriptor) bool {
+	return fd.Message() != nil && fd.Message().FullName() == uuidDescriptor.FullName()
+}
@@ -0,0 +1,134 @@
+package apltext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func checkRoundTrip(t *testing.T, name string, rotation *proto.APLRotation) {
+	t.Helper()
+	text := Format(rotation)
+	parsed, err := Parse(text)
+	if err != nil {
+		t.Fatalf("%s: failed to parse formatted rotation: %s\n%s", name, err, text)
+	}
+	if !googleProto.Equal(rotation, parsed) {
+		t.Fatalf("%s: rotation changed after round trip\nText:\n%s\nExpected: %s\nActual: %s", name, text, rotation, parsed)
+	}
+	if reformatted := Format(parsed); reformatted != text {
+		t.Fatalf("%s: formatting is not stable\nFirst:\n%s\nSecond:\n%s", name, text, reformatted)
+	}
+}
+
+func clearUUIDs(msg protoreflect.Message) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isUUID(fd):
+			msg.Clear(fd)
+		case fd.IsList() && fd.Message() != nil:
+			for i := 0; i < v.List().Len(); i++ {
+				clearUUIDs(v.List().Get(i).Message())
+			}
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			clearUUIDs(v.Message())
+		}
+		return true
+	})
+}
+
+func TestRoundTripPresetAPLs(t *testing.T) {
+	files, err := filepath.Glob("../../../ui/*/*/apls/*.apl.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("No APL files found")
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rotation := &proto.APLRotation{}
+		if err := protojson.Unmarshal(data, rotation); err != nil {
+			t.Fatalf("%s: %s", file, err)
+		}
+		clearUUIDs(rotation.ProtoReflect())
+		checkRoundTrip(t, file, rotation)
+	}
+}
+
+func TestRoundTripAllTypes(t *testing.T) {
+	rotation := &proto.APLRotation{}
+
+	for i := 0; i < actionOneof.Fields().Len(); i++ {
+		action := &proto.APLAction{}
+		action.ProtoReflect().Mutable(actionOneof.Fields().Get(i))
+		rotation.PriorityList = append(rotation.PriorityList, &proto.APLListItem{Action: action})
+	}
+
+	for i := 0; i < valueOneof.Fields().Len(); i++ {
+		value := &proto.APLValue{}
+		value.ProtoReflect().Mutable(valueOneof.Fields().Get(i))
+		rotation.PriorityList = append(rotation.PriorityList, &proto.APLListItem{
+			Action: &proto.APLAction{
+				Condition: value,
+				Action:    &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 1}}}},
+			},
+		})
+	}
+
+	checkRoundTrip(t, "all types", rotation)
+}
+
+func TestRoundTripExpressions(t *testing.T) {
+	for _, expr := range []string{
+		`is_execute_phase() && gcd_is_ready() && auto_time_to_next()`,
+		`is_execute_phase() && (gcd_is_ready() && auto_time_to_next())`,
+		`(is_execute_phase() || gcd_is_ready()) && !auto_time_to_next()`,
+		`!!is_execute_phase()`,
+		`1 + 2 * 3 - 4 / 5`,
+		`(1 + 2) * (3 - 4)`,
+		`1 - (2 - 3)`,
+		`(-1.5s < 20%) == true`,
+		`"foo bar" != "1e-5"`,
+	} {
+		value, err := ParseValue(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %s", expr, err)
+		}
+		checkRoundTrip(t, expr, &proto.APLRotation{
+			PriorityList: []*proto.APLListItem{{Action: &proto.APLAction{Condition: value}}},
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		text   string
+		line   int
+		column int
+	}{
+		{"actions+=/cast_spell,spell_id=spell:1\nactions+=/not_an_action", 2, 11},
+		{"actions+=/cast_spell,if=gcd_is_ready(", 1, 38},
+		{"  actions=/wait", 1, 3},
+		{"actions+=/cast_spell,if=1 < 2 < 3", 1, 31},
+		{"type=NotAType", 1, 6},
+	} {
+		_, err := Parse(tc.text)
+		textErr, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("Expected syntax error for %q, got %v", tc.text, err)
+		}
+		if textErr.Line != tc.line || textErr.Column != tc.column {
+			t.Fatalf("Expected error at line %d, column %d for %q, got %s", tc.line, tc.column, tc.text, textErr)
+		}
+	}
+}
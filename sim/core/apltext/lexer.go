@@ -0,0 +1,130 @@
+package apltext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string // For strings, the unquoted value.
+
+	line   int
+	column int
+}
+
+func (tok token) String() string {
+	switch tok.kind {
+	case tokenEOF:
+		return "end of line"
+	case tokenString:
+		return strconv.Quote(tok.text)
+	default:
+		return fmt.Sprintf("'%s'", tok.text)
+	}
+}
+
+// Error is a syntax error at a specific position of the input.
+type Error struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", err.Line, err.Column, err.Msg)
+}
+
+func errorAt(line int, column int, format string, args ...interface{}) *Error {
+	return &Error{
+		Line:   line,
+		Column: column,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+}
+
+// Longest first, so that e.g. '<=' is not lexed as '<' '='.
+var puncts = []string{"&&", "||", "<=", ">=", "==", "!=", "(", ")", "[", "]", "{", "}", ",", "=", ":", "!", "<", ">", "+", "-", "*", "/"}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+// Numbers may carry a unit suffix, e.g. 1.5s, 500ms or 20%, and are kept as
+// written so constants round-trip exactly.
+func isNumberChar(c byte) bool {
+	return isIdentChar(c) || c == '.' || c == '%'
+}
+
+// Splits a single line into tokens. Column numbers are offset by startColumn.
+func lex(text string, line int, startColumn int) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(text) {
+		c := text[i]
+		column := startColumn + i
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(text) && isIdentChar(text[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: text[start:i], line: line, column: column})
+		case isDigit(c) || (c == '.' && i+1 < len(text) && isDigit(text[i+1])):
+			start := i
+			for i < len(text) && isNumberChar(text[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text[start:i], line: line, column: column})
+		case c == '"':
+			quoted, err := strconv.QuotedPrefix(text[i:])
+			if err != nil {
+				return nil, errorAt(line, column, "unterminated string")
+			}
+			value, err := strconv.Unquote(quoted)
+			if err != nil {
+				return nil, errorAt(line, column, "invalid string: %s", err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, line: line, column: column})
+			i += len(quoted)
+		default:
+			matched := ""
+			for _, punct := range puncts {
+				if strings.HasPrefix(text[i:], punct) {
+					matched = punct
+					break
+				}
+			}
+			if matched == "" {
+				return nil, errorAt(line, column, "unexpected character '%c'", c)
+			}
+			tokens = append(tokens, token{kind: tokenPunct, text: matched, line: line, column: column})
+			i += len(matched)
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, line: line, column: startColumn + len(text)})
+	return tokens, nil
+}
@@ -0,0 +1,641 @@
+package apltext
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Parse reads a rotation written in the text syntax.
+func Parse(text string) (*proto.APLRotation, error) {
+	rotation := &proto.APLRotation{}
+	msg := rotation.ProtoReflect()
+
+	for lineIdx, line := range strings.Split(text, "\n") {
+		lineNum := lineIdx + 1
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		column := strings.Index(line, trimmed) + 1
+
+		nameEnd := 0
+		for nameEnd < len(trimmed) && isIdentChar(trimmed[nameEnd]) {
+			nameEnd++
+		}
+		name := trimmed[:nameEnd]
+		rest := trimmed[nameEnd:]
+
+		isListItem := strings.HasPrefix(rest, "+=/")
+		if !isListItem && !strings.HasPrefix(rest, "=") {
+			return nil, errorAt(lineNum, column+nameEnd, "expected '+=/' or '=' after '%s'", name)
+		}
+
+		fieldName := protoreflect.Name(name)
+		if alias, ok := sectionAliases[name]; ok {
+			fieldName = alias
+		}
+		fd := msg.Descriptor().Fields().ByName(fieldName)
+		if fd == nil {
+			return nil, errorAt(lineNum, column, "unknown rotation field '%s'", name)
+		}
+		if fd.IsList() != isListItem {
+			if isListItem {
+				return nil, errorAt(lineNum, column, "'%s' is not a list, use '%s=' instead", name, name)
+			}
+			return nil, errorAt(lineNum, column, "'%s' is a list, use '%s+=/' instead", name, name)
+		}
+
+		valueOffset := nameEnd + 1
+		if isListItem {
+			valueOffset = nameEnd + 3
+		}
+		tokens, err := lex(trimmed[valueOffset:], lineNum, column+valueOffset)
+		if err != nil {
+			return nil, err
+		}
+		p := &parser{tokens: tokens}
+
+		if isListItem {
+			list := msg.Mutable(fd).List()
+			elem := list.NewElement()
+			if err := p.parseItem(elem.Message()); err != nil {
+				return nil, err
+			}
+			list.Append(elem)
+		} else if err := p.parseField(msg, fd); err != nil {
+			return nil, err
+		}
+
+		if err := p.expectEnd(); err != nil {
+			return nil, err
+		}
+	}
+
+	return rotation, nil
+}
+
+// ParseValue reads a single value expression.
+func ParseValue(text string) (*proto.APLValue, error) {
+	tokens, err := lex(text, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	value, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	return value, p.expectEnd()
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isPunct(text string) bool {
+	tok := p.peek()
+	return tok.kind == tokenPunct && tok.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.isPunct(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("expected '%s', found %s", text, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) expectIdent() (token, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return tok, errorAt(tok.line, tok.column, "expected a name, found %s", tok)
+	}
+	return tok, nil
+}
+
+func (p *parser) expectEnd() error {
+	if p.peek().kind != tokenEOF {
+		return p.errorf("unexpected %s", p.peek())
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) *Error {
+	tok := p.peek()
+	return errorAt(tok.line, tok.column, format, args...)
+}
+
+func (p *parser) parseItem(msg protoreflect.Message) error {
+	if isActionItem(msg.Descriptor()) {
+		return p.parseActionItem(msg)
+	}
+	return p.parseParams(msg, ",", "")
+}
+
+// Parses params of msg until the closing punctuation (not consumed), or the
+// end of the line if closing is empty.
+func (p *parser) parseParams(msg protoreflect.Message, separator string, closing string) error {
+	if closing != "" && p.isPunct(closing) {
+		return nil
+	}
+	for {
+		nameTok, err := p.expectIdent()
+		if err != nil {
+			return err
+		}
+		fd := msg.Descriptor().Fields().ByName(protoreflect.Name(nameTok.text))
+		if fd == nil || isUUID(fd) {
+			return errorAt(nameTok.line, nameTok.column, "unknown field '%s' for %s", nameTok.text, msg.Descriptor().Name())
+		}
+		if err := p.parseParamValue(msg, fd); err != nil {
+			return err
+		}
+		if !p.accept(separator) {
+			return nil
+		}
+	}
+}
+
+// Parses '=value', or nothing for a bool field which is then set to true.
+func (p *parser) parseParamValue(msg protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	if !p.accept("=") {
+		if fd.Kind() == protoreflect.BoolKind && !fd.IsList() {
+			msg.Set(fd, protoreflect.ValueOfBool(true))
+			return nil
+		}
+		return p.expect("=")
+	}
+	return p.parseField(msg, fd)
+}
+
+// Parses an action item: the action type, then params which apply to the
+// action type, the action itself ('if') or the list item wrapping it.
+func (p *parser) parseActionItem(msg protoreflect.Message) error {
+	action := msg
+	var item protoreflect.Message
+	actionField := itemActionField(msg.Descriptor())
+	if actionField != nil {
+		item = msg
+		action = msg.Mutable(actionField).Message()
+	}
+
+	kindTok, err := p.expectIdent()
+	if err != nil {
+		return err
+	}
+	var kind protoreflect.Message
+	if kindTok.text != noneKind {
+		kindField := actionOneof.Fields().ByName(protoreflect.Name(kindTok.text))
+		if kindField == nil {
+			return errorAt(kindTok.line, kindTok.column, "unknown action '%s'", kindTok.text)
+		}
+		kind = action.Mutable(kindField).Message()
+	}
+
+	for p.accept(",") {
+		nameTok, err := p.expectIdent()
+		if err != nil {
+			return err
+		}
+		name := protoreflect.Name(nameTok.text)
+
+		target, fd := p.resolveActionParam(name, kind, action, item, actionField)
+		if fd == nil {
+			return errorAt(nameTok.line, nameTok.column, "unknown parameter '%s' for action '%s'", nameTok.text, kindTok.text)
+		}
+		if err := p.parseParamValue(target, fd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *parser) resolveActionParam(name protoreflect.Name, kind protoreflect.Message, action protoreflect.Message, item protoreflect.Message, actionField protoreflect.FieldDescriptor) (protoreflect.Message, protoreflect.FieldDescriptor) {
+	if name == conditionParam {
+		return action, action.Descriptor().Fields().ByName("condition")
+	}
+	if name == doAtParam && item != nil {
+		if fd := item.Descriptor().Fields().ByName("do_at_value"); fd != nil {
+			return item, fd
+		}
+	}
+
+	if kind != nil {
+		if fd := kind.Descriptor().Fields().ByName(name); fd != nil && !isUUID(fd) {
+			return kind, fd
+		}
+	}
+	if fd := action.Descriptor().Fields().ByName(name); fd != nil && fd.ContainingOneof() == nil {
+		return action, fd
+	}
+	if item != nil {
+		if fd := item.Descriptor().Fields().ByName(name); fd != nil && fd != actionField {
+			return item, fd
+		}
+	}
+	return nil, nil
+}
+
+// Parses the value of a field, and sets or appends it on msg.
+func (p *parser) parseField(msg protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	if fd.IsMap() {
+		return p.errorf("map fields are not supported")
+	}
+
+	if !fd.IsList() {
+		if fd.Message() != nil {
+			return p.parseMessage(msg.Mutable(fd).Message())
+		}
+		value, err := p.parseScalar(fd)
+		if err != nil {
+			return err
+		}
+		msg.Set(fd, value)
+		return nil
+	}
+
+	if err := p.expect("["); err != nil {
+		return err
+	}
+	list := msg.Mutable(fd).List()
+	for !p.isPunct("]") {
+		if fd.Message() != nil {
+			elem := list.NewElement()
+			if err := p.parseMessage(elem.Message()); err != nil {
+				return err
+			}
+			list.Append(elem)
+		} else {
+			value, err := p.parseScalar(fd)
+			if err != nil {
+				return err
+			}
+			list.Append(value)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	return p.expect("]")
+}
+
+func (p *parser) parseScalar(fd protoreflect.FieldDescriptor) (protoreflect.Value, error) {
+	tok := p.peek()
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		p.next()
+		if tok.kind == tokenIdent && (tok.text == "true" || tok.text == "false") {
+			return protoreflect.ValueOfBool(tok.text == "true"), nil
+		}
+		return protoreflect.Value{}, errorAt(tok.line, tok.column, "expected true or false, found %s", tok)
+	case protoreflect.StringKind:
+		p.next()
+		if tok.kind != tokenString {
+			return protoreflect.Value{}, errorAt(tok.line, tok.column, "expected a string, found %s", tok)
+		}
+		return protoreflect.ValueOfString(tok.text), nil
+	case protoreflect.BytesKind:
+		p.next()
+		if tok.kind != tokenString {
+			return protoreflect.Value{}, errorAt(tok.line, tok.column, "expected a string, found %s", tok)
+		}
+		return protoreflect.ValueOfBytes([]byte(tok.text)), nil
+	case protoreflect.EnumKind:
+		if tok.kind == tokenIdent {
+			p.next()
+			enumValue := fd.Enum().Values().ByName(protoreflect.Name(tok.text))
+			if enumValue == nil {
+				return protoreflect.Value{}, errorAt(tok.line, tok.column, "unknown %s value '%s'", fd.Enum().Name(), tok.text)
+			}
+			return protoreflect.ValueOfEnum(enumValue.Number()), nil
+		}
+		number, err := p.parseNumber(func(text string) (interface{}, error) { return strconv.ParseInt(text, 10, 32) })
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(number.(int64))), nil
+	}
+
+	bitSize := 64
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.FloatKind:
+		bitSize = 32
+	}
+
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		number, err := p.parseNumber(func(text string) (interface{}, error) { return strconv.ParseInt(text, 10, bitSize) })
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfInt32(int32(number.(int64))), nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		number, err := p.parseNumber(func(text string) (interface{}, error) { return strconv.ParseInt(text, 10, bitSize) })
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfInt64(number.(int64)), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		number, err := p.parseNumber(func(text string) (interface{}, error) { return strconv.ParseUint(text, 10, bitSize) })
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfUint32(uint32(number.(uint64))), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		number, err := p.parseNumber(func(text string) (interface{}, error) { return strconv.ParseUint(text, 10, bitSize) })
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfUint64(number.(uint64)), nil
+	case protoreflect.FloatKind:
+		number, err := p.parseNumber(func(text string) (interface{}, error) { return strconv.ParseFloat(text, bitSize) })
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat32(float32(number.(float64))), nil
+	case protoreflect.DoubleKind:
+		number, err := p.parseNumber(func(text string) (interface{}, error) { return strconv.ParseFloat(text, bitSize) })
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfFloat64(number.(float64)), nil
+	}
+
+	return protoreflect.Value{}, errorAt(tok.line, tok.column, "unsupported field type %s", fd.Kind())
+}
+
+// Parses an optionally negated number token with the given conversion.
+func (p *parser) parseNumber(convert func(string) (interface{}, error)) (interface{}, error) {
+	start := p.peek()
+	sign := ""
+	if p.accept("-") {
+		sign = "-"
+	}
+	tok := p.next()
+	if tok.kind != tokenNumber {
+		return nil, errorAt(tok.line, tok.column, "expected a number, found %s", tok)
+	}
+	number, err := convert(sign + tok.text)
+	if err != nil {
+		return nil, errorAt(start.line, start.column, "invalid number '%s%s'", sign, tok.text)
+	}
+	return number, nil
+}
+
+func (p *parser) parseMessage(msg protoreflect.Message) error {
+	switch msg.Descriptor().FullName() {
+	case valueDescriptor.FullName():
+		value, err := p.parseExpr()
+		if err != nil {
+			return err
+		}
+		googleProto.Merge(msg.Interface(), value)
+		return nil
+	case actionDescriptor.FullName():
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		if err := p.parseActionItem(msg); err != nil {
+			return err
+		}
+		return p.expect(")")
+	case actionIDDescriptor.FullName():
+		if p.peek().kind == tokenIdent {
+			return p.parseActionID(msg.Interface().(*proto.ActionID))
+		}
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	if err := p.parseParams(msg, ",", "}"); err != nil {
+		return err
+	}
+	return p.expect("}")
+}
+
+// Parses the spell:ID, item:ID or other:Name shorthand.
+func (p *parser) parseActionID(actionID *proto.ActionID) error {
+	kindTok := p.next()
+	if err := p.expect(":"); err != nil {
+		return err
+	}
+
+	switch kindTok.text {
+	case "spell", "item":
+		number, err := p.parseNumber(func(text string) (interface{}, error) { return strconv.ParseInt(text, 10, 32) })
+		if err != nil {
+			return err
+		}
+		if kindTok.text == "spell" {
+			actionID.RawId = &proto.ActionID_SpellId{SpellId: int32(number.(int64))}
+		} else {
+			actionID.RawId = &proto.ActionID_ItemId{ItemId: int32(number.(int64))}
+		}
+	case "other":
+		otherTok, err := p.expectIdent()
+		if err != nil {
+			return err
+		}
+		other, ok := proto.OtherAction_value[otherTok.text]
+		if !ok {
+			return errorAt(otherTok.line, otherTok.column, "unknown OtherAction '%s'", otherTok.text)
+		}
+		actionID.RawId = &proto.ActionID_OtherId{OtherId: proto.OtherAction(other)}
+	default:
+		return errorAt(kindTok.line, kindTok.column, "expected spell, item or other, found %s", kindTok)
+	}
+	return nil
+}
+
+func (p *parser) parseExpr() (*proto.APLValue, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (*proto.APLValue, error) {
+	vals, err := p.parseNaryOperands("||", p.parseAnd)
+	if err != nil || len(vals) == 1 {
+		return firstValue(vals), err
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Or{Or: &proto.APLValueOr{Vals: vals}}}, nil
+}
+
+func (p *parser) parseAnd() (*proto.APLValue, error) {
+	vals, err := p.parseNaryOperands("&&", p.parseCompare)
+	if err != nil || len(vals) == 1 {
+		return firstValue(vals), err
+	}
+	return &proto.APLValue{Value: &proto.APLValue_And{And: &proto.APLValueAnd{Vals: vals}}}, nil
+}
+
+func (p *parser) parseNaryOperands(op string, parseOperand func() (*proto.APLValue, error)) ([]*proto.APLValue, error) {
+	var vals []*proto.APLValue
+	for {
+		val, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, val)
+		if !p.accept(op) {
+			return vals, nil
+		}
+	}
+}
+
+func firstValue(vals []*proto.APLValue) *proto.APLValue {
+	if len(vals) == 0 {
+		return nil
+	}
+	return vals[0]
+}
+
+func (p *parser) parseCompare() (*proto.APLValue, error) {
+	lhs, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	if tok.kind != tokenPunct {
+		return lhs, nil
+	}
+	for op, text := range compareOperators {
+		if tok.text == text {
+			p.next()
+			rhs, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			return &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{Op: op, Lhs: lhs, Rhs: rhs}}}, nil
+		}
+	}
+	return lhs, nil
+}
+
+func (p *parser) parseSum() (*proto.APLValue, error) {
+	return p.parseMath(p.parseProduct, proto.APLValueMath_OpAdd, proto.APLValueMath_OpSub)
+}
+
+func (p *parser) parseProduct() (*proto.APLValue, error) {
+	return p.parseMath(p.parseUnary, proto.APLValueMath_OpMul, proto.APLValueMath_OpDiv)
+}
+
+// Parses a left-associative chain of math operators.
+func (p *parser) parseMath(parseOperand func() (*proto.APLValue, error), ops ...proto.APLValueMath_MathOperator) (*proto.APLValue, error) {
+	lhs, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+outer:
+	for {
+		for _, op := range ops {
+			if p.accept(mathOperators[op]) {
+				rhs, err := parseOperand()
+				if err != nil {
+					return nil, err
+				}
+				lhs = &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{Op: op, Lhs: lhs, Rhs: rhs}}}
+				continue outer
+			}
+		}
+		return lhs, nil
+	}
+}
+
+func (p *parser) parseUnary() (*proto.APLValue, error) {
+	if p.accept("!") {
+		val, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: val}}}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (*proto.APLValue, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenNumber:
+		p.next()
+		return constValue(tok.text), nil
+	case tokenString:
+		p.next()
+		return constValue(tok.text), nil
+	case tokenPunct:
+		if p.accept("(") {
+			val, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return val, p.expect(")")
+		}
+		if p.accept("-") {
+			numTok := p.next()
+			if numTok.kind != tokenNumber {
+				return nil, errorAt(numTok.line, numTok.column, "expected a number after '-', found %s", numTok)
+			}
+			return constValue("-" + numTok.text), nil
+		}
+	case tokenIdent:
+		p.next()
+		if !p.isPunct("(") {
+			if tok.text == "true" || tok.text == "false" {
+				return constValue(tok.text), nil
+			}
+			return nil, errorAt(tok.line, tok.column, "expected '(' after '%s'", tok.text)
+		}
+		return p.parseCall(tok)
+	}
+	return nil, errorAt(tok.line, tok.column, "expected a value, found %s", tok)
+}
+
+// Parses a value written as kind(field=value, ...).
+func (p *parser) parseCall(kindTok token) (*proto.APLValue, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	value := &proto.APLValue{}
+	if kindTok.text != noneKind {
+		kindField := valueOneof.Fields().ByName(protoreflect.Name(kindTok.text))
+		if kindField == nil {
+			return nil, errorAt(kindTok.line, kindTok.column, "unknown value '%s'", kindTok.text)
+		}
+		kind := value.ProtoReflect().Mutable(kindField).Message()
+		if err := p.parseParams(kind, ",", ")"); err != nil {
+			return nil, err
+		}
+	}
+	return value, p.expect(")")
+}
+
+func constValue(val string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+}
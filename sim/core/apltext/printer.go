@@ -0,0 +1,309 @@
+package apltext
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Operator precedence, from loosest to tightest binding.
+const (
+	precOr = iota + 1
+	precAnd
+	precCompare
+	precSum
+	precProduct
+	precUnary
+	precPrimary
+)
+
+var compareOperators = map[proto.APLValueCompare_ComparisonOperator]string{
+	proto.APLValueCompare_OpEq: "==",
+	proto.APLValueCompare_OpNe: "!=",
+	proto.APLValueCompare_OpLt: "<",
+	proto.APLValueCompare_OpLe: "<=",
+	proto.APLValueCompare_OpGt: ">",
+	proto.APLValueCompare_OpGe: ">=",
+}
+
+var mathOperators = map[proto.APLValueMath_MathOperator]string{
+	proto.APLValueMath_OpAdd: "+",
+	proto.APLValueMath_OpSub: "-",
+	proto.APLValueMath_OpMul: "*",
+	proto.APLValueMath_OpDiv: "/",
+}
+
+// Constants which lex back as a single number token (optionally negated) and
+// can therefore be written without quotes.
+var bareConstRegex = regexp.MustCompile(`^-?([0-9]|\.[0-9])[0-9A-Za-z.%]*$`)
+
+// Format prints a rotation in the text syntax. The output parses back into an
+// equal rotation, except for value UUIDs which are dropped.
+func Format(rotation *proto.APLRotation) string {
+	var sb strings.Builder
+	msg := rotation.ProtoReflect()
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !msg.Has(fd) {
+			continue
+		}
+
+		name := sectionName(fd)
+		if fd.IsList() {
+			list := msg.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				sb.WriteString(name)
+				sb.WriteString("+=/")
+				sb.WriteString(formatItem(list.Get(j).Message()))
+				sb.WriteString("\n")
+			}
+		} else {
+			sb.WriteString(name)
+			sb.WriteString("=")
+			sb.WriteString(formatField(fd, msg.Get(fd)))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// FormatValue prints a single value expression.
+func FormatValue(value *proto.APLValue) string {
+	text, _ := formatValue(value.ProtoReflect())
+	return text
+}
+
+// FormatAction prints a single action, in the same form as a list item.
+func FormatAction(action *proto.APLAction) string {
+	return formatActionItem(action.ProtoReflect())
+}
+
+func sectionName(fd protoreflect.FieldDescriptor) string {
+	for alias, name := range sectionAliases {
+		if fd.Name() == name {
+			return alias
+		}
+	}
+	return string(fd.Name())
+}
+
+func formatItem(msg protoreflect.Message) string {
+	if isActionItem(msg.Descriptor()) {
+		return formatActionItem(msg)
+	}
+	return strings.Join(formatParams(msg, nil), ",")
+}
+
+// Prints an APLAction, or a list item wrapping one, as the action type
+// followed by the action fields, the condition and the item fields.
+func formatActionItem(msg protoreflect.Message) string {
+	action := msg
+	var item protoreflect.Message
+	if actionField := itemActionField(msg.Descriptor()); actionField != nil {
+		item = msg
+		action = msg.Get(actionField).Message()
+	}
+
+	var parts []string
+	if kindField := action.WhichOneof(actionOneof); kindField != nil {
+		parts = append(parts, string(kindField.Name()))
+		parts = append(parts, formatParams(action.Get(kindField).Message(), nil)...)
+	} else {
+		parts = append(parts, noneKind)
+	}
+
+	parts = append(parts, formatParams(action, func(fd protoreflect.FieldDescriptor) string {
+		if fd.ContainingOneof() != nil {
+			return ""
+		}
+		if fd.Name() == "condition" {
+			return conditionParam
+		}
+		return string(fd.Name())
+	})...)
+
+	if item != nil {
+		parts = append(parts, formatParams(item, func(fd protoreflect.FieldDescriptor) string {
+			if fd.Name() == "action" {
+				return ""
+			}
+			if fd.Name() == "do_at_value" {
+				return doAtParam
+			}
+			return string(fd.Name())
+		})...)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Prints every set field of msg as name=value. paramName may rename fields,
+// or skip them by returning "".
+func formatParams(msg protoreflect.Message, paramName func(protoreflect.FieldDescriptor) string) []string {
+	var params []string
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !msg.Has(fd) || isUUID(fd) {
+			continue
+		}
+
+		name := string(fd.Name())
+		if paramName != nil {
+			name = paramName(fd)
+		}
+		if name == "" {
+			continue
+		}
+		params = append(params, name+"="+formatField(fd, msg.Get(fd)))
+	}
+	return params
+}
+
+func formatField(fd protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	if !fd.IsList() {
+		return formatSingular(fd, value)
+	}
+
+	list := value.List()
+	elems := make([]string, list.Len())
+	for i := range elems {
+		elems[i] = formatSingular(fd, list.Get(i))
+	}
+	return "[" + strings.Join(elems, ", ") + "]"
+}
+
+func formatSingular(fd protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return strconv.FormatBool(value.Bool())
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return strconv.FormatInt(value.Int(), 10)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return strconv.FormatUint(value.Uint(), 10)
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 32)
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(value.Float(), 'g', -1, 64)
+	case protoreflect.StringKind:
+		return strconv.Quote(value.String())
+	case protoreflect.BytesKind:
+		return strconv.Quote(string(value.Bytes()))
+	case protoreflect.EnumKind:
+		if enumValue := fd.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return strconv.FormatInt(int64(value.Enum()), 10)
+	default:
+		return formatMessage(value.Message())
+	}
+}
+
+func formatMessage(msg protoreflect.Message) string {
+	switch msg.Descriptor().FullName() {
+	case valueDescriptor.FullName():
+		text, _ := formatValue(msg)
+		return text
+	case actionDescriptor.FullName():
+		return "(" + formatActionItem(msg) + ")"
+	case actionIDDescriptor.FullName():
+		if text, ok := formatActionID(msg.Interface().(*proto.ActionID)); ok {
+			return text
+		}
+	}
+	return "{" + strings.Join(formatParams(msg, nil), ",") + "}"
+}
+
+func formatActionID(actionID *proto.ActionID) (string, bool) {
+	if actionID.Tag != 0 {
+		return "", false
+	}
+	switch id := actionID.RawId.(type) {
+	case *proto.ActionID_SpellId:
+		return "spell:" + strconv.Itoa(int(id.SpellId)), true
+	case *proto.ActionID_ItemId:
+		return "item:" + strconv.Itoa(int(id.ItemId)), true
+	case *proto.ActionID_OtherId:
+		return "other:" + id.OtherId.String(), true
+	}
+	return "", false
+}
+
+// Returns the expression for a value, along with its precedence so the
+// caller can decide whether it needs parentheses.
+func formatValue(msg protoreflect.Message) (string, int) {
+	kindField := msg.WhichOneof(valueOneof)
+	if kindField == nil {
+		return noneKind + "()", precPrimary
+	}
+
+	switch value := msg.Interface().(*proto.APLValue).Value.(type) {
+	case *proto.APLValue_Const:
+		if value.Const.Val == "true" || value.Const.Val == "false" || bareConstRegex.MatchString(value.Const.Val) {
+			return value.Const.Val, precPrimary
+		}
+		return strconv.Quote(value.Const.Val), precPrimary
+	case *proto.APLValue_And:
+		if len(value.And.Vals) >= 2 && !hasNilValue(value.And.Vals...) {
+			return formatNary(value.And.Vals, " && ", precAnd), precAnd
+		}
+	case *proto.APLValue_Or:
+		if len(value.Or.Vals) >= 2 && !hasNilValue(value.Or.Vals...) {
+			return formatNary(value.Or.Vals, " || ", precOr), precOr
+		}
+	case *proto.APLValue_Not:
+		if value.Not.Val != nil {
+			return "!" + formatOperand(value.Not.Val, precUnary, false), precUnary
+		}
+	case *proto.APLValue_Cmp:
+		if op, ok := compareOperators[value.Cmp.Op]; ok && !hasNilValue(value.Cmp.Lhs, value.Cmp.Rhs) {
+			return formatOperand(value.Cmp.Lhs, precCompare, true) + " " + op + " " + formatOperand(value.Cmp.Rhs, precCompare, true), precCompare
+		}
+	case *proto.APLValue_Math:
+		if op, ok := mathOperators[value.Math.Op]; ok && !hasNilValue(value.Math.Lhs, value.Math.Rhs) {
+			prec := precSum
+			if value.Math.Op == proto.APLValueMath_OpMul || value.Math.Op == proto.APLValueMath_OpDiv {
+				prec = precProduct
+			}
+			return formatOperand(value.Math.Lhs, prec, false) + " " + op + " " + formatOperand(value.Math.Rhs, prec, true), prec
+		}
+	}
+
+	// Anything without operator syntax, or which that syntax can't express,
+	// is written as a call with the value fields as arguments.
+	params := formatParams(msg.Get(kindField).Message(), nil)
+	return string(kindField.Name()) + "(" + strings.Join(params, ", ") + ")", precPrimary
+}
+
+func formatNary(vals []*proto.APLValue, separator string, prec int) string {
+	parts := make([]string, len(vals))
+	for i, val := range vals {
+		parts[i] = formatOperand(val, prec, true)
+	}
+	return strings.Join(parts, separator)
+}
+
+// Formats an operand of an operator with precedence prec. Operands binding
+// equally tight are parenthesized when strict, to keep the tree shape of
+// right-hand sides and of nested and/or lists.
+func formatOperand(val *proto.APLValue, prec int, strict bool) string {
+	text, operandPrec := formatValue(val.ProtoReflect())
+	if operandPrec < prec || (strict && operandPrec == prec) {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+func hasNilValue(vals ...*proto.APLValue) bool {
+	for _, val := range vals {
+		if val == nil {
+			return true
+		}
+	}
+	return false
+}
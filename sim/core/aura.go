@@ -206,6 +206,9 @@ func (aura *Aura) SetStacks(sim *Simulation, newStacks int32) {
 		aura.Unit.Log(sim, "%s stacks: %d --> %d", aura.ActionID, oldStacks, newStacks)
 	}
 	aura.stacks = newStacks
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+		sim.CombatLog.AddAura(sim, aura, proto.CombatLogEventType_CombatLogEventTypeAuraStacksChanged, oldStacks)
+	}
 	if aura.OnStacksChange != nil {
 		aura.OnStacksChange(aura, sim, oldStacks, newStacks)
 	}
@@ -649,6 +652,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 		if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 			aura.Unit.Log(sim, "Aura refreshed: %s", aura.ActionID)
 		}
+		if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+			sim.CombatLog.AddAura(sim, aura, proto.CombatLogEventType_CombatLogEventTypeAuraRefreshed, aura.stacks)
+		}
 		aura.Refresh(sim)
 		return
 	}
@@ -739,6 +745,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 		aura.Unit.Log(sim, "Aura gained: %s", aura.ActionID)
 	}
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+		sim.CombatLog.AddAura(sim, aura, proto.CombatLogEventType_CombatLogEventTypeAuraGained, 0)
+	}
 
 	// don't invoke possible callbacks until the internal state is consistent
 	if aura.OnGain != nil {
@@ -770,6 +779,12 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 		}
 		sim.CurrentTime = oldTime
 	}
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+		oldTime := sim.CurrentTime
+		sim.CurrentTime = min(sim.CurrentTime, aura.expires)
+		sim.CombatLog.AddAura(sim, aura, proto.CombatLogEventType_CombatLogEventTypeAuraFaded, aura.stacks)
+		sim.CurrentTime = oldTime
+	}
 
 	aura.expires = 0
 	aura.fadeTime = sim.CurrentTime
@@ -633,6 +633,7 @@ func (unit *Unit) NewDamageAbsorptionAura(config AbsorptionAuraConfig) *DamageAb
 		if aura.Aura.IsActive() && (result.Damage > 0) && extraSpellCheck(sim, spell, result, isPeriodic) {
 			absorbedDamage := min(aura.ShieldStrength, result.Damage*config.DamageMultiplier)
 			result.Damage -= absorbedDamage
+			result.Absorbed += absorbedDamage
 			aura.ShieldStrength -= absorbedDamage
 
 			if sim.Log != nil {
@@ -23,12 +23,18 @@ const (
 // raidSimRunner runs a standard raid simulation.
 type raidSimRunner func(*proto.RaidSimRequest, chan *proto.ProgressMetrics, bool, simsignals.Signals) *proto.RaidSimResult
 
+// RemoteRaidSimRunner runs a raid simulation outside of this process, e.g. on
+// other machines.
+type RemoteRaidSimRunner func(*proto.RaidSimRequest, simsignals.Signals) *proto.RaidSimResult
+
 // bulkSimRunner runs a bulk simulation.
 type bulkSimRunner struct {
 	// SingleRaidSimRunner used to run one simulation of the bulk.
 	SingleRaidSimRunner raidSimRunner
 	// Request used for this bulk simulation.
 	Request *proto.BulkSimRequest
+	// Maximum number of simulations running at once, defaults to the number of CPUs.
+	Concurrency int
 }
 
 func BulkSim(signals simsignals.Signals, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics) *proto.BulkSimResult {
@@ -37,7 +43,31 @@ func BulkSim(signals simsignals.Signals, request *proto.BulkSimRequest, progress
 		Request:             request,
 	}
 
-	result := bulk.Run(signals, progress)
+	return bulk.runAndReport(signals, progress)
+}
+
+// BulkSimRemote is BulkSim with every simulation of the bulk run by runner,
+// with up to concurrency of them at once.
+func BulkSimRemote(signals simsignals.Signals, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, runner RemoteRaidSimRunner, concurrency int) *proto.BulkSimResult {
+	bulk := &bulkSimRunner{
+		SingleRaidSimRunner: func(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, _ bool, signals simsignals.Signals) *proto.RaidSimResult {
+			result := runner(rsr, signals)
+			progress <- &proto.ProgressMetrics{
+				CompletedIterations: result.IterationsDone,
+				FinalRaidResult:     result,
+			}
+			close(progress)
+			return result
+		},
+		Request:     request,
+		Concurrency: concurrency,
+	}
+
+	return bulk.runAndReport(signals, progress)
+}
+
+func (b *bulkSimRunner) runAndReport(signals simsignals.Signals, progress chan *proto.ProgressMetrics) *proto.BulkSimResult {
+	result := b.Run(signals, progress)
 
 	if progress != nil {
 		progress <- &proto.ProgressMetrics{
@@ -241,9 +271,9 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 }
 
 func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos []singleBulkSim, iterations int32, progress chan *proto.ProgressMetrics) ([]*itemSubstitutionSimResult, *itemSubstitutionSimResult, *proto.ErrorOutcome) {
-	concurrency := runtime.NumCPU() + 1
+	concurrency := b.Concurrency
 	if concurrency <= 0 {
-		concurrency = 2
+		concurrency = runtime.NumCPU() + 1
 	}
 
 	tickets := make(chan struct{}, concurrency)
@@ -304,9 +334,12 @@ func (b *bulkSimRunner) getRankedResults(signals simsignals.Signals, validCombos
 			go func(sub singleBulkSim) {
 				// overwrite the requests iterations with the input for this function.
 				sub.req.SimOptions.Iterations = int32(iterations)
+				release := acquireWorker()
+				result := b.SingleRaidSimRunner(sub.req, singleSimProgress, false, signals)
+				release()
 				results <- &itemSubstitutionSimResult{
 					Request:      sub.req,
-					Result:       b.SingleRaidSimRunner(sub.req, singleSimProgress, false, signals),
+					Result:       result,
 					Substitution: sub.eq,
 					ChangeLog:    sub.cl,
 				}
@@ -162,7 +162,11 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 				spell.SpellMetrics[target.UnitIndex].TotalCastTime += effectiveTime
 			}
 
-			spell.Unit.SetGCDTimer(sim, max(sim.CurrentTime+effectiveTime, spell.Unit.NextGCDAt()))
+			gcdReadyAt := max(sim.CurrentTime+effectiveTime, spell.Unit.NextGCDAt())
+			spell.Unit.SetGCDTimer(sim, gcdReadyAt)
+			if delay := spell.Unit.executionDelay(sim); delay > 0 {
+				spell.Unit.SetRotationTimer(sim, gcdReadyAt+delay)
+			}
 		}
 
 		if (spell.Flags&SpellFlagCanCastWhileMoving == 0) && (spell.CurCast.CastTime > 0) && spell.Unit.Moving {
@@ -175,6 +179,9 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 				spell.Unit.Log(sim, "Casting %s (Cost = %0.03f, Cast Time = %s, Effective Time = %s)",
 					spell.ActionID, max(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
 			}
+			if sim.CombatLog != nil {
+				sim.CombatLog.AddCastStart(sim, spell, target)
+			}
 
 			spell.Unit.Hardcast = Hardcast{
 				Expires:  sim.CurrentTime + spell.CurCast.CastTime,
@@ -183,6 +190,9 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 					if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
 						spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 					}
+					if sim.CombatLog != nil {
+						sim.CombatLog.AddCastFinish(sim, spell, target)
+					}
 
 					if spell.Cost != nil {
 						spell.Cost.SpendCost(sim, spell)
@@ -219,6 +229,10 @@ func (spell *Spell) makeCastFunc(config CastConfig) CastSuccessFunc {
 				spell.ActionID, max(0, spell.CurCast.Cost), spell.CurCast.CastTime, spell.CurCast.EffectiveTime())
 			spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 		}
+		if sim.CombatLog != nil {
+			sim.CombatLog.AddCastStart(sim, spell, target)
+			sim.CombatLog.AddCastFinish(sim, spell, target)
+		}
 
 		if spell.Cost != nil {
 			spell.Cost.SpendCost(sim, spell)
@@ -299,6 +313,10 @@ func (spell *Spell) makeCastFuncSimple() CastSuccessFunc {
 				spell.ActionID, 0.0, "0s", "0s")
 			spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 		}
+		if sim.CombatLog != nil {
+			sim.CombatLog.AddCastStart(sim, spell, target)
+			sim.CombatLog.AddCastFinish(sim, spell, target)
+		}
 
 		if spell.MaxCharges > 0 {
 			spell.ConsumeCharge(sim)
@@ -329,6 +347,10 @@ func (spell *Spell) makeCastFuncAutosOrProcs() CastSuccessFunc {
 				spell.ActionID, 0.0, "0s", "0s")
 			spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 		}
+		if sim.CombatLog != nil {
+			sim.CombatLog.AddCastStart(sim, spell, target)
+			sim.CombatLog.AddCastFinish(sim, spell, target)
+		}
 
 		spell.applyEffects(sim, target)
 
@@ -349,6 +371,10 @@ func (spell *Spell) Proc(sim *Simulation, target *Unit) {
 			spell.ActionID, 0.0, "0s", "0s")
 		spell.Unit.Log(sim, "Completed cast %s", spell.ActionID)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.AddCastStart(sim, spell, target)
+		sim.CombatLog.AddCastFinish(sim, spell, target)
+	}
 
 	spell.applyEffects(sim, target)
 
@@ -104,6 +104,8 @@ func NewCharacter(party *Party, partyIndex int, player *proto.Player) Character
 			ReactionTime:            time.Duration(max(player.ReactionTimeMs, 10)) * time.Millisecond,
 			ChannelClipDelay:        max(0, time.Duration(player.ChannelClipDelayMs)*time.Millisecond),
 			StartDistanceFromTarget: player.DistanceFromTarget,
+			StartPosition:           Vector2FromProto(player.Position),
+			hasStartPosition:        player.Position != nil,
 		},
 
 		Name:  player.Name,
@@ -125,6 +127,9 @@ func NewCharacter(party *Party, partyIndex int, player *proto.Player) Character
 	}
 	character.GCD = character.NewTimer()
 	character.RotationTimer = character.NewTimer()
+	if player.ExecutionModel != nil {
+		character.executionModel = newExecutionModel(player.ExecutionModel, character.ReactionTime)
+	}
 
 	character.Label = fmt.Sprintf("%s (#%d)", character.Name, character.Index+1)
 
@@ -0,0 +1,156 @@
+package core
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// CombatLog records structured combat events for external consumers, as an
+// alternative to scraping the text debug log. Like sim.Log, it is nil when
+// disabled so every recording site must check for nil first.
+type CombatLog struct {
+	Events []*proto.CombatLogEvent
+
+	iteration int32
+
+	// Indexed by UnitIndex. Nil when all units are accepted.
+	unitFilter []bool
+	// Indexed by event type. Nil when all types are accepted.
+	typeFilter []bool
+}
+
+func newCombatLog(env *Environment, filter *proto.CombatLogFilter) *CombatLog {
+	cl := &CombatLog{}
+	if filter == nil {
+		return cl
+	}
+
+	if len(filter.Units) > 0 {
+		cl.unitFilter = make([]bool, len(env.AllUnits))
+		for _, ref := range filter.Units {
+			switch ref.Type {
+			case proto.UnitReference_AllPlayers:
+				for _, unit := range env.Raid.AllPlayerUnits {
+					cl.unitFilter[unit.UnitIndex] = true
+				}
+			case proto.UnitReference_AllTargets:
+				for _, unit := range env.Encounter.AllTargetUnits {
+					cl.unitFilter[unit.UnitIndex] = true
+				}
+			default:
+				if unit := env.GetUnit(ref, nil); unit != nil {
+					cl.unitFilter[unit.UnitIndex] = true
+				}
+			}
+		}
+	}
+
+	if len(filter.Types) > 0 {
+		cl.typeFilter = make([]bool, len(proto.CombatLogEventType_name))
+		for _, eventType := range filter.Types {
+			if int(eventType) < len(cl.typeFilter) {
+				cl.typeFilter[eventType] = true
+			}
+		}
+	}
+
+	return cl
+}
+
+func (cl *CombatLog) accepts(eventType proto.CombatLogEventType, source *Unit, target *Unit) bool {
+	if cl.typeFilter != nil && !cl.typeFilter[eventType] {
+		return false
+	}
+	if cl.unitFilter != nil {
+		return (source != nil && cl.unitFilter[source.UnitIndex]) || (target != nil && cl.unitFilter[target.UnitIndex])
+	}
+	return true
+}
+
+// Creates a new event with the common fields filled in, or returns nil if the
+// event is rejected by the filter.
+func (cl *CombatLog) newEvent(sim *Simulation, eventType proto.CombatLogEventType, source *Unit, target *Unit) *proto.CombatLogEvent {
+	if !cl.accepts(eventType, source, target) {
+		return nil
+	}
+
+	event := &proto.CombatLogEvent{
+		Iteration:   cl.iteration,
+		Timestamp:   sim.CurrentTime.Seconds(),
+		Type:        eventType,
+		SourceIndex: -1,
+		TargetIndex: -1,
+	}
+	if source != nil {
+		event.SourceIndex = source.UnitIndex
+	}
+	if target != nil {
+		event.TargetIndex = target.UnitIndex
+	}
+	cl.Events = append(cl.Events, event)
+	return event
+}
+
+func (cl *CombatLog) AddCastStart(sim *Simulation, spell *Spell, target *Unit) {
+	cl.addCast(sim, proto.CombatLogEventType_CombatLogEventTypeCastStart, spell, target)
+}
+
+func (cl *CombatLog) AddCastFinish(sim *Simulation, spell *Spell, target *Unit) {
+	cl.addCast(sim, proto.CombatLogEventType_CombatLogEventTypeCastFinish, spell, target)
+}
+
+func (cl *CombatLog) addCast(sim *Simulation, eventType proto.CombatLogEventType, spell *Spell, target *Unit) {
+	if spell.Flags.Matches(SpellFlagNoLogs) {
+		return
+	}
+	if event := cl.newEvent(sim, eventType, spell.Unit, target); event != nil {
+		event.ActionId = spell.ActionID.ToProto()
+		event.SpellSchoolMask = int32(spell.SpellSchool)
+	}
+}
+
+func (cl *CombatLog) AddSpellResult(sim *Simulation, spell *Spell, result *SpellResult, isPeriodic bool, isHealing bool) {
+	eventType := proto.CombatLogEventType_CombatLogEventTypeDamage
+	if isHealing {
+		eventType = proto.CombatLogEventType_CombatLogEventTypeHealing
+	}
+	if event := cl.newEvent(sim, eventType, spell.Unit, result.Target); event != nil {
+		event.ActionId = spell.ActionID.ToProto()
+		event.Amount = result.Damage
+		event.Outcome = result.Outcome.String()
+		event.IsPeriodic = isPeriodic
+		event.Threat = result.Threat
+		event.SpellSchoolMask = int32(spell.SpellSchool)
+	}
+}
+
+func (cl *CombatLog) AddAura(sim *Simulation, aura *Aura, eventType proto.CombatLogEventType, previousStacks int32) {
+	if event := cl.newEvent(sim, eventType, aura.Unit, nil); event != nil {
+		event.ActionId = aura.ActionID.ToProto()
+		event.Label = aura.Label
+		event.Stacks = aura.stacks
+		event.PreviousStacks = previousStacks
+	}
+}
+
+func (cl *CombatLog) AddResource(sim *Simulation, unit *Unit, metrics *ResourceMetrics, amount float64, newValue float64) {
+	eventType := proto.CombatLogEventType_CombatLogEventTypeResourceGain
+	if amount < 0 {
+		eventType = proto.CombatLogEventType_CombatLogEventTypeResourceSpend
+	}
+	if event := cl.newEvent(sim, eventType, unit, nil); event != nil {
+		event.ActionId = metrics.ActionID.ToProto()
+		event.Amount = amount
+		event.ResourceType = metrics.Type
+		event.ResourceValue = newValue
+	}
+}
+
+func (cl *CombatLog) AddPetSummon(sim *Simulation, pet *Pet) {
+	if event := cl.newEvent(sim, proto.CombatLogEventType_CombatLogEventTypePetSummon, &pet.Owner.Unit, &pet.Unit); event != nil {
+		event.Label = pet.Label
+	}
+}
+
+func (cl *CombatLog) AddTargetChange(sim *Simulation, unit *Unit, newTarget *Unit) {
+	cl.newEvent(sim, proto.CombatLogEventType_CombatLogEventTypeTargetChange, unit, newTarget)
+}
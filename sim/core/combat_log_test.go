@@ -0,0 +1,42 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestCombatLogFilter(t *testing.T) {
+	player := &Unit{UnitIndex: 0}
+	target := &Unit{UnitIndex: 1}
+	other := &Unit{UnitIndex: 2}
+
+	cl := &CombatLog{
+		unitFilter: []bool{true, false, false},
+		typeFilter: make([]bool, len(proto.CombatLogEventType_name)),
+	}
+	cl.typeFilter[proto.CombatLogEventType_CombatLogEventTypeDamage] = true
+
+	sim := &Simulation{CurrentTime: time.Second * 3}
+
+	if cl.newEvent(sim, proto.CombatLogEventType_CombatLogEventTypeDamage, player, target) == nil {
+		t.Fatalf("Expected damage from filtered unit to be recorded")
+	}
+	if cl.newEvent(sim, proto.CombatLogEventType_CombatLogEventTypeDamage, target, player) == nil {
+		t.Fatalf("Expected damage taken by filtered unit to be recorded")
+	}
+	if cl.newEvent(sim, proto.CombatLogEventType_CombatLogEventTypeDamage, target, other) != nil {
+		t.Fatalf("Expected damage between unfiltered units to be dropped")
+	}
+	if cl.newEvent(sim, proto.CombatLogEventType_CombatLogEventTypeHealing, player, player) != nil {
+		t.Fatalf("Expected healing to be dropped by the type filter")
+	}
+
+	if len(cl.Events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(cl.Events))
+	}
+	if event := cl.Events[0]; event.Timestamp != 3 || event.SourceIndex != 0 || event.TargetIndex != 1 {
+		t.Fatalf("Unexpected event contents: %v", event)
+	}
+}
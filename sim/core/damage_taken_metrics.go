@@ -0,0 +1,214 @@
+package core
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Number of worst damage windows kept over all iterations.
+const numWorstDamageSpikes = 5
+
+// Window width used when the healing model has no burst window.
+const defaultDamageSpikeWindow = 6
+
+// Damage taken before absorbs and Stagger, split by what mitigated it.
+type damageMitigation struct {
+	tracked bool
+
+	raw            float64
+	armorMitigated float64
+	avoided        float64
+	blocked        float64
+}
+
+func (result *SpellResult) setMitigation(afterAttackMods float64, afterArmor float64, afterTargetMods float64, afterOutcome float64) {
+	result.mitigation = damageMitigation{
+		tracked:        true,
+		raw:            afterAttackMods,
+		armorMitigated: afterAttackMods - afterArmor,
+	}
+
+	if !result.Landed() {
+		result.mitigation.avoided = afterTargetMods
+	} else if result.DidBlock() {
+		result.mitigation.blocked = max(0, afterTargetMods-afterOutcome)
+	}
+}
+
+type damageTakenKey struct {
+	ActionID  ActionID
+	UnitIndex int32
+}
+
+type DamageTakenMetrics struct {
+	Hits   int32
+	Avoids int32
+	Blocks int32
+
+	Raw            float64
+	Avoided        float64
+	Blocked        float64
+	ArmorMitigated float64
+	Reduced        float64
+	Absorbed       float64
+	Staggered      float64
+	Taken          float64
+}
+
+func (dtm *DamageTakenMetrics) ToProto(key damageTakenKey) *proto.DamageTakenMetrics {
+	return &proto.DamageTakenMetrics{
+		Id:        key.ActionID.ToProto(),
+		UnitIndex: key.UnitIndex,
+
+		Hits:   dtm.Hits,
+		Avoids: dtm.Avoids,
+		Blocks: dtm.Blocks,
+
+		Raw:            dtm.Raw,
+		Avoided:        dtm.Avoided,
+		Blocked:        dtm.Blocked,
+		ArmorMitigated: dtm.ArmorMitigated,
+		Reduced:        dtm.Reduced,
+		Absorbed:       dtm.Absorbed,
+		Staggered:      dtm.Staggered,
+		Taken:          dtm.Taken,
+	}
+}
+
+func (unitMetrics *UnitMetrics) addDamageTaken(spell *Spell, result *SpellResult) {
+	key := damageTakenKey{ActionID: spell.ActionID, UnitIndex: spell.Unit.UnitIndex}
+	dtm, ok := unitMetrics.damageTaken[key]
+	if !ok {
+		dtm = &DamageTakenMetrics{}
+		unitMetrics.damageTaken[key] = dtm
+	}
+
+	mitigation := &result.mitigation
+	if !result.Landed() {
+		dtm.Avoids++
+	} else {
+		dtm.Hits++
+		if result.DidBlock() {
+			dtm.Blocks++
+		}
+	}
+
+	dtm.Raw += mitigation.raw
+	dtm.Avoided += mitigation.avoided
+	dtm.Blocked += mitigation.blocked
+	dtm.ArmorMitigated += mitigation.armorMitigated
+	dtm.Absorbed += result.Absorbed
+	dtm.Staggered += result.Staggered
+	dtm.Taken += result.Damage
+
+	// Everything not accounted for above, so that the parts add up to the raw damage.
+	dtm.Reduced += mitigation.raw - mitigation.armorMitigated - mitigation.avoided - mitigation.blocked - result.Absorbed - result.Staggered - result.Damage
+}
+
+type damageSpike struct {
+	seed      int64
+	startTime time.Duration
+	damage    float64
+	percent   float64
+	events    []tmiListItem
+}
+
+func (spike *damageSpike) ToProto() *proto.DamageSpike {
+	events := make([]*proto.DamageSpikeEvent, len(spike.events))
+	for i, event := range spike.events {
+		events[i] = &proto.DamageSpikeEvent{
+			Timestamp: event.Timestamp.Seconds(),
+			Id:        event.ActionID.ToProto(),
+			UnitIndex: event.UnitIndex,
+			Damage:    event.Damage,
+		}
+	}
+
+	return &proto.DamageSpike{
+		Seed:          spike.seed,
+		StartTime:     spike.startTime.Seconds(),
+		Damage:        spike.damage,
+		DamagePercent: spike.percent,
+		Events:        events,
+	}
+}
+
+func (unitMetrics *UnitMetrics) damageSpikeWindow() time.Duration {
+	if unitMetrics.tmiBin > 0 {
+		return time.Duration(unitMetrics.tmiBin) * time.Second
+	}
+	return defaultDamageSpikeWindow * time.Second
+}
+
+// Finds the window with the most damage taken in this iteration, and keeps it
+// if it is among the worst of all iterations so far.
+func (unitMetrics *UnitMetrics) doneDamageSpikeIteration(sim *Simulation) {
+	window := unitMetrics.damageSpikeWindow()
+	events := unitMetrics.tmiList
+
+	var worstStart, worstEnd int
+	var worstPercent, percent float64
+	end := 0
+	for start := range events {
+		for ; end < len(events) && events[end].Timestamp < events[start].Timestamp+window; end++ {
+			percent += events[end].WeightedDamage
+		}
+		if percent > worstPercent {
+			worstStart, worstEnd, worstPercent = start, end, percent
+		}
+		percent -= events[start].WeightedDamage
+	}
+
+	unitMetrics.largestDamageWindow.Total = worstPercent * 100
+
+	// Hack because of the way DistributionMetrics does its calculations.
+	unitMetrics.largestDamageWindow.Total *= sim.Duration.Seconds()
+
+	if worstPercent == 0 {
+		return
+	}
+
+	numSpikes := len(unitMetrics.worstDamageSpikes)
+	if numSpikes >= numWorstDamageSpikes && unitMetrics.worstDamageSpikes[numSpikes-1].percent >= worstPercent*100 {
+		return
+	}
+
+	spike := &damageSpike{
+		seed:      sim.rand.GetSeed(),
+		startTime: events[worstStart].Timestamp,
+		percent:   worstPercent * 100,
+		events:    slices.Clone(events[worstStart:worstEnd]),
+	}
+	for _, event := range spike.events {
+		spike.damage += event.Damage
+	}
+
+	unitMetrics.worstDamageSpikes = append(unitMetrics.worstDamageSpikes, spike)
+	slices.SortStableFunc(unitMetrics.worstDamageSpikes, func(a, b *damageSpike) int {
+		return cmp.Compare(b.percent, a.percent)
+	})
+	if len(unitMetrics.worstDamageSpikes) > numWorstDamageSpikes {
+		unitMetrics.worstDamageSpikes = unitMetrics.worstDamageSpikes[:numWorstDamageSpikes]
+	}
+}
+
+func (unitMetrics *UnitMetrics) damageTakenToProto() ([]*proto.DamageTakenMetrics, *proto.DamageSpikeMetrics) {
+	damageTaken := make([]*proto.DamageTakenMetrics, 0, len(unitMetrics.damageTaken))
+	for key, dtm := range unitMetrics.damageTaken {
+		damageTaken = append(damageTaken, dtm.ToProto(key))
+	}
+	slices.SortFunc(damageTaken, func(a, b *proto.DamageTakenMetrics) int {
+		return cmp.Compare(b.Raw, a.Raw)
+	})
+
+	damageSpikes := &proto.DamageSpikeMetrics{
+		WindowSeconds: unitMetrics.damageSpikeWindow().Seconds(),
+		LargestWindow: unitMetrics.largestDamageWindow.ToProto(),
+		WorstWindows:  MapSlice(unitMetrics.worstDamageSpikes, (*damageSpike).ToProto),
+	}
+
+	return damageTaken, damageSpikes
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDamageTakenAccounting(t *testing.T) {
+	metrics := NewUnitMetrics()
+	spell := &Spell{ActionID: ActionID{SpellID: 1}, Unit: &Unit{UnitIndex: 3}}
+
+	// 300 mitigated by armor, 100 by target modifiers, then 200 absorbed
+	// and 100 staggered.
+	hit := &SpellResult{Outcome: OutcomeHit, Damage: 300, Absorbed: 200, Staggered: 100}
+	hit.setMitigation(1000, 700, 600, 600)
+	metrics.addDamageTaken(spell, hit)
+
+	// 200 blocked, then 100 removed by a post outcome modifier.
+	block := &SpellResult{Outcome: OutcomeHit | OutcomeBlock, Damage: 400}
+	block.setMitigation(1000, 700, 700, 500)
+	metrics.addDamageTaken(spell, block)
+
+	dodge := &SpellResult{Outcome: OutcomeDodge}
+	dodge.setMitigation(1000, 700, 700, 0)
+	metrics.addDamageTaken(spell, dodge)
+
+	dtm := metrics.damageTaken[damageTakenKey{ActionID: spell.ActionID, UnitIndex: 3}]
+	if dtm == nil {
+		t.Fatalf("Expected damage taken to be tracked by source")
+	}
+	if dtm.Hits != 2 || dtm.Blocks != 1 || dtm.Avoids != 1 {
+		t.Fatalf("Expected 2 hits, 1 block and 1 avoid, got %d, %d and %d", dtm.Hits, dtm.Blocks, dtm.Avoids)
+	}
+
+	expected := DamageTakenMetrics{
+		Hits:   2,
+		Avoids: 1,
+		Blocks: 1,
+
+		Raw:            3000,
+		Avoided:        700,
+		Blocked:        200,
+		ArmorMitigated: 900,
+		Reduced:        200,
+		Absorbed:       200,
+		Staggered:      100,
+		Taken:          700,
+	}
+	if *dtm != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, *dtm)
+	}
+
+	parts := dtm.Avoided + dtm.Blocked + dtm.ArmorMitigated + dtm.Reduced + dtm.Absorbed + dtm.Staggered + dtm.Taken
+	if parts != dtm.Raw {
+		t.Fatalf("Expected the parts to add up to the raw damage %f, got %f", dtm.Raw, parts)
+	}
+
+	// Sources are listed with the most raw damage first.
+	other := &Spell{ActionID: ActionID{SpellID: 2}, Unit: &Unit{UnitIndex: 3}}
+	small := &SpellResult{Outcome: OutcomeHit, Damage: 10}
+	small.setMitigation(10, 10, 10, 10)
+	metrics.addDamageTaken(other, small)
+
+	damageTaken, _ := metrics.damageTakenToProto()
+	if len(damageTaken) != 2 || damageTaken[0].Raw != 3000 || damageTaken[1].Raw != 10 {
+		t.Fatalf("Expected 2 sources sorted by raw damage, got %v", damageTaken)
+	}
+}
+
+func TestDamageSpikeWindows(t *testing.T) {
+	sim := SetupFakeSim()
+	metrics := NewUnitMetrics()
+	metrics.tmiBin = 6
+
+	event := func(seconds float64, percent float64) tmiListItem {
+		return tmiListItem{
+			Timestamp:      DurationFromSeconds(seconds),
+			WeightedDamage: percent,
+			Damage:         percent * 1000,
+		}
+	}
+
+	// The worst 6s window starts at 13s, and ends just before 19s.
+	metrics.tmiList = []tmiListItem{
+		event(0, 0.1),
+		event(10, 0.2),
+		event(13, 0.3),
+		event(15.9, 0.1),
+		event(16, 0.4),
+		event(19, 0.3),
+	}
+	metrics.doneDamageSpikeIteration(sim)
+
+	if total := metrics.largestDamageWindow.Total / sim.Duration.Seconds(); !WithinToleranceFloat64(80, total, 0.0001) {
+		t.Fatalf("Expected the largest window to be 80%% of max health, got %f", total)
+	}
+	spike := metrics.worstDamageSpikes[0]
+	if spike.startTime != time.Second*13 || len(spike.events) != 3 || !WithinToleranceFloat64(800, spike.damage, 0.0001) {
+		t.Fatalf("Expected a spike of 800 damage from 3 events at 13s, got %0.1f from %d events at %s", spike.damage, len(spike.events), spike.startTime)
+	}
+
+	// Only the worst windows over all iterations are kept.
+	for i := 0; i <= 6; i++ {
+		metrics.tmiList = nil
+		if i > 0 {
+			metrics.tmiList = []tmiListItem{event(1, 0.1*float64(i))}
+		}
+		metrics.doneDamageSpikeIteration(sim)
+	}
+
+	if len(metrics.worstDamageSpikes) != numWorstDamageSpikes {
+		t.Fatalf("Expected %d damage spikes, got %d", numWorstDamageSpikes, len(metrics.worstDamageSpikes))
+	}
+	for i, expectedPercent := range []float64{80, 60, 50, 40, 30} {
+		if percent := metrics.worstDamageSpikes[i].percent; !WithinToleranceFloat64(expectedPercent, percent, 0.0001) {
+			t.Fatalf("Expected spike %d to be %f%%, got %f%%", i+1, expectedPercent, percent)
+		}
+	}
+}
@@ -142,6 +142,8 @@ func (env *Environment) initialize(raidProto *proto.Raid, encounterProto *proto.
 	}
 
 	raidStats := env.Raid.applyCharacterEffects(raidProto)
+	env.applyRaidDamageProfile(encounterProto.RaidDamage)
+	env.applyAggroModel(encounterProto.Aggro)
 
 	for _, party := range env.Raid.Parties {
 		for _, playerOrPet := range party.PlayersAndPets {
@@ -279,6 +281,7 @@ func (env *Environment) reset(sim *Simulation) {
 
 	// Reset primary targets damage taken for tracking health fights.
 	env.Encounter.DamageTaken = 0
+	env.Encounter.resetActiveTargets()
 
 	// Targets need to be reset before the raid, so that players can check for
 	// the presence of permanent target auras in their Reset handlers.
@@ -0,0 +1,198 @@
+package core
+
+import (
+	"math"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Rough values for players of each skill level, to compare against the
+// default frame perfect execution.
+var executionModelPresets = map[proto.ExecutionModel_SkillLevel]*proto.ExecutionModel{
+	proto.ExecutionModel_SkillLevelExpert: {
+		LatencyMeanMs:       30,
+		LatencyStdevMs:      10,
+		ReactionTimeMeanMs:  250,
+		ReactionTimeStdevMs: 50,
+		GcdGapChance:        0.01,
+		GcdGapMeanMs:        300,
+		MissedProcChance:    0.01,
+		CooldownDelayChance: 0.05,
+		CooldownDelayMeanMs: 2000,
+		MissedQueueChance:   0.05,
+	},
+	proto.ExecutionModel_SkillLevelGood: {
+		LatencyMeanMs:       60,
+		LatencyStdevMs:      20,
+		ReactionTimeMeanMs:  350,
+		ReactionTimeStdevMs: 80,
+		GcdGapChance:        0.03,
+		GcdGapMeanMs:        500,
+		MissedProcChance:    0.03,
+		CooldownDelayChance: 0.15,
+		CooldownDelayMeanMs: 4000,
+		MissedQueueChance:   0.15,
+	},
+	proto.ExecutionModel_SkillLevelAverage: {
+		LatencyMeanMs:       100,
+		LatencyStdevMs:      40,
+		ReactionTimeMeanMs:  500,
+		ReactionTimeStdevMs: 150,
+		GcdGapChance:        0.06,
+		GcdGapMeanMs:        800,
+		MissedProcChance:    0.08,
+		CooldownDelayChance: 0.3,
+		CooldownDelayMeanMs: 8000,
+		MissedQueueChance:   0.35,
+	},
+	proto.ExecutionModel_SkillLevelCasual: {
+		LatencyMeanMs:       150,
+		LatencyStdevMs:      60,
+		ReactionTimeMeanMs:  700,
+		ReactionTimeStdevMs: 250,
+		GcdGapChance:        0.12,
+		GcdGapMeanMs:        1200,
+		MissedProcChance:    0.15,
+		CooldownDelayChance: 0.5,
+		CooldownDelayMeanMs: 15000,
+		MissedQueueChance:   0.6,
+	},
+}
+
+const executionModelLabel = "Execution Noise"
+
+type procReaction struct {
+	startedAt time.Duration
+	delay     time.Duration
+}
+
+// Samples the timing of a human player. Units without one execute perfectly,
+// and don't use any random numbers for it.
+type executionModel struct {
+	config           *proto.ExecutionModel
+	baseReactionTime time.Duration
+
+	// Reaction to the current activation of each aura checked so far.
+	procReactions map[*Aura]procReaction
+}
+
+func newExecutionModel(config *proto.ExecutionModel, baseReactionTime time.Duration) *executionModel {
+	if preset, ok := executionModelPresets[config.SkillLevel]; ok {
+		config = preset
+	} else {
+		config = googleProto.Clone(config).(*proto.ExecutionModel)
+	}
+	if config.ReactionTimeMeanMs <= 0 {
+		config.ReactionTimeMeanMs = float64(baseReactionTime.Milliseconds())
+	}
+
+	return &executionModel{
+		config:           config,
+		baseReactionTime: baseReactionTime,
+		procReactions:    make(map[*Aura]procReaction),
+	}
+}
+
+func (model *executionModel) reset(unit *Unit) {
+	unit.ReactionTime = model.baseReactionTime
+	clear(model.procReactions)
+}
+
+func durationFromMs(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// Log-normal, so that samples are positive with a long tail of slow ones.
+func (model *executionModel) sampleLogNormal(sim *Simulation, meanMs float64, stdevMs float64) time.Duration {
+	if meanMs <= 0 {
+		return 0
+	}
+	if stdevMs <= 0 {
+		return durationFromMs(meanMs)
+	}
+
+	sigmaSq := math.Log1p(stdevMs * stdevMs / (meanMs * meanMs))
+	mu := math.Log(meanMs) - sigmaSq/2
+	return durationFromMs(math.Exp(mu + math.Sqrt(sigmaSq)*sim.RandomNormFloat(executionModelLabel)))
+}
+
+func (model *executionModel) sampleExp(sim *Simulation, chance float64, meanMs float64) time.Duration {
+	if !sim.Proc(chance, executionModelLabel) {
+		return 0
+	}
+	return durationFromMs(meanMs * sim.RandomExpFloat(executionModelLabel))
+}
+
+func (model *executionModel) reactionTime(sim *Simulation) time.Duration {
+	return max(model.sampleLogNormal(sim, model.config.ReactionTimeMeanMs, model.config.ReactionTimeStdevMs), 10*time.Millisecond)
+}
+
+// Time between the end of a GCD or cast and the next action.
+func (model *executionModel) actionDelay(sim *Simulation) time.Duration {
+	return model.sampleLogNormal(sim, model.config.LatencyMeanMs, model.config.LatencyStdevMs) +
+		model.sampleExp(sim, model.config.GcdGapChance, model.config.GcdGapMeanMs)
+}
+
+func (model *executionModel) queueDelay(sim *Simulation) time.Duration {
+	if !sim.Proc(model.config.MissedQueueChance, executionModelLabel) {
+		return 0
+	}
+	return model.reactionTime(sim)
+}
+
+func (model *executionModel) procReactionTime(sim *Simulation, aura *Aura) time.Duration {
+	reaction, ok := model.procReactions[aura]
+	if !ok || reaction.startedAt != aura.StartedAt() {
+		reaction = procReaction{
+			startedAt: aura.StartedAt(),
+			delay:     model.reactionTime(sim),
+		}
+		if sim.Proc(model.config.MissedProcChance, executionModelLabel) {
+			reaction.delay = NeverExpires
+		}
+		model.procReactions[aura] = reaction
+	}
+	return reaction.delay
+}
+
+// Resamples the reaction time for the next rotation decision.
+func (unit *Unit) sampleReactionTime(sim *Simulation) {
+	if unit.executionModel != nil {
+		unit.ReactionTime = unit.executionModel.reactionTime(sim)
+	}
+}
+
+// Extra time after a GCD or cast before the unit acts again.
+func (unit *Unit) executionDelay(sim *Simulation) time.Duration {
+	if unit.executionModel == nil {
+		return 0
+	}
+	return unit.executionModel.actionDelay(sim)
+}
+
+// Reaction time to the current activation of an aura. Sampled once per
+// activation, so that the rotation doesn't notice it and then forget again.
+func (unit *Unit) ProcReactionTime(sim *Simulation, aura *Aura) time.Duration {
+	if unit.executionModel == nil || !aura.IsActive() {
+		return unit.ReactionTime
+	}
+	return unit.executionModel.procReactionTime(sim, aura)
+}
+
+// Whether a human would have noticed this cooldown is ready by now. The delay
+// is rolled once each time the cooldown comes back.
+func (mcd *MajorCooldown) executionDelayElapsed(sim *Simulation, character *Character) bool {
+	model := character.executionModel
+	if model == nil {
+		return true
+	}
+
+	if readyAt := mcd.ReadyAt(); !mcd.executionDelayRolled || mcd.executionDelayReadyAt != readyAt {
+		mcd.executionDelayRolled = true
+		mcd.executionDelayReadyAt = readyAt
+		mcd.executionDelayUntil = sim.CurrentTime + model.sampleExp(sim, model.config.CooldownDelayChance, model.config.CooldownDelayMeanMs)
+	}
+	return sim.CurrentTime >= mcd.executionDelayUntil
+}
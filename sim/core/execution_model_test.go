@@ -0,0 +1,125 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func TestExecutionModelPresetsGetWorse(t *testing.T) {
+	skillLevels := []proto.ExecutionModel_SkillLevel{
+		proto.ExecutionModel_SkillLevelExpert,
+		proto.ExecutionModel_SkillLevelGood,
+		proto.ExecutionModel_SkillLevelAverage,
+		proto.ExecutionModel_SkillLevelCasual,
+	}
+
+	fields := map[string]func(*proto.ExecutionModel) float64{
+		"latency":        func(config *proto.ExecutionModel) float64 { return config.LatencyMeanMs },
+		"reaction time":  func(config *proto.ExecutionModel) float64 { return config.ReactionTimeMeanMs },
+		"GCD gap chance": func(config *proto.ExecutionModel) float64 { return config.GcdGapChance },
+		"GCD gap":        func(config *proto.ExecutionModel) float64 { return config.GcdGapMeanMs },
+		"missed procs":   func(config *proto.ExecutionModel) float64 { return config.MissedProcChance },
+		"cooldown delay": func(config *proto.ExecutionModel) float64 { return config.CooldownDelayMeanMs },
+		"missed queues":  func(config *proto.ExecutionModel) float64 { return config.MissedQueueChance },
+	}
+
+	for i := 1; i < len(skillLevels); i++ {
+		better := newExecutionModel(&proto.ExecutionModel{SkillLevel: skillLevels[i-1]}, time.Millisecond*100).config
+		worse := newExecutionModel(&proto.ExecutionModel{SkillLevel: skillLevels[i]}, time.Millisecond*100).config
+		for name, field := range fields {
+			if field(worse) <= field(better) {
+				t.Fatalf("Expected %s to have worse %s than %s: %f vs %f", skillLevels[i], name, skillLevels[i-1], field(worse), field(better))
+			}
+		}
+	}
+}
+
+func TestExecutionModelCustomConfig(t *testing.T) {
+	config := &proto.ExecutionModel{LatencyMeanMs: 50}
+	model := newExecutionModel(config, time.Millisecond*300)
+
+	if model.config.ReactionTimeMeanMs != 300 {
+		t.Fatalf("Expected a custom model without a reaction time to use the unit's, got %f", model.config.ReactionTimeMeanMs)
+	}
+	if config.ReactionTimeMeanMs != 0 {
+		t.Fatalf("Expected the custom config not to be modified")
+	}
+}
+
+func TestExecutionModelSampleMeans(t *testing.T) {
+	sim := SetupFakeSim()
+	model := newExecutionModel(&proto.ExecutionModel{SkillLevel: proto.ExecutionModel_SkillLevelAverage}, time.Millisecond*100)
+	config := model.config
+
+	const numSamples = 20000
+	var reactionSum, reactionSumSq, delaySum float64
+	for range numSamples {
+		reaction := float64(model.reactionTime(sim).Milliseconds())
+		reactionSum += reaction
+		reactionSumSq += reaction * reaction
+		delaySum += float64(model.actionDelay(sim).Milliseconds())
+	}
+
+	reactionMean := reactionSum / numSamples
+	reactionStdev := math.Sqrt(reactionSumSq/numSamples - reactionMean*reactionMean)
+	if !WithinToleranceFloat64(config.ReactionTimeMeanMs, reactionMean, config.ReactionTimeMeanMs*0.03) {
+		t.Fatalf("Expected a mean reaction time of %f, got %f", config.ReactionTimeMeanMs, reactionMean)
+	}
+	if !WithinToleranceFloat64(config.ReactionTimeStdevMs, reactionStdev, config.ReactionTimeStdevMs*0.1) {
+		t.Fatalf("Expected a reaction time stdev of %f, got %f", config.ReactionTimeStdevMs, reactionStdev)
+	}
+
+	expectedDelay := config.LatencyMeanMs + config.GcdGapChance*config.GcdGapMeanMs
+	if delayMean := delaySum / numSamples; !WithinToleranceFloat64(expectedDelay, delayMean, expectedDelay*0.05) {
+		t.Fatalf("Expected a mean action delay of %f, got %f", expectedDelay, delayMean)
+	}
+}
+
+func TestExecutionModelProcReactionPerActivation(t *testing.T) {
+	sim := SetupFakeSim()
+	model := newExecutionModel(&proto.ExecutionModel{
+		ReactionTimeMeanMs:  500,
+		ReactionTimeStdevMs: 200,
+	}, time.Millisecond*100)
+	aura := &Aura{}
+
+	first := model.procReactionTime(sim, aura)
+	for range 10 {
+		if reaction := model.procReactionTime(sim, aura); reaction != first {
+			t.Fatalf("Expected the reaction to an activation to be sampled once, got %s and %s", first, reaction)
+		}
+	}
+
+	resampled := false
+	for i := range 10 {
+		aura.startTime = time.Second * time.Duration(i+1)
+		if model.procReactionTime(sim, aura) != first {
+			resampled = true
+		}
+	}
+	if !resampled {
+		t.Fatalf("Expected new activations to resample the reaction")
+	}
+
+	model.config.MissedProcChance = 1
+	aura.startTime = time.Minute
+	if reaction := model.procReactionTime(sim, aura); reaction != NeverExpires {
+		t.Fatalf("Expected a missed proc to never be reacted to, got %s", reaction)
+	}
+}
+
+func TestExecutionModelDisabled(t *testing.T) {
+	sim := SetupFakeSim()
+	unit := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+	unit.executionModel = nil
+
+	if delay := unit.executionDelay(sim); delay != 0 {
+		t.Fatalf("Expected no execution delay without a model, got %s", delay)
+	}
+	if reaction := unit.ProcReactionTime(sim, &Aura{}); reaction != unit.ReactionTime {
+		t.Fatalf("Expected the unit's reaction time without a model, got %s", reaction)
+	}
+}
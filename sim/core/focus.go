@@ -97,6 +97,9 @@ func (fb *focusBar) AddFocus(sim *Simulation, amount float64, metrics *ResourceM
 	if (fb.isPlayer || fb.currentFocus != newFocus) && sim.Log != nil {
 		fb.unit.Log(sim, "Gained %0.3f focus from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, fb.currentFocus, newFocus, fb.maxFocus)
 	}
+	if fb.isPlayer && sim.CombatLog != nil {
+		sim.CombatLog.AddResource(sim, fb.unit, metrics, amount, newFocus)
+	}
 	if fb.isPlayer {
 		metrics.AddEvent(amount, newFocus-fb.currentFocus)
 	}
@@ -119,6 +122,9 @@ func (fb *focusBar) SpendFocus(sim *Simulation, amount float64, metrics *Resourc
 	if sim.Log != nil {
 		fb.unit.Log(sim, "Spent %0.3f focus from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, fb.currentFocus, newFocus, fb.maxFocus)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.AddResource(sim, fb.unit, metrics, -amount, newFocus)
+	}
 
 	fb.currentFocus = newFocus
 }
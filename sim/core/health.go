@@ -53,15 +53,25 @@ func (hb *healthBar) GainHealth(sim *Simulation, amount float64, metrics *Resour
 	oldHealth := hb.currentHealth
 	newHealth := min(oldHealth+amount, hb.unit.MaxHealth())
 	metrics.AddEvent(amount, newHealth-oldHealth)
+	metrics.Overheal += amount - (newHealth - oldHealth)
 
 	if sim.Log != nil {
 		hb.unit.Log(sim, "Gained %0.3f health from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, oldHealth, newHealth, hb.MaxHealth())
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.AddResource(sim, hb.unit, metrics, amount, newHealth)
+	}
 
 	hb.currentHealth = newHealth
 }
 
 func (hb *healthBar) RemoveHealth(sim *Simulation, amount float64) {
+	hb.RemoveHealthFromSpell(sim, amount, nil)
+}
+
+// Same as RemoveHealth, but remembers the spell that dealt the damage for the
+// damage spike metrics of tanks.
+func (hb *healthBar) RemoveHealthFromSpell(sim *Simulation, amount float64, spell *Spell) {
 	if amount < 0 {
 		panic("Trying to remove negative health!")
 	}
@@ -76,6 +86,11 @@ func (hb *healthBar) RemoveHealth(sim *Simulation, amount float64) {
 		entry := tmiListItem{
 			Timestamp:      sim.CurrentTime,
 			WeightedDamage: amount / hb.MaxHealth(),
+			Damage:         amount,
+		}
+		if spell != nil {
+			entry.ActionID = spell.ActionID
+			entry.UnitIndex = spell.Unit.UnitIndex
 		}
 		hb.unit.Metrics.tmiList = append(hb.unit.Metrics.tmiList, entry)
 	}
@@ -83,6 +98,9 @@ func (hb *healthBar) RemoveHealth(sim *Simulation, amount float64) {
 	if sim.Log != nil {
 		hb.unit.Log(sim, "Spent %0.3f health from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, oldHealth, newHealth, hb.MaxHealth())
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.AddResource(sim, hb.unit, metrics, -amount, newHealth)
+	}
 
 	hb.currentHealth = newHealth
 }
@@ -116,7 +134,7 @@ func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel)
 		},
 		OnSpellHitTaken: func(aura *Aura, sim *Simulation, spell *Spell, result *SpellResult) {
 			if result.Damage > 0 {
-				aura.Unit.RemoveHealth(sim, result.Damage)
+				aura.Unit.RemoveHealthFromSpell(sim, result.Damage, spell)
 
 				if aura.Unit.CurrentHealth() <= 0 && !aura.Unit.Metrics.Died {
 					// Queue a pending action to let shield effects give health
@@ -133,7 +151,7 @@ func (character *Character) trackChanceOfDeath(healingModel *proto.HealingModel)
 		},
 		OnPeriodicDamageTaken: func(aura *Aura, sim *Simulation, spell *Spell, result *SpellResult) {
 			if result.Damage > 0 {
-				aura.Unit.RemoveHealth(sim, result.Damage)
+				aura.Unit.RemoveHealthFromSpell(sim, result.Damage, spell)
 
 				if aura.Unit.CurrentHealth() <= 0 && !aura.Unit.Metrics.Died {
 					// Queue a pending action to let shield effects give health
@@ -0,0 +1,223 @@
+// Package lp solves small mixed integer linear programs, like choosing the
+// reforges and gems of a set of gear. It uses a dense two-phase simplex for
+// the linear relaxations and depth-first branch and bound for the binary
+// variables, which is plenty for problems of a few hundred variables.
+package lp
+
+import (
+	"errors"
+	"math"
+)
+
+const epsilon = 1e-9
+
+// Values this close to 0 or 1 count as integral.
+const integralityTolerance = 1e-6
+
+var (
+	ErrInfeasible = errors.New("lp: problem is infeasible")
+	ErrUnbounded  = errors.New("lp: problem is unbounded")
+)
+
+// Problem maximizes a linear objective over non-negative variables, subject
+// to linear constraints. Continuous variables have no upper bound unless one
+// is given through a constraint.
+type Problem struct {
+	objective []float64
+	binary    []bool
+	rows      []constraint
+}
+
+// Sum of coefficients[i] * x[i] <= rhs.
+type constraint struct {
+	coefficients map[int]float64
+	rhs          float64
+}
+
+type Solution struct {
+	Values    []float64
+	Objective float64
+
+	// False if the search stopped at the node limit before proving that no
+	// better solution exists.
+	Optimal bool
+}
+
+// AddVariable adds a variable with the given objective coefficient and
+// returns its index. Binary variables are either 0 or 1 in the solution.
+func (p *Problem) AddVariable(objective float64, binary bool) int {
+	p.objective = append(p.objective, objective)
+	p.binary = append(p.binary, binary)
+	return len(p.objective) - 1
+}
+
+func (p *Problem) NumVariables() int {
+	return len(p.objective)
+}
+
+// AddLessEq adds the constraint sum(coefficients[i] * x[i]) <= rhs.
+func (p *Problem) AddLessEq(coefficients map[int]float64, rhs float64) {
+	p.rows = append(p.rows, constraint{coefficients: coefficients, rhs: rhs})
+}
+
+// AddGreaterEq adds the constraint sum(coefficients[i] * x[i]) >= rhs.
+func (p *Problem) AddGreaterEq(coefficients map[int]float64, rhs float64) {
+	negated := make(map[int]float64, len(coefficients))
+	for i, c := range coefficients {
+		negated[i] = -c
+	}
+	p.AddLessEq(negated, -rhs)
+}
+
+// Solve finds the best solution, exploring at most maxNodes branches. If the
+// limit is reached the best solution found so far is returned.
+func (p *Problem) Solve(maxNodes int) (Solution, error) {
+	rows := p.denseRows()
+
+	type fixing struct {
+		variable int
+		value    float64
+	}
+
+	best := Solution{Objective: math.Inf(-1)}
+	found := false
+	stack := [][]fixing{nil}
+	nodes := 0
+	unbounded := false
+
+	for len(stack) > 0 {
+		if nodes >= maxNodes {
+			break
+		}
+		nodes++
+
+		fixings := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		fixed := make(map[int]float64, len(fixings))
+		for _, f := range fixings {
+			fixed[f.variable] = f.value
+		}
+
+		values, objective, err := p.solveRelaxation(rows, fixed)
+		if err == ErrUnbounded {
+			unbounded = true
+			continue
+		}
+		if err != nil || (found && objective <= best.Objective+epsilon) {
+			continue
+		}
+
+		// Branch on the most fractional binary variable.
+		branchVar := -1
+		bestDistance := integralityTolerance
+		for i, isBinary := range p.binary {
+			if !isBinary {
+				continue
+			}
+			distance := math.Min(values[i], 1-values[i])
+			if distance > bestDistance {
+				branchVar = i
+				bestDistance = distance
+			}
+		}
+
+		if branchVar == -1 {
+			for i, isBinary := range p.binary {
+				if isBinary {
+					values[i] = math.Round(values[i])
+				}
+			}
+			best = Solution{Values: values, Objective: objective}
+			found = true
+			continue
+		}
+
+		// Pushed last so it is explored first, which finds good solutions
+		// early since most variables pick one of several options.
+		zero := append(append([]fixing{}, fixings...), fixing{branchVar, 0})
+		one := append(append([]fixing{}, fixings...), fixing{branchVar, 1})
+		stack = append(stack, zero, one)
+	}
+
+	if !found {
+		if unbounded {
+			return best, ErrUnbounded
+		}
+		return best, ErrInfeasible
+	}
+	best.Optimal = len(stack) == 0
+	return best, nil
+}
+
+// Returns the constraints as dense rows, including upper bounds of 1 for
+// binary variables which are not already bounded by another constraint.
+func (p *Problem) denseRows() []constraint {
+	n := len(p.objective)
+	bounded := make([]bool, n)
+	for _, row := range p.rows {
+		if row.rhs > 1 {
+			continue
+		}
+		allNonNegative := true
+		for _, c := range row.coefficients {
+			if c < 0 {
+				allNonNegative = false
+				break
+			}
+		}
+		if !allNonNegative {
+			continue
+		}
+		for i, c := range row.coefficients {
+			if c >= 1 {
+				bounded[i] = true
+			}
+		}
+	}
+
+	rows := append([]constraint{}, p.rows...)
+	for i, isBinary := range p.binary {
+		if isBinary && !bounded[i] {
+			rows = append(rows, constraint{coefficients: map[int]float64{i: 1}, rhs: 1})
+		}
+	}
+	return rows
+}
+
+// Solves the linear relaxation with some variables fixed to a value.
+func (p *Problem) solveRelaxation(rows []constraint, fixed map[int]float64) ([]float64, float64, error) {
+	n := len(p.objective)
+	objective := make([]float64, n)
+	constant := 0.0
+	for i, c := range p.objective {
+		if value, ok := fixed[i]; ok {
+			constant += c * value
+		} else {
+			objective[i] = c
+		}
+	}
+
+	a := make([][]float64, len(rows))
+	b := make([]float64, len(rows))
+	for r, row := range rows {
+		a[r] = make([]float64, n)
+		b[r] = row.rhs
+		for i, c := range row.coefficients {
+			if value, ok := fixed[i]; ok {
+				b[r] -= c * value
+			} else {
+				a[r][i] = c
+			}
+		}
+	}
+
+	values, value, err := simplex(objective, a, b)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, v := range fixed {
+		values[i] = v
+	}
+	return values, value + constant, nil
+}
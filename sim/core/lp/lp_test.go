@@ -0,0 +1,86 @@
+package lp
+
+import (
+	"math"
+	"testing"
+)
+
+func expectNear(t *testing.T, name string, actual float64, expected float64) {
+	t.Helper()
+	if math.Abs(actual-expected) > 1e-6 {
+		t.Fatalf("Expected %s to be %f, got %f", name, expected, actual)
+	}
+}
+
+func TestSolveLinear(t *testing.T) {
+	// max 3x + 2y, x + y <= 4, x + 3y <= 6, x <= 3
+	p := &Problem{}
+	x := p.AddVariable(3, false)
+	y := p.AddVariable(2, false)
+	p.AddLessEq(map[int]float64{x: 1, y: 1}, 4)
+	p.AddLessEq(map[int]float64{x: 1, y: 3}, 6)
+	p.AddLessEq(map[int]float64{x: 1}, 3)
+
+	solution, err := p.Solve(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNear(t, "objective", solution.Objective, 11)
+	expectNear(t, "x", solution.Values[x], 3)
+	expectNear(t, "y", solution.Values[y], 1)
+}
+
+func TestSolveGreaterEq(t *testing.T) {
+	// max -x - y, x + 2y >= 4, 3x + y >= 6
+	p := &Problem{}
+	x := p.AddVariable(-1, false)
+	y := p.AddVariable(-1, false)
+	p.AddGreaterEq(map[int]float64{x: 1, y: 2}, 4)
+	p.AddGreaterEq(map[int]float64{x: 3, y: 1}, 6)
+
+	solution, err := p.Solve(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNear(t, "objective", solution.Objective, -2.8)
+	expectNear(t, "x", solution.Values[x], 1.6)
+	expectNear(t, "y", solution.Values[y], 1.2)
+}
+
+func TestSolveKnapsack(t *testing.T) {
+	// The relaxation takes a fraction of the densest items, the best integral
+	// solution is items 1 and 2.
+	values := []float64{10, 13, 7, 8}
+	weights := []float64{5, 6, 4, 5}
+
+	p := &Problem{}
+	capacity := map[int]float64{}
+	for i := range values {
+		capacity[p.AddVariable(values[i], true)] = weights[i]
+	}
+	p.AddLessEq(capacity, 11)
+
+	solution, err := p.Solve(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !solution.Optimal {
+		t.Fatalf("Expected the search to finish")
+	}
+	expectNear(t, "objective", solution.Objective, 23)
+	for i, expected := range []float64{1, 1, 0, 0} {
+		expectNear(t, "item", solution.Values[i], expected)
+	}
+}
+
+func TestSolveInfeasible(t *testing.T) {
+	p := &Problem{}
+	x := p.AddVariable(1, true)
+	y := p.AddVariable(1, true)
+	p.AddLessEq(map[int]float64{x: 1, y: 1}, 1)
+	p.AddGreaterEq(map[int]float64{x: 1, y: 1}, 2)
+
+	if _, err := p.Solve(100); err != ErrInfeasible {
+		t.Fatalf("Expected ErrInfeasible, got %v", err)
+	}
+}
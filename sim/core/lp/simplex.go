@@ -0,0 +1,208 @@
+package lp
+
+import (
+	"errors"
+	"math"
+)
+
+// Pivots without progress before switching to Bland's rule, which is slower
+// but cannot cycle.
+const maxDegeneratePivots = 50
+
+var errIterationLimit = errors.New("lp: simplex iteration limit reached")
+
+type tableau struct {
+	rows      [][]float64 // Last column is the right hand side.
+	objective []float64   // Reduced costs, last column is the objective value.
+	basis     []int
+	allowed   []bool // Columns which may enter the basis.
+}
+
+// Maximizes c * x subject to a * x <= b and x >= 0, using the two-phase
+// simplex method on a dense tableau.
+func simplex(c []float64, a [][]float64, b []float64) ([]float64, float64, error) {
+	n := len(c)
+	m := len(a)
+
+	numArtificial := 0
+	for _, rhs := range b {
+		if rhs < 0 {
+			numArtificial++
+		}
+	}
+
+	// Columns are the variables, one slack per row and one artificial
+	// variable per row with a negative right hand side.
+	numCols := n + m + numArtificial
+	t := &tableau{
+		rows:      make([][]float64, m),
+		objective: make([]float64, numCols+1),
+		basis:     make([]int, m),
+		allowed:   make([]bool, numCols),
+	}
+
+	artificial := n + m
+	for i := range a {
+		row := make([]float64, numCols+1)
+		copy(row, a[i])
+		row[n+i] = 1
+		row[numCols] = b[i]
+		t.basis[i] = n + i
+
+		if b[i] < 0 {
+			for j := range row {
+				row[j] = -row[j]
+			}
+			row[artificial] = 1
+			t.basis[i] = artificial
+			artificial++
+		}
+		t.rows[i] = row
+	}
+
+	if numArtificial > 0 {
+		phaseOne := make([]float64, numCols)
+		for j := n + m; j < numCols; j++ {
+			phaseOne[j] = -1
+		}
+		for j := range t.allowed {
+			t.allowed[j] = true
+		}
+		t.setObjective(phaseOne)
+		if err := t.run(); err != nil {
+			return nil, 0, err
+		}
+		if t.objective[numCols] < -1e-7 {
+			return nil, 0, ErrInfeasible
+		}
+
+		// Move artificial variables which are still basic at 0 out of the
+		// basis. Rows where that is impossible are redundant.
+		for i, basic := range t.basis {
+			if basic < n+m {
+				continue
+			}
+			for j := 0; j < n+m; j++ {
+				if math.Abs(t.rows[i][j]) > epsilon {
+					t.pivot(i, j)
+					break
+				}
+			}
+		}
+	}
+
+	phaseTwo := make([]float64, numCols)
+	copy(phaseTwo, c)
+	for j := range t.allowed {
+		t.allowed[j] = j < n+m
+	}
+	t.setObjective(phaseTwo)
+	if err := t.run(); err != nil {
+		return nil, 0, err
+	}
+
+	values := make([]float64, n)
+	for i, basic := range t.basis {
+		if basic < n {
+			values[basic] = t.rows[i][numCols]
+		}
+	}
+	return values, t.objective[numCols], nil
+}
+
+// Sets the objective to maximize, expressed in terms of the non-basic
+// variables.
+func (t *tableau) setObjective(c []float64) {
+	last := len(t.objective) - 1
+	for j := range c {
+		t.objective[j] = -c[j]
+	}
+	t.objective[last] = 0
+
+	for i, basic := range t.basis {
+		factor := t.objective[basic]
+		if factor == 0 {
+			continue
+		}
+		for j, v := range t.rows[i] {
+			t.objective[j] -= factor * v
+		}
+	}
+}
+
+func (t *tableau) pivot(pivotRow int, pivotCol int) {
+	row := t.rows[pivotRow]
+	scale := 1 / row[pivotCol]
+	for j := range row {
+		row[j] *= scale
+	}
+	row[pivotCol] = 1
+
+	eliminate := func(target []float64) {
+		factor := target[pivotCol]
+		if factor == 0 {
+			return
+		}
+		for j, v := range row {
+			target[j] -= factor * v
+		}
+		target[pivotCol] = 0
+	}
+	for i, other := range t.rows {
+		if i != pivotRow {
+			eliminate(other)
+		}
+	}
+	eliminate(t.objective)
+	t.basis[pivotRow] = pivotCol
+}
+
+func (t *tableau) run() error {
+	last := len(t.objective) - 1
+	maxIterations := 50 * (len(t.rows) + last)
+	degeneratePivots := 0
+
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		useBland := degeneratePivots > maxDegeneratePivots
+
+		pivotCol := -1
+		for j := 0; j < last; j++ {
+			if !t.allowed[j] || t.objective[j] >= -epsilon {
+				continue
+			}
+			if pivotCol == -1 || (!useBland && t.objective[j] < t.objective[pivotCol]) {
+				pivotCol = j
+				if useBland {
+					break
+				}
+			}
+		}
+		if pivotCol == -1 {
+			return nil
+		}
+
+		pivotRow := -1
+		bestRatio := math.Inf(1)
+		for i, row := range t.rows {
+			if row[pivotCol] <= epsilon {
+				continue
+			}
+			ratio := row[last] / row[pivotCol]
+			if ratio < bestRatio-epsilon || (ratio <= bestRatio+epsilon && pivotRow != -1 && t.basis[i] < t.basis[pivotRow]) {
+				pivotRow = i
+				bestRatio = ratio
+			}
+		}
+		if pivotRow == -1 {
+			return ErrUnbounded
+		}
+
+		if bestRatio <= epsilon {
+			degeneratePivots++
+		} else {
+			degeneratePivots = 0
+		}
+		t.pivot(pivotRow, pivotCol)
+	}
+	return errIterationLimit
+}
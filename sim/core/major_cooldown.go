@@ -68,6 +68,11 @@ type MajorCooldown struct {
 
 	// Whether this MCD is currently disabled.
 	disabled bool
+
+	// Delay before a human player uses this MCD, rolled when it comes off cooldown.
+	executionDelayRolled  bool
+	executionDelayReadyAt time.Duration
+	executionDelayUntil   time.Duration
 }
 
 func (mcd *MajorCooldown) ReadyAt() time.Duration {
@@ -131,7 +136,7 @@ func (mcd *MajorCooldown) shouldActivateHelper(sim *Simulation, character *Chara
 	}
 
 	if mcd.numUsages < len(mcd.timings) {
-		return sim.CurrentTime >= mcd.timings[mcd.numUsages]
+		return sim.CurrentTime >= mcd.timings[mcd.numUsages] && mcd.executionDelayElapsed(sim, character)
 	}
 
 	if mcd.Type.Matches(CooldownTypeSurvival) && character.cooldownConfigs.HpPercentForDefensives != 0 {
@@ -140,7 +145,7 @@ func (mcd *MajorCooldown) shouldActivateHelper(sim *Simulation, character *Chara
 		}
 	}
 
-	return mcd.ShouldActivate(sim, character)
+	return mcd.ShouldActivate(sim, character) && mcd.executionDelayElapsed(sim, character)
 }
 
 // Activates this MCD, if all the conditions pass.
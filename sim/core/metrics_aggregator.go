@@ -88,19 +88,27 @@ type UnitMetrics struct {
 	dtps   DistributionMetrics
 	tmi    DistributionMetrics
 	hps    DistributionMetrics
+	ehps   DistributionMetrics
 	tto    DistributionMetrics
 
 	tmiList   []tmiListItem
 	isTanking bool
 	tmiBin    int32
 
+	// Only tracked for tanks.
+	damageTaken         map[damageTakenKey]*DamageTakenMetrics
+	largestDamageWindow DistributionMetrics
+	worstDamageSpikes   []*damageSpike
+
 	CharacterIterationMetrics
 
 	// Aggregate values. These are updated after each iteration.
-	numItersDead int32
-	oomTimeSum   float64
-	actions      map[ActionID]*ActionMetrics
-	resources    []*ResourceMetrics
+	numItersDead      int32
+	numItersAggroPull int32
+	aggroPullsSum     int32
+	oomTimeSum        float64
+	actions           map[ActionID]*ActionMetrics
+	resources         []*ResourceMetrics
 }
 
 // Metrics for the current iteration, for 1 agent. Keep this as a separate
@@ -115,6 +123,8 @@ type CharacterIterationMetrics struct {
 	OOMTime time.Duration // time spent not casting and waiting for regen.
 
 	FirstOOMTimestamp time.Duration // Timestamp at which unit first went OOM.
+
+	AggroPulls int32 // Number of times this unit pulled aggro from a target.
 }
 
 type ActionMetrics struct {
@@ -129,6 +139,11 @@ type ActionMetrics struct {
 type tmiListItem struct {
 	Timestamp      time.Duration
 	WeightedDamage float64
+
+	// Source of the damage, for damage spike metrics.
+	Damage    float64
+	ActionID  ActionID
+	UnitIndex int32
 }
 
 func (actionMetrics *ActionMetrics) ToProto(actionID ActionID) *proto.ActionMetrics {
@@ -174,6 +189,8 @@ type SpellMetrics struct {
 	TotalHealing           float64 // Healing done by all casts of this spell.
 	TotalCritHealing       float64 // Healing done by all critical casts of this spell.
 	TotalShielding         float64 // Shielding done by all casts of this spell.
+	TotalOverhealing       float64 // Healing done by all casts of this spell in excess of missing health.
+	TotalAbsorbed          float64 // Damage absorbed by shields from all casts of this spell.
 	TotalCastTime          time.Duration
 }
 
@@ -205,6 +222,8 @@ type TargetedActionMetrics struct {
 	Healing           float64
 	CritHealing       float64
 	Shielding         float64
+	Overhealing       float64
+	Absorbed          float64
 	CastTime          time.Duration
 }
 
@@ -236,6 +255,8 @@ func (tam *TargetedActionMetrics) ToProto() *proto.TargetedActionMetrics {
 		Healing:           tam.Healing,
 		CritHealing:       tam.CritHealing,
 		Shielding:         tam.Shielding,
+		Overhealing:       tam.Overhealing,
+		Absorbed:          tam.Absorbed,
 		CastTimeMs:        float64(tam.CastTime.Milliseconds()),
 	}
 }
@@ -247,8 +268,12 @@ func NewUnitMetrics() UnitMetrics {
 		dtps:    NewDistributionMetrics(),
 		tmi:     NewDistributionMetrics(),
 		hps:     NewDistributionMetrics(),
+		ehps:    NewDistributionMetrics(),
 		tto:     NewDistributionMetrics(),
 		actions: make(map[ActionID]*ActionMetrics),
+
+		damageTaken:         make(map[damageTakenKey]*DamageTakenMetrics),
+		largestDamageWindow: NewDistributionMetrics(),
 	}
 }
 
@@ -259,6 +284,7 @@ type ResourceMetrics struct {
 	Events     int32
 	Gain       float64
 	ActualGain float64
+	Overheal   float64 // Health gained over max health, only set for health metrics.
 
 	EventsFromPreviousIterations     int32
 	ActualGainFromPreviousIterations float64
@@ -272,6 +298,7 @@ func (resourceMetrics *ResourceMetrics) ToProto() *proto.ResourceMetrics {
 		Events:     resourceMetrics.Events,
 		Gain:       resourceMetrics.Gain,
 		ActualGain: resourceMetrics.ActualGain,
+		Overheal:   resourceMetrics.Overheal,
 	}
 }
 
@@ -397,6 +424,8 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 		tam.Healing += spellTargetMetrics.TotalHealing
 		tam.CritHealing += spellTargetMetrics.TotalCritHealing
 		tam.Shielding += spellTargetMetrics.TotalShielding
+		tam.Overhealing += spellTargetMetrics.TotalOverhealing
+		tam.Absorbed += spellTargetMetrics.TotalAbsorbed
 		if !spell.Flags.Matches(SpellFlagPassiveSpell) {
 			tam.CastTime += spellTargetMetrics.TotalCastTime
 		}
@@ -409,6 +438,7 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 			unitMetrics.threat.Total += spellTargetMetrics.TotalThreat
 		} else {
 			unitMetrics.hps.Total += spellTargetMetrics.TotalHealing + spellTargetMetrics.TotalShielding
+			unitMetrics.ehps.Total += spellTargetMetrics.TotalHealing - spellTargetMetrics.TotalOverhealing + spellTargetMetrics.TotalAbsorbed
 		}
 	}
 }
@@ -442,7 +472,9 @@ func (unitMetrics *UnitMetrics) reset() {
 	unitMetrics.dtps.reset()
 	unitMetrics.tmi.reset()
 	unitMetrics.tmiList = nil
+	unitMetrics.largestDamageWindow.reset()
 	unitMetrics.hps.reset()
+	unitMetrics.ehps.reset()
 	unitMetrics.tto.reset()
 	unitMetrics.CharacterIterationMetrics = CharacterIterationMetrics{}
 
@@ -484,6 +516,9 @@ func (unitMetrics *UnitMetrics) doneIteration(unit *Unit, sim *Simulation) {
 
 		// Hack because of the way DistributionMetrics does its calculations.
 		unitMetrics.tmi.Total *= sim.Duration.Seconds()
+
+		unitMetrics.doneDamageSpikeIteration(sim)
+		unitMetrics.largestDamageWindow.doneIteration(sim)
 	}
 
 	unitMetrics.dps.doneIteration(sim)
@@ -491,12 +526,17 @@ func (unitMetrics *UnitMetrics) doneIteration(unit *Unit, sim *Simulation) {
 	unitMetrics.dtps.doneIteration(sim)
 	unitMetrics.tmi.doneIteration(sim)
 	unitMetrics.hps.doneIteration(sim)
+	unitMetrics.ehps.doneIteration(sim)
 	unitMetrics.tto.doneIteration(sim)
 
 	unitMetrics.oomTimeSum += unitMetrics.OOMTime.Seconds()
 	if unitMetrics.Died {
 		unitMetrics.numItersDead++
 	}
+	if unitMetrics.AggroPulls > 0 {
+		unitMetrics.numItersAggroPull++
+		unitMetrics.aggroPullsSum += unitMetrics.AggroPulls
+	}
 }
 
 func (unitMetrics *UnitMetrics) calculateTMI(unit *Unit, sim *Simulation) float64 {
@@ -579,9 +619,13 @@ func (unitMetrics *UnitMetrics) ToProto() *proto.UnitMetrics {
 		Dtps:          unitMetrics.dtps.ToProto(),
 		Tmi:           unitMetrics.tmi.ToProto(),
 		Hps:           unitMetrics.hps.ToProto(),
+		Ehps:          unitMetrics.ehps.ToProto(),
 		Tto:           unitMetrics.tto.ToProto(),
 		SecondsOomAvg: unitMetrics.oomTimeSum / n,
 		ChanceOfDeath: float64(unitMetrics.numItersDead) / n,
+
+		AggroPullsAvg:     float64(unitMetrics.aggroPullsSum) / n,
+		ChanceOfAggroPull: float64(unitMetrics.numItersAggroPull) / n,
 	}
 
 	protoMetrics.Actions = make([]*proto.ActionMetrics, 0, len(unitMetrics.actions))
@@ -596,6 +640,10 @@ func (unitMetrics *UnitMetrics) ToProto() *proto.UnitMetrics {
 		}
 	}
 
+	if unitMetrics.isTanking {
+		protoMetrics.DamageTaken, protoMetrics.DamageSpikes = unitMetrics.damageTakenToProto()
+	}
+
 	return protoMetrics
 }
 
@@ -9,13 +9,25 @@ import (
 
 type MovementAction struct {
 	PendingAction
-	srcPosition float64       // starting position
-	startTime   time.Duration // starting time of the movement
-	speed       float64       // theoretical movement speed, can be 0
+	startTime time.Duration // starting time of the movement
+
+	// Radial movement towards or away from the target, from MoveTo. The
+	// distance is tracked as a scalar so that it matches the requested range.
+	radial      bool
+	srcDistance float64 // starting distance from the target
+	speed       float64 // theoretical movement speed, can be 0
+
+	srcPosition Vector2 // starting position
+	destination Vector2
+	velocity    Vector2 // yards per second
 }
 
-func (action *MovementAction) GetCurrentPosition(sim *Simulation) float64 {
-	return action.srcPosition + float64(sim.CurrentTime-action.startTime)*action.speed/float64(time.Second)
+func (action *MovementAction) GetCurrentPosition(sim *Simulation) Vector2 {
+	return action.srcPosition.Add(action.velocity.Scale(float64(sim.CurrentTime-action.startTime) / float64(time.Second)))
+}
+
+func (action *MovementAction) getCurrentDistance(sim *Simulation) float64 {
+	return action.srcDistance + float64(sim.CurrentTime-action.startTime)*action.speed/float64(time.Second)
 }
 
 func (unit *Unit) initMovement() {
@@ -53,7 +65,43 @@ func (unit *Unit) MoveTo(moveRange float64, sim *Simulation) {
 	unit.UpdatePosition(sim)
 	moveDistance := moveRange - unit.DistanceFromTarget
 	timeToMove := time.Duration(math.Abs(moveDistance)/unit.GetMovementSpeed()*1000) * time.Millisecond
-	registerMovementAction(unit, sim, unit.GetMovementSpeed()*TernaryFloat64(moveDistance < 0, -1., 1.), sim.CurrentTime+timeToMove)
+	speed := unit.GetMovementSpeed() * TernaryFloat64(moveDistance < 0, -1., 1.)
+
+	// Walk along the line through the target, or the target's facing when
+	// standing right on top of it.
+	var direction Vector2
+	if unit.CurrentTarget != nil {
+		targetPosition := unit.CurrentTarget.GetPosition(sim)
+		direction = unit.Position.Sub(targetPosition).Normalized()
+		if direction == (Vector2{}) {
+			direction = Vector2FromAngle(unit.CurrentTarget.Facing)
+			if !unit.PseudoStats.InFrontOfTarget {
+				direction = direction.Scale(-1)
+			}
+		}
+	}
+
+	registerMovementAction(unit, sim, MovementAction{
+		radial:      true,
+		speed:       speed,
+		destination: unit.Position.Add(direction.Scale(moveDistance)),
+		velocity:    direction.Scale(speed),
+	}, sim.CurrentTime+timeToMove)
+}
+
+// Walks this unit to a point, e.g. to dodge a void zone or follow the boss.
+func (unit *Unit) MoveToPosition(sim *Simulation, destination Vector2) {
+	unit.UpdatePosition(sim)
+	offset := destination.Sub(unit.Position)
+	if offset.Length() == 0 {
+		return
+	}
+
+	timeToMove := time.Duration(offset.Length()/unit.GetMovementSpeed()*1000) * time.Millisecond
+	registerMovementAction(unit, sim, MovementAction{
+		destination: destination,
+		velocity:    offset.Normalized().Scale(unit.GetMovementSpeed()),
+	}, sim.CurrentTime+timeToMove)
 }
 
 func (unit *Unit) MoveDuration(duration time.Duration, sim *Simulation) {
@@ -62,7 +110,10 @@ func (unit *Unit) MoveDuration(duration time.Duration, sim *Simulation) {
 	}
 
 	unit.UpdatePosition(sim)
-	registerMovementAction(unit, sim, 0., sim.CurrentTime+duration)
+	registerMovementAction(unit, sim, MovementAction{
+		radial:      true,
+		destination: unit.Position,
+	}, sim.CurrentTime+duration)
 }
 
 func (unit *Unit) UpdatePosition(sim *Simulation) {
@@ -70,15 +121,30 @@ func (unit *Unit) UpdatePosition(sim *Simulation) {
 		return
 	}
 
+	action := unit.movementAction
+	unit.Position = action.GetCurrentPosition(sim)
+	unit.updateFollowerDistances(sim)
+
 	oldDist := unit.DistanceFromTarget
-	unit.DistanceFromTarget = unit.movementAction.GetCurrentPosition(sim)
+	if action.radial {
+		unit.DistanceFromTarget = action.getCurrentDistance(sim)
+	} else if unit.CurrentTarget != nil {
+		unit.DistanceFromTarget = unit.Position.DistanceTo(unit.CurrentTarget.GetPosition(sim))
+	}
 	if oldDist == unit.DistanceFromTarget {
 		return
 	}
 
 	unit.OnMovement(sim, unit.DistanceFromTarget, MovementUpdate)
+	unit.updateAutoAttackRange(sim)
+
+	yards := max(int32(unit.DistanceFromTarget), 1) // never set to 0 yards as we deactivate the aura
+	if yards != unit.moveAura.GetStacks() {
+		unit.moveAura.SetStacks(sim, yards)
+	}
+}
 
-	// update auto attack state
+func (unit *Unit) updateAutoAttackRange(sim *Simulation) {
 	if unit.AutoAttacks.mh.enabled != unit.AutoAttacks.mh.IsInRange() {
 		if unit.AutoAttacks.mh.IsInRange() {
 			unit.AutoAttacks.EnableMeleeSwing(sim)
@@ -94,36 +160,46 @@ func (unit *Unit) UpdatePosition(sim *Simulation) {
 			unit.AutoAttacks.CancelRangedSwing(sim)
 		}
 	}
+}
 
-	yards := max(int32(unit.DistanceFromTarget), 1) // never set to 0 yards as we deactivate the aura
-	if yards != unit.moveAura.GetStacks() {
-		unit.moveAura.SetStacks(sim, yards)
+func (unit *Unit) FinalizeMovement(sim *Simulation) {
+	if !unit.Moving {
+		return
 	}
+
+	unit.UpdatePosition(sim)
+	// Avoid rounding errors piling up over many movements.
+	if !unit.movementAction.radial {
+		unit.Position = unit.movementAction.destination
+	}
+	unit.moveAura.Deactivate(sim)
+
+	unit.OnMovement(sim, unit.DistanceFromTarget, MovementEnd)
 }
 
-func (unit *Unit) FinalizeMovement(sim *Simulation) {
+// Stops any ongoing movement where the unit currently is.
+func (unit *Unit) StopMovement(sim *Simulation) {
 	if !unit.Moving {
 		return
 	}
 
 	unit.UpdatePosition(sim)
+	unit.movementAction.Cancel(sim)
 	unit.moveAura.Deactivate(sim)
 
 	unit.OnMovement(sim, unit.DistanceFromTarget, MovementEnd)
 }
 
-func registerMovementAction(unit *Unit, sim *Simulation, speed float64, endTime time.Duration) {
+func registerMovementAction(unit *Unit, sim *Simulation, movementAction MovementAction, endTime time.Duration) {
 	if unit.movementAction != nil {
 		unit.movementAction.Cancel(sim)
 	} else {
 		unit.moveSpell.Cast(sim, unit.CurrentTarget)
 	}
 
-	movementAction := MovementAction{
-		startTime:   sim.CurrentTime,
-		speed:       speed,
-		srcPosition: unit.DistanceFromTarget,
-	}
+	movementAction.startTime = sim.CurrentTime
+	movementAction.srcDistance = unit.DistanceFromTarget
+	movementAction.srcPosition = unit.Position
 
 	movementAction.NextActionAt = endTime
 	movementAction.OnAction = func(sim *Simulation) {
@@ -167,6 +243,8 @@ func (unit *Unit) MultiplyMovementSpeed(sim *Simulation, amount float64) {
 	if unit.movementAction != nil && unit.movementAction.speed != 0 {
 		dest := unit.movementAction.speed * float64(unit.movementAction.NextActionAt-unit.movementAction.startTime) / float64(time.Second)
 		unit.MoveTo(dest, sim)
+	} else if unit.movementAction != nil && !unit.movementAction.radial {
+		unit.MoveToPosition(sim, unit.movementAction.destination)
 	}
 }
 
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"math"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Point or offset on the ground, in yards.
+type Vector2 struct {
+	X float64
+	Y float64
+}
+
+func Vector2FromProto(position *proto.Position) Vector2 {
+	if position == nil {
+		return Vector2{}
+	}
+	return Vector2{X: position.X, Y: position.Y}
+}
+
+func Vector2FromAngle(angle float64) Vector2 {
+	return Vector2{X: math.Cos(angle), Y: math.Sin(angle)}
+}
+
+func (v Vector2) Add(other Vector2) Vector2 {
+	return Vector2{X: v.X + other.X, Y: v.Y + other.Y}
+}
+
+func (v Vector2) Sub(other Vector2) Vector2 {
+	return Vector2{X: v.X - other.X, Y: v.Y - other.Y}
+}
+
+func (v Vector2) Scale(factor float64) Vector2 {
+	return Vector2{X: v.X * factor, Y: v.Y * factor}
+}
+
+func (v Vector2) Length() float64 {
+	return math.Hypot(v.X, v.Y)
+}
+
+func (v Vector2) DistanceTo(other Vector2) float64 {
+	return other.Sub(v).Length()
+}
+
+// Returns the unit vector in the same direction, or the zero vector.
+func (v Vector2) Normalized() Vector2 {
+	length := v.Length()
+	if length == 0 {
+		return Vector2{}
+	}
+	return v.Scale(1 / length)
+}
+
+func (v Vector2) Angle() float64 {
+	return math.Atan2(v.Y, v.X)
+}
+
+// Places units without an explicit start position relative to their target,
+// based on the scalar distance and facing settings. Units with one get those
+// settings from their position instead.
+func (unit *Unit) initPosition() {
+	target := unit.defaultTarget
+	if unit.hasStartPosition {
+		if target != nil && unit.Type != EnemyUnit {
+			unit.StartDistanceFromTarget = unit.StartPosition.DistanceTo(target.StartPosition)
+			unit.PseudoStats.InFrontOfTarget = target.isInFrontArc(target.StartPosition, target.startFacing, unit.StartPosition, 180)
+		}
+	} else if unit.Type != EnemyUnit {
+		var origin Vector2
+		var facing float64
+		if target != nil {
+			origin = target.StartPosition
+			facing = target.startFacing
+		}
+
+		offset := Vector2FromAngle(facing).Scale(unit.StartDistanceFromTarget)
+		if !unit.PseudoStats.InFrontOfTarget {
+			offset = offset.Scale(-1)
+		}
+		unit.StartPosition = origin.Add(offset)
+	}
+
+	if target != nil && unit.Type != EnemyUnit {
+		unit.startFacing = target.StartPosition.Sub(unit.StartPosition).Angle()
+	}
+}
+
+func (unit *Unit) resetPosition() {
+	unit.Position = unit.StartPosition
+	unit.Facing = unit.startFacing
+	unit.DistanceFromTarget = unit.StartDistanceFromTarget
+}
+
+// Current position, including the progress of any ongoing movement.
+func (unit *Unit) GetPosition(sim *Simulation) Vector2 {
+	if unit.Moving {
+		return unit.movementAction.GetCurrentPosition(sim)
+	}
+	return unit.Position
+}
+
+// Distance between two units. For the current target this is the tracked
+// DistanceFromTarget, so scalar distance settings keep working exactly.
+func (unit *Unit) DistanceTo(sim *Simulation, other *Unit) float64 {
+	if other == nil || (other == unit.CurrentTarget && !other.Moving) {
+		return unit.DistanceFromTarget
+	}
+	return unit.GetPosition(sim).DistanceTo(other.GetPosition(sim))
+}
+
+// Whether other stands inside the cone of the given width centered on the
+// direction this unit is facing.
+func (unit *Unit) IsInFrontArc(sim *Simulation, other *Unit, arcDegrees float64) bool {
+	return unit.isInFrontArc(unit.GetPosition(sim), unit.Facing, other.GetPosition(sim), arcDegrees)
+}
+
+func (unit *Unit) isInFrontArc(position Vector2, facing float64, otherPosition Vector2, arcDegrees float64) bool {
+	offset := otherPosition.Sub(position)
+	if offset.Length() == 0 {
+		return true
+	}
+
+	angle := math.Abs(math.Remainder(offset.Angle()-facing, 2*math.Pi))
+	return angle <= arcDegrees*math.Pi/360
+}
+
+// Turns this unit towards another one.
+func (unit *Unit) FaceUnit(sim *Simulation, other *Unit) {
+	offset := other.GetPosition(sim).Sub(unit.GetPosition(sim))
+	if offset.Length() > 0 {
+		unit.Facing = offset.Angle()
+	}
+}
+
+// Number of active enemy targets within radius yards of this unit, e.g. for
+// the targets hit by a cleave or a point blank AoE.
+func (unit *Unit) NumEnemiesInRange(sim *Simulation, radius float64) int32 {
+	var numTargets int32
+	for _, target := range unit.Env.Encounter.ActiveTargetUnits {
+		if unit.DistanceTo(sim, target) <= radius {
+			numTargets++
+		}
+	}
+	return numTargets
+}
+
+// Teleports this unit, cancelling any ongoing movement.
+func (unit *Unit) SetPosition(sim *Simulation, position Vector2) {
+	unit.StopMovement(sim)
+	unit.Position = position
+	unit.updateDistanceFromTarget(sim)
+	unit.updateFollowerDistances(sim)
+}
+
+// Walks this unit to within moveRange yards of another one, along the line
+// between them.
+func (unit *Unit) MoveToUnit(sim *Simulation, other *Unit, moveRange float64) {
+	if other == unit.CurrentTarget && !other.Moving {
+		unit.MoveTo(moveRange, sim)
+		return
+	}
+
+	unit.UpdatePosition(sim)
+	otherPosition := other.GetPosition(sim)
+	direction := unit.Position.Sub(otherPosition).Normalized()
+	unit.MoveToPosition(sim, otherPosition.Add(direction.Scale(moveRange)))
+}
+
+// Recomputes the distance to the current target from both positions.
+func (unit *Unit) updateDistanceFromTarget(sim *Simulation) {
+	if unit.CurrentTarget == nil {
+		return
+	}
+
+	distance := unit.GetPosition(sim).DistanceTo(unit.CurrentTarget.GetPosition(sim))
+	if distance == unit.DistanceFromTarget {
+		return
+	}
+
+	unit.DistanceFromTarget = distance
+	unit.updateAutoAttackRange(sim)
+}
+
+// Units which target this one keep their own position, so their distance
+// changes when this unit moves.
+func (unit *Unit) updateFollowerDistances(sim *Simulation) {
+	if unit.Type != EnemyUnit {
+		return
+	}
+
+	for _, follower := range unit.Env.AllUnits {
+		if follower.CurrentTarget == unit && !follower.Moving {
+			follower.updateDistanceFromTarget(sim)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func expectPosition(t *testing.T, label string, expected Vector2, actual Vector2) {
+	t.Helper()
+	if expected.DistanceTo(actual) > 0.001 {
+		t.Fatalf("Expected %s at %v, got %v", label, expected, actual)
+	}
+}
+
+func TestVector2Math(t *testing.T) {
+	a := Vector2{X: 3, Y: 4}
+	b := Vector2{X: -1, Y: 2}
+
+	expectPosition(t, "sum", Vector2{X: 2, Y: 6}, a.Add(b))
+	expectPosition(t, "difference", Vector2{X: 4, Y: 2}, a.Sub(b))
+	expectPosition(t, "scaled vector", Vector2{X: 6, Y: 8}, a.Scale(2))
+	expectPosition(t, "normalized vector", Vector2{X: 0.6, Y: 0.8}, a.Normalized())
+	expectPosition(t, "normalized zero vector", Vector2{}, Vector2{}.Normalized())
+	expectPosition(t, "unit vector", Vector2{X: 0, Y: 1}, Vector2FromAngle(math.Pi/2))
+
+	if a.Length() != 5 {
+		t.Fatalf("Expected a length of 5, got %f", a.Length())
+	}
+	if distance := a.DistanceTo(Vector2{}); distance != 5 {
+		t.Fatalf("Expected a distance of 5, got %f", distance)
+	}
+	if angle := b.Sub(a).Angle(); !WithinToleranceFloat64(-math.Pi+math.Atan(0.5), angle, 0.0001) {
+		t.Fatalf("Expected an angle of %f, got %f", -math.Pi+math.Atan(0.5), angle)
+	}
+}
+
+func TestIsInFrontArc(t *testing.T) {
+	unit := &Unit{}
+	origin := Vector2{}
+
+	testCases := []struct {
+		label    string
+		facing   float64
+		other    Vector2
+		arc      float64
+		expected bool
+	}{
+		{"ahead", 0, Vector2{X: 10}, 90, true},
+		{"beside, narrow arc", 0, Vector2{Y: 10}, 90, false},
+		{"beside, half circle", 0, Vector2{Y: 10}, 180, true},
+		{"behind, half circle", 0, Vector2{X: -10}, 180, false},
+		{"behind, full circle", 0, Vector2{X: -10}, 360, true},
+		{"across the angle wrap", math.Pi - 0.1, Vector2FromAngle(-math.Pi + 0.1), 30, true},
+		{"same position", 0, origin, 1, true},
+	}
+
+	for _, testCase := range testCases {
+		if actual := unit.isInFrontArc(origin, testCase.facing, testCase.other, testCase.arc); actual != testCase.expected {
+			t.Fatalf("%s: expected in front arc to be %t", testCase.label, testCase.expected)
+		}
+	}
+}
+
+func TestUnitRange(t *testing.T) {
+	sim := SetupFakeSim()
+	player := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+	target := sim.Encounter.ActiveTargetUnits[0]
+
+	target.SetPosition(sim, Vector2{})
+	player.SetPosition(sim, Vector2{X: 30, Y: 40})
+	if player.DistanceFromTarget != 50 || player.DistanceTo(sim, target) != 50 {
+		t.Fatalf("Expected the player to be 50 yards from the target, got %f", player.DistanceFromTarget)
+	}
+
+	if numInRange := player.NumEnemiesInRange(sim, 49); numInRange != 0 {
+		t.Fatalf("Expected no enemies within 49 yards, got %d", numInRange)
+	}
+	if numInRange := player.NumEnemiesInRange(sim, 50); numInRange != 1 {
+		t.Fatalf("Expected 1 enemy within 50 yards, got %d", numInRange)
+	}
+
+	// The player keeps its position when its target moves.
+	target.SetPosition(sim, Vector2{X: 30})
+	expectPosition(t, "player", Vector2{X: 30, Y: 40}, player.GetPosition(sim))
+	if player.DistanceFromTarget != 40 {
+		t.Fatalf("Expected the player to be 40 yards from the moved target, got %f", player.DistanceFromTarget)
+	}
+
+	player.FaceUnit(sim, target)
+	if !player.IsInFrontArc(sim, target, 10) {
+		t.Fatalf("Expected the target to be in front of the player after facing it")
+	}
+}
+
+func TestMoveToPosition(t *testing.T) {
+	sim := SetupFakeSim()
+	player := &sim.Raid.Parties[0].Players[0].GetCharacter().Unit
+	target := sim.Encounter.ActiveTargetUnits[0]
+
+	target.SetPosition(sim, Vector2{})
+	player.SetPosition(sim, Vector2{X: 20})
+
+	// Players move at 7 yards per second.
+	player.MoveToPosition(sim, Vector2{X: 6})
+	if !player.Moving || player.movementAction.NextActionAt != time.Second*2 {
+		t.Fatalf("Expected a 2s movement")
+	}
+
+	sim.CurrentTime = time.Second
+	expectPosition(t, "player halfway", Vector2{X: 13}, player.GetPosition(sim))
+	player.UpdatePosition(sim)
+	if player.DistanceFromTarget != 13 {
+		t.Fatalf("Expected the player to be 13 yards from the target halfway, got %f", player.DistanceFromTarget)
+	}
+
+	// Doubling the speed halves the rest of the movement.
+	player.MultiplyMovementSpeed(sim, 2)
+	if player.movementAction.NextActionAt != time.Millisecond*1500 {
+		t.Fatalf("Expected the movement to end at 1.5s, got %s", player.movementAction.NextActionAt)
+	}
+
+	sim.CurrentTime = time.Millisecond * 1500
+	player.FinalizeMovement(sim)
+	if player.Moving {
+		t.Fatalf("Expected the player to stop at the destination")
+	}
+	expectPosition(t, "player at the destination", Vector2{X: 6}, player.GetPosition(sim))
+	if player.DistanceFromTarget != 6 {
+		t.Fatalf("Expected the player to be 6 yards from the target, got %f", player.DistanceFromTarget)
+	}
+
+	// Moving to the current position does nothing.
+	player.MoveToPosition(sim, Vector2{X: 6})
+	if player.Moving {
+		t.Fatalf("Expected no movement to the current position")
+	}
+}
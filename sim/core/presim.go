@@ -70,6 +70,7 @@ func (sim *Simulation) runPresims(request *proto.RaidSimRequest) *proto.RaidSimR
 	presimRequest.SimOptions.Debug = false
 	presimRequest.SimOptions.DebugFirstIteration = false
 	presimRequest.SimOptions.Iterations = numPresimIterations
+	presimRequest.SimOptions.TargetPrecision = nil
 	duration := DurationFromSeconds(presimRequest.Encounter.Duration)
 
 	var lastResult *proto.RaidSimResult
@@ -160,6 +160,35 @@ func (raid *Raid) GetLowestHealthAllyUnit() *Unit {
 	return lowestHealthUnit
 }
 
+// Returns up to count enabled raid members other than exclude, the most
+// injured first when health is being tracked. Used to pick smart heal targets.
+func (raid *Raid) SmartHealTargets(exclude *Unit, count int) []*Unit {
+	if count <= 0 {
+		return nil
+	}
+
+	candidates := make([]*Unit, 0, len(raid.AllPlayerUnits))
+	for _, unit := range raid.AllPlayerUnits {
+		if unit != exclude && unit.IsEnabled() {
+			candidates = append(candidates, unit)
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b *Unit) int {
+		if !a.HasHealthBar() || !b.HasHealthBar() {
+			return 0
+		}
+		if a.CurrentHealthPercent() < b.CurrentHealthPercent() {
+			return -1
+		} else if a.CurrentHealthPercent() > b.CurrentHealthPercent() {
+			return 1
+		}
+		return 0
+	})
+
+	return candidates[:min(count, len(candidates))]
+}
+
 // Makes a new raid.
 func NewRaid(raidConfig *proto.Raid) *Raid {
 	numParties := int(raidConfig.NumActiveParties)
@@ -0,0 +1,731 @@
+package core
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultRaidCompResults = 5
+	defaultRaidCompMaxSims = 50
+
+	// Rough share of raid DPS that one raid buff or debuff category is worth,
+	// used to rank candidates before they are simmed.
+	raidCompCategoryValue = 0.03
+)
+
+// A raid buff or debuff that only needs one provider in the raid.
+type raidCompCategory struct {
+	name  string
+	specs []proto.Spec
+	// Whether the request's own buffs and debuffs already provide it.
+	provided func(raidBuffs *proto.RaidBuffs, debuffs *proto.Debuffs) bool
+	// Adds it to the buffs and debuffs of a comp which provides it. Nil for
+	// cooldowns which the providers cast themselves in raid sims.
+	apply func(raidBuffs *proto.RaidBuffs, debuffs *proto.Debuffs)
+}
+
+var raidCompDeathKnightSpecs = []proto.Spec{proto.Spec_SpecBloodDeathKnight, proto.Spec_SpecFrostDeathKnight, proto.Spec_SpecUnholyDeathKnight}
+var raidCompDruidSpecs = []proto.Spec{proto.Spec_SpecBalanceDruid, proto.Spec_SpecFeralDruid, proto.Spec_SpecGuardianDruid, proto.Spec_SpecRestorationDruid}
+var raidCompHunterSpecs = []proto.Spec{proto.Spec_SpecBeastMasteryHunter, proto.Spec_SpecMarksmanshipHunter, proto.Spec_SpecSurvivalHunter}
+var raidCompMageSpecs = []proto.Spec{proto.Spec_SpecArcaneMage, proto.Spec_SpecFireMage, proto.Spec_SpecFrostMage}
+var raidCompMonkSpecs = []proto.Spec{proto.Spec_SpecBrewmasterMonk, proto.Spec_SpecMistweaverMonk, proto.Spec_SpecWindwalkerMonk}
+var raidCompPaladinSpecs = []proto.Spec{proto.Spec_SpecHolyPaladin, proto.Spec_SpecProtectionPaladin, proto.Spec_SpecRetributionPaladin}
+var raidCompPriestSpecs = []proto.Spec{proto.Spec_SpecDisciplinePriest, proto.Spec_SpecHolyPriest, proto.Spec_SpecShadowPriest}
+var raidCompRogueSpecs = []proto.Spec{proto.Spec_SpecAssassinationRogue, proto.Spec_SpecCombatRogue, proto.Spec_SpecSubtletyRogue}
+var raidCompShamanSpecs = []proto.Spec{proto.Spec_SpecElementalShaman, proto.Spec_SpecEnhancementShaman, proto.Spec_SpecRestorationShaman}
+var raidCompWarlockSpecs = []proto.Spec{proto.Spec_SpecAfflictionWarlock, proto.Spec_SpecDemonologyWarlock, proto.Spec_SpecDestructionWarlock}
+var raidCompWarriorSpecs = []proto.Spec{proto.Spec_SpecArmsWarrior, proto.Spec_SpecFuryWarrior, proto.Spec_SpecProtectionWarrior}
+
+// Hunter pets can cover most buff categories, but which one depends on the
+// pet, so hunters only count for Trueshot Aura here.
+var raidCompCategories = []raidCompCategory{
+	{
+		name:  "Attack Power",
+		specs: slices.Concat(raidCompDeathKnightSpecs, raidCompHunterSpecs, raidCompWarriorSpecs),
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.HornOfWinter || raidBuffs.TrueshotAura || raidBuffs.BattleShout
+		},
+		apply: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+			raidBuffs.BattleShout = true
+		},
+	},
+	{
+		name:  "Attack Speed",
+		specs: slices.Concat([]proto.Spec{proto.Spec_SpecFrostDeathKnight, proto.Spec_SpecUnholyDeathKnight, proto.Spec_SpecEnhancementShaman}, raidCompRogueSpecs),
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.UnholyAura || raidBuffs.CacklingHowl || raidBuffs.SerpentsSwiftness || raidBuffs.SwiftbladesCunning || raidBuffs.UnleashedRage
+		},
+		apply: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+			raidBuffs.UnholyAura = true
+		},
+	},
+	{
+		name:  "Spell Power",
+		specs: slices.Concat(raidCompMageSpecs, raidCompShamanSpecs, raidCompWarlockSpecs),
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.StillWater || raidBuffs.ArcaneBrilliance || raidBuffs.BurningWrath || raidBuffs.DarkIntent
+		},
+		apply: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+			raidBuffs.BurningWrath = true
+		},
+	},
+	{
+		name:  "Spell Haste",
+		specs: []proto.Spec{proto.Spec_SpecBalanceDruid, proto.Spec_SpecShadowPriest, proto.Spec_SpecElementalShaman},
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.MoonkinAura || raidBuffs.MindQuickening || raidBuffs.ShadowForm || raidBuffs.ElementalOath
+		},
+		apply: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+			raidBuffs.MoonkinAura = true
+		},
+	},
+	{
+		name:  "Critical Strike",
+		specs: slices.Concat([]proto.Spec{proto.Spec_SpecFeralDruid, proto.Spec_SpecGuardianDruid, proto.Spec_SpecWindwalkerMonk}, raidCompMageSpecs),
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.LeaderOfThePack || raidBuffs.TerrifyingRoar || raidBuffs.FuriousHowl || raidBuffs.LegacyOfTheWhiteTiger || raidBuffs.ArcaneBrilliance
+		},
+		apply: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+			raidBuffs.LeaderOfThePack = true
+		},
+	},
+	{
+		name:  "Mastery",
+		specs: slices.Concat(raidCompPaladinSpecs, raidCompShamanSpecs),
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.RoarOfCourage || raidBuffs.SpiritBeastBlessing || raidBuffs.BlessingOfMight || raidBuffs.GraceOfAir
+		},
+		apply: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+			raidBuffs.GraceOfAir = true
+		},
+	},
+	{
+		name:  "Stats",
+		specs: slices.Concat(raidCompDruidSpecs, raidCompMonkSpecs, raidCompPaladinSpecs),
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.MarkOfTheWild || raidBuffs.EmbraceOfTheShaleSpider || raidBuffs.LegacyOfTheEmperor || raidBuffs.BlessingOfKings
+		},
+		apply: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+			raidBuffs.MarkOfTheWild = true
+		},
+	},
+	{
+		name:  "Stamina",
+		specs: slices.Concat(raidCompPriestSpecs, raidCompWarlockSpecs, raidCompWarriorSpecs),
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.QirajiFortitude || raidBuffs.PowerWordFortitude || raidBuffs.CommandingShout
+		},
+		apply: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+			raidBuffs.PowerWordFortitude = true
+		},
+	},
+	{
+		// Time Warp isn't modelled, so only shamans count.
+		name:  "Bloodlust",
+		specs: raidCompShamanSpecs,
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.Bloodlust
+		},
+	},
+	{
+		name:  "Stormlash Totem",
+		specs: raidCompShamanSpecs,
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.StormlashTotemCount > 0
+		},
+	},
+	{
+		name:  "Skull Banner",
+		specs: raidCompWarriorSpecs,
+		provided: func(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) bool {
+			return raidBuffs.SkullBannerCount > 0
+		},
+	},
+	{
+		name:  "Weakened Armor",
+		specs: slices.Concat([]proto.Spec{proto.Spec_SpecFeralDruid, proto.Spec_SpecGuardianDruid}, raidCompRogueSpecs, raidCompWarriorSpecs),
+		provided: func(_ *proto.RaidBuffs, debuffs *proto.Debuffs) bool {
+			return debuffs.WeakenedArmor
+		},
+		apply: func(_ *proto.RaidBuffs, debuffs *proto.Debuffs) {
+			debuffs.WeakenedArmor = true
+		},
+	},
+	{
+		name:  "Physical Vulnerability",
+		specs: []proto.Spec{proto.Spec_SpecFrostDeathKnight, proto.Spec_SpecUnholyDeathKnight, proto.Spec_SpecRetributionPaladin, proto.Spec_SpecArmsWarrior, proto.Spec_SpecFuryWarrior},
+		provided: func(_ *proto.RaidBuffs, debuffs *proto.Debuffs) bool {
+			return debuffs.PhysicalVulnerability
+		},
+		apply: func(_ *proto.RaidBuffs, debuffs *proto.Debuffs) {
+			debuffs.PhysicalVulnerability = true
+		},
+	},
+	{
+		name:  "Spell Damage Taken",
+		specs: slices.Concat(raidCompRogueSpecs, raidCompWarlockSpecs),
+		provided: func(_ *proto.RaidBuffs, debuffs *proto.Debuffs) bool {
+			return debuffs.FireBreath || debuffs.LightningBreath || debuffs.MasterPoisoner || debuffs.CurseOfElements
+		},
+		apply: func(_ *proto.RaidBuffs, debuffs *proto.Debuffs) {
+			debuffs.CurseOfElements = true
+		},
+	},
+	{
+		name:  "Weakened Blows",
+		specs: slices.Concat([]proto.Spec{proto.Spec_SpecBloodDeathKnight, proto.Spec_SpecFeralDruid, proto.Spec_SpecGuardianDruid, proto.Spec_SpecBrewmasterMonk, proto.Spec_SpecProtectionPaladin, proto.Spec_SpecRetributionPaladin}, raidCompShamanSpecs, raidCompWarlockSpecs, raidCompWarriorSpecs),
+		provided: func(_ *proto.RaidBuffs, debuffs *proto.Debuffs) bool {
+			return debuffs.WeakenedBlows
+		},
+		apply: func(_ *proto.RaidBuffs, debuffs *proto.Debuffs) {
+			debuffs.WeakenedBlows = true
+		},
+	},
+}
+
+var raidCompHealerSpecs = []proto.Spec{proto.Spec_SpecRestorationDruid, proto.Spec_SpecMistweaverMonk, proto.Spec_SpecHolyPaladin, proto.Spec_SpecDisciplinePriest, proto.Spec_SpecHolyPriest, proto.Spec_SpecRestorationShaman}
+var raidCompMeleeSpecs = slices.Concat(raidCompDeathKnightSpecs, raidCompRogueSpecs, raidCompWarriorSpecs, []proto.Spec{proto.Spec_SpecFeralDruid, proto.Spec_SpecGuardianDruid, proto.Spec_SpecBrewmasterMonk, proto.Spec_SpecWindwalkerMonk, proto.Spec_SpecProtectionPaladin, proto.Spec_SpecRetributionPaladin, proto.Spec_SpecEnhancementShaman})
+
+// Bit set of the indices into raidCompCategories.
+type raidCompCoverage uint32
+
+func (coverage raidCompCoverage) count() int {
+	n := 0
+	for ; coverage != 0; coverage &= coverage - 1 {
+		n++
+	}
+	return n
+}
+
+func (coverage raidCompCoverage) names(covered bool) []string {
+	var names []string
+	for i, category := range raidCompCategories {
+		if (coverage&(1<<i) != 0) == covered {
+			names = append(names, category.name)
+		}
+	}
+	return names
+}
+
+type raidCompOptimizer struct {
+	request  *proto.RaidCompOptimizeRequest
+	specs    []proto.Spec
+	coverage []raidCompCoverage
+	base     raidCompCoverage
+	// Pool players who are in every comp.
+	locked []bool
+	isTank []bool
+	// DPS of each pool player simmed alone.
+	screenDps []float64
+	meanDps   float64
+
+	simOptions *proto.SimOptions
+	evaluated  map[string]*proto.RaidCompOptimizeComp
+	simsDone   int32
+}
+
+func newRaidCompOptimizer(request *proto.RaidCompOptimizeRequest) (*raidCompOptimizer, error) {
+	if request.SimOptions == nil {
+		return nil, errors.New("raid comp request needs sim options")
+	}
+	if request.RaidSize != 10 && request.RaidSize != 25 {
+		return nil, fmt.Errorf("raid size must be 10 or 25, not %d", request.RaidSize)
+	}
+	if len(request.Players) < int(request.RaidSize) {
+		return nil, fmt.Errorf("%d players are not enough for a %d player raid", len(request.Players), request.RaidSize)
+	}
+
+	rco := &raidCompOptimizer{
+		request:   request,
+		specs:     make([]proto.Spec, len(request.Players)),
+		coverage:  make([]raidCompCoverage, len(request.Players)),
+		locked:    make([]bool, len(request.Players)),
+		isTank:    make([]bool, len(request.Players)),
+		evaluated: make(map[string]*proto.RaidCompOptimizeComp),
+	}
+
+	raidBuffs := request.RaidBuffs
+	if raidBuffs == nil {
+		raidBuffs = &proto.RaidBuffs{}
+	}
+	debuffs := request.Debuffs
+	if debuffs == nil {
+		debuffs = &proto.Debuffs{}
+	}
+	for i, category := range raidCompCategories {
+		if category.provided(raidBuffs, debuffs) {
+			rco.base |= 1 << i
+		}
+	}
+
+	for idx, player := range request.Players {
+		if player == nil || player.GetSpec() == nil {
+			return nil, fmt.Errorf("player %d has no spec", idx)
+		}
+		rco.specs[idx] = PlayerProtoToSpec(player)
+		for i, category := range raidCompCategories {
+			if slices.Contains(category.specs, rco.specs[idx]) {
+				rco.coverage[idx] |= 1 << i
+			}
+		}
+	}
+
+	numLocked := 0
+	lock := func(indices []int32, isTank bool) error {
+		for _, idx := range indices {
+			if idx < 0 || int(idx) >= len(request.Players) {
+				return fmt.Errorf("invalid player index: %d", idx)
+			}
+			if !rco.locked[idx] {
+				numLocked++
+			}
+			rco.locked[idx] = true
+			rco.isTank[idx] = rco.isTank[idx] || isTank
+		}
+		return nil
+	}
+	if err := lock(request.RequiredPlayers, false); err != nil {
+		return nil, err
+	}
+	if err := lock(request.TankPlayers, true); err != nil {
+		return nil, err
+	}
+	if numLocked > int(request.RaidSize) {
+		return nil, fmt.Errorf("%d required players don't fit in a %d player raid", numLocked, request.RaidSize)
+	}
+
+	rco.simOptions = googleProto.Clone(request.SimOptions).(*proto.SimOptions)
+	// Every comp uses the same seed so that their random numbers line up as
+	// much as possible.
+	if rco.simOptions.RandomSeed == 0 {
+		rco.simOptions.RandomSeed = time.Now().UnixNano()
+	}
+	rco.simOptions.UseLabeledRands = true
+
+	return rco, nil
+}
+
+func (rco *raidCompOptimizer) compCoverage(members []int32) raidCompCoverage {
+	coverage := rco.base
+	for _, idx := range members {
+		coverage |= rco.coverage[idx]
+	}
+	return coverage
+}
+
+// Value of a player joining a comp with the given coverage, before simming.
+func (rco *raidCompOptimizer) candidateScore(idx int32, coverage raidCompCoverage) float64 {
+	newCategories := (rco.coverage[idx] &^ coverage).count()
+	return rco.screenDps[idx] + float64(newCategories)*raidCompCategoryValue*float64(rco.request.RaidSize)*rco.meanDps
+}
+
+// Fills the raid with the best candidates one at a time, starting from the
+// locked players.
+func (rco *raidCompOptimizer) greedyComp() []int32 {
+	var members []int32
+	for idx, locked := range rco.locked {
+		if locked {
+			members = append(members, int32(idx))
+		}
+	}
+
+	for len(members) < int(rco.request.RaidSize) {
+		coverage := rco.compCoverage(members)
+		best := int32(-1)
+		bestScore := 0.0
+		for idx := range rco.request.Players {
+			if slices.Contains(members, int32(idx)) {
+				continue
+			}
+			if score := rco.candidateScore(int32(idx), coverage); best == -1 || score > bestScore {
+				best = int32(idx)
+				bestScore = score
+			}
+		}
+		members = append(members, best)
+	}
+	return members
+}
+
+// Splits a comp into parties of 5, grouping tanks, melee, ranged and healers.
+func (rco *raidCompOptimizer) partiesForComp(members []int32) [][]int32 {
+	roleOrder := func(idx int32) int {
+		switch {
+		case rco.isTank[idx]:
+			return 0
+		case slices.Contains(raidCompHealerSpecs, rco.specs[idx]):
+			return 3
+		case slices.Contains(raidCompMeleeSpecs, rco.specs[idx]):
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	sorted := slices.Clone(members)
+	slices.SortStableFunc(sorted, func(a, b int32) int {
+		return cmp.Or(cmp.Compare(roleOrder(a), roleOrder(b)), cmp.Compare(a, b))
+	})
+
+	parties := make([][]int32, 0, len(sorted)/5)
+	for start := 0; start < len(sorted); start += 5 {
+		parties = append(parties, sorted[start:min(start+5, len(sorted))])
+	}
+	return parties
+}
+
+func raidCompKey(parties [][]int32) string {
+	partyKeys := make([]string, len(parties))
+	for i, party := range parties {
+		sorted := slices.Sorted(slices.Values(party))
+		partyKeys[i] = fmt.Sprint(sorted)
+	}
+	slices.Sort(partyKeys)
+	return strings.Join(partyKeys, "|")
+}
+
+// Points Tricks of the Trade and Power Infusion at the strongest player in the
+// raid, since the indices saved with each player don't match the comp.
+func setRaidCompBuffTarget(player *proto.Player, target *proto.UnitReference) {
+	rogueOptions := func(classOptions **proto.RogueOptions) {
+		if *classOptions == nil {
+			*classOptions = &proto.RogueOptions{}
+		}
+		(*classOptions).TricksOfTheTradeTarget = target
+	}
+
+	switch spec := player.Spec.(type) {
+	case *proto.Player_AssassinationRogue:
+		if spec.AssassinationRogue.Options == nil {
+			spec.AssassinationRogue.Options = &proto.AssassinationRogue_Options{}
+		}
+		rogueOptions(&spec.AssassinationRogue.Options.ClassOptions)
+	case *proto.Player_CombatRogue:
+		if spec.CombatRogue.Options == nil {
+			spec.CombatRogue.Options = &proto.CombatRogue_Options{}
+		}
+		rogueOptions(&spec.CombatRogue.Options.ClassOptions)
+	case *proto.Player_SubtletyRogue:
+		if spec.SubtletyRogue.Options == nil {
+			spec.SubtletyRogue.Options = &proto.SubtletyRogue_Options{}
+		}
+		rogueOptions(&spec.SubtletyRogue.Options.ClassOptions)
+	case *proto.Player_DisciplinePriest:
+		if spec.DisciplinePriest.Options == nil {
+			spec.DisciplinePriest.Options = &proto.DisciplinePriest_Options{}
+		}
+		spec.DisciplinePriest.Options.PowerInfusionTarget = target
+	case *proto.Player_ShadowPriest:
+		if spec.ShadowPriest.Options == nil {
+			spec.ShadowPriest.Options = &proto.ShadowPriest_Options{}
+		}
+		spec.ShadowPriest.Options.PowerInfusionTarget = target
+	}
+}
+
+// Buffs and debuffs of a comp, the request's own plus those its members provide.
+func (rco *raidCompOptimizer) compBuffs(members []int32) (*proto.RaidBuffs, *proto.Debuffs) {
+	raidBuffs := &proto.RaidBuffs{}
+	if rco.request.RaidBuffs != nil {
+		raidBuffs = googleProto.Clone(rco.request.RaidBuffs).(*proto.RaidBuffs)
+	}
+	debuffs := &proto.Debuffs{}
+	if rco.request.Debuffs != nil {
+		debuffs = googleProto.Clone(rco.request.Debuffs).(*proto.Debuffs)
+	}
+
+	coverage := rco.compCoverage(members)
+	for i, category := range raidCompCategories {
+		if coverage&(1<<i) != 0 && category.apply != nil {
+			category.apply(raidBuffs, debuffs)
+		}
+	}
+	return raidBuffs, debuffs
+}
+
+func (rco *raidCompOptimizer) raidSimRequest(parties [][]int32) *proto.RaidSimRequest {
+	var members []int32
+	for _, party := range parties {
+		members = append(members, party...)
+	}
+	raidBuffs, debuffs := rco.compBuffs(members)
+
+	raid := &proto.Raid{
+		NumActiveParties: int32(len(parties)),
+		Buffs:            raidBuffs,
+		Debuffs:          debuffs,
+	}
+
+	bestTarget := -1
+	bestTargetIdx := int32(-1)
+	for partyIdx, party := range parties {
+		protoParty := &proto.Party{Buffs: &proto.PartyBuffs{}}
+		for slot, idx := range party {
+			raidIndex := partyIdx*5 + slot
+			protoParty.Players = append(protoParty.Players, googleProto.Clone(rco.request.Players[idx]).(*proto.Player))
+			if rco.isTank[idx] {
+				raid.Tanks = append(raid.Tanks, &proto.UnitReference{Type: proto.UnitReference_Player, Index: int32(raidIndex)})
+			} else if bestTarget == -1 || rco.screenDps[idx] > rco.screenDps[bestTargetIdx] {
+				bestTarget = raidIndex
+				bestTargetIdx = idx
+			}
+		}
+		raid.Parties = append(raid.Parties, protoParty)
+	}
+
+	if bestTarget != -1 {
+		for partyIdx, party := range raid.Parties {
+			for slot, player := range party.Players {
+				if partyIdx*5+slot != bestTarget {
+					setRaidCompBuffTarget(player, &proto.UnitReference{Type: proto.UnitReference_Player, Index: int32(bestTarget)})
+				}
+			}
+		}
+	}
+
+	return &proto.RaidSimRequest{
+		Raid:       raid,
+		Encounter:  rco.request.Encounter,
+		SimOptions: rco.simOptions,
+	}
+}
+
+// Finds the best comps for a raid out of a pool of players. The pool is
+// ranked with single player sims, a first comp is picked greedily by DPS and
+// buff coverage, and then improved by swapping players in and out of the comp
+// or between parties, simming the raid for each swap.
+func runRaidCompOptimize(request *proto.RaidCompOptimizeRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidCompOptimizeResult {
+	errorResult := func(err error) *proto.RaidCompOptimizeResult {
+		return &proto.RaidCompOptimizeResult{Error: &proto.ErrorOutcome{Message: err.Error()}}
+	}
+
+	rco, err := newRaidCompOptimizer(request)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	maxSims := request.MaxSims
+	if maxSims <= 0 {
+		maxSims = defaultRaidCompMaxSims
+	}
+	screeningIterations := request.ScreeningIterations
+	if screeningIterations <= 0 {
+		screeningIterations = max(rco.simOptions.Iterations/10, 1)
+	}
+
+	var iterationsTotal int32 = screeningIterations*int32(len(request.Players)) + rco.simOptions.Iterations*maxSims
+	var iterationsDone int32 = 0
+	var simsTotal int32 = int32(len(request.Players)) + maxSims
+	var simsCompleted int32 = 0
+
+	waitForResult := func(srcProgressChannel chan *proto.ProgressMetrics) *proto.RaidSimResult {
+		var lastCompleted int32 = 0
+		for metrics := range srcProgressChannel {
+			iterationsDone += metrics.CompletedIterations - lastCompleted
+			lastCompleted = metrics.CompletedIterations
+
+			if progress != nil {
+				progress <- &proto.ProgressMetrics{
+					TotalIterations:     iterationsTotal,
+					CompletedIterations: iterationsDone,
+					CompletedSims:       simsCompleted,
+					TotalSims:           simsTotal,
+				}
+			}
+
+			if metrics.FinalRaidResult != nil {
+				simsCompleted++
+				return metrics.FinalRaidResult
+			}
+		}
+		return nil
+	}
+
+	simFunc := runSimConcurrent
+	// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
+	if IsRunningInWasm() {
+		simFunc = RunSim
+	}
+	runRaidSim := func(simRequest *proto.RaidSimRequest) (*proto.RaidSimResult, error) {
+		simProgress := make(chan *proto.ProgressMetrics, 100)
+		go simFunc(simRequest, simProgress, signals)
+		simResult := waitForResult(simProgress)
+		if simResult.Error != nil {
+			return nil, errors.New(simResult.Error.Message)
+		}
+		return simResult, nil
+	}
+
+	// Rank the pool.
+	rco.screenDps = make([]float64, len(request.Players))
+	screenOptions := googleProto.Clone(rco.simOptions).(*proto.SimOptions)
+	screenOptions.Iterations = screeningIterations
+	screenOptions.TargetPrecision = nil
+	for idx, player := range request.Players {
+		raidProto := SinglePlayerRaidProto(googleProto.Clone(player).(*proto.Player), &proto.PartyBuffs{}, request.RaidBuffs, request.Debuffs)
+		if rco.isTank[idx] {
+			raidProto.Tanks = []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}}
+		}
+		simResult, err := runRaidSim(&proto.RaidSimRequest{
+			Raid:       raidProto,
+			Encounter:  request.Encounter,
+			SimOptions: screenOptions,
+		})
+		if err != nil {
+			return errorResult(fmt.Errorf("player %d: %s", idx, err))
+		}
+		rco.screenDps[idx] = simResult.RaidMetrics.Dps.Avg
+		rco.meanDps += simResult.RaidMetrics.Dps.Avg / float64(len(request.Players))
+	}
+
+	evaluate := func(parties [][]int32) (*proto.RaidCompOptimizeComp, error) {
+		key := raidCompKey(parties)
+		if comp, ok := rco.evaluated[key]; ok {
+			return comp, nil
+		}
+
+		simResult, err := runRaidSim(rco.raidSimRequest(parties))
+		if err != nil {
+			return nil, err
+		}
+		rco.simsDone++
+
+		var members []int32
+		comp := &proto.RaidCompOptimizeComp{
+			RaidDps:            simResult.RaidMetrics.Dps.Avg,
+			RaidDpsCiHalfWidth: ciHalfWidth(simResult.RaidMetrics.Dps.Stdev, int(simResult.IterationsDone)),
+		}
+		for _, party := range parties {
+			comp.Parties = append(comp.Parties, &proto.RaidCompOptimizeParty{Players: slices.Clone(party)})
+			members = append(members, party...)
+		}
+		coverage := rco.compCoverage(members)
+		comp.Covered = coverage.names(true)
+		comp.Missing = coverage.names(false)
+
+		rco.evaluated[key] = comp
+		return comp, nil
+	}
+
+	current := rco.partiesForComp(rco.greedyComp())
+	currentComp, err := evaluate(current)
+	if err != nil {
+		return errorResult(err)
+	}
+
+	// Hill climb, taking the first swap that improves raid DPS.
+	for rco.simsDone < maxSims && !signals.Abort.IsTriggered() {
+		improved := false
+		for _, candidate := range rco.neighbours(current) {
+			if rco.simsDone >= maxSims || signals.Abort.IsTriggered() {
+				break
+			}
+			if _, ok := rco.evaluated[raidCompKey(candidate)]; ok {
+				continue
+			}
+			comp, err := evaluate(candidate)
+			if err != nil {
+				return errorResult(err)
+			}
+			if comp.RaidDps > currentComp.RaidDps {
+				current = candidate
+				currentComp = comp
+				improved = true
+				break
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	result := &proto.RaidCompOptimizeResult{SimsDone: rco.simsDone}
+	for _, comp := range rco.evaluated {
+		result.Comps = append(result.Comps, comp)
+	}
+	slices.SortFunc(result.Comps, func(a, b *proto.RaidCompOptimizeComp) int {
+		return cmp.Compare(b.RaidDps, a.RaidDps)
+	})
+	numResults := int(request.NumResults)
+	if numResults <= 0 {
+		numResults = defaultRaidCompResults
+	}
+	result.Comps = result.Comps[:min(numResults, len(result.Comps))]
+	return result
+}
+
+// Comps one swap away from the given one, most promising first. Swaps with the
+// bench that lose buff coverage are pruned. Swaps between parties come last,
+// since few effects in this expansion are party-wide.
+func (rco *raidCompOptimizer) neighbours(parties [][]int32) [][][]int32 {
+	var members []int32
+	for _, party := range parties {
+		members = append(members, party...)
+	}
+	coverage := rco.compCoverage(members)
+
+	type benchSwap struct {
+		out   int32
+		in    int32
+		score float64
+	}
+	var swaps []benchSwap
+	for _, out := range members {
+		if rco.locked[out] {
+			continue
+		}
+		remaining := slices.DeleteFunc(slices.Clone(members), func(idx int32) bool { return idx == out })
+		remainingCoverage := rco.compCoverage(remaining)
+		for in := range rco.request.Players {
+			if slices.Contains(members, int32(in)) {
+				continue
+			}
+			if (remainingCoverage | rco.coverage[in]).count() < coverage.count() {
+				continue
+			}
+			swaps = append(swaps, benchSwap{
+				out:   out,
+				in:    int32(in),
+				score: rco.candidateScore(int32(in), remainingCoverage) - rco.candidateScore(out, remainingCoverage),
+			})
+		}
+	}
+	slices.SortStableFunc(swaps, func(a, b benchSwap) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	var candidates [][][]int32
+	for _, swap := range swaps {
+		newMembers := slices.Clone(members)
+		newMembers[slices.Index(newMembers, swap.out)] = swap.in
+		candidates = append(candidates, rco.partiesForComp(newMembers))
+	}
+
+	for partyA := range parties {
+		for partyB := partyA + 1; partyB < len(parties); partyB++ {
+			for slotA := range parties[partyA] {
+				for slotB := range parties[partyB] {
+					if rco.specs[parties[partyA][slotA]] == rco.specs[parties[partyB][slotB]] {
+						continue
+					}
+					candidate := make([][]int32, len(parties))
+					for i, party := range parties {
+						candidate[i] = slices.Clone(party)
+					}
+					candidate[partyA][slotA], candidate[partyB][slotB] = candidate[partyB][slotB], candidate[partyA][slotA]
+					candidates = append(candidates, candidate)
+				}
+			}
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+const defaultTargetDummyHealth = 300000
+
+// Sets up the encounter's raid damage profile, which lets the primary target
+// periodically damage the whole raid and spike random raid members so healers
+// have something to heal. Target dummies are given a health bar so they take
+// part as well.
+func (env *Environment) applyRaidDamageProfile(profile *proto.RaidDamageProfile) {
+	if profile == nil || len(env.Encounter.AllTargets) == 0 {
+		return
+	}
+	if profile.PulseDamage <= 0 && profile.SpikeDamage <= 0 {
+		return
+	}
+
+	dummyHealth := profile.TargetDummyHealth
+	if dummyHealth <= 0 {
+		dummyHealth = defaultTargetDummyHealth
+	}
+	for _, party := range env.Raid.Parties {
+		for _, player := range party.Players {
+			if dummy, ok := player.(*TargetDummy); ok {
+				dummy.AddStat(stats.Health, dummyHealth)
+				dummy.EnableHealthBar()
+				dummy.trackChanceOfDeath(nil)
+			}
+		}
+	}
+
+	target := &env.Encounter.AllTargets[0].Unit
+	variation := Clamp(profile.DamageVariation, 0, 1)
+	rollDamage := func(sim *Simulation, damage float64) float64 {
+		return damage * (1 + variation*(2*sim.RandomFloat("Raid Damage Variation")-1))
+	}
+
+	registerRaidDamageSpell := func(tag int32, applyEffects ApplySpellResults) *Spell {
+		return target.RegisterSpell(SpellConfig{
+			ActionID:    ActionID{OtherID: proto.OtherAction_OtherActionRaidDamage, Tag: tag},
+			SpellSchool: SpellSchoolFromProto(profile.School),
+			ProcMask:    ProcMaskSpellDamage,
+			Flags:       SpellFlagIgnoreAttackerModifiers | SpellFlagNoOnCastComplete,
+
+			DamageMultiplier: 1,
+
+			ApplyEffects: applyEffects,
+		})
+	}
+
+	if profile.PulseDamage > 0 && profile.PulseInterval > 0 {
+		pulse := registerRaidDamageSpell(1, func(sim *Simulation, _ *Unit, spell *Spell) {
+			for _, unit := range sim.Raid.AllPlayerUnits {
+				if unit.IsActive() && unit.HasHealthBar() {
+					spell.CalcAndDealDamage(sim, unit, rollDamage(sim, profile.PulseDamage), spell.OutcomeAlwaysHit)
+				}
+			}
+		})
+
+		target.RegisterResetEffect(func(sim *Simulation) {
+			StartPeriodicAction(sim, PeriodicActionOptions{
+				Period: DurationFromSeconds(profile.PulseInterval),
+				OnAction: func(sim *Simulation) {
+					pulse.Cast(sim, sim.Raid.AllPlayerUnits[0])
+				},
+			})
+		})
+	}
+
+	if profile.SpikeDamage > 0 && profile.SpikeInterval > 0 {
+		spike := registerRaidDamageSpell(2, func(sim *Simulation, spikeTarget *Unit, spell *Spell) {
+			spell.CalcAndDealDamage(sim, spikeTarget, rollDamage(sim, profile.SpikeDamage), spell.OutcomeAlwaysHit)
+		})
+		nextSpikeIn := func(sim *Simulation) time.Duration {
+			return DurationFromSeconds(profile.SpikeInterval * (0.5 + sim.RandomFloat("Raid Damage Spike Interval")))
+		}
+
+		target.RegisterResetEffect(func(sim *Simulation) {
+			pa := &PendingAction{
+				NextActionAt: nextSpikeIn(sim),
+			}
+			pa.OnAction = func(sim *Simulation) {
+				if spikeTarget := randomLivingRaidMember(sim); spikeTarget != nil {
+					spike.Cast(sim, spikeTarget)
+				}
+				pa.NextActionAt = sim.CurrentTime + nextSpikeIn(sim)
+				sim.AddPendingAction(pa)
+			}
+			sim.AddPendingAction(pa)
+		})
+	}
+}
+
+func randomLivingRaidMember(sim *Simulation) *Unit {
+	var candidates []*Unit
+	for _, unit := range sim.Raid.AllPlayerUnits {
+		if unit.IsActive() && unit.HasHealthBar() {
+			candidates = append(candidates, unit)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return candidates[int(sim.RandomFloat("Raid Damage Spike Target")*float64(len(candidates)))%len(candidates)]
+}
@@ -0,0 +1,182 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+// Sets up a sim with a caster and two target dummies taking raid damage.
+func setupRaidDamageSim(profile *proto.RaidDamageProfile) *Simulation {
+	return NewSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{RandomSeed: 101},
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				{
+					Players: []*proto.Player{
+						{
+							Name:      "Caster",
+							Class:     proto.Class_ClassShaman,
+							Buffs:     &proto.IndividualBuffs{},
+							Spec:      &proto.Player_ElementalShaman{},
+							Equipment: &proto.EquipmentSpec{},
+						},
+					},
+					Buffs: &proto.PartyBuffs{},
+				},
+			},
+			TargetDummies: 2,
+		},
+		Encounter: &proto.Encounter{
+			Duration: 60,
+			Targets: []*proto.Target{
+				{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+			},
+			RaidDamage: profile,
+		},
+	}, simsignals.CreateSignals())
+}
+
+// Registers a heal on the caster which always heals for exactly the base
+// amount it is given.
+func registerTestHeal(sim *Simulation) *Spell {
+	caster := sim.Raid.AllPlayerUnits[0]
+	heal := caster.RegisterSpell(SpellConfig{
+		ActionID:         ActionID{SpellID: 43},
+		SpellSchool:      SpellSchoolNature,
+		ProcMask:         ProcMaskSpellHealing,
+		Flags:            SpellFlagHelpful,
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+	})
+	heal.finalize()
+	return heal
+}
+
+func TestRaidDamageDrainsHealth(t *testing.T) {
+	sim := setupRaidDamageSim(&proto.RaidDamageProfile{
+		PulseDamage:       1000,
+		PulseInterval:     2,
+		SpikeDamage:       5000,
+		SpikeInterval:     5,
+		TargetDummyHealth: 1_000_000,
+	})
+	sim.Reset()
+	sim.PrePull()
+
+	dummies := sim.Raid.GetTargetDummies()
+	if len(dummies) != 2 {
+		t.Fatalf("Expected 2 target dummies, got %d", len(dummies))
+	}
+	for _, dummy := range dummies {
+		if !dummy.HasHealthBar() || dummy.MaxHealth() < 1_000_000 {
+			t.Fatalf("Expected the raid damage profile to give %s a health bar", dummy.Label)
+		}
+		if dummy.CurrentHealth() != dummy.MaxHealth() {
+			t.Fatalf("Expected %s to start at full health", dummy.Label)
+		}
+	}
+
+	for sim.CurrentTime < time.Second*20 {
+		sim.Step()
+	}
+
+	// Pulses every 2s hit everyone, and spikes every 2.5s to 7.5s hit one
+	// random raid member.
+	target := sim.Encounter.AllTargetUnits[0]
+	pulse := target.GetSpell(ActionID{OtherID: proto.OtherAction_OtherActionRaidDamage, Tag: 1})
+	spike := target.GetSpell(ActionID{OtherID: proto.OtherAction_OtherActionRaidDamage, Tag: 2})
+	numSpikes := 0
+	for _, unit := range sim.Raid.AllPlayerUnits {
+		numSpikes += int(spike.SpellMetrics[unit.UnitIndex].Hits)
+	}
+	if numSpikes < 2 || numSpikes > 9 {
+		t.Fatalf("Expected 2 to 9 spikes in 20s, got %d", numSpikes)
+	}
+
+	for _, dummy := range dummies {
+		numPulses := pulse.SpellMetrics[dummy.UnitIndex].Hits
+		if numPulses < 10 || numPulses > 11 {
+			t.Fatalf("Expected %s to be hit by 10 pulses in 20s, got %d", dummy.Label, numPulses)
+		}
+
+		expectedLost := 1000*float64(numPulses) + 5000*float64(spike.SpellMetrics[dummy.UnitIndex].Hits)
+		if lost := dummy.MaxHealth() - dummy.CurrentHealth(); lost != expectedLost {
+			t.Fatalf("Expected %s to lose %0.f health, lost %0.f", dummy.Label, expectedLost, lost)
+		}
+	}
+}
+
+func TestOverhealIsNotEffectiveHealing(t *testing.T) {
+	sim := setupRaidDamageSim(&proto.RaidDamageProfile{
+		PulseDamage:       1000,
+		PulseInterval:     2,
+		TargetDummyHealth: 1_000_000,
+	})
+	heal := registerTestHeal(sim)
+	sim.Reset()
+	sim.PrePull()
+
+	dummy := sim.Raid.GetTargetDummies()[0]
+	healthMetrics := heal.HealthMetrics(&dummy.Unit)
+
+	// Only the 3000 missing health of the 5000 heal is effective.
+	dummy.RemoveHealth(sim, 3000)
+	heal.CalcAndDealHealing(sim, &dummy.Unit, 5000, heal.OutcomeAlwaysHit)
+	spellMetrics := heal.SpellMetrics[dummy.UnitIndex]
+	if spellMetrics.TotalHealing != 5000 || spellMetrics.TotalOverhealing != 2000 {
+		t.Fatalf("Expected 5000 healing with 2000 overhealing, got %0.f with %0.f", spellMetrics.TotalHealing, spellMetrics.TotalOverhealing)
+	}
+	if healthMetrics.ActualGain != 3000 || healthMetrics.Overheal != 2000 {
+		t.Fatalf("Expected 3000 health gained with 2000 overheal, got %0.f with %0.f", healthMetrics.ActualGain, healthMetrics.Overheal)
+	}
+	if dummy.CurrentHealth() != dummy.MaxHealth() {
+		t.Fatalf("Expected the dummy to be back at full health")
+	}
+
+	// Healing a unit at full health is all overheal.
+	heal.CalcAndDealHealing(sim, &dummy.Unit, 5000, heal.OutcomeAlwaysHit)
+	spellMetrics = heal.SpellMetrics[dummy.UnitIndex]
+	if spellMetrics.TotalHealing != 10000 || spellMetrics.TotalOverhealing != 7000 {
+		t.Fatalf("Expected 10000 healing with 7000 overhealing, got %0.f with %0.f", spellMetrics.TotalHealing, spellMetrics.TotalOverhealing)
+	}
+	if healthMetrics.ActualGain != 3000 || healthMetrics.Overheal != 7000 {
+		t.Fatalf("Expected 3000 health gained with 7000 overheal, got %0.f with %0.f", healthMetrics.ActualGain, healthMetrics.Overheal)
+	}
+}
+
+func TestEffectiveHPSDistribution(t *testing.T) {
+	sim := setupRaidDamageSim(&proto.RaidDamageProfile{
+		PulseDamage:       1000,
+		PulseInterval:     2,
+		TargetDummyHealth: 1_000_000,
+	})
+	heal := registerTestHeal(sim)
+	caster := sim.Raid.AllPlayerUnits[0]
+	dummy := sim.Raid.GetTargetDummies()[0]
+
+	// Each iteration heals 6000 into a dummy missing the given health.
+	for _, missingHealth := range []float64{6000, 3000, 0} {
+		sim.Reset()
+		sim.PrePull()
+		if missingHealth > 0 {
+			dummy.RemoveHealth(sim, missingHealth)
+		}
+		heal.CalcAndDealHealing(sim, &dummy.Unit, 6000, heal.OutcomeAlwaysHit)
+		sim.Cleanup()
+	}
+
+	metrics := caster.Metrics.ToProto()
+	if metrics.Hps.Avg != 100 || metrics.Hps.Stdev != 0 {
+		t.Fatalf("Expected 100 HPS every iteration, got %0.3f +/- %0.3f", metrics.Hps.Avg, metrics.Hps.Stdev)
+	}
+	// 6000, 3000 and 0 effective healing over 60s.
+	if metrics.Ehps.Avg != 50 || metrics.Ehps.Max != 100 || metrics.Ehps.Min != 0 {
+		t.Fatalf("Expected 50 average EHPS between 0 and 100, got %0.3f between %0.3f and %0.3f", metrics.Ehps.Avg, metrics.Ehps.Min, metrics.Ehps.Max)
+	}
+	if !WithinToleranceFloat64(metrics.Ehps.Stdev, 40.825, 0.001) {
+		t.Fatalf("Expected an EHPS standard deviation of 40.825, got %0.3f", metrics.Ehps.Stdev)
+	}
+}
@@ -0,0 +1,406 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/lp"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Branches explored before settling for the best gear found so far.
+const reforgeOptimizerMaxNodes = 5000
+
+// A reforge or gem the optimizer can pick, each is a binary variable of the
+// problem.
+type reforgeChoice struct {
+	variable int
+	slot     proto.ItemSlot
+
+	reforgeID int32
+
+	socket int
+	gemID  int32
+}
+
+// Optimizes the reforges and gems of a player's gear by solving an integer
+// linear program, see OptimizeReforges in api.go.
+//
+// Every reforge and every gem in every socket is a binary variable, scored by
+// the EP of the stats it adds. Socket bonuses are variables which may only be
+// picked if every socket of the item has a matching gem. Caps make the EP of a
+// stat concave and piecewise linear, which is modeled with one continuous
+// variable per breakpoint for the amount of the stat above it.
+type reforgeOptimizer struct {
+	request *proto.ReforgeOptimizeRequest
+	weights stats.Stats
+	gems    []Gem
+	frozen  map[proto.ItemSlot]bool
+
+	problem     lp.Problem
+	choices     []reforgeChoice
+	statsByVar  map[int]stats.Stats
+	cappedStats map[stats.Stat]bool
+}
+
+func optimizeReforges(request *proto.ReforgeOptimizeRequest) (result *proto.ReforgeOptimizeResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.ReforgeOptimizeResult{
+				ErrorResult: fmt.Sprintf("%v", err),
+			}
+		}
+	}()
+
+	if request.Player == nil || request.Player.Equipment == nil {
+		return &proto.ReforgeOptimizeResult{ErrorResult: "request has no player equipment"}
+	}
+	if request.StatWeights == nil {
+		return &proto.ReforgeOptimizeResult{ErrorResult: "request has no stat weights"}
+	}
+
+	optimizer := &reforgeOptimizer{
+		request:     request,
+		weights:     stats.FromProtoArray(request.StatWeights.Stats),
+		frozen:      map[proto.ItemSlot]bool{},
+		statsByVar:  map[int]stats.Stats{},
+		cappedStats: map[stats.Stat]bool{},
+	}
+	for _, slot := range request.FrozenSlots {
+		optimizer.frozen[slot] = true
+	}
+	for _, gemID := range request.GemIds {
+		gem, ok := GemsByID[gemID]
+		if !ok {
+			return &proto.ReforgeOptimizeResult{ErrorResult: fmt.Sprintf("no gem with id: %d", gemID)}
+		}
+		optimizer.gems = append(optimizer.gems, gem)
+	}
+	for _, statCap := range request.StatCaps {
+		optimizer.cappedStats[stats.Stat(statCap.Stat)] = true
+	}
+
+	stripped := optimizer.stripGear()
+	strippedEquipment := ProtoToEquipment(stripped.Equipment)
+	baseStats := stats.FromProtoArray(optimizer.finalStats(stripped).Stats)
+
+	optimizer.addReforges(strippedEquipment)
+	optimizer.addGems(strippedEquipment, stripped.Equipment)
+	optimizer.addMetaRequirements(strippedEquipment)
+	basePenalty, err := optimizer.addCaps(baseStats)
+	if err != nil {
+		return &proto.ReforgeOptimizeResult{ErrorResult: err.Error()}
+	}
+
+	solution, err := optimizer.problem.Solve(reforgeOptimizerMaxNodes)
+	if err != nil {
+		return &proto.ReforgeOptimizeResult{ErrorResult: "no gear satisfies the requirements: " + err.Error()}
+	}
+
+	optimized := googleProto.Clone(stripped).(*proto.Player)
+	for _, choice := range optimizer.choices {
+		if solution.Values[choice.variable] < 0.5 {
+			continue
+		}
+		itemSpec := optimized.Equipment.Items[choice.slot]
+		if choice.gemID != 0 {
+			itemSpec.Gems[choice.socket] = choice.gemID
+		} else {
+			itemSpec.Reforging = choice.reforgeID
+		}
+	}
+
+	result = &proto.ReforgeOptimizeResult{
+		Equipment: optimized.Equipment,
+		Stats:     optimizer.finalStats(optimized),
+		Score:     solution.Objective - basePenalty,
+		Optimal:   solution.Optimal,
+	}
+
+	if request.VerifySimOptions != nil {
+		result.OriginalResult = RunRaidSim(optimizer.simRequest(request.Player))
+		result.OptimizedResult = RunRaidSim(optimizer.simRequest(optimized))
+	}
+	return result
+}
+
+// Returns the player with the reforges and gems which are being optimized
+// removed.
+func (optimizer *reforgeOptimizer) stripGear() *proto.Player {
+	player := googleProto.Clone(optimizer.request.Player).(*proto.Player)
+	equipment := ProtoToEquipment(player.Equipment)
+
+	for slot, item := range equipment {
+		if item.ID == 0 || optimizer.frozen[proto.ItemSlot(slot)] {
+			continue
+		}
+
+		itemSpec := player.Equipment.Items[slot]
+		if !optimizer.request.KeepReforges {
+			itemSpec.Reforging = 0
+		}
+		for socket := 0; socket < numGemSockets(item); socket++ {
+			if len(optimizer.eligibleGems(item, socket)) == 0 {
+				continue
+			}
+			for len(itemSpec.Gems) <= socket {
+				itemSpec.Gems = append(itemSpec.Gems, 0)
+			}
+			itemSpec.Gems[socket] = 0
+		}
+	}
+	return player
+}
+
+func (optimizer *reforgeOptimizer) finalStats(player *proto.Player) *proto.UnitStats {
+	encounter := optimizer.request.Encounter
+	if encounter == nil {
+		encounter = &proto.Encounter{}
+	}
+	raid := SinglePlayerRaidProto(googleProto.Clone(player).(*proto.Player), optimizer.request.PartyBuffs, optimizer.request.RaidBuffs, optimizer.request.Debuffs)
+	_, raidStats, _ := NewEnvironment(raid, encounter, true)
+	return raidStats.Parties[0].Players[0].FinalStats
+}
+
+func (optimizer *reforgeOptimizer) simRequest(player *proto.Player) *proto.RaidSimRequest {
+	return &proto.RaidSimRequest{
+		Raid:       SinglePlayerRaidProto(player, optimizer.request.PartyBuffs, optimizer.request.RaidBuffs, optimizer.request.Debuffs),
+		Encounter:  optimizer.request.Encounter,
+		SimOptions: optimizer.request.VerifySimOptions,
+	}
+}
+
+// Adds a binary variable which adds the given stats when picked.
+func (optimizer *reforgeOptimizer) addStatsVariable(statsAdded stats.Stats) int {
+	score := 0.0
+	for stat, value := range statsAdded {
+		score += value * optimizer.weights[stat]
+	}
+	variable := optimizer.problem.AddVariable(score, true)
+	optimizer.statsByVar[variable] = statsAdded
+	return variable
+}
+
+func (optimizer *reforgeOptimizer) addReforges(equipment Equipment) {
+	if optimizer.request.KeepReforges {
+		return
+	}
+
+	reforgeIDs := make([]int32, 0, len(ReforgeStatsByID))
+	for id := range ReforgeStatsByID {
+		reforgeIDs = append(reforgeIDs, id)
+	}
+	slices.Sort(reforgeIDs)
+
+	for slot, item := range equipment {
+		if item.ID == 0 || optimizer.frozen[proto.ItemSlot(slot)] {
+			continue
+		}
+
+		baseStats := ItemEquipmentBaseStats(item)
+		oneReforge := map[int]float64{}
+		for _, id := range reforgeIDs {
+			reforge := ReforgeStatsByID[id]
+			if !validateReforging(&item, reforge) {
+				continue
+			}
+			// Reforging into a stat without value only loses EP.
+			if optimizer.weights[reforge.ToStat] <= 0 && !optimizer.cappedStats[stats.Stat(reforge.ToStat)] {
+				continue
+			}
+
+			reforged := item
+			reforged.Reforging = &reforge
+			variable := optimizer.addStatsVariable(ItemEquipmentBaseStats(reforged).Subtract(baseStats))
+			optimizer.choices = append(optimizer.choices, reforgeChoice{
+				variable:  variable,
+				slot:      proto.ItemSlot(slot),
+				reforgeID: id,
+			})
+			oneReforge[variable] = 1
+		}
+		if len(oneReforge) > 0 {
+			optimizer.problem.AddLessEq(oneReforge, 1)
+		}
+	}
+}
+
+func (optimizer *reforgeOptimizer) addGems(equipment Equipment, equipmentSpec *proto.EquipmentSpec) {
+	for slot, item := range equipment {
+		if item.ID == 0 || optimizer.frozen[proto.ItemSlot(slot)] {
+			continue
+		}
+		gemIDs := equipmentSpec.Items[slot].Gems
+
+		// For each socket of the item, the gem variables matching its color.
+		// Nil for sockets which keep their gem.
+		matching := make([]map[int]float64, len(item.GemSockets))
+		bonusPossible := true
+
+		for socket := 0; socket < numGemSockets(item); socket++ {
+			if socket < len(gemIDs) && gemIDs[socket] != 0 {
+				if socket < len(item.GemSockets) && !ColorIntersects(item.GemSockets[socket], GemsByID[gemIDs[socket]].Color) {
+					bonusPossible = false
+				}
+				continue
+			}
+
+			eligible := optimizer.eligibleGems(item, socket)
+			if len(eligible) == 0 {
+				if socket < len(item.GemSockets) {
+					bonusPossible = false
+				}
+				continue
+			}
+
+			oneGem := map[int]float64{}
+			if socket < len(item.GemSockets) {
+				matching[socket] = map[int]float64{}
+			}
+			for _, gem := range eligible {
+				variable := optimizer.addStatsVariable(gem.Stats)
+				optimizer.choices = append(optimizer.choices, reforgeChoice{
+					variable: variable,
+					slot:     proto.ItemSlot(slot),
+					socket:   socket,
+					gemID:    gem.ID,
+				})
+				oneGem[variable] = 1
+				if socket < len(item.GemSockets) && ColorIntersects(item.GemSockets[socket], gem.Color) {
+					matching[socket][variable] = 1
+				}
+			}
+			optimizer.problem.AddLessEq(oneGem, 1)
+		}
+
+		// Items whose sockets all keep their gems already have the bonus in
+		// the base stats if they match.
+		socketsChosen := slices.ContainsFunc(matching, func(m map[int]float64) bool { return m != nil })
+		if !bonusPossible || !socketsChosen || item.SocketBonus == (stats.Stats{}) {
+			continue
+		}
+
+		bonus := optimizer.addStatsVariable(item.SocketBonus)
+		for _, gemVars := range matching {
+			if gemVars == nil {
+				continue
+			}
+			// bonus <= sum of matching gems in the socket
+			constraint := map[int]float64{bonus: 1}
+			for variable := range gemVars {
+				constraint[variable] = -1
+			}
+			optimizer.problem.AddLessEq(constraint, 0)
+		}
+	}
+}
+
+func (optimizer *reforgeOptimizer) addMetaRequirements(equipment Equipment) {
+	requirements := []struct {
+		color   proto.GemColor
+		minGems int32
+	}{
+		{proto.GemColor_GemColorRed, optimizer.request.MinRedGems},
+		{proto.GemColor_GemColorYellow, optimizer.request.MinYellowGems},
+		{proto.GemColor_GemColorBlue, optimizer.request.MinBlueGems},
+	}
+
+	for _, requirement := range requirements {
+		color, minGems := requirement.color, requirement.minGems
+		if minGems <= 0 {
+			continue
+		}
+
+		// Gems which are kept count towards the requirement.
+		keptGems := 0
+		for _, item := range equipment {
+			for _, gem := range item.Gems {
+				if gem.ID != 0 && ColorIntersects(color, gem.Color) {
+					keptGems++
+				}
+			}
+		}
+
+		chosenGems := map[int]float64{}
+		for _, choice := range optimizer.choices {
+			if choice.gemID != 0 && ColorIntersects(color, GemsByID[choice.gemID].Color) {
+				chosenGems[choice.variable] = 1
+			}
+		}
+		optimizer.problem.AddGreaterEq(chosenGems, float64(int(minGems)-keptGems))
+	}
+}
+
+// Adds a variable for each breakpoint of each cap, and returns the EP lost to
+// caps by the stripped gear, so scores only count the chosen reforges and gems.
+func (optimizer *reforgeOptimizer) addCaps(baseStats stats.Stats) (float64, error) {
+	basePenalty := 0.0
+	for _, statCap := range optimizer.request.StatCaps {
+		stat := stats.Stat(statCap.Stat)
+		if len(statCap.Breakpoints) != len(statCap.PostCapWeights) {
+			return 0, fmt.Errorf("cap on %s needs one post cap weight per breakpoint", stat.StatName())
+		}
+
+		weight := optimizer.weights[stat]
+		for i, breakpoint := range statCap.Breakpoints {
+			postCapWeight := statCap.PostCapWeights[i]
+			if i > 0 && breakpoint < statCap.Breakpoints[i-1] {
+				return 0, fmt.Errorf("breakpoints of %s must be in ascending order", stat.StatName())
+			}
+			if postCapWeight > weight {
+				return 0, fmt.Errorf("EP of %s may not increase past a breakpoint", stat.StatName())
+			}
+
+			// Amount of the stat above the breakpoint, which loses the
+			// difference in EP:
+			// aboveCap >= baseStats[stat] + added stats - breakpoint
+			aboveCap := optimizer.problem.AddVariable(postCapWeight-weight, false)
+			constraint := map[int]float64{aboveCap: 1}
+			for variable, statsAdded := range optimizer.statsByVar {
+				if statsAdded[stat] != 0 {
+					constraint[variable] = -statsAdded[stat]
+				}
+			}
+			optimizer.problem.AddGreaterEq(constraint, baseStats[stat]-breakpoint)
+
+			basePenalty += (postCapWeight - weight) * max(0, baseStats[stat]-breakpoint)
+			weight = postCapWeight
+		}
+	}
+	return basePenalty, nil
+}
+
+// Returns the candidate gems which fit in a socket of the item.
+func (optimizer *reforgeOptimizer) eligibleGems(item Item, socket int) []Gem {
+	socketColor := proto.GemColor_GemColorPrismatic
+	if socket < len(item.GemSockets) {
+		socketColor = item.GemSockets[socket]
+	}
+
+	var eligible []Gem
+	for _, gem := range optimizer.gems {
+		if gem.DisabledInChallengeMode && item.ChallengeMode {
+			continue
+		}
+		switch socketColor {
+		case proto.GemColor_GemColorMeta, proto.GemColor_GemColorCogwheel, proto.GemColor_GemColorShaTouched:
+			if gem.Color != socketColor {
+				continue
+			}
+		default:
+			if gem.Color == proto.GemColor_GemColorMeta || gem.Color == proto.GemColor_GemColorCogwheel || gem.Color == proto.GemColor_GemColorShaTouched {
+				continue
+			}
+		}
+		eligible = append(eligible, gem)
+	}
+	return eligible
+}
+
+// Number of sockets, including extra sockets like belt buckles which only
+// show up as additional gems.
+func numGemSockets(item Item) int {
+	return max(len(item.GemSockets), len(item.Gems))
+}
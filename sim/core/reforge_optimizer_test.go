@@ -0,0 +1,248 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+const (
+	reforgeTestCritChest    = 91001
+	reforgeTestMasteryLegs  = 91002
+	reforgeTestBonusChest   = 91003
+	reforgeTestNoBonusChest = 91004
+	reforgeTestYellowLegs   = 91005
+
+	reforgeTestRedGem    = 92001
+	reforgeTestBlueGem   = 92002
+	reforgeTestYellowGem = 92003
+
+	// Real reforge ids stop at 168.
+	reforgeTestCritToHit      = 901
+	reforgeTestCritToHaste    = 902
+	reforgeTestMasteryToHit   = 903
+	reforgeTestMasteryToHaste = 904
+)
+
+// Hand built items, gems and reforges so the optimizer can be tested without
+// the item database.
+var reforgeTestDatabase = &proto.SimDatabase{
+	Items: []*proto.SimItem{
+		reforgeTestItem(reforgeTestCritChest, proto.ItemType_ItemTypeChest, stats.CritRating, nil, stats.Stats{}),
+		reforgeTestItem(reforgeTestMasteryLegs, proto.ItemType_ItemTypeLegs, stats.MasteryRating, nil, stats.Stats{}),
+		reforgeTestItem(reforgeTestBonusChest, proto.ItemType_ItemTypeChest, stats.CritRating,
+			[]proto.GemColor{proto.GemColor_GemColorRed, proto.GemColor_GemColorBlue}, stats.Stats{stats.Intellect: 200}),
+		reforgeTestItem(reforgeTestNoBonusChest, proto.ItemType_ItemTypeChest, stats.CritRating,
+			[]proto.GemColor{proto.GemColor_GemColorRed, proto.GemColor_GemColorBlue}, stats.Stats{stats.Intellect: 20}),
+		reforgeTestItem(reforgeTestYellowLegs, proto.ItemType_ItemTypeLegs, stats.MasteryRating,
+			[]proto.GemColor{proto.GemColor_GemColorYellow}, stats.Stats{}),
+	},
+	Gems: []*proto.SimGem{
+		{Id: reforgeTestRedGem, Color: proto.GemColor_GemColorRed, Stats: stats.Stats{stats.Intellect: 160}.ToProtoArray()},
+		{Id: reforgeTestBlueGem, Color: proto.GemColor_GemColorBlue, Stats: stats.Stats{stats.Intellect: 120}.ToProtoArray()},
+		{Id: reforgeTestYellowGem, Color: proto.GemColor_GemColorYellow, Stats: stats.Stats{stats.Intellect: 160}.ToProtoArray()},
+	},
+	ReforgeStats: []*proto.ReforgeStat{
+		{Id: reforgeTestCritToHit, FromStat: proto.Stat(stats.CritRating), ToStat: proto.Stat(stats.HitRating), Multiplier: 0.4},
+		{Id: reforgeTestCritToHaste, FromStat: proto.Stat(stats.CritRating), ToStat: proto.Stat(stats.HasteRating), Multiplier: 0.4},
+		{Id: reforgeTestMasteryToHit, FromStat: proto.Stat(stats.MasteryRating), ToStat: proto.Stat(stats.HitRating), Multiplier: 0.4},
+		{Id: reforgeTestMasteryToHaste, FromStat: proto.Stat(stats.MasteryRating), ToStat: proto.Stat(stats.HasteRating), Multiplier: 0.4},
+	},
+}
+
+// An item with 1000 of a single reforgeable stat.
+func reforgeTestItem(id int32, itemType proto.ItemType, stat stats.Stat, sockets []proto.GemColor, socketBonus stats.Stats) *proto.SimItem {
+	return &proto.SimItem{
+		Id:          id,
+		Type:        itemType,
+		GemSockets:  sockets,
+		SocketBonus: socketBonus.ToProtoArray(),
+		ScalingOptions: map[int32]*proto.ScalingItemProperties{
+			0: {Stats: map[int32]float64{int32(stat): 1000}},
+		},
+	}
+}
+
+func reforgeTestRequest(weights stats.Stats, items ...*itemWithSlot) *proto.ReforgeOptimizeRequest {
+	addToDatabase(reforgeTestDatabase)
+
+	return &proto.ReforgeOptimizeRequest{
+		Player: &proto.Player{
+			Name:      "Caster",
+			Class:     proto.Class_ClassShaman,
+			Race:      proto.Race_RaceTroll,
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: createEquipmentFromItems(items...),
+		},
+		StatWeights: &proto.UnitStats{Stats: weights.ToProtoArray()},
+		GemIds:      []int32{reforgeTestRedGem, reforgeTestBlueGem, reforgeTestYellowGem},
+	}
+}
+
+func optimizeReforgesOrFail(t *testing.T, request *proto.ReforgeOptimizeRequest) *proto.ReforgeOptimizeResult {
+	result := optimizeReforges(request)
+	if result.ErrorResult != "" {
+		t.Fatalf("Reforge optimization failed: %s", result.ErrorResult)
+	}
+	return result
+}
+
+func assertGems(t *testing.T, itemSpec *proto.ItemSpec, expected ...int32) {
+	if len(itemSpec.Gems) != len(expected) {
+		t.Fatalf("Expected gems %v on item %d, got %v", expected, itemSpec.Id, itemSpec.Gems)
+	}
+	for i := range expected {
+		if itemSpec.Gems[i] != expected[i] {
+			t.Fatalf("Expected gems %v on item %d, got %v", expected, itemSpec.Id, itemSpec.Gems)
+		}
+	}
+}
+
+func TestReforgeOptimizerHitCap(t *testing.T) {
+	request := reforgeTestRequest(stats.Stats{stats.HitRating: 2, stats.HasteRating: 1},
+		&itemWithSlot{Item: &proto.ItemSpec{Id: reforgeTestCritChest}, Slot: proto.ItemSlot_ItemSlotChest},
+		&itemWithSlot{Item: &proto.ItemSpec{Id: reforgeTestMasteryLegs}, Slot: proto.ItemSlot_ItemSlotLegs},
+	)
+	// Hit is worth nothing past 400, so only one of the two reforges into hit pays off.
+	request.StatCaps = []*proto.ReforgeStatCap{{
+		Stat:           proto.Stat(stats.HitRating),
+		Breakpoints:    []float64{400},
+		PostCapWeights: []float64{0},
+	}}
+
+	result := optimizeReforgesOrFail(t, request)
+
+	chest := result.Equipment.Items[proto.ItemSlot_ItemSlotChest].Reforging
+	legs := result.Equipment.Items[proto.ItemSlot_ItemSlotLegs].Reforging
+	toHit := 0
+	if chest == reforgeTestCritToHit {
+		toHit++
+	} else if chest != reforgeTestCritToHaste {
+		t.Fatalf("Expected the chest to be reforged, got reforge %d", chest)
+	}
+	if legs == reforgeTestMasteryToHit {
+		toHit++
+	} else if legs != reforgeTestMasteryToHaste {
+		t.Fatalf("Expected the legs to be reforged, got reforge %d", legs)
+	}
+	if toHit != 1 {
+		t.Fatalf("Expected exactly one reforge into hit, got %d", toHit)
+	}
+
+	if hit := result.Stats.Stats[stats.HitRating]; hit != 400 {
+		t.Fatalf("Expected hit to land on the 400 breakpoint, got %0.f", hit)
+	}
+	if expectedScore := 400*2 + 400*1.0; result.Score != expectedScore {
+		t.Fatalf("Expected a score of %0.f, got %f", expectedScore, result.Score)
+	}
+}
+
+func TestReforgeOptimizerSocketBonus(t *testing.T) {
+	weights := stats.Stats{stats.Intellect: 1}
+
+	// A red and a blue gem is 280 intellect, plus a 200 intellect bonus beats
+	// two red gems.
+	request := reforgeTestRequest(weights, &itemWithSlot{Item: &proto.ItemSpec{Id: reforgeTestBonusChest}, Slot: proto.ItemSlot_ItemSlotChest})
+	result := optimizeReforgesOrFail(t, request)
+	assertGems(t, result.Equipment.Items[proto.ItemSlot_ItemSlotChest], reforgeTestRedGem, reforgeTestBlueGem)
+	if result.Score != 480 {
+		t.Fatalf("Expected the socket bonus to be counted for matching gems, got a score of %f", result.Score)
+	}
+
+	// With a 20 intellect bonus two red gems win, and the bonus must not be
+	// counted since the blue socket is not matched.
+	request = reforgeTestRequest(weights, &itemWithSlot{Item: &proto.ItemSpec{Id: reforgeTestNoBonusChest}, Slot: proto.ItemSlot_ItemSlotChest})
+	result = optimizeReforgesOrFail(t, request)
+	gems := result.Equipment.Items[proto.ItemSlot_ItemSlotChest].Gems
+	if len(gems) != 2 || gems[1] == reforgeTestBlueGem || gems[0] == reforgeTestBlueGem {
+		t.Fatalf("Expected no blue gems without a worthwhile socket bonus, got %v", gems)
+	}
+	if result.Score != 320 {
+		t.Fatalf("Expected the socket bonus to be skipped for unmatched gems, got a score of %f", result.Score)
+	}
+	if intellect := result.Stats.Stats[stats.Intellect]; intellect < 320 {
+		t.Fatalf("Expected the gems' intellect in the final stats, got %0.f", intellect)
+	}
+}
+
+func TestReforgeOptimizerMetaRequirements(t *testing.T) {
+	request := reforgeTestRequest(stats.Stats{stats.Intellect: 1},
+		&itemWithSlot{Item: &proto.ItemSpec{Id: reforgeTestNoBonusChest}, Slot: proto.ItemSlot_ItemSlotChest},
+		&itemWithSlot{Item: &proto.ItemSpec{Id: reforgeTestYellowLegs}, Slot: proto.ItemSlot_ItemSlotLegs},
+	)
+	// Blue gems are the weakest, so they are only picked to meet the meta requirement.
+	request.MinBlueGems = 2
+
+	result := optimizeReforgesOrFail(t, request)
+
+	blueGems := 0
+	for _, itemSpec := range result.Equipment.Items {
+		for _, gemID := range itemSpec.Gems {
+			if gemID == reforgeTestBlueGem {
+				blueGems++
+			}
+		}
+	}
+	if blueGems != 2 {
+		t.Fatalf("Expected exactly 2 blue gems for the meta gem, got %d", blueGems)
+	}
+	// The blue socket bonus comes for free with one of the blue gems.
+	if expectedScore := 160 + 120 + 120 + 20.0; result.Score != expectedScore {
+		t.Fatalf("Expected a score of %0.f, got %f", expectedScore, result.Score)
+	}
+
+	request.MinBlueGems = 4
+	if result := optimizeReforges(request); result.ErrorResult == "" {
+		t.Fatalf("Expected an error when there are fewer sockets than required gems")
+	}
+}
+
+func TestReforgeOptimizerFrozenSlotsAndKeepReforges(t *testing.T) {
+	weights := stats.Stats{stats.Intellect: 1, stats.HasteRating: 1}
+	chest := func() *itemWithSlot {
+		return &itemWithSlot{
+			Item: &proto.ItemSpec{Id: reforgeTestNoBonusChest, Reforging: reforgeTestCritToHit, Gems: []int32{reforgeTestBlueGem, reforgeTestBlueGem}},
+			Slot: proto.ItemSlot_ItemSlotChest,
+		}
+	}
+	legs := func() *itemWithSlot {
+		return &itemWithSlot{
+			Item: &proto.ItemSpec{Id: reforgeTestYellowLegs, Reforging: reforgeTestMasteryToHit, Gems: []int32{reforgeTestBlueGem}},
+			Slot: proto.ItemSlot_ItemSlotLegs,
+		}
+	}
+
+	// A frozen slot keeps its reforge and gems, while the others are optimized.
+	request := reforgeTestRequest(weights, chest(), legs())
+	request.FrozenSlots = []proto.ItemSlot{proto.ItemSlot_ItemSlotChest}
+	result := optimizeReforgesOrFail(t, request)
+
+	frozenChest := result.Equipment.Items[proto.ItemSlot_ItemSlotChest]
+	if frozenChest.Reforging != reforgeTestCritToHit {
+		t.Fatalf("Expected the frozen chest to keep its reforge, got %d", frozenChest.Reforging)
+	}
+	assertGems(t, frozenChest, reforgeTestBlueGem, reforgeTestBlueGem)
+	optimizedLegs := result.Equipment.Items[proto.ItemSlot_ItemSlotLegs]
+	if optimizedLegs.Reforging != reforgeTestMasteryToHaste {
+		t.Fatalf("Expected the legs to be reforged into haste, got %d", optimizedLegs.Reforging)
+	}
+	if len(optimizedLegs.Gems) != 1 || optimizedLegs.Gems[0] == reforgeTestBlueGem {
+		t.Fatalf("Expected the blue gem in the legs to be replaced, got %v", optimizedLegs.Gems)
+	}
+
+	// KeepReforges only optimizes the gems.
+	request = reforgeTestRequest(weights, chest(), legs())
+	request.KeepReforges = true
+	result = optimizeReforgesOrFail(t, request)
+
+	if reforging := result.Equipment.Items[proto.ItemSlot_ItemSlotChest].Reforging; reforging != reforgeTestCritToHit {
+		t.Fatalf("Expected the chest to keep its reforge, got %d", reforging)
+	}
+	if reforging := result.Equipment.Items[proto.ItemSlot_ItemSlotLegs].Reforging; reforging != reforgeTestMasteryToHit {
+		t.Fatalf("Expected the legs to keep their reforge, got %d", reforging)
+	}
+	if gems := result.Equipment.Items[proto.ItemSlot_ItemSlotLegs].Gems; len(gems) != 1 || gems[0] == reforgeTestBlueGem {
+		t.Fatalf("Expected the gems to still be optimized, got %v", gems)
+	}
+}
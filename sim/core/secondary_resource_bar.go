@@ -66,6 +66,9 @@ func (bar *DefaultSecondaryResourceBarImpl) Gain(sim *Simulation, amount int32,
 			bar.config.Max,
 		)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.AddResource(sim, bar.unit, metrics, float64(amount), float64(bar.value))
+	}
 
 	bar.invokeOnGain(sim, amount, amountGained, action)
 }
@@ -112,6 +115,10 @@ func (bar *DefaultSecondaryResourceBarImpl) Spend(sim *Simulation, amount int32,
 		)
 	}
 
+	if sim.CombatLog != nil {
+		sim.CombatLog.AddResource(sim, bar.unit, metrics, float64(-amount), float64(bar.value-amount))
+	}
+
 	metrics.AddEvent(float64(-amount), float64(-amount))
 	bar.invokeOnSpend(sim, amount, action)
 	bar.value -= amount
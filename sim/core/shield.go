@@ -5,6 +5,11 @@ import "strconv"
 type ShieldConfig struct {
 	SelfOnly bool // Set to true to only create the self-shield.
 
+	// Set to true to have the shield absorb incoming damage until it is used
+	// up, recording the consumed amount as absorbed in the spell metrics.
+	// Leave unset for shields that implement their own absorb handling.
+	AbsorbDamage bool
+
 	Spell *Spell
 
 	Aura
@@ -14,21 +19,40 @@ type ShieldConfig struct {
 type Shield struct {
 	Spell *Spell
 
+	// Amount left to absorb, only tracked for shields with AbsorbDamage set.
+	Remaining float64
+
 	// Embed Aura so we can use IsActive/Refresh/etc directly.
 	*Aura
 }
 
 func (shield *Shield) Apply(sim *Simulation, shieldAmount float64) {
-	caster := shield.Spell.Unit
-	target := shield.Aura.Unit
-	//attackTable := caster.AttackTables[target.UnitIndex]
-
 	// Shields are not affected by healing pseudostats the same way heals are.
 	// So we only apply the spell-specific multiplier.
 	shieldAmount *= shield.Spell.DamageMultiplier
 
+	shield.apply(sim, shieldAmount, shieldAmount)
+}
+
+// Adds to the amount left on the shield instead of replacing it, keeping the
+// total at or below maxAmount, e.g. for Divine Aegis.
+func (shield *Shield) ApplyAdditive(sim *Simulation, shieldAmount float64, maxAmount float64) {
+	remaining := 0.0
+	if shield.Aura.IsActive() {
+		remaining = shield.Remaining
+	}
+	added := min(shieldAmount*shield.Spell.DamageMultiplier, max(maxAmount-remaining, 0))
+
+	shield.apply(sim, remaining+added, added)
+}
+
+func (shield *Shield) apply(sim *Simulation, remaining float64, added float64) {
+	caster := shield.Spell.Unit
+	target := shield.Aura.Unit
+
 	stacks := shield.Aura.GetStacks()
 	shield.Aura.Deactivate(sim)
+	shield.Remaining = remaining
 	shield.Aura.Activate(sim)
 	if shield.Aura.MaxStacks > 0 {
 		shield.Aura.SetStacks(sim, stacks)
@@ -36,14 +60,20 @@ func (shield *Shield) Apply(sim *Simulation, shieldAmount float64) {
 
 	threat := 0.0 // TODO
 	shield.Spell.SpellMetrics[target.UnitIndex].TotalThreat += threat
-	shield.Spell.SpellMetrics[target.UnitIndex].TotalShielding += shieldAmount
+	shield.Spell.SpellMetrics[target.UnitIndex].TotalShielding += added
 	shield.Spell.SpellMetrics[target.UnitIndex].Hits++
 
 	if sim.Log != nil {
-		caster.Log(sim, "%s %s Hit for %0.3f shielding. (Threat: %0.3f)", target.LogLabel(), shield.Spell.ActionID, shieldAmount, threat)
+		caster.Log(sim, "%s %s Hit for %0.3f shielding. (Threat: %0.3f)", target.LogLabel(), shield.Spell.ActionID, added, threat)
 	}
 }
 
+func (shield *Shield) registerAbsorb() {
+	shield.Aura.Unit.AddDynamicDamageTakenModifier(func(sim *Simulation, spell *Spell, result *SpellResult, _ bool) {
+		shield.absorb(sim, spell, result)
+	})
+}
+
 func newShield(config Shield) *Shield {
 	shield := &Shield{}
 	*shield = config
@@ -51,6 +81,27 @@ func newShield(config Shield) *Shield {
 	return shield
 }
 
+// Consumes the shield against an incoming hit.
+func (shield *Shield) absorb(sim *Simulation, spell *Spell, result *SpellResult) {
+	if !shield.Aura.IsActive() || result.Damage <= 0 || spell.Flags.Matches(SpellFlagBypassAbsorbs) {
+		return
+	}
+
+	absorbed := min(shield.Remaining, result.Damage)
+	result.Damage -= absorbed
+	result.Absorbed += absorbed
+	shield.Remaining -= absorbed
+	shield.Spell.SpellMetrics[shield.Aura.Unit.UnitIndex].TotalAbsorbed += absorbed
+
+	if sim.Log != nil {
+		shield.Aura.Unit.Log(sim, "%s absorbed %0.3f damage, %0.3f remaining.", shield.Spell.ActionID, absorbed, shield.Remaining)
+	}
+
+	if shield.Remaining <= 0 {
+		shield.Aura.Deactivate(sim)
+	}
+}
+
 type ShieldArray []*Shield
 
 func (shields ShieldArray) Get(target *Unit) *Shield {
@@ -78,6 +129,9 @@ func (spell *Spell) createShields(config ShieldConfig) {
 	if config.SelfOnly {
 		shield.Aura = caster.GetOrRegisterAura(auraConfig)
 		spell.selfShield = newShield(shield)
+		if config.AbsorbDamage {
+			spell.selfShield.registerAbsorb()
+		}
 	} else {
 		auraConfig.Label += "-" + strconv.Itoa(int(caster.UnitIndex))
 		if spell.shields == nil {
@@ -87,6 +141,9 @@ func (spell *Spell) createShields(config ShieldConfig) {
 			if !caster.IsOpponent(target) {
 				shield.Aura = target.GetOrRegisterAura(auraConfig)
 				spell.shields[target.UnitIndex] = newShield(shield)
+				if config.AbsorbDamage {
+					spell.shields[target.UnitIndex].registerAbsorb()
+				}
 			}
 		}
 	}
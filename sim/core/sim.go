@@ -45,6 +45,9 @@ type Simulation struct {
 
 	Log func(string, ...interface{})
 
+	// Structured combat log, nil when disabled.
+	CombatLog *CombatLog
+
 	executePhase int32 // 20, 25, 35, 45 or 90 for the respective execute range, 100 otherwise
 
 	executePhaseCallbacks []func(*Simulation, int32) // 2nd parameter is 90 for 90%, 45 for 45%, 35 for 35%, 25 for 25% and 20 for 20%
@@ -262,6 +265,10 @@ func (sim *Simulation) RandomExpFloat(label string) float64 {
 	return rand.New(sim.labelRand(label)).ExpFloat64()
 }
 
+func (sim *Simulation) RandomNormFloat(label string) float64 {
+	return rand.New(sim.labelRand(label)).NormFloat64()
+}
+
 // Shorthand for commonly-used RNG behavior.
 // Returns a random number between min and max.
 func (sim *Simulation) Roll(min float64, max float64) float64 {
@@ -302,6 +309,10 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 		}
 	}
 
+	if sim.Options.CombatLog {
+		sim.CombatLog = newCombatLog(sim.Environment, sim.Options.CombatLogFilter)
+	}
+
 	// Uncomment this to print logs directly to console.
 	// sim.Options.Debug = true
 	// sim.Log = func(message string, vals ...interface{}) {
@@ -318,9 +329,20 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 	if !sim.Options.Debug {
 		sim.Log = nil
 	}
+	combatLog := sim.CombatLog
+	if !sim.Options.Debug {
+		sim.CombatLog = nil
+	}
+
+	precisionTracker := newPrecisionTracker(sim)
+	iterationsDone := int32(1)
 
 	var st time.Time
 	for i := int32(1); i < sim.Options.Iterations; i++ {
+		if precisionTracker != nil && precisionTracker.isDone() {
+			break
+		}
+
 		if sim.Signals.Abort.IsTriggered() {
 			quitResult := &proto.RaidSimResult{Error: &proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted}}
 			if sim.ProgressReport != nil {
@@ -343,6 +365,9 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 
 		// Before each iteration, reset state to seed+iterations
 		sim.reseedRands(int64(i))
+		if sim.CombatLog != nil {
+			sim.CombatLog.iteration = i
+		}
 
 		sim.runOnce()
 		iterDuration := sim.Duration
@@ -350,6 +375,7 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 			iterDuration = sim.CurrentTime
 		}
 		totalDuration += iterDuration
+		iterationsDone++
 	}
 	result := &proto.RaidSimResult{
 		RaidMetrics:      sim.Raid.GetMetrics(),
@@ -357,16 +383,23 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 
 		Logs:                   logsBuffer.String(),
 		FirstIterationDuration: firstIterationDuration.Seconds(),
-		AvgIterationDuration:   totalDuration.Seconds() / float64(sim.Options.Iterations),
-		IterationsDone:         sim.Options.Iterations,
+		AvgIterationDuration:   totalDuration.Seconds() / float64(iterationsDone),
+		IterationsDone:         iterationsDone,
+	}
+	if combatLog != nil {
+		result.CombatLog = combatLog.Events
+	}
+	if precisionTracker != nil {
+		result.Precision = newPrecisionResult(result, precisionTracker.config.Metric, precisionTracker.config.Unit)
+		result.Precision.TargetReached = precisionTracker.isDone()
 	}
 
 	// Final progress report
 	if sim.ProgressReport != nil {
-		sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: sim.Options.Iterations, CompletedIterations: sim.Options.Iterations, Dps: result.RaidMetrics.Dps.Avg, FinalRaidResult: result})
+		sim.ProgressReport(&proto.ProgressMetrics{TotalIterations: sim.Options.Iterations, CompletedIterations: iterationsDone, Dps: result.RaidMetrics.Dps.Avg, FinalRaidResult: result})
 	}
 
-	if d := sim.Options.Iterations; d > 3000 {
+	if d := iterationsDone; d > 3000 {
 		log.Printf("running %d iterations took %s", d, time.Since(t0))
 	}
 
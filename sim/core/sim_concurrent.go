@@ -1,12 +1,14 @@
 package core
 
 import (
+	"cmp"
 	"fmt"
 	"log"
 	"math"
 	"reflect"
 	"runtime"
 	"runtime/debug"
+	"slices"
 
 	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/simsignals"
@@ -42,10 +44,19 @@ func SplitSimRequestForConcurrency(request *proto.RaidSimRequest, splitCount int
 		split[i] = googleProto.Clone(request).(*proto.RaidSimRequest)
 		split[i].SimOptions.Iterations = iterPerSplit
 		split[i].SimOptions.DebugFirstIteration = false // No logs
+		if !split[i].SimOptions.Debug {
+			split[i].SimOptions.CombatLog = false // Only the first split records the first iteration.
+		}
 		split[i].SimOptions.RandomSeed = nextStartSeed
 		nextStartSeed += int64(split[i].SimOptions.Iterations)
 	}
 
+	if request.SimOptions.TargetPrecision != nil {
+		for _, splitRequest := range split {
+			splitRequest.SimOptions.TargetPrecision = splitTargetPrecision(request.SimOptions.TargetPrecision, splitCount)
+		}
+	}
+
 	res.SplitsDone = splitCount
 	res.Requests = split
 	return res
@@ -75,6 +86,7 @@ func (rsrc *raidSimResultCombiner) newUnitMetrics(baseUnit *proto.UnitMetrics) *
 		Dtps:      rsrc.newDistMetrics(),
 		Tmi:       rsrc.newDistMetrics(),
 		Hps:       rsrc.newDistMetrics(),
+		Ehps:      rsrc.newDistMetrics(),
 		Tto:       rsrc.newDistMetrics(),
 		Actions:   make([]*proto.ActionMetrics, 0, len(baseUnit.Actions)),
 		Auras:     make([]*proto.AuraMetrics, len(baseUnit.Auras)),
@@ -89,6 +101,13 @@ func (rsrc *raidSimResultCombiner) newUnitMetrics(baseUnit *proto.UnitMetrics) *
 		}
 	}
 
+	if baseUnit.DamageSpikes != nil {
+		newUm.DamageSpikes = &proto.DamageSpikeMetrics{
+			WindowSeconds: baseUnit.DamageSpikes.WindowSeconds,
+			LargestWindow: rsrc.newDistMetrics(),
+		}
+	}
+
 	for i, pet := range baseUnit.Pets {
 		newUm.Pets[i] = rsrc.newUnitMetrics(pet)
 	}
@@ -194,6 +213,8 @@ func (rsrc *raidSimResultCombiner) addActionMetrics(unit *proto.UnitMetrics, add
 		baseTgt.Healing += addTgt.Healing
 		baseTgt.CritHealing += addTgt.CritHealing
 		baseTgt.Shielding += addTgt.Shielding
+		baseTgt.Overhealing += addTgt.Overhealing
+		baseTgt.Absorbed += addTgt.Absorbed
 		baseTgt.CastTimeMs += addTgt.CastTimeMs
 	}
 }
@@ -234,6 +255,51 @@ func (rsrc *raidSimResultCombiner) addResourceMetrics(unit *proto.UnitMetrics, a
 	rm.Events += add.Events
 	rm.Gain += add.Gain
 	rm.ActualGain += add.ActualGain
+	rm.Overheal += add.Overheal
+}
+
+func (rsrc *raidSimResultCombiner) addDamageTakenMetrics(unit *proto.UnitMetrics, add *proto.DamageTakenMetrics) {
+	var dtm *proto.DamageTakenMetrics
+
+	addKey := add.Id.String()
+	for _, baseDamageTaken := range unit.DamageTaken {
+		if (baseDamageTaken.UnitIndex == add.UnitIndex) && (baseDamageTaken.Id.String() == addKey) {
+			dtm = baseDamageTaken
+			break
+		}
+	}
+
+	if dtm == nil {
+		dtm = &proto.DamageTakenMetrics{
+			Id:        add.Id,
+			UnitIndex: add.UnitIndex,
+		}
+		unit.DamageTaken = append(unit.DamageTaken, dtm)
+	}
+
+	dtm.Hits += add.Hits
+	dtm.Avoids += add.Avoids
+	dtm.Blocks += add.Blocks
+	dtm.Raw += add.Raw
+	dtm.Avoided += add.Avoided
+	dtm.Blocked += add.Blocked
+	dtm.ArmorMitigated += add.ArmorMitigated
+	dtm.Reduced += add.Reduced
+	dtm.Absorbed += add.Absorbed
+	dtm.Staggered += add.Staggered
+	dtm.Taken += add.Taken
+}
+
+func (rsrc *raidSimResultCombiner) combineDamageSpikeMetrics(base *proto.DamageSpikeMetrics, add *proto.DamageSpikeMetrics, isLast bool, weight float64) {
+	rsrc.combineDistMetrics(base.LargestWindow, add.LargestWindow, isLast, weight)
+
+	base.WorstWindows = append(base.WorstWindows, add.WorstWindows...)
+	slices.SortStableFunc(base.WorstWindows, func(a, b *proto.DamageSpike) int {
+		return cmp.Compare(b.DamagePercent, a.DamagePercent)
+	})
+	if len(base.WorstWindows) > numWorstDamageSpikes {
+		base.WorstWindows = base.WorstWindows[:numWorstDamageSpikes]
+	}
 }
 
 func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, add *proto.UnitMetrics, isLast bool, weight float64) {
@@ -242,10 +308,13 @@ func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, a
 	rsrc.combineDistMetrics(base.Dtps, add.Dtps, isLast, weight)
 	rsrc.combineDistMetrics(base.Tmi, add.Tmi, isLast, weight)
 	rsrc.combineDistMetrics(base.Hps, add.Hps, isLast, weight)
+	rsrc.combineDistMetrics(base.Ehps, add.Ehps, isLast, weight)
 	rsrc.combineDistMetrics(base.Tto, add.Tto, isLast, weight)
 
 	base.SecondsOomAvg += add.SecondsOomAvg * weight
 	base.ChanceOfDeath += add.ChanceOfDeath * weight
+	base.AggroPullsAvg += add.AggroPullsAvg * weight
+	base.ChanceOfAggroPull += add.ChanceOfAggroPull * weight
 
 	for _, addAction := range add.Actions {
 		rsrc.addActionMetrics(base, addAction)
@@ -259,6 +328,13 @@ func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, a
 		rsrc.addResourceMetrics(base, addResource)
 	}
 
+	for _, addDamageTaken := range add.DamageTaken {
+		rsrc.addDamageTakenMetrics(base, addDamageTaken)
+	}
+	if (base.DamageSpikes != nil) && (add.DamageSpikes != nil) {
+		rsrc.combineDamageSpikeMetrics(base.DamageSpikes, add.DamageSpikes, isLast, weight)
+	}
+
 	for i, addPet := range add.Pets {
 		rsrc.combineUnitMetrics(base.Pets[i], addPet, isLast, weight)
 	}
@@ -281,6 +357,14 @@ func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast
 		rsrc.combineUnitMetrics(rsrc.Combined.EncounterMetrics.Targets[i], tar, isLast, weight)
 	}
 
+	if rsrc.Debug {
+		// Each split numbers its iterations from 0, so shift them to be unique across the combined result.
+		for _, event := range result.CombatLog {
+			event.Iteration += rsrc.Combined.IterationsDone
+		}
+		rsrc.Combined.CombatLog = append(rsrc.Combined.CombatLog, result.CombatLog...)
+	}
+
 	rsrc.Combined.AvgIterationDuration += result.AvgIterationDuration * weight
 	rsrc.Combined.IterationsDone += result.IterationsDone
 
@@ -304,6 +388,7 @@ func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
 
 	if !rsrc.Debug {
 		newRsr.Logs = baseRsr.Logs
+		newRsr.CombatLog = baseRsr.CombatLog
 	}
 
 	for i, party := range baseRsr.RaidMetrics.Parties {
@@ -340,6 +425,16 @@ func CombineConcurrentSimResults(results []*proto.RaidSimResult, isDebug bool) *
 		rsrc.AddResult(result, i == numResults-1, resultWeight)
 	}
 
+	// Each split was given a proportionally looser target, so the combined
+	// result reached the target if all splits did.
+	if basePrecision := results[0].Precision; basePrecision != nil {
+		rsrc.Combined.Precision = newPrecisionResult(rsrc.Combined, basePrecision.Metric, basePrecision.Unit)
+		rsrc.Combined.Precision.TargetReached = true
+		for _, result := range results {
+			rsrc.Combined.Precision.TargetReached = rsrc.Combined.Precision.TargetReached && result.Precision.GetTargetReached()
+		}
+	}
+
 	return rsrc.Combined
 }
 
@@ -459,7 +554,11 @@ func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.Progre
 	}
 
 	for i, req := range splitRes.Requests {
-		go RunSim(req, substituteChannels[i], signals)
+		go func(req *proto.RaidSimRequest, progress chan *proto.ProgressMetrics) {
+			release := acquireWorker()
+			defer release()
+			RunSim(req, progress, signals)
+		}(req, substituteChannels[i])
 	}
 
 	progressCounter := 0
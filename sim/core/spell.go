@@ -32,7 +32,7 @@ type SpellConfig struct {
 	Cast               CastConfig
 	ExtraCastCondition CanCastCondition
 
-	// Optional range constraints. If supplied, these are used to modify the ExtraCastCondition above to additionally check for DistanceFromTarget.
+	// Optional range constraints. If supplied, these are used to modify the ExtraCastCondition above to additionally check the distance to the target.
 	MinRange     float64
 	MaxRange     float64
 	Charges      int // The maximum amount of charges this spell can have
@@ -104,7 +104,7 @@ type Spell struct {
 	SharedCD           Cooldown
 	ExtraCastCondition CanCastCondition
 
-	// Optional range constraints. If supplied, these are used to modify the ExtraCastCondition above to additionally check for DistanceFromTarget.
+	// Optional range constraints. If supplied, these are used to modify the ExtraCastCondition above to additionally check the distance to the target.
 	MinRange     float64
 	MaxRange     float64
 	MaxCharges   int // Maximum amount of charges the spell can have
@@ -337,7 +337,14 @@ func (unit *Unit) RegisterSpell(config SpellConfig) *Spell {
 		spell.MaxRange = config.MaxRange
 		oldExtraCastCondition := spell.ExtraCastCondition
 		spell.ExtraCastCondition = func(sim *Simulation, target *Unit) bool {
-			if ((spell.MinRange != 0) && (spell.Unit.DistanceFromTarget < spell.MinRange)) || ((spell.MaxRange != 0) && (spell.Unit.DistanceFromTarget > spell.MaxRange)) {
+			// Ranges to friendly targets aren't modeled, so those use the
+			// distance to the current target like before.
+			distance := spell.Unit.DistanceFromTarget
+			if (target != nil) && (target.Type == EnemyUnit) {
+				distance = spell.Unit.DistanceTo(sim, target)
+			}
+
+			if ((spell.MinRange != 0) && (distance < spell.MinRange)) || ((spell.MaxRange != 0) && (distance > spell.MaxRange)) {
 				/*if sim.Log != nil {
 					sim.Log("Cannot cast spell %s, out of range!", spell.ActionID)
 				}*/
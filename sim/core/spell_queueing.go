@@ -60,6 +60,9 @@ func (unit *Unit) QueueSpell(sim *Simulation, spell *Spell, target *Unit, queueA
 	}
 
 	fireAt := queueAt + time.Duration(1) // 1ns artificial delay guarantees last-second cancellation if desired
+	if unit.executionModel != nil {
+		fireAt += unit.executionModel.queueDelay(sim)
+	}
 	unit.QueuedSpell.InitiateQueue(sim, spell, target, fireAt)
 
 	if sim.Log != nil {
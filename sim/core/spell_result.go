@@ -19,6 +19,12 @@ type SpellResult struct {
 	ArmorMultiplier  float64 // Armor multiplier
 	PreOutcomeDamage float64 // Damage done by this cast before Outcome is applied
 
+	Absorbed  float64 // Damage removed by absorbs.
+	Staggered float64 // Damage turned into a Stagger DoT.
+
+	// Mitigation before absorbs, only tracked for damage taken by tanks.
+	mitigation damageMitigation
+
 	inUse bool
 }
 
@@ -72,6 +78,9 @@ func (spell *Spell) NewResult(target *Unit) *SpellResult {
 	result.Outcome = OutcomeEmpty // for blocks
 	result.inUse = true
 	result.PreOutcomeDamage = 0
+	result.Absorbed = 0
+	result.Staggered = 0
+	result.mitigation = damageMitigation{}
 
 	return result
 }
@@ -243,7 +252,7 @@ func (spell *Spell) calcDamageInternal(sim *Simulation, target *Unit, baseDamage
 	result := spell.NewResult(target)
 	result.Damage = baseDamage
 
-	if sim.Log == nil {
+	if sim.Log == nil && !target.Metrics.isTanking {
 		result.Damage *= attackerMultiplier
 		result.applyArmor(spell, isPeriodic, attackTable)
 		result.applyTargetModifiers(sim, spell, attackTable, isPeriodic)
@@ -262,13 +271,19 @@ func (spell *Spell) calcDamageInternal(sim *Simulation, target *Unit, baseDamage
 		outcomeApplier(sim, result, attackTable)
 		afterOutcome := result.Damage
 
+		if target.Metrics.isTanking {
+			result.setMitigation(afterAttackMods, afterArmor, afterTargetMods, afterOutcome)
+		}
+
 		spell.ApplyPostOutcomeDamageModifiers(sim, result, isPeriodic)
 		afterPostOutcome := result.Damage
 
-		spell.Unit.Log(
-			sim,
-			"%s %s [DEBUG] MAP: %0.01f, RAP: %0.01f, SP: %0.01f, BaseDamage:%0.01f, AfterAttackerMods:%0.01f, AfterArmor:%0.01f, AfterTargetMods:%0.01f, AfterOutcome:%0.01f, AfterPostOutcome:%0.01f",
-			target.LogLabel(), spell.ActionID, spell.Unit.GetStat(stats.AttackPower), spell.Unit.GetStat(stats.RangedAttackPower), spell.SpellPower(), baseDamage, afterAttackMods, afterArmor, afterTargetMods, afterOutcome, afterPostOutcome)
+		if sim.Log != nil {
+			spell.Unit.Log(
+				sim,
+				"%s %s [DEBUG] MAP: %0.01f, RAP: %0.01f, SP: %0.01f, BaseDamage:%0.01f, AfterAttackerMods:%0.01f, AfterArmor:%0.01f, AfterTargetMods:%0.01f, AfterOutcome:%0.01f, AfterPostOutcome:%0.01f",
+				target.LogLabel(), spell.ActionID, spell.Unit.GetStat(stats.AttackPower), spell.Unit.GetStat(stats.RangedAttackPower), spell.SpellPower(), baseDamage, afterAttackMods, afterArmor, afterTargetMods, afterOutcome, afterPostOutcome)
+		}
 	}
 
 	result.Threat = spell.ThreatFromDamage(sim, result.Outcome, result.Damage, attackTable)
@@ -337,6 +352,13 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 			spell.SpellMetrics[result.Target.UnitIndex].TotalBlockDamage += result.Damage
 		}
 		spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
+		if sim.Encounter.aggro != nil {
+			result.Target.addThreat(sim, spell.Unit, result.Threat)
+		}
+
+		if result.mitigation.tracked && spell.Unit.IsOpponent(result.Target) {
+			result.Target.Metrics.addDamageTaken(spell, result)
+		}
 	}
 
 	// Mark total damage done in raid so far for health based fights.
@@ -352,6 +374,9 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
 		}
 	}
+	if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		sim.CombatLog.AddSpellResult(sim, spell, result, isPeriodic, false)
+	}
 
 	if !spell.Flags.Matches(SpellFlagNoOnDamageDealt) {
 		if isPeriodic {
@@ -559,7 +584,12 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 	}
 	spell.SpellMetrics[result.Target.UnitIndex].TotalHealing += result.Damage
 	spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
+	if sim.Encounter.aggro != nil && sim.CurrentTime >= 0 {
+		spell.Unit.addHealingThreat(sim, result.Threat)
+	}
 	if result.Target.HasHealthBar() {
+		missingHealth := result.Target.MaxHealth() - result.Target.CurrentHealth()
+		spell.SpellMetrics[result.Target.UnitIndex].TotalOverhealing += max(0, result.Damage-missingHealth)
 		result.Target.GainHealth(sim, result.Damage, spell.HealthMetrics(result.Target))
 	}
 
@@ -570,6 +600,9 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
 		}
 	}
+	if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		sim.CombatLog.AddSpellResult(sim, spell, result, isPeriodic, true)
+	}
 
 	if isPeriodic {
 		spell.Unit.OnPeriodicHealDealt(sim, spell, result)
@@ -0,0 +1,238 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Bonus stats added to measure how final stats follow bonus stats.
+const statScalingProbeAmount = 1000.0
+
+// Returns the values of an axis, and the amounts to add to the bonus stats to
+// reach them. For totals, finalPerBonus is how much the final stat changes per
+// point of bonus stats, so targets are converted through the stat's multipliers.
+func statScalingAxisValues(axis *proto.StatScalingAxis, finalStat float64, finalPerBonus float64) ([]float64, []float64) {
+	if axis == nil {
+		return []float64{0}, []float64{0}
+	}
+
+	values := make([]float64, axis.Steps)
+	mods := make([]float64, axis.Steps)
+	for i := range values {
+		if axis.Steps == 1 {
+			values[i] = axis.Min
+		} else {
+			values[i] = axis.Min + (axis.Max-axis.Min)*float64(i)/float64(axis.Steps-1)
+		}
+
+		mods[i] = values[i]
+		if axis.Total {
+			mods[i] = (values[i] - finalStat) / finalPerBonus
+		}
+	}
+	return values, mods
+}
+
+// Returns the player's final stats with extra bonus stats added.
+func statScalingFinalStats(raid *proto.Raid, encounter *proto.Encounter, extraBonus stats.Stats) stats.Stats {
+	raid = googleProto.Clone(raid).(*proto.Raid)
+	bonusStats := raid.Parties[0].Players[0].BonusStats
+	for i := range bonusStats.Stats {
+		bonusStats.Stats[i] += extraBonus[i]
+	}
+
+	computeStatsResult := ComputeStats(&proto.ComputeStatsRequest{
+		Raid:      raid,
+		Encounter: encounter,
+	})
+	return stats.FromProtoArray(computeStatsResult.RaidStats.Parties[0].Players[0].FinalStats.Stats)
+}
+
+// Returns the final value of an axis's stat, and how much it changes per point
+// of bonus stats. Stat dependencies are linear, so one probe is enough.
+func statScalingAxisTotal(axis *proto.StatScalingAxis, raid *proto.Raid, encounter *proto.Encounter, finalStats stats.Stats) (float64, float64) {
+	if axis == nil || !axis.Total {
+		return 0, 1
+	}
+
+	var probe stats.Stats
+	probe[axis.Stat] = statScalingProbeAmount
+	probeStats := statScalingFinalStats(raid, encounter, probe)
+
+	finalPerBonus := (probeStats[axis.Stat] - finalStats[axis.Stat]) / statScalingProbeAmount
+	if finalPerBonus <= 0 {
+		finalPerBonus = 1
+	}
+	return finalStats[axis.Stat], finalPerBonus
+}
+
+func validateStatScalingAxis(axis *proto.StatScalingAxis) error {
+	if axis.Steps < 1 {
+		return errors.New("stat scaling axis needs at least 1 step")
+	}
+	if axis.Stat < 0 || int(axis.Stat) >= stats.ProtoStatsLen {
+		return fmt.Errorf("invalid stat for stat scaling: %d", axis.Stat)
+	}
+	return nil
+}
+
+func buildStatScalingBaseRequest(request *proto.StatScalingRequest) *proto.RaidSimRequest {
+	player := googleProto.Clone(request.Player).(*proto.Player)
+	if player.BonusStats == nil {
+		player.BonusStats = &proto.UnitStats{}
+	}
+	if player.BonusStats.Stats == nil {
+		player.BonusStats.Stats = make([]float64, stats.ProtoStatsLen)
+	}
+
+	raidProto := SinglePlayerRaidProto(player, request.PartyBuffs, request.RaidBuffs, request.Debuffs)
+	raidProto.Tanks = request.Tanks
+
+	simOptions := googleProto.Clone(request.SimOptions).(*proto.SimOptions)
+	simOptions.SaveAllValues = true
+	// Every point needs the same iterations for their random numbers to line up.
+	simOptions.TargetPrecision = nil
+	if simOptions.RandomSeed == 0 {
+		simOptions.RandomSeed = time.Now().UnixNano()
+	}
+	simOptions.UseLabeledRands = true
+
+	return &proto.RaidSimRequest{
+		Raid:       raidProto,
+		Encounter:  request.Encounter,
+		SimOptions: simOptions,
+	}
+}
+
+// Sims every point of a grid over one or two stats and returns how each metric
+// changes with them.
+func runStatScaling(request *proto.StatScalingRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.StatScalingResult {
+	errorResult := func(err error) *proto.StatScalingResult {
+		return &proto.StatScalingResult{Error: &proto.ErrorOutcome{Message: err.Error()}}
+	}
+
+	if request.Player == nil || request.SimOptions == nil {
+		return errorResult(errors.New("stat scaling request needs a player and sim options"))
+	}
+	if request.X == nil {
+		return errorResult(errors.New("stat scaling request has no stat to sweep"))
+	}
+	for _, axis := range []*proto.StatScalingAxis{request.X, request.Y} {
+		if axis == nil {
+			continue
+		}
+		if err := validateStatScalingAxis(axis); err != nil {
+			return errorResult(err)
+		}
+	}
+
+	baseRequest := buildStatScalingBaseRequest(request)
+
+	var finalStats stats.Stats
+	if request.X.Total || (request.Y != nil && request.Y.Total) {
+		finalStats = statScalingFinalStats(baseRequest.Raid, request.Encounter, stats.Stats{})
+	}
+	xTotal, xFinalPerBonus := statScalingAxisTotal(request.X, baseRequest.Raid, request.Encounter, finalStats)
+	yTotal, yFinalPerBonus := statScalingAxisTotal(request.Y, baseRequest.Raid, request.Encounter, finalStats)
+	xValues, xMods := statScalingAxisValues(request.X, xTotal, xFinalPerBonus)
+	yValues, yMods := statScalingAxisValues(request.Y, yTotal, yFinalPerBonus)
+
+	var iterationsTotal int32 = baseRequest.SimOptions.Iterations * int32(len(xValues)*len(yValues))
+	var iterationsDone int32 = 0
+	var simsTotal int32 = int32(len(xValues) * len(yValues))
+	var simsCompleted int32 = 0
+
+	waitForResult := func(srcProgressChannel chan *proto.ProgressMetrics) *proto.RaidSimResult {
+		var lastCompleted int32 = 0
+		for metrics := range srcProgressChannel {
+			iterationsDone += metrics.CompletedIterations - lastCompleted
+			lastCompleted = metrics.CompletedIterations
+
+			if progress != nil {
+				progress <- &proto.ProgressMetrics{
+					TotalIterations:     iterationsTotal,
+					CompletedIterations: iterationsDone,
+					CompletedSims:       simsCompleted,
+					TotalSims:           simsTotal,
+				}
+			}
+
+			if metrics.FinalRaidResult != nil {
+				simsCompleted++
+				return metrics.FinalRaidResult
+			}
+		}
+		return nil
+	}
+
+	simFunc := runSimConcurrent
+	// Don't use go threads in wasm, it just adds more overhead and makes the worker more unresponsive.
+	if IsRunningInWasm() {
+		simFunc = RunSim
+	}
+
+	result := &proto.StatScalingResult{
+		XSteps: int32(len(xValues)),
+		YSteps: int32(len(yValues)),
+	}
+	var first *proto.UnitMetrics
+	for yIdx, yValue := range yValues {
+		for xIdx, xValue := range xValues {
+			simRequest := googleProto.Clone(baseRequest).(*proto.RaidSimRequest)
+			bonusStats := simRequest.Raid.Parties[0].Players[0].BonusStats
+			bonusStats.Stats[request.X.Stat] += xMods[xIdx]
+			if request.Y != nil {
+				bonusStats.Stats[request.Y.Stat] += yMods[yIdx]
+			}
+
+			simProgress := make(chan *proto.ProgressMetrics, 100)
+			go simFunc(simRequest, simProgress, signals)
+			simResult := waitForResult(simProgress)
+			if simResult.Error != nil {
+				return &proto.StatScalingResult{Error: simResult.Error}
+			}
+
+			player := simResult.RaidMetrics.Parties[0].Players[0]
+			if first == nil {
+				first = player
+			}
+			result.Points = append(result.Points, &proto.StatScalingPoint{
+				X:    xValue,
+				Y:    yValue,
+				Dps:  newStatScalingValue(player.Dps, first.Dps),
+				Hps:  newStatScalingValue(player.Hps, first.Hps),
+				Tps:  newStatScalingValue(player.Threat, first.Threat),
+				Dtps: newStatScalingValue(player.Dtps, first.Dtps),
+			})
+		}
+	}
+	return result
+}
+
+func newStatScalingValue(metrics *proto.DistributionMetrics, first *proto.DistributionMetrics) *proto.StatScalingValue {
+	var value, delta aggregator
+	for i, v := range metrics.AllValues {
+		value.add(v)
+		if i < len(first.AllValues) {
+			delta.add(v - first.AllValues[i])
+		}
+	}
+	if value.n == 0 {
+		return &proto.StatScalingValue{}
+	}
+
+	avg, stdev := value.meanAndStdDev()
+	deltaAvg, deltaStdev := delta.meanAndStdDev()
+	return &proto.StatScalingValue{
+		Avg:              avg,
+		CiHalfWidth:      ciHalfWidth(stdev, value.n),
+		Delta:            deltaAvg,
+		DeltaCiHalfWidth: ciHalfWidth(deltaStdev, delta.n),
+	}
+}
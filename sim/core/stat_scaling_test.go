@@ -0,0 +1,79 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+func TestStatScalingAxisValues(t *testing.T) {
+	values, mods := statScalingAxisValues(nil, 0, 1)
+	if len(values) != 1 || values[0] != 0 || mods[0] != 0 {
+		t.Fatalf("Expected a missing axis to have a single zero value, got %v and %v", values, mods)
+	}
+
+	added := &proto.StatScalingAxis{Stat: proto.Stat_StatCritRating, Min: 0, Max: 1000, Steps: 3}
+	values, mods = statScalingAxisValues(added, 5000, 2)
+	for i, expected := range []float64{0, 500, 1000} {
+		if values[i] != expected || mods[i] != expected {
+			t.Fatalf("Expected step %d to add %f, got value %f and mod %f", i, expected, values[i], mods[i])
+		}
+	}
+
+	total := &proto.StatScalingAxis{Stat: proto.Stat_StatStamina, Min: 1200, Max: 1200, Steps: 1, Total: true}
+	values, mods = statScalingAxisValues(total, 1000, 1.1)
+	if values[0] != 1200 || !WithinToleranceFloat64(200/1.1, mods[0], 0.0001) {
+		t.Fatalf("Expected a total of 1200 to add %f, got value %f and mod %f", 200/1.1, values[0], mods[0])
+	}
+}
+
+func TestStatScalingTotalsThroughMultipliers(t *testing.T) {
+	request := &proto.StatScalingRequest{
+		Player: &proto.Player{
+			Name:      "Caster",
+			Class:     proto.Class_ClassShaman,
+			Buffs:     &proto.IndividualBuffs{},
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: &proto.EquipmentSpec{},
+		},
+		PartyBuffs: &proto.PartyBuffs{},
+		RaidBuffs:  &proto.RaidBuffs{PowerWordFortitude: true},
+		Debuffs:    &proto.Debuffs{},
+		Encounter: &proto.Encounter{
+			Targets: []*proto.Target{
+				{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+			},
+			Duration: 180,
+		},
+		SimOptions: &proto.SimOptions{RandomSeed: 101},
+	}
+	baseRequest := buildStatScalingBaseRequest(request)
+
+	finalStats := statScalingFinalStats(baseRequest.Raid, request.Encounter, stats.Stats{})
+	axis := &proto.StatScalingAxis{
+		Stat:  proto.Stat_StatStamina,
+		Min:   finalStats[stats.Stamina] + 1000,
+		Max:   finalStats[stats.Stamina] + 5000,
+		Steps: 3,
+		Total: true,
+	}
+
+	total, finalPerBonus := statScalingAxisTotal(axis, baseRequest.Raid, request.Encounter, finalStats)
+	if total != finalStats[stats.Stamina] {
+		t.Fatalf("Expected the axis total to start at the final Stamina %f, got %f", finalStats[stats.Stamina], total)
+	}
+	if finalPerBonus <= 1 {
+		t.Fatalf("Expected Power Word: Fortitude to multiply bonus Stamina, got %f final per bonus", finalPerBonus)
+	}
+
+	values, mods := statScalingAxisValues(axis, total, finalPerBonus)
+	for i := range values {
+		var bonus stats.Stats
+		bonus[stats.Stamina] = mods[i]
+		actual := statScalingFinalStats(baseRequest.Raid, request.Encounter, bonus)[stats.Stamina]
+		if !WithinToleranceFloat64(values[i], actual, 1) {
+			t.Fatalf("Expected step %d to reach %f Stamina, got %f", i, values[i], actual)
+		}
+	}
+}
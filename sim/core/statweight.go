@@ -66,6 +66,7 @@ type StatWeightValues struct {
 	WeightsStdev  UnitStats
 	EpValues      UnitStats
 	EpValuesStdev UnitStats
+	CrossTerms    []*proto.StatWeightsCrossTerm
 }
 
 func NewStatWeightValues() StatWeightValues {
@@ -83,6 +84,7 @@ func (swv *StatWeightValues) ToProto() *proto.StatWeightValues {
 		WeightsStdev:  swv.WeightsStdev.ExportWeights(),
 		EpValues:      swv.EpValues.ExportWeights(),
 		EpValuesStdev: swv.EpValuesStdev.ExportWeights(),
+		CrossTerms:    swv.CrossTerms,
 	}
 }
 
@@ -136,6 +138,8 @@ func buildStatWeightRequests(swr *proto.StatWeightsRequest) *proto.StatWeightReq
 	// Cut in half since we're doing above and below separately.
 	// This number needs to be the same for the baseline sim too, so that RNG lines up perfectly.
 	swr.SimOptions.Iterations /= 2
+	// Stopping early would also break that.
+	swr.SimOptions.TargetPrecision = nil
 
 	// Make sure an RNG seed is always set because it gives more consistent results.
 	// When there is no user-supplied seed it needs to be a randomly-selected seed
@@ -157,14 +161,52 @@ func buildStatWeightRequests(swr *proto.StatWeightsRequest) *proto.StatWeightReq
 		StatSimRequests: []*proto.StatWeightsStatRequestData{},
 	}
 
+	statModsHigh := statWeightMods(swr)
+	if swr.Method == proto.StatWeightsMethod_StatWeightsMethodRegression {
+		addRegressionSampleRequests(swr, swBaseResponse, statModsHigh)
+		return swBaseResponse
+	}
+
 	// Do half the iterations with a positive, and half with a negative value for better accuracy.
-	const defaultStatMod = 320.0 // match to the impact of a single gem for secondaries
 	statModsLow := make([]float64, stats.UnitStatsLen)
-	statModsHigh := make([]float64, stats.UnitStatsLen)
+	for i, statMod := range statModsHigh {
+		statModsLow[i] = -statMod
+	}
+
+	for i := range statModsLow {
+		stat := stats.UnitStatFromIdx(i)
+		if statModsLow[stat] == 0 {
+			continue
+		}
+
+		lowSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
+		stat.AddToStatsProto(lowSimRequest.Raid.Parties[0].Players[0].BonusStats, statModsLow[stat])
+
+		highSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
+		stat.AddToStatsProto(highSimRequest.Raid.Parties[0].Players[0].BonusStats, statModsHigh[stat])
+
+		swBaseResponse.StatSimRequests = append(swBaseResponse.StatSimRequests, &proto.StatWeightsStatRequestData{
+			StatData: &proto.StatWeightsStatData{
+				UnitStat: int32(stat),
+				ModLow:   statModsLow[stat],
+				ModHigh:  statModsHigh[stat],
+			},
+			RequestLow:  lowSimRequest,
+			RequestHigh: highSimRequest,
+		})
+	}
+
+	return swBaseResponse
+}
+
+// Returns the amount to change each weighed stat by, 0 for stats which are not
+// weighed.
+func statWeightMods(swr *proto.StatWeightsRequest) []float64 {
+	const defaultStatMod = 320.0 // match to the impact of a single gem for secondaries
+	statMods := make([]float64, stats.UnitStatsLen)
 
 	// Make sure reference stat is included.
-	statModsLow[swr.EpReferenceStat] = -defaultStatMod
-	statModsHigh[swr.EpReferenceStat] = defaultStatMod
+	statMods[swr.EpReferenceStat] = defaultStatMod
 
 	statsToWeigh := stats.ProtoArrayToStatsList(swr.StatsToWeigh)
 	for _, s := range statsToWeigh {
@@ -176,8 +218,7 @@ func buildStatWeightRequests(swr *proto.StatWeightsRequest) *proto.StatWeightReq
 		} else if stat.EqualsStat(stats.Armor) || stat.EqualsStat(stats.BonusArmor) {
 			statMod = defaultStatMod * 10
 		}
-		statModsHigh[stat] = statMod
-		statModsLow[stat] = -statMod
+		statMods[stat] = statMod
 	}
 	for _, s := range swr.PseudoStatsToWeigh {
 		stat := stats.UnitStatFromPseudoStat(s)
@@ -197,8 +238,7 @@ func buildStatWeightRequests(swr *proto.StatWeightsRequest) *proto.StatWeightReq
 			panic(fmt.Sprintf("Unsupported PseudoStat in stat weights request: %s", statName))
 		}
 
-		statModsHigh[stat] = statMod
-		statModsLow[stat] = -statMod
+		statMods[stat] = statMod
 
 		// If a school-specific Hit/Crit percentage stat is being
 		// weighed, then remove the base Rating stat from the request to
@@ -206,42 +246,21 @@ func buildStatWeightRequests(swr *proto.StatWeightsRequest) *proto.StatWeightReq
 		// reconstructed from the PseudoStat EPs when writing the final
 		// results.
 		if strings.Contains(statName, "Hit") {
-			statModsLow[stats.HitRating] = 0
-			statModsHigh[stats.HitRating] = 0
+			statMods[stats.HitRating] = 0
 		} else if strings.Contains(statName, "Crit") {
-			statModsLow[stats.CritRating] = 0
-			statModsHigh[stats.CritRating] = 0
-		}
-
-	}
-
-	for i := range statModsLow {
-		stat := stats.UnitStatFromIdx(i)
-		if statModsLow[stat] == 0 {
-			continue
+			statMods[stats.CritRating] = 0
 		}
 
-		lowSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
-		stat.AddToStatsProto(lowSimRequest.Raid.Parties[0].Players[0].BonusStats, statModsLow[stat])
-
-		highSimRequest := googleProto.Clone(swBaseResponse.BaseRequest).(*proto.RaidSimRequest)
-		stat.AddToStatsProto(highSimRequest.Raid.Parties[0].Players[0].BonusStats, statModsHigh[stat])
-
-		swBaseResponse.StatSimRequests = append(swBaseResponse.StatSimRequests, &proto.StatWeightsStatRequestData{
-			StatData: &proto.StatWeightsStatData{
-				UnitStat: int32(stat),
-				ModLow:   statModsLow[stat],
-				ModHigh:  statModsHigh[stat],
-			},
-			RequestLow:  lowSimRequest,
-			RequestHigh: highSimRequest,
-		})
 	}
 
-	return swBaseResponse
+	return statMods
 }
 
 func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsResult {
+	if len(swcr.SampleSimResults) > 0 {
+		return computeRegressionStatWeights(swcr)
+	}
+
 	haveRefStat := false
 	for _, statResult := range swcr.StatSimResults {
 		if statResult.StatData.UnitStat == int32(swcr.EpReferenceStat) {
@@ -294,12 +313,17 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 		result.PDeath.WeightsStdev.AddStat(stat, 0)
 	}
 
-	referenceStat := stats.Stat(swcr.EpReferenceStat)
+	weighedStats := make([]stats.UnitStat, len(swcr.StatSimResults))
+	for i, statData := range swcr.StatSimResults {
+		weighedStats[i] = stats.UnitStatFromIdx(int(statData.StatData.UnitStat))
+	}
+	computeEpValues(result, weighedStats, stats.Stat(swcr.EpReferenceStat))
 
-	// Compute EP results.
-	for _, statData := range swcr.StatSimResults {
-		stat := stats.UnitStatFromIdx(int(statData.StatData.UnitStat))
+	return result.ToProto()
+}
 
+func computeEpValues(result *StatWeightsResult, weighedStats []stats.UnitStat, referenceStat stats.Stat) {
+	for _, stat := range weighedStats {
 		calcEpResults := func(weightResults *StatWeightValues, refStat stats.Stat) {
 			if weightResults.Weights.Stats[refStat] == 0 {
 				return
@@ -317,8 +341,6 @@ func computeStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsRe
 		calcEpResults(&result.Tmi, DTPSReferenceStat)
 		calcEpResults(&result.PDeath, DTPSReferenceStat)
 	}
-
-	return result.ToProto()
 }
 
 // Run stat weight sims and compute weights.
@@ -335,6 +357,10 @@ func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.Prog
 		iterationsTotal += reqData.RequestHigh.SimOptions.Iterations
 		simsTotal += 2
 	}
+	for _, reqData := range requestData.SampleSimRequests {
+		iterationsTotal += reqData.Request.SimOptions.Iterations
+		simsTotal++
+	}
 
 	waitForResult := func(srcProgressChannel chan *proto.ProgressMetrics) *proto.RaidSimResult {
 		var lastCompleted int32 = 0
@@ -396,9 +422,28 @@ func runStatWeights(request *proto.StatWeightsRequest, progress chan *proto.Prog
 		})
 	}
 
+	sampleResults := []*proto.StatWeightsSampleResultData{}
+
+	for _, reqData := range requestData.SampleSimRequests {
+		sampleProgress := make(chan *proto.ProgressMetrics, 100)
+		go simFunc(reqData.Request, sampleProgress, signals)
+		sampleRes := waitForResult(sampleProgress)
+		if sampleRes.Error != nil {
+			return &proto.StatWeightsResult{Error: sampleRes.Error}
+		}
+
+		sampleResults = append(sampleResults, &proto.StatWeightsSampleResultData{
+			StatMods: reqData.StatMods,
+			Result:   sampleRes,
+		})
+	}
+
 	return computeStatWeights(&proto.StatWeightsCalcRequest{
-		BaseResult:      baselineResult,
-		EpReferenceStat: requestData.EpReferenceStat,
-		StatSimResults:  statResults,
+		BaseResult:          baselineResult,
+		EpReferenceStat:     requestData.EpReferenceStat,
+		StatSimResults:      statResults,
+		RegressionUnitStats: requestData.RegressionUnitStats,
+		RegressionQuadratic: requestData.RegressionQuadratic,
+		SampleSimResults:    sampleResults,
 	})
 }
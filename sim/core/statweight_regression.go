@@ -0,0 +1,330 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Stat weights by regression: instead of changing one stat at a time, each
+// sample sim changes every weighed stat at once by a random fraction of its
+// finite difference step, and a linear or quadratic model is fitted to the
+// per-iteration results of all samples. Labeled rands give each iteration the
+// same RNG in every sample, so the model has a separate intercept for each
+// iteration, which cancels out most of the noise.
+
+// Samples added beyond the number of model terms when none are requested.
+const regressionExtraSamples = 4
+
+func numRegressionTerms(numStats int, quadratic bool) int {
+	if quadratic {
+		return numStats + numStats*(numStats+1)/2
+	}
+	return numStats
+}
+
+// Returns the model terms of a sample: the stat changes scaled to [-1, 1],
+// followed by each product of two of them for quadratic models.
+func regressionTerms(scaledMods []float64, quadratic bool) []float64 {
+	terms := slices.Clone(scaledMods)
+	if quadratic {
+		for i := range scaledMods {
+			for j := i; j < len(scaledMods); j++ {
+				terms = append(terms, scaledMods[i]*scaledMods[j])
+			}
+		}
+	}
+	return terms
+}
+
+func addRegressionSampleRequests(swr *proto.StatWeightsRequest, requestData *proto.StatWeightRequestsData, statMods []float64) {
+	var unitStats []stats.UnitStat
+	for i, statMod := range statMods {
+		if statMod != 0 {
+			unitStats = append(unitStats, stats.UnitStatFromIdx(i))
+			requestData.RegressionUnitStats = append(requestData.RegressionUnitStats, int32(i))
+		}
+	}
+	requestData.RegressionQuadratic = swr.RegressionQuadratic
+
+	// Together with the base sim this leaves at least one degree of freedom
+	// for the error of each term.
+	minSamples := numRegressionTerms(len(unitStats), swr.RegressionQuadratic) + 1
+	numSamples := int(swr.RegressionSamples)
+	if numSamples <= 0 {
+		numSamples = minSamples - 1 + regressionExtraSamples
+	}
+	numSamples = max(numSamples, minSamples)
+
+	// Seeded by the sim so the same request always samples the same stats.
+	rand := NewSplitMix(uint64(swr.SimOptions.RandomSeed))
+	for range numSamples {
+		request := googleProto.Clone(requestData.BaseRequest).(*proto.RaidSimRequest)
+		mods := make([]float64, len(unitStats))
+		for i, stat := range unitStats {
+			mods[i] = statMods[stat] * (2*rand.NextFloat64() - 1)
+			stat.AddToStatsProto(request.Raid.Parties[0].Players[0].BonusStats, mods[i])
+		}
+
+		requestData.SampleSimRequests = append(requestData.SampleSimRequests, &proto.StatWeightsSampleRequestData{
+			StatMods: mods,
+			Request:  request,
+		})
+	}
+}
+
+func computeRegressionStatWeights(swcr *proto.StatWeightsCalcRequest) *proto.StatWeightsResult {
+	if !slices.Contains(swcr.RegressionUnitStats, int32(swcr.EpReferenceStat)) {
+		return &proto.StatWeightsResult{Error: &proto.ErrorOutcome{Message: "No result for reference stat exists!"}}
+	}
+	unitStats := MapSlice(swcr.RegressionUnitStats, func(stat int32) stats.UnitStat {
+		return stats.UnitStatFromIdx(int(stat))
+	})
+	quadratic := swcr.RegressionQuadratic
+
+	// Scale each stat by its largest change so all terms have a similar size.
+	scales := make([]float64, len(unitStats))
+	for _, sample := range swcr.SampleSimResults {
+		for i, mod := range sample.StatMods {
+			scales[i] = max(scales[i], math.Abs(mod))
+		}
+	}
+	for i, scale := range scales {
+		if scale == 0 {
+			return &proto.StatWeightsResult{Error: &proto.ErrorOutcome{Message: fmt.Sprintf("No sample changes stat %d", unitStats[i])}}
+		}
+	}
+
+	// The base sim is the sample without any stat changes.
+	terms := [][]float64{make([]float64, numRegressionTerms(len(unitStats), quadratic))}
+	players := []*proto.UnitMetrics{swcr.BaseResult.RaidMetrics.Parties[0].Players[0]}
+	for _, sample := range swcr.SampleSimResults {
+		scaledMods := make([]float64, len(unitStats))
+		for i, mod := range sample.StatMods {
+			scaledMods[i] = mod / scales[i]
+		}
+		terms = append(terms, regressionTerms(scaledMods, quadratic))
+		players = append(players, sample.Result.RaidMetrics.Parties[0].Players[0])
+	}
+
+	model, ok := newRegressionModel(terms)
+	if !ok {
+		return &proto.StatWeightsResult{Error: &proto.ErrorOutcome{Message: "Stat weight samples are too similar to fit a model, use more samples"}}
+	}
+
+	result := NewStatWeightsResult()
+	fitMetric := func(values [][]float64, weightResults *StatWeightValues) {
+		coefficients, stdevs, ok := model.fit(values)
+		if !ok {
+			return
+		}
+
+		// The weight is the slope at the base stats, where quadratic terms
+		// don't contribute.
+		for i, stat := range unitStats {
+			weightResults.Weights.AddStat(stat, coefficients[i]/scales[i])
+			weightResults.WeightsStdev.AddStat(stat, stdevs[i]/scales[i])
+		}
+
+		if quadratic {
+			term := len(unitStats)
+			for i := range unitStats {
+				for j := i; j < len(unitStats); j++ {
+					scale := scales[i] * scales[j]
+					weightResults.CrossTerms = append(weightResults.CrossTerms, &proto.StatWeightsCrossTerm{
+						UnitStat_1: int32(unitStats[i]),
+						UnitStat_2: int32(unitStats[j]),
+						Value:      coefficients[term] / scale,
+						Stdev:      stdevs[term] / scale,
+					})
+					term++
+				}
+			}
+		}
+	}
+	allValues := func(getMetrics func(*proto.UnitMetrics) *proto.DistributionMetrics) [][]float64 {
+		return MapSlice(players, func(player *proto.UnitMetrics) []float64 {
+			return getMetrics(player).AllValues
+		})
+	}
+
+	fitMetric(allValues(func(p *proto.UnitMetrics) *proto.DistributionMetrics { return p.Dps }), &result.Dps)
+	fitMetric(allValues(func(p *proto.UnitMetrics) *proto.DistributionMetrics { return p.Hps }), &result.Hps)
+	fitMetric(allValues(func(p *proto.UnitMetrics) *proto.DistributionMetrics { return p.Threat }), &result.Tps)
+	fitMetric(allValues(func(p *proto.UnitMetrics) *proto.DistributionMetrics { return p.Dtps }), &result.Dtps)
+	fitMetric(allValues(func(p *proto.UnitMetrics) *proto.DistributionMetrics { return p.Tmi }), &result.Tmi)
+	// Chance of death only exists for the whole sim, so it is fitted as a
+	// single iteration.
+	fitMetric(MapSlice(players, func(player *proto.UnitMetrics) []float64 {
+		return []float64{player.ChanceOfDeath}
+	}), &result.PDeath)
+
+	computeEpValues(result, unitStats, stats.Stat(swcr.EpReferenceStat))
+	return result.ToProto()
+}
+
+// Least squares fit of sim results to the terms of each sample, with a
+// separate intercept for each iteration.
+type regressionModel struct {
+	centered [][]float64 // Terms minus their mean over all samples.
+	inverse  [][]float64 // Inverse of centered^T * centered.
+}
+
+func newRegressionModel(terms [][]float64) (*regressionModel, bool) {
+	numSamples := len(terms)
+	numTerms := len(terms[0])
+
+	means := make([]float64, numTerms)
+	for _, sampleTerms := range terms {
+		for k, term := range sampleTerms {
+			means[k] += term / float64(numSamples)
+		}
+	}
+	centered := MapSlice(terms, func(sampleTerms []float64) []float64 {
+		row := make([]float64, numTerms)
+		for k, term := range sampleTerms {
+			row[k] = term - means[k]
+		}
+		return row
+	})
+
+	gram := make([][]float64, numTerms)
+	for a := range gram {
+		gram[a] = make([]float64, numTerms)
+		for b := range gram[a] {
+			for _, row := range centered {
+				gram[a][b] += row[a] * row[b]
+			}
+		}
+	}
+
+	inverse, ok := invertMatrix(gram)
+	if !ok {
+		return nil, false
+	}
+	return &regressionModel{centered: centered, inverse: inverse}, true
+}
+
+// Returns the coefficient and standard error of each term, given the values of
+// every iteration of every sample. Fails if there are no values, samples have
+// different numbers of iterations, or there are too few to estimate errors.
+func (model *regressionModel) fit(values [][]float64) ([]float64, []float64, bool) {
+	numSamples := len(model.centered)
+	numTerms := len(model.inverse)
+	numIterations := len(values[0])
+	if numIterations == 0 {
+		return nil, nil, false
+	}
+	for _, sampleValues := range values {
+		if len(sampleValues) != numIterations {
+			return nil, nil, false
+		}
+	}
+
+	degreesOfFreedom := numIterations*(numSamples-1) - numTerms
+	if degreesOfFreedom <= 0 {
+		return nil, nil, false
+	}
+
+	// With an intercept per iteration, the coefficients only depend on the
+	// mean of each sample. Centered terms sum to 0, so the means don't need
+	// centering.
+	sampleMeans := MapSlice(values, func(sampleValues []float64) float64 {
+		sum := 0.0
+		for _, value := range sampleValues {
+			sum += value
+		}
+		return sum / float64(numIterations)
+	})
+
+	termSums := make([]float64, numTerms)
+	for s, row := range model.centered {
+		for k, term := range row {
+			termSums[k] += term * sampleMeans[s]
+		}
+	}
+	coefficients := make([]float64, numTerms)
+	for a := range coefficients {
+		for b, sum := range termSums {
+			coefficients[a] += model.inverse[a][b] * sum
+		}
+	}
+
+	fitted := MapSlice(model.centered, func(row []float64) float64 {
+		sum := 0.0
+		for k, term := range row {
+			sum += term * coefficients[k]
+		}
+		return sum
+	})
+
+	residualSumSq := 0.0
+	for i := 0; i < numIterations; i++ {
+		iterationMean := 0.0
+		for _, sampleValues := range values {
+			iterationMean += sampleValues[i] / float64(numSamples)
+		}
+		for s, sampleValues := range values {
+			residual := sampleValues[i] - iterationMean - fitted[s]
+			residualSumSq += residual * residual
+		}
+	}
+	variance := residualSumSq / float64(degreesOfFreedom)
+
+	stdevs := make([]float64, numTerms)
+	for k := range stdevs {
+		stdevs[k] = math.Sqrt(variance * model.inverse[k][k] / float64(numIterations))
+	}
+	return coefficients, stdevs, true
+}
+
+// Gauss-Jordan elimination with partial pivoting. Fails for singular matrices.
+func invertMatrix(matrix [][]float64) ([][]float64, bool) {
+	n := len(matrix)
+	work := make([][]float64, n)
+	for i := range work {
+		work[i] = make([]float64, 2*n)
+		copy(work[i], matrix[i])
+		work[i][n+i] = 1
+	}
+
+	largest := 0.0
+	for _, row := range matrix {
+		for _, v := range row {
+			largest = max(largest, math.Abs(v))
+		}
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(work[row][col]) > math.Abs(work[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(work[pivot][col]) <= 1e-12*largest {
+			return nil, false
+		}
+		work[col], work[pivot] = work[pivot], work[col]
+
+		scale := 1 / work[col][col]
+		for j := range work[col] {
+			work[col][j] *= scale
+		}
+		for row := range work {
+			if row == col || work[row][col] == 0 {
+				continue
+			}
+			factor := work[row][col]
+			for j := range work[row] {
+				work[row][j] -= factor * work[col][j]
+			}
+		}
+	}
+
+	return MapSlice(work, func(row []float64) []float64 { return row[n:] }), true
+}
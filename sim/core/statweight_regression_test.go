@@ -0,0 +1,49 @@
+package core
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRegressionModelFit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	coefficients := []float64{300, 100, -40, 10, 0}
+
+	// A quadratic model of two stats, with a large amount of noise shared by
+	// every sample of an iteration and a little noise of its own.
+	numSamples := 12
+	numIterations := 2000
+	terms := [][]float64{regressionTerms([]float64{0, 0}, true)}
+	for len(terms) < numSamples {
+		terms = append(terms, regressionTerms([]float64{2*rng.Float64() - 1, 2*rng.Float64() - 1}, true))
+	}
+	sharedNoise := make([]float64, numIterations)
+	for i := range sharedNoise {
+		sharedNoise[i] = rng.NormFloat64() * 1000
+	}
+	values := MapSlice(terms, func(sampleTerms []float64) []float64 {
+		sampleValues := make([]float64, numIterations)
+		for i := range sampleValues {
+			sampleValues[i] = 50000 + sharedNoise[i] + rng.NormFloat64()*20
+			for k, term := range sampleTerms {
+				sampleValues[i] += coefficients[k] * term
+			}
+		}
+		return sampleValues
+	})
+
+	model, ok := newRegressionModel(terms)
+	if !ok {
+		t.Fatalf("Failed to create model")
+	}
+	fitted, stdevs, ok := model.fit(values)
+	if !ok {
+		t.Fatalf("Failed to fit model")
+	}
+	for k, expected := range coefficients {
+		if math.Abs(fitted[k]-expected) > 4*stdevs[k] || stdevs[k] > 5 {
+			t.Errorf("Term %d: expected %f, got %f +/- %f", k, expected, fitted[k], stdevs[k])
+		}
+	}
+}
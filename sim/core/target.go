@@ -32,6 +32,9 @@ type Encounter struct {
 
 	// Value to multiply by, for damage spells which are subject to the aoe cap.
 	aoeCapMultiplier float64
+
+	// Threat tables of the targets, nil unless the aggro model is enabled.
+	aggro *aggroModel
 }
 
 func NewEncounter(options *proto.Encounter) Encounter {
@@ -136,6 +139,24 @@ func (encounter *Encounter) removeInactiveTarget(target *Target) {
 	encounter.updateAOECapMultiplier()
 }
 
+// Restores the targets which are enabled at the start of the encounter, in
+// their original order, undoing any targets enabled or disabled during the
+// previous iteration.
+func (encounter *Encounter) resetActiveTargets() {
+	encounter.ActiveTargets = encounter.ActiveTargets[:0]
+	encounter.ActiveTargetUnits = encounter.ActiveTargetUnits[:0]
+
+	for _, target := range encounter.AllTargets {
+		target.enabled = !target.disabledAtStart
+		if target.enabled {
+			encounter.ActiveTargets = append(encounter.ActiveTargets, target)
+			encounter.ActiveTargetUnits = append(encounter.ActiveTargetUnits, &target.Unit)
+		}
+	}
+
+	encounter.updateAOECapMultiplier()
+}
+
 func (encounter *Encounter) doneIteration(sim *Simulation) {
 	for _, target := range encounter.AllTargets {
 		target.doneIteration(sim)
@@ -159,6 +180,8 @@ type Target struct {
 	Unit
 
 	AI TargetAI
+
+	disabledAtStart bool
 }
 
 func NewTarget(options *proto.Target, targetIndex int32) *Target {
@@ -182,7 +205,12 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 			StatDependencyManager: stats.NewStatDependencyManager(),
 			ReactionTime:          time.Millisecond * 1620,
 			enabled:               !options.DisabledAtStart,
+
+			StartPosition:    Vector2FromProto(options.Position),
+			hasStartPosition: options.Position != nil,
 		},
+
+		disabledAtStart: options.DisabledAtStart,
 	}
 	defaultRaidBossLevel := int32(CharacterLevel + 3)
 	target.GCD = target.NewTimer()
@@ -206,8 +234,12 @@ func NewTarget(options *proto.Target, targetIndex int32) *Target {
 	target.PseudoStats.DamageSpread = options.DamageSpread
 
 	preset := GetPresetTargetWithID(options.Id)
-	if preset != nil && preset.AI != nil {
+	// A custom timeline replaces hand-written preset AIs, but presets built
+	// on timelines read it themselves.
+	if preset != nil && preset.AI != nil && (options.Timeline == nil || preset.Config.Timeline != nil) {
 		target.AI = preset.AI()
+	} else if options.Timeline != nil && timelineAI != nil {
+		target.AI = timelineAI()
 	}
 
 	return target
@@ -233,6 +265,11 @@ func (target *Target) Enable(sim *Simulation) {
 	if target.defaultTarget != nil {
 		target.defaultTarget.CurrentTarget = &target.Unit
 		target.CurrentTarget = target.defaultTarget
+
+		if sim.CombatLog != nil {
+			sim.CombatLog.AddTargetChange(sim, target.defaultTarget, &target.Unit)
+			sim.CombatLog.AddTargetChange(sim, &target.Unit, target.defaultTarget)
+		}
 	}
 
 	target.AutoAttacks.EnableAutoSwing(sim)
@@ -277,6 +314,9 @@ func (target *Target) Disable(sim *Simulation, expireAuras bool) {
 
 	if target.CurrentTarget != nil {
 		target.CurrentTarget.CurrentTarget = &target.NextActiveTarget().Unit
+		if sim.CombatLog != nil {
+			sim.CombatLog.AddTargetChange(sim, target.CurrentTarget, target.CurrentTarget.CurrentTarget)
+		}
 		target.CurrentTarget = nil
 	}
 }
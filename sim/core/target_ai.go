@@ -64,6 +64,14 @@ func (target *Target) ExecuteCustomRotation(sim *Simulation) {
 
 type AIFactory func() TargetAI
 
+// Builds the AI of targets with a BossTimeline. Set by the encounters package,
+// which core can't import.
+var timelineAI AIFactory
+
+func SetTimelineAI(factory AIFactory) {
+	timelineAI = factory
+}
+
 type PresetTarget struct {
 	// String in folder-structure format identifying a category for this unit, e.g. "Black Temple/Bosses".
 	PathPrefix string
@@ -114,7 +122,7 @@ func GetPresetTargetWithID(id int32) *PresetTarget {
 	return nil
 }
 
-func AddPresetEncounter(name string, targetPaths []string) {
+func AddPresetEncounter(name string, targetPaths []string) *proto.PresetEncounter {
 	if len(targetPaths) == 0 {
 		log.Fatalf("Encounter must have targets!")
 	}
@@ -140,8 +148,10 @@ func AddPresetEncounter(name string, targetPaths []string) {
 		}
 	}
 
-	PresetEncounters = append(PresetEncounters, &proto.PresetEncounter{
+	presetEncounter := &proto.PresetEncounter{
 		Path:    path,
 		Targets: targetProtos,
-	})
+	}
+	PresetEncounters = append(PresetEncounters, presetEncounter)
+	return presetEncounter
 }
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"math"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	// Two-sided z-score for a 95% confidence interval.
+	confidenceZScore95 = 1.96
+
+	defaultPrecisionMinIterations = 1000
+)
+
+func ciHalfWidth(stdev float64, n int) float64 {
+	if n < 2 {
+		return math.Inf(1)
+	}
+	return confidenceZScore95 * stdev / math.Sqrt(float64(n))
+}
+
+func isPrecisionReached(config *proto.TargetPrecision, avg float64, halfWidth float64) bool {
+	if config.CiHalfWidth <= 0 && config.CiHalfWidthPercent <= 0 {
+		return false
+	}
+	if config.CiHalfWidth > 0 && halfWidth > config.CiHalfWidth {
+		return false
+	}
+	if config.CiHalfWidthPercent > 0 && halfWidth > math.Abs(avg)*config.CiHalfWidthPercent/100 {
+		return false
+	}
+	return true
+}
+
+// Decides when a sim with target precision has run enough iterations.
+type precisionTracker struct {
+	config        *proto.TargetPrecision
+	metric        *DistributionMetrics
+	minIterations int
+}
+
+func newPrecisionTracker(sim *Simulation) *precisionTracker {
+	config := sim.Options.TargetPrecision
+	if config == nil {
+		return nil
+	}
+
+	tracker := &precisionTracker{
+		config:        config,
+		minIterations: defaultPrecisionMinIterations,
+	}
+	if config.MinIterations > 0 {
+		tracker.minIterations = int(config.MinIterations)
+	}
+
+	switch config.Metric {
+	case proto.TargetPrecision_MetricRaidDps:
+		tracker.metric = &sim.Raid.dpsMetrics
+	case proto.TargetPrecision_MetricRaidHps:
+		tracker.metric = &sim.Raid.hpsMetrics
+	case proto.TargetPrecision_MetricUnitDps, proto.TargetPrecision_MetricUnitHps:
+		unit := sim.Environment.GetUnit(config.Unit, nil)
+		if unit == nil || unit.Type != PlayerUnit {
+			panic("Target precision unit must be a player in the raid")
+		}
+		if config.Metric == proto.TargetPrecision_MetricUnitDps {
+			tracker.metric = &unit.Metrics.dps
+		} else {
+			tracker.metric = &unit.Metrics.hps
+		}
+	}
+	return tracker
+}
+
+// Should be called after each iteration.
+func (tracker *precisionTracker) isDone() bool {
+	if tracker.metric.n < tracker.minIterations {
+		return false
+	}
+	avg, stdev := tracker.metric.meanAndStdDev()
+	return isPrecisionReached(tracker.config, avg, ciHalfWidth(stdev, tracker.metric.n))
+}
+
+// Returns the targeted metric from a finished sim result.
+func getPrecisionMetric(result *proto.RaidSimResult, metric proto.TargetPrecision_Metric, unitRef *proto.UnitReference) *proto.DistributionMetrics {
+	switch metric {
+	case proto.TargetPrecision_MetricRaidDps:
+		return result.RaidMetrics.Dps
+	case proto.TargetPrecision_MetricRaidHps:
+		return result.RaidMetrics.Hps
+	}
+
+	raidIndex := int(unitRef.GetIndex())
+	parties := result.RaidMetrics.Parties
+	if raidIndex/5 >= len(parties) || raidIndex%5 >= len(parties[raidIndex/5].Players) {
+		return nil
+	}
+	player := parties[raidIndex/5].Players[raidIndex%5]
+	if metric == proto.TargetPrecision_MetricUnitDps {
+		return player.Dps
+	}
+	return player.Hps
+}
+
+func newPrecisionResult(result *proto.RaidSimResult, metric proto.TargetPrecision_Metric, unitRef *proto.UnitReference) *proto.PrecisionResult {
+	precision := &proto.PrecisionResult{
+		Metric: metric,
+		Unit:   unitRef,
+	}
+	if dist := getPrecisionMetric(result, metric, unitRef); dist != nil {
+		precision.Avg = dist.Avg
+		precision.CiHalfWidth = ciHalfWidth(dist.Stdev, int(dist.AggregatorData.GetN()))
+	}
+	return precision
+}
+
+// Adjusts the target precision for one of splitCount concurrent sims, such
+// that the combined result of all splits reaches the original target.
+func splitTargetPrecision(config *proto.TargetPrecision, splitCount int32) *proto.TargetPrecision {
+	split := googleProto.Clone(config).(*proto.TargetPrecision)
+
+	// The confidence interval shrinks with the square root of the number of
+	// iterations, and the splits together run splitCount times as many.
+	scale := math.Sqrt(float64(splitCount))
+	split.CiHalfWidth *= scale
+	split.CiHalfWidthPercent *= scale
+
+	minIterations := config.MinIterations
+	if minIterations <= 0 {
+		minIterations = defaultPrecisionMinIterations
+	}
+	split.MinIterations = max(1, minIterations/splitCount)
+	return split
+}
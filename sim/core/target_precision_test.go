@@ -0,0 +1,232 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func init() {
+	RegisterAgentFactory(
+		proto.Player_EnhancementShaman{},
+		proto.Spec_SpecEnhancementShaman,
+		NewFakeNoisyShaman,
+		func(player *proto.Player, spec interface{}) {
+			playerSpec, ok := spec.(*proto.Player_EnhancementShaman)
+			if !ok {
+				panic("Invalid spec value for Enhancement Shaman!")
+			}
+			player.Spec = playerSpec
+		},
+	)
+}
+
+// A fake agent hitting its target for a random 0-2000 damage every second, so
+// its DPS varies between iterations.
+func NewFakeNoisyShaman(char *Character, _ *proto.Player) Agent {
+	fa := &FakeAgent{
+		Character: *char,
+	}
+
+	fa.Init = func() {
+		fa.Spell = fa.RegisterSpell(SpellConfig{
+			ActionID:    ActionID{SpellID: 44},
+			SpellSchool: SpellSchoolPhysical,
+			ProcMask:    ProcMaskSpellDamage,
+			Flags:       SpellFlagIgnoreArmor | SpellFlagIgnoreModifiers,
+
+			DamageMultiplier: 1,
+			ThreatMultiplier: 1,
+
+			ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+				spell.CalcAndDealDamage(sim, target, 2000*sim.RandomFloat("Noisy Damage"), spell.OutcomeAlwaysHit)
+			},
+		})
+
+		fa.RegisterResetEffect(func(sim *Simulation) {
+			StartPeriodicAction(sim, PeriodicActionOptions{
+				Period: time.Second,
+				OnAction: func(sim *Simulation) {
+					fa.Spell.Cast(sim, sim.Encounter.ActiveTargetUnits[0])
+				},
+			})
+		})
+	}
+
+	return fa
+}
+
+func TestIsPrecisionReached(t *testing.T) {
+	absolute := &proto.TargetPrecision{CiHalfWidth: 50}
+	if !isPrecisionReached(absolute, 100000, 49) {
+		t.Fatalf("Expected absolute target to be reached")
+	}
+	if isPrecisionReached(absolute, 100000, 51) {
+		t.Fatalf("Expected absolute target not to be reached")
+	}
+
+	both := &proto.TargetPrecision{CiHalfWidth: 50, CiHalfWidthPercent: 0.01}
+	if isPrecisionReached(both, 100000, 20) {
+		t.Fatalf("Expected relative target not to be reached")
+	}
+	if !isPrecisionReached(both, 100000, 10) {
+		t.Fatalf("Expected both targets to be reached")
+	}
+
+	if isPrecisionReached(&proto.TargetPrecision{}, 100000, 0) {
+		t.Fatalf("Expected target without thresholds never to be reached")
+	}
+}
+
+func TestSplitTargetPrecision(t *testing.T) {
+	split := splitTargetPrecision(&proto.TargetPrecision{CiHalfWidth: 10, CiHalfWidthPercent: 0.1}, 4)
+	if split.CiHalfWidth != 20 || math.Abs(split.CiHalfWidthPercent-0.2) > 1e-9 {
+		t.Fatalf("Unexpected split thresholds %f, %f", split.CiHalfWidth, split.CiHalfWidthPercent)
+	}
+	if split.MinIterations != defaultPrecisionMinIterations/4 {
+		t.Fatalf("Unexpected split min iterations %d", split.MinIterations)
+	}
+
+	// The combined half-width of 4 splits at the split target is the original target.
+	stdev := 1000.0
+	n := int(math.Pow(confidenceZScore95*stdev/split.CiHalfWidth, 2))
+	if combined := ciHalfWidth(stdev, 4*n); math.Abs(combined-10) > 0.01 {
+		t.Fatalf("Unexpected combined half-width %f", combined)
+	}
+}
+
+func noisySimRequest(iterations int32, targetPrecision *proto.TargetPrecision) *proto.RaidSimRequest {
+	return &proto.RaidSimRequest{
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Name:      "Noisy",
+			Class:     proto.Class_ClassShaman,
+			Spec:      &proto.Player_EnhancementShaman{},
+			Equipment: &proto.EquipmentSpec{},
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration:          60,
+			DurationVariation: 5,
+			Targets: []*proto.Target{
+				{Name: "target", Level: 93, MobType: proto.MobType_MobTypeDemon},
+			},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations:      iterations,
+			RandomSeed:      101,
+			TargetPrecision: targetPrecision,
+		},
+	}
+}
+
+func noisyUnitDpsPrecision() *proto.TargetPrecision {
+	return &proto.TargetPrecision{
+		Metric:             proto.TargetPrecision_MetricUnitDps,
+		Unit:               &proto.UnitReference{Type: proto.UnitReference_Player, Index: 0},
+		CiHalfWidthPercent: 1,
+		MinIterations:      50,
+	}
+}
+
+// Checks the reported iterations and duration of a finished sim, and that the
+// precision matches the targeted metric.
+func checkPrecisionResult(t *testing.T, result *proto.RaidSimResult, maxIterations int32) {
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	dps := result.RaidMetrics.Parties[0].Players[0].Dps
+	if n := dps.AggregatorData.GetN(); n != result.IterationsDone {
+		t.Fatalf("Expected %d iterations in the DPS metrics, got %d", result.IterationsDone, n)
+	}
+	if result.IterationsDone <= 0 || result.IterationsDone > maxIterations {
+		t.Fatalf("Expected 1 to %d iterations, got %d", maxIterations, result.IterationsDone)
+	}
+	// Fights last 55s to 65s.
+	if result.AvgIterationDuration < 55 || result.AvgIterationDuration > 65 {
+		t.Fatalf("Expected an average iteration duration of 55s to 65s, got %0.3fs", result.AvgIterationDuration)
+	}
+
+	if result.Precision == nil {
+		t.Fatalf("Expected a precision result")
+	}
+	if result.Precision.Avg != dps.Avg {
+		t.Fatalf("Expected the precision average %0.3f to be the player's DPS %0.3f", result.Precision.Avg, dps.Avg)
+	}
+	expectedHalfWidth := ciHalfWidth(dps.Stdev, int(result.IterationsDone))
+	if math.Abs(result.Precision.CiHalfWidth-expectedHalfWidth) > 1e-9 {
+		t.Fatalf("Expected a confidence interval half-width of %0.3f, got %0.3f", expectedHalfWidth, result.Precision.CiHalfWidth)
+	}
+}
+
+func TestTargetPrecisionStopsEarly(t *testing.T) {
+	result := RunRaidSim(noisySimRequest(100000, noisyUnitDpsPrecision()))
+
+	checkPrecisionResult(t, result, 100000)
+	if result.IterationsDone < 50 || result.IterationsDone >= 10000 {
+		t.Fatalf("Expected the sim to stop early after at least 50 iterations, ran %d", result.IterationsDone)
+	}
+	if !result.Precision.TargetReached || result.Precision.CiHalfWidth > result.Precision.Avg/100 {
+		t.Fatalf("Expected a half-width within 1%% of %0.3f, got %0.3f", result.Precision.Avg, result.Precision.CiHalfWidth)
+	}
+
+	// One iteration less is not enough to reach the target.
+	previous := RunRaidSim(noisySimRequest(result.IterationsDone-1, noisyUnitDpsPrecision()))
+	checkPrecisionResult(t, previous, result.IterationsDone-1)
+	if previous.IterationsDone != result.IterationsDone-1 || previous.Precision.TargetReached {
+		t.Fatalf("Expected the target not to be reached before the last iteration")
+	}
+}
+
+func TestTargetPrecisionNotReached(t *testing.T) {
+	targetPrecision := noisyUnitDpsPrecision()
+	targetPrecision.CiHalfWidthPercent = 0.01
+	result := RunRaidSim(noisySimRequest(300, targetPrecision))
+
+	checkPrecisionResult(t, result, 300)
+	if result.IterationsDone != 300 || result.Precision.TargetReached {
+		t.Fatalf("Expected all 300 iterations without reaching the target, got %d", result.IterationsDone)
+	}
+}
+
+func TestTargetPrecisionConcurrentSplit(t *testing.T) {
+	runSplits := func(request *proto.RaidSimRequest) ([]*proto.RaidSimResult, *proto.RaidSimResult) {
+		split := SplitSimRequestForConcurrency(request, 4)
+		if split.ErrorResult != "" {
+			t.Fatalf("Failed to split request: %s", split.ErrorResult)
+		}
+		results := make([]*proto.RaidSimResult, len(split.Requests))
+		for i, splitRequest := range split.Requests {
+			results[i] = RunRaidSim(splitRequest)
+			checkPrecisionResult(t, results[i], splitRequest.SimOptions.Iterations)
+		}
+		return results, CombineConcurrentSimResults(results, false)
+	}
+
+	results, combined := runSplits(noisySimRequest(100000, noisyUnitDpsPrecision()))
+	checkPrecisionResult(t, combined, 100000)
+	totalIterations := int32(0)
+	for _, result := range results {
+		if !result.Precision.TargetReached {
+			t.Fatalf("Expected every split to reach its target")
+		}
+		totalIterations += result.IterationsDone
+	}
+	if combined.IterationsDone != totalIterations || totalIterations >= 10000 {
+		t.Fatalf("Expected the splits to stop early and add up to %d iterations, got %d", totalIterations, combined.IterationsDone)
+	}
+	// The splits' targets are loosened so the combined interval reaches the
+	// original target, up to the splits' differing deviations.
+	if !combined.Precision.TargetReached || combined.Precision.CiHalfWidth > 1.1*combined.Precision.Avg/100 {
+		t.Fatalf("Expected a combined half-width within 1%% of %0.3f, got %0.3f", combined.Precision.Avg, combined.Precision.CiHalfWidth)
+	}
+
+	// A split which runs out of iterations keeps the combined result from
+	// reaching the target.
+	_, combined = runSplits(noisySimRequest(40, noisyUnitDpsPrecision()))
+	checkPrecisionResult(t, combined, 40)
+	if combined.IterationsDone != 40 || combined.Precision.TargetReached {
+		t.Fatalf("Expected all 40 iterations without reaching the target, got %d", combined.IterationsDone)
+	}
+}
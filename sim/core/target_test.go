@@ -0,0 +1,84 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+// Sets up a sim with a boss, an add and a late add which starts disabled,
+// none of which have an AI.
+func setupTargetSim() *Simulation {
+	sim := NewSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{RandomSeed: 101},
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{
+				{
+					Players: []*proto.Player{
+						{
+							Name:      "Caster",
+							Class:     proto.Class_ClassShaman,
+							Buffs:     &proto.IndividualBuffs{},
+							Spec:      &proto.Player_ElementalShaman{},
+							Equipment: &proto.EquipmentSpec{},
+						},
+					},
+					Buffs: &proto.PartyBuffs{},
+				},
+			},
+		},
+		Encounter: &proto.Encounter{
+			Duration: 180,
+			Targets: []*proto.Target{
+				{Name: "Boss", Level: 93, MobType: proto.MobType_MobTypeDemon},
+				{Name: "Add", Level: 92, MobType: proto.MobType_MobTypeDemon},
+				{Name: "Late Add", Level: 92, MobType: proto.MobType_MobTypeDemon, DisabledAtStart: true},
+			},
+		},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+	sim.PrePull()
+
+	return sim
+}
+
+func TestDisableTargetWithoutAI(t *testing.T) {
+	sim := setupTargetSim()
+	add := sim.Encounter.AllTargets[1]
+	if add.AI != nil {
+		t.Fatalf("Expected the add to have no AI")
+	}
+
+	sim.DisableTargetUnit(&add.Unit, true)
+	if add.IsEnabled() || len(sim.Encounter.ActiveTargetUnits) != 1 {
+		t.Fatalf("Expected the add to be disabled")
+	}
+
+	sim.EnableTargetUnit(&add.Unit)
+	if !add.IsEnabled() || len(sim.Encounter.ActiveTargetUnits) != 2 {
+		t.Fatalf("Expected the add to be enabled again")
+	}
+}
+
+func TestResetRestoresStartingTargets(t *testing.T) {
+	sim := setupTargetSim()
+	boss, add, lateAdd := sim.Encounter.AllTargets[0], sim.Encounter.AllTargets[1], sim.Encounter.AllTargets[2]
+
+	// Swap the boss out for the late add, as a phase change would.
+	sim.EnableTargetUnit(&lateAdd.Unit)
+	sim.DisableTargetUnit(&boss.Unit, true)
+	if boss.IsEnabled() || !lateAdd.IsEnabled() {
+		t.Fatalf("Expected the late add to replace the boss")
+	}
+
+	sim.Cleanup()
+	sim.Reset()
+
+	if !boss.IsEnabled() || !add.IsEnabled() || lateAdd.IsEnabled() {
+		t.Fatalf("Expected the next iteration to start with the starting targets enabled")
+	}
+	if len(sim.Encounter.ActiveTargets) != 2 || sim.Encounter.ActiveTargets[0] != boss || sim.Encounter.ActiveTargets[1] != add {
+		t.Fatalf("Expected the active targets to be back in their starting order")
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/stats"
 	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
 )
 
 var DefaultSimTestOptions = &proto.SimOptions{
@@ -217,6 +219,89 @@ func RaidBenchmark(b *testing.B, rsr *proto.RaidSimRequest) {
 	}
 }
 
+// Total health of the targets in health based preset encounters. A single
+// player can't kill a 25H boss, so the presets' health is scaled down to this,
+// keeping each target's share of it.
+const presetEncounterTestHealth = 6_000_000
+
+// Sims every preset encounter under pathPrefix with the player, buffs and
+// tanks of a build file, failing if a sim errors or the player deals or takes
+// no damage. The player's gear is left off so the presets can be simmed
+// without the item database.
+func PresetEncounterTest(t *testing.T, pathPrefix string, buildDir string, buildFile string) {
+	t.Helper()
+
+	filePath := buildDir + "/" + buildFile + ".build.json"
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("failed to load build json file: %s, %s", filePath, err)
+	}
+	simSettings := &proto.IndividualSimSettings{}
+	if err := protojson.Unmarshal(data, simSettings); err != nil {
+		t.Fatalf("failed to parse build json file: %s, %s", filePath, err)
+	}
+
+	player := googleProto.Clone(simSettings.Player).(*proto.Player)
+	player.Equipment = &proto.EquipmentSpec{}
+	player.ItemSwap = nil
+
+	numEncounters := 0
+	for _, presetEncounter := range PresetEncounters {
+		if !strings.HasPrefix(presetEncounter.Path, pathPrefix+"/") {
+			continue
+		}
+		numEncounters++
+
+		t.Run(strings.TrimPrefix(presetEncounter.Path, pathPrefix+"/"), func(t *testing.T) {
+			raid := SinglePlayerRaidProto(player, simSettings.PartyBuffs, simSettings.RaidBuffs, simSettings.Debuffs)
+			raid.Tanks = simSettings.Tanks
+
+			encounter := MakeSingleTargetEncounter(0)
+			encounter.UseHealth = presetEncounter.UseHealth
+			encounter.Targets = MapSlice(presetEncounter.Targets, func(presetTarget *proto.PresetTarget) *proto.Target {
+				return googleProto.Clone(presetTarget.Target).(*proto.Target)
+			})
+
+			if encounter.UseHealth {
+				totalHealth := 0.0
+				for _, target := range encounter.Targets {
+					totalHealth += target.Stats[stats.Health]
+				}
+				for _, target := range encounter.Targets {
+					target.Stats[stats.Health] *= presetEncounterTestHealth / totalHealth
+				}
+			}
+
+			result := RunRaidSim(&proto.RaidSimRequest{
+				Raid:       raid,
+				Encounter:  encounter,
+				SimOptions: DefaultSimTestOptions,
+			})
+			if result.Error != nil {
+				t.Fatalf("Sim failed with error: %s", result.Error.Message)
+			}
+
+			playerMetrics := result.RaidMetrics.Parties[0].Players[0]
+			if playerMetrics.Dps.Avg <= 0 {
+				t.Fatalf("Expected the player to deal damage")
+			}
+			if playerMetrics.Dtps.Avg <= 0 {
+				t.Fatalf("Expected the player to take damage")
+			}
+
+			// Health based encounters last until the targets die, which
+			// takes a different time each iteration.
+			if encounter.UseHealth && (result.AvgIterationDuration == result.FirstIterationDuration) {
+				t.Fatalf("Expected the duration to vary between iterations, got %0.1fs each time", result.FirstIterationDuration)
+			}
+		})
+	}
+
+	if numEncounters == 0 {
+		t.Fatalf("No preset encounters under %s", pathPrefix)
+	}
+}
+
 func GetAplRotation(dir string, file string) RotationCombo {
 	filePath := dir + "/" + file + ".apl.json"
 	data, err := os.ReadFile(filePath)
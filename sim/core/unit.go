@@ -65,15 +65,28 @@ type Unit struct {
 	// Amount of time following a post-GCD channel tick, to when the next action can be performed.
 	ChannelClipDelay time.Duration
 
+	// Optional noise on the timing of the unit's actions. Nil for frame perfect execution.
+	executionModel *executionModel
+
 	// How far this unit is from its target(s). Measured in yards, this is used
 	// for calculating spell travel time for certain spells.
 	StartDistanceFromTarget float64
 	DistanceFromTarget      float64
-	Moving                  bool
-	movementCallbacks       []MovementCallback
-	moveAura                *Aura
-	moveSpell               *Spell
-	movementAction          *MovementAction
+
+	// Position on the ground in yards, and the direction the unit faces in
+	// radians. Units without an explicit start position are placed relative to
+	// their target using the distance above.
+	StartPosition    Vector2
+	Position         Vector2
+	Facing           float64
+	startFacing      float64
+	hasStartPosition bool
+
+	Moving            bool
+	movementCallbacks []MovementCallback
+	moveAura          *Aura
+	moveSpell         *Spell
+	movementAction    *MovementAction
 
 	// Environment in which this Unit exists. This will be nil until after the
 	// construction phase.
@@ -179,6 +192,10 @@ type Unit struct {
 	defaultTarget   *Unit
 	SecondaryTarget *Unit // Only used for NPCs in tank swap AIs currently.
 
+	// Unit credited with the threat this unit generates, e.g. while
+	// Misdirection or Tricks of the Trade is active. Nil for itself.
+	ThreatRedirectTarget *Unit
+
 	// The currently-channeled DOT spell, otherwise nil.
 	ChanneledDot *Dot
 
@@ -691,6 +708,7 @@ func (unit *Unit) finalize() {
 	}
 
 	unit.defaultTarget = unit.CurrentTarget
+	unit.initPosition()
 	unit.applyParryHaste()
 	unit.updateCastSpeed()
 	unit.updateAttackSpeed()
@@ -733,7 +751,11 @@ func (unit *Unit) reset(sim *Simulation, _ Agent) {
 	unit.Hardcast.Expires = startingCDTime
 	unit.ChanneledDot = nil
 	unit.QueuedSpell = nil
-	unit.DistanceFromTarget = unit.StartDistanceFromTarget
+	unit.ThreatRedirectTarget = nil
+	unit.resetPosition()
+	if unit.executionModel != nil {
+		unit.executionModel.reset(unit)
+	}
 	unit.Metrics.reset()
 	unit.ResetStatDeps()
 	unit.statsWithoutDeps = unit.initialStatsWithoutDeps
@@ -776,6 +798,10 @@ func (unit *Unit) onEncounterStart(sim *Simulation) {
 		agent.OnEncounterStart(sim)
 	}
 
+	if unit.Rotation != nil {
+		unit.Rotation.onEncounterStart(sim)
+	}
+
 	unit.OnEncounterStart(sim)
 }
 
@@ -0,0 +1,29 @@
+package core
+
+// workerBudget limits how many single-threaded sims may run at once across
+// all requests in the process. Nil means unlimited, which is the default since
+// most callers (CLI, wasm, tests) run a single request at a time.
+var workerBudget chan struct{}
+
+// SetWorkerBudget caps the number of sims that run concurrently across all
+// requests. Concurrent requests still split their work as usual, but the
+// splits queue for a free worker. A value <= 0 removes the limit. This must be
+// called before any sims are started.
+func SetWorkerBudget(workers int) {
+	if workers <= 0 {
+		workerBudget = nil
+		return
+	}
+	workerBudget = make(chan struct{}, workers)
+}
+
+// acquireWorker blocks until a worker is available and returns the function
+// that releases it.
+func acquireWorker() func() {
+	budget := workerBudget
+	if budget == nil {
+		return func() {}
+	}
+	budget <- struct{}{}
+	return func() { <-budget }
+}
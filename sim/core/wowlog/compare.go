@@ -0,0 +1,199 @@
+package wowlog
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Per fight values of one ability in the log and in the sim, averaged over
+// all iterations for the sim.
+type SpellComparison struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	LogCasts float64 `json:"logCasts"`
+	SimCasts float64 `json:"simCasts"`
+
+	LogDamage float64 `json:"logDamage"`
+	SimDamage float64 `json:"simDamage"`
+
+	LogHealing float64 `json:"logHealing"`
+	SimHealing float64 `json:"simHealing"`
+
+	LogCritPercent float64 `json:"logCritPercent"`
+	SimCritPercent float64 `json:"simCritPercent"`
+}
+
+type AuraComparison struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	LogUptimePercent float64 `json:"logUptimePercent"`
+	SimUptimePercent float64 `json:"simUptimePercent"`
+}
+
+type ResourceComparison struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	LogGain float64 `json:"logGain"`
+	SimGain float64 `json:"simGain"`
+}
+
+// Logged casts which were left out of the replay.
+type SkippedCast struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Casts int32  `json:"casts"`
+}
+
+type Report struct {
+	Player          string  `json:"player"`
+	Encounter       string  `json:"encounter"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Iterations      int32   `json:"iterations"`
+
+	Spells    []*SpellComparison    `json:"spells"`
+	Auras     []*AuraComparison     `json:"auras"`
+	Resources []*ResourceComparison `json:"resources"`
+	Skipped   []*SkippedCast        `json:"skipped"`
+}
+
+// Compares the summary of a fight against the sim results of its replay.
+// Abilities which only show up on one side are included as well, they often
+// point at missing procs or mismatched spell IDs.
+func (summary *Summary) Compare(result *proto.RaidSimResult, player *proto.UnitMetrics, skipped map[int32]int32) *Report {
+	iterations := float64(max(result.IterationsDone, 1))
+	simDuration := result.AvgIterationDuration
+	if simDuration <= 0 {
+		simDuration = summary.Duration.Seconds()
+	}
+
+	report := &Report{
+		Player:          summary.Player,
+		Encounter:       summary.Encounter,
+		DurationSeconds: summary.Duration.Seconds(),
+		Iterations:      result.IterationsDone,
+	}
+
+	spells := make(map[ActionKey]*SpellComparison)
+	spell := func(key ActionKey) *SpellComparison {
+		comparison, ok := spells[key]
+		if !ok {
+			comparison = &SpellComparison{ID: key.String(), Name: summary.Name(key)}
+			spells[key] = comparison
+		}
+		return comparison
+	}
+	for key, spellSummary := range summary.Spells {
+		comparison := spell(key)
+		comparison.LogCasts = float64(spellSummary.Casts)
+		comparison.LogDamage = spellSummary.Damage
+		comparison.LogHealing = spellSummary.Healing
+		if spellSummary.Hits > 0 {
+			comparison.LogCritPercent = 100 * float64(spellSummary.Crits) / float64(spellSummary.Hits)
+		}
+	}
+
+	simHits := make(map[ActionKey]int32)
+	simCrits := make(map[ActionKey]int32)
+	for _, action := range player.Actions {
+		key := protoKey(action.Id)
+		var casts, hits, crits int32
+		var damage, healing float64
+		for _, target := range action.Targets {
+			casts += target.Casts
+			hits += target.Hits + target.Ticks
+			crits += target.Crits + target.CritTicks
+			damage += target.Damage
+			healing += target.Healing + target.Shielding
+		}
+		if casts == 0 && damage == 0 && healing == 0 {
+			continue
+		}
+
+		comparison := spell(key)
+		comparison.SimCasts += float64(casts) / iterations
+		comparison.SimDamage += damage / iterations
+		comparison.SimHealing += healing / iterations
+		simHits[key] += hits
+		simCrits[key] += crits
+	}
+	for key, hits := range simHits {
+		if hits > 0 {
+			spells[key].SimCritPercent = 100 * float64(simCrits[key]) / float64(hits)
+		}
+	}
+
+	report.Spells = sortedValues(spells, func(a, b *SpellComparison) int {
+		return cmp.Or(cmp.Compare(max(b.LogDamage+b.LogHealing, b.SimDamage+b.SimHealing), max(a.LogDamage+a.LogHealing, a.SimDamage+a.SimHealing)), cmp.Compare(a.ID, b.ID))
+	})
+
+	auras := make(map[ActionKey]*AuraComparison)
+	for key, uptime := range summary.Auras {
+		auras[key] = &AuraComparison{
+			ID:               key.String(),
+			Name:             summary.Name(key),
+			LogUptimePercent: 100 * uptime.Seconds() / summary.Duration.Seconds(),
+		}
+	}
+	for _, aura := range player.Auras {
+		key := protoKey(aura.Id)
+		if key.SpellID == 0 || aura.UptimeSecondsAvg == 0 {
+			continue
+		}
+		comparison, ok := auras[key]
+		if !ok {
+			comparison = &AuraComparison{ID: key.String(), Name: summary.Name(key)}
+			auras[key] = comparison
+		}
+		comparison.SimUptimePercent = max(comparison.SimUptimePercent, 100*aura.UptimeSecondsAvg/simDuration)
+	}
+	report.Auras = sortedValues(auras, func(a, b *AuraComparison) int {
+		return cmp.Or(cmp.Compare(max(b.LogUptimePercent, b.SimUptimePercent), max(a.LogUptimePercent, a.SimUptimePercent)), cmp.Compare(a.ID, b.ID))
+	})
+
+	resources := make(map[ResourceKey]*ResourceComparison)
+	resource := func(key ResourceKey) *ResourceComparison {
+		comparison, ok := resources[key]
+		if !ok {
+			comparison = &ResourceComparison{ID: key.Action.String(), Name: summary.Name(key.Action), Type: key.Type.String()}
+			resources[key] = comparison
+		}
+		return comparison
+	}
+	for key, gain := range summary.Resources {
+		resource(key).LogGain = gain
+	}
+	for _, resourceMetrics := range player.Resources {
+		if resourceMetrics.Type == proto.ResourceType_ResourceTypeHealth || resourceMetrics.ActualGain <= 0 {
+			continue
+		}
+		resource(ResourceKey{Action: protoKey(resourceMetrics.Id), Type: resourceMetrics.Type}).SimGain += resourceMetrics.ActualGain / iterations
+	}
+	report.Resources = sortedValues(resources, func(a, b *ResourceComparison) int {
+		return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(max(b.LogGain, b.SimGain), max(a.LogGain, a.SimGain)), cmp.Compare(a.ID, b.ID))
+	})
+
+	for spellID, casts := range skipped {
+		key := spellKey(spellID)
+		report.Skipped = append(report.Skipped, &SkippedCast{ID: key.String(), Name: summary.Name(key), Casts: casts})
+	}
+	slices.SortFunc(report.Skipped, func(a, b *SkippedCast) int {
+		return cmp.Or(cmp.Compare(b.Casts, a.Casts), cmp.Compare(a.ID, b.ID))
+	})
+
+	return report
+}
+
+func sortedValues[K comparable, V any](m map[K]V, compare func(a, b V) int) []V {
+	values := make([]V, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+	slices.SortFunc(values, compare)
+	return values
+}
@@ -0,0 +1,339 @@
+package wowlog
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Casts this long before the start of the fight are replayed as prepull
+// actions, anything earlier is ignored.
+const prepullWindow = 10 * time.Second
+
+// Spell ID the log uses for hunter auto shots.
+const autoShotSpellID = 75
+
+// Identifies an ability on both sides of the comparison. Auto attacks are
+// logged as swings, which the sim tracks as other actions.
+type ActionKey struct {
+	SpellID int32
+	OtherID proto.OtherAction
+}
+
+func spellKey(spellID int32) ActionKey {
+	if spellID == autoShotSpellID {
+		return ActionKey{OtherID: proto.OtherAction_OtherActionShoot}
+	}
+	if spellID == 0 {
+		return ActionKey{OtherID: proto.OtherAction_OtherActionAttack}
+	}
+	return ActionKey{SpellID: spellID}
+}
+
+// Action IDs from the sim are merged across tags, e.g. main hand and off hand
+// auto attacks.
+func protoKey(id *proto.ActionID) ActionKey {
+	return ActionKey{SpellID: id.GetSpellId(), OtherID: id.GetOtherId()}
+}
+
+func (key ActionKey) String() string {
+	if key.SpellID != 0 {
+		return "spell:" + strconv.Itoa(int(key.SpellID))
+	}
+	return "other:" + key.OtherID.String()
+}
+
+type Cast struct {
+	SpellID int32
+	Time    time.Duration // Since the start of the fight, negative when prepull.
+	OnSelf  bool
+}
+
+type SpellSummary struct {
+	Name string
+
+	Casts   int32
+	Hits    int32
+	Crits   int32
+	Damage  float64
+	Healing float64
+}
+
+type ResourceKey struct {
+	Action ActionKey
+	Type   proto.ResourceType
+}
+
+// One player's part of a fight.
+type Summary struct {
+	Player    string
+	Encounter string
+	Duration  time.Duration
+
+	Casts     []Cast
+	Spells    map[ActionKey]*SpellSummary
+	Auras     map[ActionKey]time.Duration // Uptime of buffs on the player.
+	Resources map[ResourceKey]float64     // Resources gained by the player.
+
+	names map[ActionKey]string
+}
+
+// Name of the ability as written in the log.
+func (summary *Summary) Name(key ActionKey) string {
+	if name, ok := summary.names[key]; ok {
+		return name
+	}
+	return key.String()
+}
+
+// Returns the encounter with the given 1-based index. Zero selects the only
+// encounter in the log, or the whole log if there are none.
+func (log *Log) Segment(index int) (name string, start time.Duration, end time.Duration, err error) {
+	if index == 0 {
+		switch len(log.Encounters) {
+		case 0:
+			return "", log.Start, log.End, nil
+		case 1:
+			index = 1
+		default:
+			msg := "the log contains several encounters, select one of:"
+			for i, encounter := range log.Encounters {
+				msg += fmt.Sprintf("\n  %d: %s (%s)", i+1, encounter.Name, (encounter.End - encounter.Start).Round(time.Second))
+			}
+			return "", 0, 0, errors.New(msg)
+		}
+	}
+	if index < 1 || index > len(log.Encounters) {
+		return "", 0, 0, fmt.Errorf("encounter %d does not exist, the log contains %d", index, len(log.Encounters))
+	}
+
+	encounter := log.Encounters[index-1]
+	return encounter.Name, encounter.Start, encounter.End, nil
+}
+
+// Summarizes the player's casts, damage, healing, buffs and resource gains in
+// the selected encounter.
+func (log *Log) Summarize(encounterIndex int) (*Summary, error) {
+	encounterName, start, end, err := log.Segment(encounterIndex)
+	if err != nil {
+		return nil, err
+	}
+	if end <= start {
+		return nil, errors.New("the selected segment is empty")
+	}
+
+	summary := &Summary{
+		Player:    log.PlayerName,
+		Encounter: encounterName,
+		Duration:  end - start,
+		Spells:    make(map[ActionKey]*SpellSummary),
+		Auras:     make(map[ActionKey]time.Duration),
+		Resources: make(map[ResourceKey]float64),
+		names:     make(map[ActionKey]string),
+	}
+
+	spell := func(event *Event) *SpellSummary {
+		key := spellKey(event.SpellID)
+		spellSummary, ok := summary.Spells[key]
+		if !ok {
+			spellSummary = &SpellSummary{Name: event.SpellName}
+			if event.SpellID == 0 {
+				spellSummary.Name = "Melee"
+			}
+			summary.Spells[key] = spellSummary
+			summary.names[key] = spellSummary.Name
+		}
+		return spellSummary
+	}
+
+	castStarts := make(map[int32]time.Duration)
+	auraStarts := make(map[ActionKey]time.Duration)
+	for _, event := range log.Events {
+		if event.Timestamp > end {
+			break
+		}
+
+		fromPlayer := event.SourceGUID == log.PlayerGUID
+		toPlayer := event.DestGUID == log.PlayerGUID
+
+		// Buffs are followed from the start of the log, so that buffs which
+		// were already up at the pull count towards the uptime.
+		if toPlayer && event.AuraType == "BUFF" {
+			key := spellKey(event.SpellID)
+			summary.names[key] = event.SpellName
+			switch event.Type {
+			case "SPELL_AURA_APPLIED":
+				auraStarts[key] = max(event.Timestamp, start)
+			case "SPELL_AURA_REMOVED":
+				if gainedAt, ok := auraStarts[key]; ok {
+					delete(auraStarts, key)
+					if event.Timestamp > start {
+						summary.Auras[key] += event.Timestamp - gainedAt
+					}
+				} else if event.Timestamp > start {
+					summary.Auras[key] += event.Timestamp - start
+				}
+			}
+			continue
+		}
+
+		if event.Timestamp < start-prepullWindow {
+			continue
+		}
+
+		if fromPlayer {
+			switch event.Type {
+			case "SPELL_CAST_START":
+				castStarts[event.SpellID] = event.Timestamp
+				continue
+			case "SPELL_CAST_SUCCESS":
+				castAt := event.Timestamp
+				if startedAt, ok := castStarts[event.SpellID]; ok {
+					castAt = startedAt
+					delete(castStarts, event.SpellID)
+				}
+				summary.Casts = append(summary.Casts, Cast{
+					SpellID: event.SpellID,
+					Time:    castAt - start,
+					OnSelf:  toPlayer,
+				})
+				if event.Timestamp >= start {
+					spell(event).Casts++
+				}
+				continue
+			}
+		}
+
+		if event.Timestamp < start {
+			continue
+		}
+
+		switch event.Type {
+		case "SWING_DAMAGE", "RANGE_DAMAGE", "SPELL_DAMAGE", "SPELL_PERIODIC_DAMAGE":
+			if fromPlayer && !toPlayer {
+				spellSummary := spell(event)
+				spellSummary.Hits++
+				spellSummary.Damage += event.Amount
+				if event.Critical {
+					spellSummary.Crits++
+				}
+			}
+		case "SPELL_HEAL", "SPELL_PERIODIC_HEAL":
+			if fromPlayer {
+				spellSummary := spell(event)
+				spellSummary.Hits++
+				spellSummary.Healing += event.Amount
+				if event.Critical {
+					spellSummary.Crits++
+				}
+			}
+		case "SPELL_ENERGIZE", "SPELL_PERIODIC_ENERGIZE":
+			if toPlayer {
+				key := spellKey(event.SpellID)
+				summary.names[key] = event.SpellName
+				summary.Resources[ResourceKey{Action: key, Type: resourceType(event.PowerType)}] += event.Amount
+			}
+		}
+	}
+
+	for key, gainedAt := range auraStarts {
+		summary.Auras[key] += end - gainedAt
+	}
+
+	return summary, nil
+}
+
+// Maps the power types used by the log to the sim's resource types.
+func resourceType(powerType int32) proto.ResourceType {
+	switch powerType {
+	case 0:
+		return proto.ResourceType_ResourceTypeMana
+	case 1:
+		return proto.ResourceType_ResourceTypeRage
+	case 2:
+		return proto.ResourceType_ResourceTypeFocus
+	case 3:
+		return proto.ResourceType_ResourceTypeEnergy
+	case 4:
+		return proto.ResourceType_ResourceTypeComboPoints
+	case 6:
+		return proto.ResourceType_ResourceTypeRunicPower
+	case 12:
+		return proto.ResourceType_ResourceTypeChi
+	default:
+		return proto.ResourceType_ResourceTypeGenericResource
+	}
+}
+
+// Builds a rotation which casts the logged spells in order, each no earlier
+// than it was cast in the log. A cast which isn't ready yet holds back the
+// rest of the sequence, so the sim never gets ahead of the player. Spells
+// for which isCastable returns false, e.g. procs and item effects, are left
+// out and returned instead.
+func (summary *Summary) Rotation(isCastable func(spellID int32) bool) (*proto.APLRotation, map[int32]int32) {
+	rotation := &proto.APLRotation{
+		Type: proto.APLRotation_TypeAPL,
+	}
+	skipped := make(map[int32]int32)
+
+	var sequence []*proto.APLAction
+	for _, cast := range summary.Casts {
+		if !isCastable(cast.SpellID) {
+			if cast.Time >= 0 {
+				skipped[cast.SpellID]++
+			}
+			continue
+		}
+
+		castSpell := &proto.APLActionCastSpell{
+			SpellId: &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: cast.SpellID}},
+		}
+		if cast.OnSelf {
+			castSpell.Target = &proto.UnitReference{Type: proto.UnitReference_Self}
+		}
+		action := &proto.APLAction{
+			Action: &proto.APLAction_CastSpell{CastSpell: castSpell},
+		}
+
+		if cast.Time < 0 {
+			rotation.PrepullActions = append(rotation.PrepullActions, &proto.APLPrepullAction{
+				Action:    action,
+				DoAtValue: constValue(formatSeconds(cast.Time)),
+			})
+			continue
+		}
+
+		sequence = append(sequence, &proto.APLAction{
+			Action: &proto.APLAction_Schedule{Schedule: &proto.APLActionSchedule{
+				Schedule:    formatSeconds(cast.Time),
+				InnerAction: action,
+			}},
+		})
+	}
+
+	if len(sequence) > 0 {
+		rotation.PriorityList = []*proto.APLListItem{{
+			Action: &proto.APLAction{
+				Action: &proto.APLAction_Sequence{Sequence: &proto.APLActionSequence{
+					Name:    "Replay",
+					Actions: sequence,
+				}},
+			},
+		}}
+	}
+
+	return rotation, skipped
+}
+
+func constValue(val string) *proto.APLValue {
+	return &proto.APLValue{
+		Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}},
+	}
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64) + "s"
+}
@@ -0,0 +1,320 @@
+// Package wowlog reads the combat log files written by the game client
+// (WoWCombatLog.txt) and turns one player's part of a fight into a summary
+// that can be replayed and compared against the sim.
+//
+// Each line of a log is a timestamp followed by a comma separated event:
+//
+//	4/12 21:03:45.123  SPELL_DAMAGE,Player-1-0A,"Name-Realm",0x511,0x0,Creature-0-1,"Boss",0x10a48,0x0,12345,"Spell",0x1,...
+//
+// Only the events needed for the comparison are decoded. Logs written with
+// and without advanced combat logging are both supported, the number of
+// advanced fields is taken from the first SPELL_CAST_SUCCESS in the file.
+package wowlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Number of fields before the event specific ones: the event type, then the
+// GUID, name, flags and raid flags of both the source and the destination.
+const numBaseFields = 9
+
+// Spell ID, name and school.
+const numSpellPrefixFields = 3
+
+type Event struct {
+	Timestamp time.Duration // Time of day, increasing past midnight.
+	Type      string        // E.g. SPELL_CAST_SUCCESS.
+
+	SourceGUID string
+	SourceName string
+	DestGUID   string
+	DestName   string
+
+	// Zero for swings.
+	SpellID   int32
+	SpellName string
+
+	// Damage or healing done including absorbed damage and overhealing, or
+	// resource gained excluding the part over the cap.
+	Amount   float64
+	Critical bool
+
+	PowerType int32  // For energize events.
+	AuraType  string // BUFF or DEBUFF, for aura events.
+}
+
+type Encounter struct {
+	ID      int32
+	Name    string
+	Success bool
+
+	Start time.Duration
+	End   time.Duration
+}
+
+type Log struct {
+	// Events of the player the log was read for, in order.
+	Events     []*Event
+	Encounters []*Encounter
+
+	PlayerName string
+	PlayerGUID string
+
+	// Time of the first and last event of any unit.
+	Start time.Duration
+	End   time.Duration
+}
+
+// Reads a combat log, keeping the events where the named player is either the
+// source or the destination. The name may be given with or without realm.
+func Parse(r io.Reader, playerName string) (*Log, error) {
+	log := &Log{PlayerName: playerName}
+
+	var lines [][]string
+	var timestamps []time.Duration
+	numAdvancedFields := -1
+	var lastTimestamp, dayOffset time.Duration
+	hasEvents := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		timestampStr, eventStr, ok := strings.Cut(line, "  ")
+		if !ok {
+			continue
+		}
+		timestamp, err := parseTimestamp(timestampStr)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if timestamp+dayOffset < lastTimestamp-12*time.Hour {
+			dayOffset += 24 * time.Hour
+		}
+		timestamp += dayOffset
+		lastTimestamp = timestamp
+
+		fields := splitFields(eventStr)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "ENCOUNTER_START":
+			if len(fields) >= 3 {
+				log.Encounters = append(log.Encounters, &Encounter{
+					ID:    parseInt32(fields[1]),
+					Name:  fields[2],
+					Start: timestamp,
+					End:   -1,
+				})
+			}
+			continue
+		case "ENCOUNTER_END":
+			if len(log.Encounters) > 0 && len(fields) >= 6 {
+				encounter := log.Encounters[len(log.Encounters)-1]
+				if encounter.End < 0 {
+					encounter.End = timestamp
+					encounter.Success = fields[5] == "1"
+				}
+			}
+			continue
+		}
+
+		if len(fields) < numBaseFields {
+			continue
+		}
+		if !hasEvents {
+			log.Start = timestamp
+			hasEvents = true
+		}
+		log.End = timestamp
+
+		if numAdvancedFields < 0 && fields[0] == "SPELL_CAST_SUCCESS" {
+			numAdvancedFields = len(fields) - numBaseFields - numSpellPrefixFields
+		}
+
+		if log.PlayerGUID == "" {
+			if strings.HasPrefix(fields[1], "Player-") && matchesName(fields[2], playerName) {
+				log.PlayerGUID = fields[1]
+			} else if strings.HasPrefix(fields[5], "Player-") && matchesName(fields[6], playerName) {
+				log.PlayerGUID = fields[5]
+			}
+		}
+		if log.PlayerGUID != "" && (fields[1] == log.PlayerGUID || fields[5] == log.PlayerGUID) {
+			lines = append(lines, fields)
+			timestamps = append(timestamps, timestamp)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read combat log: %w", err)
+	}
+
+	if log.PlayerGUID == "" {
+		return nil, fmt.Errorf("no events found for player %q", playerName)
+	}
+
+	for _, encounter := range log.Encounters {
+		if encounter.End < 0 {
+			encounter.End = log.End
+		}
+	}
+
+	numAdvancedFields = max(numAdvancedFields, 0)
+	for i, fields := range lines {
+		if event := decodeEvent(fields, timestamps[i], numAdvancedFields); event != nil {
+			log.Events = append(log.Events, event)
+		}
+	}
+	return log, nil
+}
+
+func matchesName(name string, playerName string) bool {
+	if name == playerName {
+		return true
+	}
+	baseName, _, _ := strings.Cut(name, "-")
+	return baseName == playerName
+}
+
+// Parses the time of day of a timestamp like '4/12 21:03:45.123' or
+// '4/12/2024 21:03:45.1234-4', ignoring the date and time zone.
+func parseTimestamp(timestamp string) (time.Duration, error) {
+	_, timeStr, ok := strings.Cut(timestamp, " ")
+	if !ok {
+		return 0, fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	if idx := strings.IndexAny(timeStr, "+-"); idx >= 0 {
+		timeStr = timeStr[:idx]
+	}
+
+	parts := strings.Split(timeStr, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+	hours, err1 := strconv.Atoi(parts[0])
+	minutes, err2 := strconv.Atoi(parts[1])
+	seconds, err3 := strconv.ParseFloat(parts[2], 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, fmt.Errorf("invalid timestamp %q", timestamp)
+	}
+
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second)), nil
+}
+
+// Splits an event on commas outside of quotes, and unquotes the fields.
+func splitFields(eventStr string) []string {
+	var fields []string
+	var sb strings.Builder
+	inQuotes := false
+	for i := 0; i < len(eventStr); i++ {
+		c := eventStr[i]
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ',' && !inQuotes:
+			fields = append(fields, sb.String())
+			sb.Reset()
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return append(fields, sb.String())
+}
+
+func parseInt32(field string) int32 {
+	value, _ := strconv.ParseInt(field, 0, 32)
+	return int32(value)
+}
+
+func parseFloat(field string) float64 {
+	value, _ := strconv.ParseFloat(field, 64)
+	return value
+}
+
+// Events which carry the advanced combat logging fields between the prefix
+// and the suffix.
+func hasAdvancedFields(eventType string) bool {
+	return eventType == "SPELL_CAST_SUCCESS" ||
+		strings.HasSuffix(eventType, "_DAMAGE") ||
+		strings.HasSuffix(eventType, "_HEAL") ||
+		strings.HasSuffix(eventType, "_ENERGIZE")
+}
+
+func decodeEvent(fields []string, timestamp time.Duration, numAdvancedFields int) *Event {
+	event := &Event{
+		Timestamp:  timestamp,
+		Type:       fields[0],
+		SourceGUID: fields[1],
+		SourceName: fields[2],
+		DestGUID:   fields[5],
+		DestName:   fields[6],
+	}
+
+	suffixStart := numBaseFields
+	if !strings.HasPrefix(event.Type, "SWING_") {
+		if len(fields) < numBaseFields+numSpellPrefixFields {
+			return nil
+		}
+		event.SpellID = parseInt32(fields[numBaseFields])
+		event.SpellName = fields[numBaseFields+1]
+		suffixStart += numSpellPrefixFields
+	}
+	if hasAdvancedFields(event.Type) {
+		suffixStart += numAdvancedFields
+	}
+	if suffixStart > len(fields) {
+		return nil
+	}
+	suffix := fields[suffixStart:]
+
+	switch event.Type {
+	case "SPELL_CAST_START", "SPELL_CAST_SUCCESS":
+	case "SWING_DAMAGE", "RANGE_DAMAGE", "SPELL_DAMAGE", "SPELL_PERIODIC_DAMAGE":
+		// amount[, baseAmount], overkill, school, resisted, blocked, absorbed, critical, glancing, crushing[, isOffHand]
+		if len(suffix) < 9 {
+			return nil
+		}
+		critIdx := len(suffix) - 3
+		if len(suffix) >= 10 {
+			critIdx = len(suffix) - 4
+		}
+		event.Amount = parseFloat(suffix[0]) + parseFloat(suffix[critIdx-1])
+		event.Critical = suffix[critIdx] == "1"
+	case "SPELL_HEAL", "SPELL_PERIODIC_HEAL":
+		// amount[, baseAmount], overhealing, absorbed, critical
+		if len(suffix) < 4 {
+			return nil
+		}
+		event.Amount = parseFloat(suffix[0])
+		event.Critical = suffix[len(suffix)-1] == "1"
+	case "SPELL_ENERGIZE", "SPELL_PERIODIC_ENERGIZE":
+		// amount, overEnergize, powerType[, maxPower]
+		if len(suffix) < 3 {
+			return nil
+		}
+		event.Amount = parseFloat(suffix[0]) - parseFloat(suffix[1])
+		event.PowerType = parseInt32(suffix[2])
+	case "SPELL_AURA_APPLIED", "SPELL_AURA_REMOVED":
+		if len(suffix) < 1 {
+			return nil
+		}
+		event.AuraType = suffix[0]
+	default:
+		return nil
+	}
+
+	return event
+}
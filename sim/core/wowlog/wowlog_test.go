@@ -0,0 +1,124 @@
+package wowlog
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Uses three advanced fields to check that their number is detected.
+const testLog = `4/12 21:00:00.000  COMBAT_LOG_VERSION,9,ADVANCED_LOG_ENABLED,1,BUILD_VERSION,5.5.0,PROJECT_ID,2
+4/12 21:00:05.000  SPELL_AURA_APPLIED,Player-1-A,"Tester-Realm",0x511,0x0,Player-1-A,"Tester-Realm",0x511,0x0,1000,"Pre Buff",0x1,BUFF
+4/12 21:00:08.000  SPELL_CAST_START,Player-1-A,"Tester-Realm",0x511,0x0,0000000000000000,nil,0x80000000,0x80000000,2000,"Prepull Bolt",0x4
+4/12 21:00:09.500  SPELL_CAST_SUCCESS,Player-1-A,"Tester-Realm",0x511,0x0,Creature-0-1,"Boss",0x10a48,0x0,2000,"Prepull Bolt",0x4,Player-1-A,0000000000000000,100
+4/12 21:00:10.000  ENCOUNTER_START,1234,"Test Boss",14,10,1
+4/12 21:00:11.000  SPELL_CAST_SUCCESS,Player-1-A,"Tester-Realm",0x511,0x0,Creature-0-1,"Boss",0x10a48,0x0,3000,"Strike, Improved",0x1,Player-1-A,0000000000000000,100
+4/12 21:00:11.000  SPELL_DAMAGE,Player-1-A,"Tester-Realm",0x511,0x0,Creature-0-1,"Boss",0x10a48,0x0,3000,"Strike, Improved",0x1,Player-1-A,0000000000000000,100,1000,-1,1,0,0,200,1,nil,nil
+4/12 21:00:12.000  SWING_DAMAGE,Player-1-A,"Tester-Realm",0x511,0x0,Creature-0-1,"Boss",0x10a48,0x0,Player-1-A,0000000000000000,100,500,-1,1,0,0,0,nil,nil,nil,nil
+4/12 21:00:13.000  SPELL_ENERGIZE,Player-1-A,"Tester-Realm",0x511,0x0,Player-1-A,"Tester-Realm",0x511,0x0,4000,"Focus Gain",0x1,Player-1-A,0000000000000000,100,20,5,2,100
+4/12 21:00:15.000  SPELL_AURA_REMOVED,Player-1-A,"Tester-Realm",0x511,0x0,Player-1-A,"Tester-Realm",0x511,0x0,1000,"Pre Buff",0x1,BUFF
+4/12 21:00:16.000  SPELL_AURA_APPLIED,Player-1-A,"Tester-Realm",0x511,0x0,Player-1-A,"Tester-Realm",0x511,0x0,5000,"Proc",0x1,BUFF
+4/12 21:00:17.000  SPELL_CAST_SUCCESS,Player-1-A,"Tester-Realm",0x511,0x0,Player-1-A,"Tester-Realm",0x511,0x0,6000,"Self Buff",0x1,Player-1-A,0000000000000000,100
+4/12 21:00:18.000  SPELL_DAMAGE,Player-1-B,"Other-Realm",0x511,0x0,Creature-0-1,"Boss",0x10a48,0x0,3000,"Strike, Improved",0x1,Player-1-B,0000000000000000,100,9999,-1,1,0,0,0,nil,nil,nil
+4/12 21:00:20.000  ENCOUNTER_END,1234,"Test Boss",14,10,1,10000
+`
+
+func parseTestLog(t *testing.T) *Log {
+	t.Helper()
+	log, err := Parse(strings.NewReader(testLog), "Tester")
+	if err != nil {
+		t.Fatalf("failed to parse log: %s", err)
+	}
+	return log
+}
+
+func TestParse(t *testing.T) {
+	log := parseTestLog(t)
+
+	if log.PlayerGUID != "Player-1-A" {
+		t.Fatalf("expected player GUID Player-1-A, got %s", log.PlayerGUID)
+	}
+	if len(log.Encounters) != 1 {
+		t.Fatalf("expected 1 encounter, got %d", len(log.Encounters))
+	}
+	if encounter := log.Encounters[0]; encounter.Name != "Test Boss" || !encounter.Success || encounter.End-encounter.Start != 10*time.Second {
+		t.Fatalf("unexpected encounter %+v", encounter)
+	}
+	if len(log.Events) != 10 {
+		t.Fatalf("expected 10 events of the player, got %d", len(log.Events))
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	summary, err := parseTestLog(t).Summarize(0)
+	if err != nil {
+		t.Fatalf("failed to summarize log: %s", err)
+	}
+
+	if summary.Duration != 10*time.Second {
+		t.Fatalf("expected a 10s fight, got %s", summary.Duration)
+	}
+
+	expectedCasts := []Cast{
+		{SpellID: 2000, Time: -2 * time.Second},
+		{SpellID: 3000, Time: time.Second},
+		{SpellID: 6000, Time: 7 * time.Second, OnSelf: true},
+	}
+	if len(summary.Casts) != len(expectedCasts) {
+		t.Fatalf("expected %d casts, got %+v", len(expectedCasts), summary.Casts)
+	}
+	for i, cast := range expectedCasts {
+		if summary.Casts[i] != cast {
+			t.Fatalf("expected cast %+v, got %+v", cast, summary.Casts[i])
+		}
+	}
+
+	strike := summary.Spells[ActionKey{SpellID: 3000}]
+	if strike == nil || strike.Name != "Strike, Improved" || strike.Casts != 1 || strike.Crits != 1 || strike.Damage != 1200 {
+		t.Fatalf("unexpected spell summary %+v", strike)
+	}
+	melee := summary.Spells[ActionKey{OtherID: proto.OtherAction_OtherActionAttack}]
+	if melee == nil || melee.Hits != 1 || melee.Crits != 0 || melee.Damage != 500 {
+		t.Fatalf("unexpected melee summary %+v", melee)
+	}
+
+	if uptime := summary.Auras[ActionKey{SpellID: 1000}]; uptime != 5*time.Second {
+		t.Fatalf("expected 5s uptime of a buff gained before the pull, got %s", uptime)
+	}
+	if uptime := summary.Auras[ActionKey{SpellID: 5000}]; uptime != 4*time.Second {
+		t.Fatalf("expected 4s uptime of a buff still up at the end, got %s", uptime)
+	}
+
+	focusKey := ResourceKey{Action: ActionKey{SpellID: 4000}, Type: proto.ResourceType_ResourceTypeFocus}
+	if gain := summary.Resources[focusKey]; gain != 15 {
+		t.Fatalf("expected 15 focus gained, got %f", gain)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	summary, err := parseTestLog(t).Summarize(1)
+	if err != nil {
+		t.Fatalf("failed to summarize log: %s", err)
+	}
+
+	rotation, skipped := summary.Rotation(func(spellID int32) bool {
+		return spellID != 6000
+	})
+
+	if len(skipped) != 1 || skipped[6000] != 1 {
+		t.Fatalf("expected the self buff to be skipped, got %v", skipped)
+	}
+	if len(rotation.PrepullActions) != 1 || rotation.PrepullActions[0].DoAtValue.GetConst().Val != "-2.000s" {
+		t.Fatalf("unexpected prepull actions %v", rotation.PrepullActions)
+	}
+
+	sequence := rotation.PriorityList[0].Action.GetSequence()
+	if sequence == nil || len(sequence.Actions) != 1 {
+		t.Fatalf("expected a sequence of 1 cast, got %v", rotation.PriorityList)
+	}
+	if schedule := sequence.Actions[0].GetSchedule(); schedule.Schedule != "1.000s" || schedule.InnerAction.GetCastSpell().SpellId.GetSpellId() != 3000 {
+		t.Fatalf("unexpected scheduled cast %v", schedule)
+	}
+}
@@ -0,0 +1,262 @@
+package distsim
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultShardsPerWorker = 4
+	defaultMaxAttempts     = 3
+
+	// Upper bound on a single shard, so a hung worker fails the shard instead
+	// of stalling the sim.
+	defaultShardTimeout = 10 * time.Minute
+)
+
+// Coordinator runs sims by fanning shards out to worker processes.
+type Coordinator struct {
+	// Base URLs of the workers, e.g. http://localhost:3334.
+	Workers []string
+	Client  *http.Client
+
+	// Number of shards per worker. More shards balance the load better between
+	// workers of different speeds, and make retrying a shard cheaper.
+	ShardsPerWorker int
+	// Number of times a shard is tried before the sim fails. A worker which
+	// fails this many shards in a row is no longer used.
+	MaxAttempts int
+
+	// If set, called after each finished shard.
+	OnProgress func(completedIterations int32, totalIterations int32)
+}
+
+func NewCoordinator(workers []string) *Coordinator {
+	coordinator := &Coordinator{
+		Client:          &http.Client{Timeout: defaultShardTimeout},
+		ShardsPerWorker: defaultShardsPerWorker,
+		MaxAttempts:     defaultMaxAttempts,
+	}
+	for _, worker := range workers {
+		coordinator.Workers = append(coordinator.Workers, strings.TrimSuffix(worker, "/"))
+	}
+	return coordinator
+}
+
+// State of a single RunRaidSim call.
+type distributedSim struct {
+	coordinator *Coordinator
+	shards      []*proto.RaidSimRequest
+
+	queue    chan int
+	mu       sync.Mutex
+	results  []*proto.RaidSimResult
+	attempts []int
+	pending  int
+	alive    int
+
+	maxAttempts int
+
+	done     chan struct{}
+	doneOnce sync.Once
+	failure  *proto.ErrorOutcome
+
+	// Cancels the requests in flight once the sim is done or aborted.
+	cancel context.CancelFunc
+}
+
+// RunRaidSim runs request on the workers and returns the combined result.
+// Failures are reported through the Error of the result, like the local sim.
+func (coordinator *Coordinator) RunRaidSim(request *proto.RaidSimRequest, signals simsignals.Signals) *proto.RaidSimResult {
+	if len(coordinator.Workers) == 0 {
+		return errorResult("no workers to run the sim on")
+	}
+
+	shardCount := int32(len(coordinator.Workers) * max(1, coordinator.ShardsPerWorker))
+	split := core.SplitSimRequestForConcurrency(request, shardCount)
+	if split.ErrorResult != "" {
+		return errorResult(split.ErrorResult)
+	}
+
+	ds := &distributedSim{
+		coordinator: coordinator,
+		shards:      split.Requests,
+		queue:       make(chan int, len(split.Requests)),
+		results:     make([]*proto.RaidSimResult, len(split.Requests)),
+		attempts:    make([]int, len(split.Requests)),
+		pending:     len(split.Requests),
+		alive:       len(coordinator.Workers),
+		maxAttempts: max(1, coordinator.MaxAttempts),
+		done:        make(chan struct{}),
+	}
+	for i := range ds.shards {
+		ds.queue <- i
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ds.cancel = cancel
+	defer cancel()
+
+	for _, worker := range coordinator.Workers {
+		go ds.runWorker(ctx, worker)
+	}
+	go ds.watchAbort(signals)
+
+	<-ds.done
+	if ds.failure != nil {
+		return &proto.RaidSimResult{Error: ds.failure}
+	}
+	return core.CombineConcurrentSimResults(ds.results, request.SimOptions.Debug)
+}
+
+func errorResult(message string) *proto.RaidSimResult {
+	return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: message}}
+}
+
+func (ds *distributedSim) finish(failure *proto.ErrorOutcome) {
+	ds.doneOnce.Do(func() {
+		ds.failure = failure
+		close(ds.done)
+		ds.cancel()
+	})
+}
+
+func (ds *distributedSim) watchAbort(signals simsignals.Signals) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ds.done:
+			return
+		case <-ticker.C:
+			if signals.Abort.IsTriggered() {
+				ds.finish(&proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted})
+				return
+			}
+		}
+	}
+}
+
+// Runs shards from the queue on a single worker until the sim is done, or
+// the worker has failed too often.
+func (ds *distributedSim) runWorker(ctx context.Context, worker string) {
+	consecutiveFailures := 0
+	for {
+		var shardIdx int
+		select {
+		case <-ds.done:
+			return
+		case shardIdx = <-ds.queue:
+		}
+
+		result, err := ds.coordinator.runShard(ctx, worker, ds.shards[shardIdx])
+		if ctx.Err() != nil {
+			// The sim finished or was aborted while the shard was in flight.
+			return
+		}
+
+		ds.mu.Lock()
+		switch {
+		case err != nil:
+			consecutiveFailures++
+			ds.attempts[shardIdx]++
+			log.Printf("Worker %s failed shard %d (attempt %d/%d): %s", worker, shardIdx, ds.attempts[shardIdx], ds.maxAttempts, err)
+
+			if ds.attempts[shardIdx] >= ds.maxAttempts {
+				ds.finish(&proto.ErrorOutcome{Message: fmt.Sprintf("shard %d failed %d times, last error: %s", shardIdx, ds.attempts[shardIdx], err)})
+			} else {
+				ds.queue <- shardIdx
+			}
+			if consecutiveFailures >= ds.maxAttempts {
+				log.Printf("Worker %s failed %d shards in a row, no longer using it", worker, consecutiveFailures)
+				ds.alive--
+				if ds.alive == 0 {
+					ds.finish(&proto.ErrorOutcome{Message: fmt.Sprintf("all workers failed, last error: %s", err)})
+				}
+				ds.mu.Unlock()
+				return
+			}
+		case result.Error != nil:
+			// Errors from the sim itself would happen again on a retry.
+			ds.finish(result.Error)
+		default:
+			consecutiveFailures = 0
+			ds.results[shardIdx] = result
+			ds.pending--
+			if ds.coordinator.OnProgress != nil {
+				ds.coordinator.OnProgress(ds.completedIterations(), ds.totalIterations())
+			}
+			if ds.pending == 0 {
+				ds.finish(nil)
+			}
+		}
+		ds.mu.Unlock()
+	}
+}
+
+func (ds *distributedSim) completedIterations() int32 {
+	var total int32
+	for _, result := range ds.results {
+		if result != nil {
+			total += result.IterationsDone
+		}
+	}
+	return total
+}
+
+func (ds *distributedSim) totalIterations() int32 {
+	var total int32
+	for _, shard := range ds.shards {
+		total += shard.SimOptions.Iterations
+	}
+	return total
+}
+
+func (coordinator *Coordinator) runShard(ctx context.Context, worker string, shard *proto.RaidSimRequest) (*proto.RaidSimResult, error) {
+	body, err := googleProto.Marshal(shard)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, worker+RaidSimPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", contentType)
+
+	client := coordinator.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	output, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", response.Status, strings.TrimSpace(string(output)))
+	}
+
+	result := &proto.RaidSimResult{}
+	if err := googleProto.Unmarshal(output, result); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,151 @@
+package distsim
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// A worker which returns a result with a constant DPS for each shard, so the
+// coordinator can be tested without running real sims.
+func newFakeWorker(t *testing.T, dps float64, failures int32) (*httptest.Server, *atomic.Int32) {
+	var shardsDone atomic.Int32
+	var failuresLeft atomic.Int32
+	failuresLeft.Store(failures)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failuresLeft.Add(-1) >= 0 {
+			http.Error(w, "worker unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		request := &proto.RaidSimRequest{}
+		if err := googleProto.Unmarshal(body, request); err != nil {
+			t.Errorf("Failed to parse shard: %s", err)
+		}
+
+		n := request.SimOptions.Iterations
+		dist := func() *proto.DistributionMetrics {
+			return &proto.DistributionMetrics{
+				Avg:            dps,
+				Hist:           map[int32]int32{},
+				AggregatorData: &proto.AggregatorData{N: n, SumSq: dps * dps * float64(n)},
+			}
+		}
+		output, _ := googleProto.Marshal(&proto.RaidSimResult{
+			RaidMetrics:      &proto.RaidMetrics{Dps: dist(), Hps: dist()},
+			EncounterMetrics: &proto.EncounterMetrics{},
+			IterationsDone:   n,
+		})
+		shardsDone.Add(1)
+		w.Write(output)
+	}))
+	t.Cleanup(server.Close)
+	return server, &shardsDone
+}
+
+// A worker which never answers, and counts the requests cancelled by the
+// coordinator.
+func newHangingWorker(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	var cancelled atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The server only notices the client going away once the body is read.
+		io.ReadAll(r.Body)
+		<-r.Context().Done()
+		cancelled.Add(1)
+	}))
+	t.Cleanup(server.Close)
+	return server, &cancelled
+}
+
+func testRequest(iterations int32) *proto.RaidSimRequest {
+	return &proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{Iterations: iterations, RandomSeed: 1},
+	}
+}
+
+func TestCoordinatorCombinesShards(t *testing.T) {
+	workerA, doneA := newFakeWorker(t, 1000, 0)
+	workerB, doneB := newFakeWorker(t, 1000, 0)
+
+	coordinator := NewCoordinator([]string{workerA.URL, workerB.URL})
+	result := coordinator.RunRaidSim(testRequest(1000), simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Unexpected error: %s", result.Error.Message)
+	}
+	if result.IterationsDone != 1000 {
+		t.Fatalf("Expected 1000 iterations, got %d", result.IterationsDone)
+	}
+	if result.RaidMetrics.Dps.Avg < 999.99 || result.RaidMetrics.Dps.Avg > 1000.01 {
+		t.Fatalf("Expected 1000 DPS, got %f", result.RaidMetrics.Dps.Avg)
+	}
+	if total := doneA.Load() + doneB.Load(); total != 2*defaultShardsPerWorker {
+		t.Fatalf("Expected %d shards, got %d", 2*defaultShardsPerWorker, total)
+	}
+}
+
+func TestCoordinatorRetriesFailedShards(t *testing.T) {
+	flaky, _ := newFakeWorker(t, 1000, 2)
+	healthy, _ := newFakeWorker(t, 1000, 0)
+
+	coordinator := NewCoordinator([]string{flaky.URL, healthy.URL})
+	result := coordinator.RunRaidSim(testRequest(1000), simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Unexpected error: %s", result.Error.Message)
+	}
+	if result.IterationsDone != 1000 {
+		t.Fatalf("Expected 1000 iterations, got %d", result.IterationsDone)
+	}
+}
+
+func TestCoordinatorFailsWithoutWorkers(t *testing.T) {
+	down, _ := newFakeWorker(t, 1000, 1000)
+
+	coordinator := NewCoordinator([]string{down.URL})
+	result := coordinator.RunRaidSim(testRequest(1000), simsignals.CreateSignals())
+	if result.Error == nil {
+		t.Fatalf("Expected an error when all workers fail")
+	}
+}
+
+func TestCoordinatorAbortCancelsShards(t *testing.T) {
+	hanging, cancelled := newHangingWorker(t)
+
+	coordinator := NewCoordinator([]string{hanging.URL})
+	signals := simsignals.CreateSignals()
+	time.AfterFunc(200*time.Millisecond, signals.Abort.Trigger)
+
+	result := coordinator.RunRaidSim(testRequest(1000), signals)
+	if result.Error == nil || result.Error.Type != proto.ErrorOutcomeType_ErrorOutcomeAborted {
+		t.Fatalf("Expected the sim to be aborted, got %v", result.Error)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cancelled.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the shard in flight to be cancelled")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCoordinatorTimesOutShards(t *testing.T) {
+	hanging, _ := newHangingWorker(t)
+
+	coordinator := NewCoordinator([]string{hanging.URL})
+	coordinator.Client.Timeout = 100 * time.Millisecond
+
+	result := coordinator.RunRaidSim(testRequest(1000), simsignals.CreateSignals())
+	if result.Error == nil {
+		t.Fatalf("Expected an error when the worker never answers")
+	}
+}
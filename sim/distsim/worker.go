@@ -0,0 +1,67 @@
+// Package distsim runs sims across multiple processes or machines. A
+// Coordinator splits each sim into shards the same way concurrent sims are
+// split between threads, sends the shards to workers over HTTP and combines
+// their results.
+//
+// Workers accept a protobuf RaidSimRequest on RaidSimPath and respond with a
+// protobuf RaidSimResult, the same as the /raidSim endpoint of the web
+// server, so a running web server can also be used as a worker.
+package distsim
+
+import (
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	RaidSimPath = "/raidSim"
+
+	contentType = "application/x-protobuf"
+)
+
+// NewWorkerHandler returns the HTTP handler of a worker. Each shard is run
+// concurrently on all CPUs, limited by core.SetWorkerBudget when several
+// shards are received at once.
+func NewWorkerHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(RaidSimPath, handleRaidSim)
+	return mux
+}
+
+func handleRaidSim(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "expected POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request := &proto.RaidSimRequest{}
+	if err := googleProto.Unmarshal(body, request); err != nil {
+		http.Error(w, "failed to parse request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.SimOptions == nil {
+		http.Error(w, "request has no sim options", http.StatusBadRequest)
+		return
+	}
+
+	result := core.RunRaidSimConcurrent(request)
+
+	output, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("Failed to marshal result: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(output)
+}
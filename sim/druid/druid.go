@@ -233,9 +233,9 @@ func (druid *Druid) RegisterBaselineSpells() {
 	druid.registerFaerieFireSpell()
 	druid.registerTranquilityCD()
 	druid.registerRejuvenationSpell()
+	druid.registerInnervateCD()
 
 	// druid.registerRebirthSpell()
-	// druid.registerInnervateCD()
 }
 
 func (druid *Druid) RegisterFeralCatSpells() {
@@ -42,6 +42,7 @@ func (bear *GuardianDruid) registerToothAndClawPassive() {
 
 		absorbedDamage := min(float64(debuff.GetStacks()), result.Damage)
 		result.Damage -= absorbedDamage
+		result.Absorbed += absorbedDamage
 
 		if sim.Log != nil {
 			result.Target.Log(sim, "Tooth and Claw absorbed %.1f damage from incoming auto-attack.", absorbedDamage)
@@ -0,0 +1,84 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/stats"
+)
+
+const (
+	InnervateAuraTag = "Innervate"
+	InnervateCD      = time.Minute * 3
+	InnervateTicks   = 10
+
+	// Each second the target regenerates mana equal to this fraction of the
+	// casting druid's Spirit.
+	InnervateSpiritCoeff = 0.5
+)
+
+// Causes the target to regenerate mana over 10 sec, based on the druid's Spirit.
+func (druid *Druid) registerInnervateCD() {
+	innervateTarget := druid.GetUnit(druid.SelfBuffs.InnervateTarget)
+	if innervateTarget == nil || !innervateTarget.HasManaBar() {
+		return
+	}
+
+	actionID := core.ActionID{SpellID: 29166, Tag: druid.Index}
+	manaMetrics := innervateTarget.NewManaMetrics(actionID)
+
+	var manaPerTick float64
+	var tickAction *core.PendingAction
+
+	innervateAura := innervateTarget.GetOrRegisterAura(core.Aura{
+		Label:    "Innervate-" + actionID.String(),
+		Tag:      InnervateAuraTag,
+		ActionID: actionID,
+		Duration: time.Second * InnervateTicks,
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			tickAction = core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:   time.Second,
+				NumTicks: InnervateTicks,
+				OnAction: func(sim *core.Simulation) {
+					aura.Unit.AddMana(sim, manaPerTick, manaMetrics)
+				},
+			})
+		},
+		OnExpire: func(_ *core.Aura, sim *core.Simulation) {
+			tickAction.Cancel(sim)
+		},
+	})
+
+	innervateSpell := druid.RegisterSpell(Humanoid|Moonkin|Tree, core.SpellConfig{
+		ActionID: actionID,
+		Flags:    core.SpellFlagAPL | core.SpellFlagHelpful,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    druid.NewTimer(),
+				Duration: InnervateCD,
+			},
+		},
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			// If target already has another innervate, don't cast.
+			return !innervateTarget.HasActiveAuraWithTag(InnervateAuraTag)
+		},
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			manaPerTick = InnervateSpiritCoeff * druid.GetStat(stats.Spirit)
+			innervateAura.Activate(sim)
+		},
+	})
+
+	druid.AddMajorCooldown(core.MajorCooldown{
+		Spell: innervateSpell.Spell,
+		Type:  core.CooldownTypeMana,
+		ShouldActivate: func(sim *core.Simulation, character *core.Character) bool {
+			// Wait until the full amount fits into the target's mana pool.
+			restored := InnervateSpiritCoeff * druid.GetStat(stats.Spirit) * InnervateTicks
+			return innervateTarget.MaxMana()-innervateTarget.CurrentMana() >= restored
+		},
+	})
+}
@@ -21,7 +21,7 @@ func (druid *Druid) registerNaturesSwiftness() {
 		ActionID: actionID,
 		Duration: core.NeverExpires,
 		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
-			if !spell.Matches(DruidSpellHealingTouch) {
+			if !spell.Matches(DruidHealingNonInstantSpells) {
 				return
 			}
 			aura.Deactivate(sim)
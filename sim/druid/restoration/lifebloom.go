@@ -0,0 +1,116 @@
+package restoration
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/druid"
+)
+
+const (
+	LifebloomTickBonusCoeff  = 0.057
+	LifebloomTickCoeff       = 0.526
+	LifebloomBloomBonusCoeff = 0.752
+	LifebloomBloomCoeff      = 5.31
+)
+
+func (resto *RestorationDruid) registerLifebloomSpell() {
+	baseTickHealing := LifebloomTickCoeff * resto.ClassSpellScaling
+	baseBloomHealing := LifebloomBloomCoeff * resto.ClassSpellScaling
+
+	// The final bloom is a separate heal that scales with the number of stacks,
+	// spell power included, so it has no bonus coefficient of its own.
+	bloomSpell := resto.RegisterSpell(druid.Any, core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 33778},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellHealing,
+		ClassSpellMask:   druid.DruidSpellLifebloom,
+		Flags:            core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   resto.DefaultCritMultiplier(),
+	})
+
+	resto.Lifebloom = resto.RegisterSpell(druid.Humanoid|druid.Tree, core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 33763},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellHealing,
+		ClassSpellMask:   druid.DruidSpellLifebloom,
+		Flags:            core.SpellFlagHelpful | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   resto.DefaultCritMultiplier(),
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 5.9,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label:     "Lifebloom",
+				MaxStacks: 3,
+			},
+
+			NumberOfTicks:       15,
+			TickLength:          time.Second,
+			AffectedByCastSpeed: true,
+			BonusCoefficient:    LifebloomTickBonusCoeff,
+
+			OnSnapshot: func(_ *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, baseTickHealing)
+				dot.SnapshotBaseDamage *= float64(max(dot.GetStacks(), 1))
+			},
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+
+				// Only a Lifebloom that runs its full duration blooms, not one that was moved to another target.
+				if dot.RemainingTicks() == 0 {
+					bloomHealing := (baseBloomHealing + LifebloomBloomBonusCoeff*bloomSpell.HealingPower(target)) * float64(dot.GetStacks())
+					bloomSpell.CalcAndDealHealing(sim, target, bloomHealing, bloomSpell.OutcomeHealingCrit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if target.IsOpponent(&resto.Unit) {
+				target = &resto.Unit
+			}
+
+			// Lifebloom can only be active on a single target, unless in Tree of Life.
+			treeOfLife := resto.TreeOfLifeAura != nil && resto.TreeOfLifeAura.IsActive()
+			if !treeOfLife && resto.lifebloomTarget != nil && resto.lifebloomTarget != target {
+				spell.Hot(resto.lifebloomTarget).Deactivate(sim)
+			}
+			resto.lifebloomTarget = target
+
+			hot := spell.Hot(target)
+			hot.Apply(sim)
+			hot.AddStack(sim)
+			hot.TakeSnapshot(sim, false)
+		},
+	})
+}
+
+// Healing Touch, Nourish and Regrowth refresh the duration of Lifebloom on their target.
+func (resto *RestorationDruid) registerLifebloomRefresh() {
+	core.MakePermanent(resto.RegisterAura(core.Aura{
+		Label: "Lifebloom Refresh" + resto.Label,
+		OnHealDealt: func(_ *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if !spell.Matches(druid.DruidHealingNonInstantSpells) {
+				return
+			}
+
+			hot := resto.Lifebloom.Hot(result.Target)
+			if hot.IsActive() {
+				hot.Apply(sim)
+			}
+		},
+	}))
+}
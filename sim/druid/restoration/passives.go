@@ -0,0 +1,88 @@
+package restoration
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/druid"
+)
+
+const (
+	// Spells whose direct healing benefits from Harmony and triggers the periodic bonus.
+	HarmonyDirectSpells = druid.DruidSpellHealingTouch | druid.DruidSpellRegrowth | druid.DruidSpellNourish | druid.DruidSpellSwiftmend
+)
+
+func (resto *RestorationDruid) RegisterRestorationPassives() {
+	resto.registerNaturalInsight()
+	resto.registerMeditation()
+	resto.registerMastery()
+	resto.registerLifebloomRefresh()
+}
+
+func (resto *RestorationDruid) registerNaturalInsight() {
+	resto.MultiplyStat(stats.Mana, 5)
+}
+
+// Allows 50% of your mana regeneration from Spirit to continue while in combat.
+func (resto *RestorationDruid) registerMeditation() {
+	resto.PseudoStats.SpiritRegenRateCombat = 0.5
+}
+
+/*
+Increases your direct healing by an additional (10 + <Mastery Rating> / 480)%, and your casting of direct healing
+spells grants you an additional (10 + <Mastery Rating> / 480)% bonus to periodic healing for 20 sec.
+*/
+func (resto *RestorationDruid) registerMastery() {
+	directMod := resto.AddDynamicMod(core.SpellModConfig{
+		ClassMask:  HarmonyDirectSpells,
+		Kind:       core.SpellMod_DamageDone_Pct,
+		FloatValue: resto.getMasteryPercent(),
+	})
+
+	periodicMod := resto.AddDynamicMod(core.SpellModConfig{
+		ClassMask:  druid.DruidSpellHoT,
+		Kind:       core.SpellMod_DotDamageDone_Pct,
+		FloatValue: resto.getMasteryPercent(),
+	})
+
+	core.MakePermanent(resto.RegisterAura(core.Aura{
+		Label:    "Mastery: Harmony" + resto.Label,
+		ActionID: core.ActionID{SpellID: 77495},
+
+		OnGain: func(_ *core.Aura, _ *core.Simulation) {
+			directMod.Activate()
+		},
+		OnExpire: func(_ *core.Aura, _ *core.Simulation) {
+			directMod.Deactivate()
+		},
+		OnCastComplete: func(_ *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell.Matches(HarmonyDirectSpells) {
+				resto.HarmonyAura.Activate(sim)
+			}
+		},
+	}))
+
+	resto.HarmonyAura = resto.RegisterAura(core.Aura{
+		Label:    "Harmony",
+		ActionID: core.ActionID{SpellID: 100977},
+		Duration: time.Second * 20,
+
+		OnGain: func(_ *core.Aura, _ *core.Simulation) {
+			periodicMod.Activate()
+		},
+		OnExpire: func(_ *core.Aura, _ *core.Simulation) {
+			periodicMod.Deactivate()
+		},
+	})
+
+	// Keep it updated when mastery changes
+	resto.AddOnMasteryStatChanged(func(_ *core.Simulation, _ float64, _ float64) {
+		directMod.UpdateFloatValue(resto.getMasteryPercent())
+		periodicMod.UpdateFloatValue(resto.getMasteryPercent())
+	})
+}
+
+func (resto *RestorationDruid) getMasteryPercent() float64 {
+	return (8.0 + resto.GetMasteryPoints()) * 0.0125
+}
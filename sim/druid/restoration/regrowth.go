@@ -0,0 +1,89 @@
+package restoration
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/druid"
+)
+
+const (
+	RegrowthBonusCoeff    = 0.958
+	RegrowthCoeff         = 9.813
+	RegrowthVariance      = 0.116
+	RegrowthHotBonusCoeff = 0.073
+	RegrowthHotCoeff      = 0.732
+
+	// The direct heal has a 60% increased critical effect chance, the glyph adds another 40% but removes the HoT.
+	RegrowthBonusCritPercent      = 60.0
+	RegrowthGlyphBonusCritPercent = 40.0
+)
+
+func (resto *RestorationDruid) registerRegrowthSpell() {
+	hasGlyph := resto.HasMajorGlyph(proto.DruidMajorGlyph_GlyphOfRegrowth)
+	baseTickHealing := RegrowthHotCoeff * resto.ClassSpellScaling
+
+	config := core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 8936},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellHealing,
+		ClassSpellMask:   druid.DruidSpellRegrowth,
+		Flags:            core.SpellFlagHelpful | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   resto.DefaultCritMultiplier(),
+		BonusCoefficient: RegrowthBonusCoeff,
+		BonusCritPercent: core.TernaryFloat64(hasGlyph, RegrowthBonusCritPercent+RegrowthGlyphBonusCritPercent, RegrowthBonusCritPercent),
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 29.7,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if target.IsOpponent(&resto.Unit) {
+				target = &resto.Unit
+			}
+
+			baseHealing := resto.CalcAndRollDamageRange(sim, RegrowthCoeff, RegrowthVariance)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+
+			if !hasGlyph {
+				spell.Hot(target).Apply(sim)
+			}
+		},
+	}
+
+	if !hasGlyph {
+		config.Hot = core.DotConfig{
+			Aura: core.Aura{
+				Label: "Regrowth",
+			},
+
+			NumberOfTicks:       3,
+			TickLength:          time.Second * 2,
+			AffectedByCastSpeed: true,
+			BonusCoefficient:    RegrowthHotBonusCoeff,
+
+			OnSnapshot: func(_ *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, baseTickHealing)
+
+				// The bonus crit chance only applies to the direct heal.
+				dot.SnapshotCritChance -= RegrowthBonusCritPercent / 100
+			},
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		}
+	}
+
+	resto.Regrowth = resto.RegisterSpell(druid.Humanoid|druid.Tree, config)
+}
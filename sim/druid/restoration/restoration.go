@@ -3,6 +3,7 @@ package restoration
 import (
 	"github.com/wowsims/mop/sim/core"
 	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
 	"github.com/wowsims/mop/sim/druid"
 )
 
@@ -31,8 +32,9 @@ func NewRestorationDruid(character *core.Character, options *proto.Player) *Rest
 		Druid: druid.New(character, druid.Tree, selfBuffs, options.TalentsString),
 	}
 
-	resto.SelfBuffs.InnervateTarget = &proto.UnitReference{}
-	if restoOptions.Options.ClassOptions.InnervateTarget != nil {
+	// Restoration druids innervate themselves unless told otherwise.
+	resto.SelfBuffs.InnervateTarget = &proto.UnitReference{Type: proto.UnitReference_Self}
+	if target := restoOptions.Options.ClassOptions.InnervateTarget; target != nil && target.Type != proto.UnitReference_Unknown {
 		resto.SelfBuffs.InnervateTarget = restoOptions.Options.ClassOptions.InnervateTarget
 	}
 
@@ -41,6 +43,18 @@ func NewRestorationDruid(character *core.Character, options *proto.Player) *Rest
 
 type RestorationDruid struct {
 	*druid.Druid
+
+	Efflorescence *druid.DruidSpell
+	Lifebloom     *druid.DruidSpell
+	Regrowth      *druid.DruidSpell
+	Swiftmend     *druid.DruidSpell
+	TreeOfLife    *druid.DruidSpell
+	WildGrowth    *druid.DruidSpell
+
+	HarmonyAura    *core.Aura
+	TreeOfLifeAura *core.Aura
+
+	lifebloomTarget *core.Unit
 }
 
 func (resto *RestorationDruid) GetDruid() *druid.Druid {
@@ -49,10 +63,26 @@ func (resto *RestorationDruid) GetDruid() *druid.Druid {
 
 func (resto *RestorationDruid) Initialize() {
 	resto.Druid.Initialize()
+
+	resto.RegisterRestorationPassives()
+	resto.RegisterRestorationSpells()
 }
 
-func (resto *RestorationDruid) ApplyTalents() {}
+func (resto *RestorationDruid) ApplyTalents() {
+	resto.Druid.ApplyTalents()
+	resto.ApplyArmorSpecializationEffect(stats.Intellect, proto.ArmorType_ArmorTypeLeather, 86097)
+
+	resto.registerTreeOfLife()
+}
+
+func (resto *RestorationDruid) RegisterRestorationSpells() {
+	resto.registerRegrowthSpell()
+	resto.registerLifebloomSpell()
+	resto.registerWildGrowthSpell()
+	resto.registerSwiftmendSpell()
+}
 
 func (resto *RestorationDruid) Reset(sim *core.Simulation) {
 	resto.Druid.Reset(sim)
+	resto.lifebloomTarget = nil
 }
@@ -0,0 +1,67 @@
+package restoration
+
+import (
+	"testing"
+
+	_ "github.com/wowsims/mop/sim/common" // imported to get caster sets included.
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func init() {
+	RegisterRestorationDruid()
+}
+
+func TestRestoration(t *testing.T) {
+	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator([]core.CharacterSuiteConfig{
+		{
+			Class:    proto.Class_ClassDruid,
+			Race:     proto.Race_RaceTauren,
+			IsHealer: true,
+
+			GearSet: core.GetGearSet("../../../ui/druid/restoration/gear_sets", "preraid"),
+			OtherGearSets: []core.GearSetCombo{
+				core.GetGearSet("../../../ui/druid/restoration/gear_sets", "p1"),
+			},
+			Talents:     StandardTalents,
+			Glyphs:      StandardGlyphs,
+			Consumables: FullConsumesSpec,
+			SpecOptions: core.SpecOptionsCombo{Label: "Standard", SpecOptions: PlayerOptionsStandard},
+			Rotation:    core.GetAplRotation("../../../ui/druid/restoration/apls", "default"),
+
+			ItemFilter: core.ItemFilter{
+				WeaponTypes: []proto.WeaponType{
+					proto.WeaponType_WeaponTypeDagger,
+					proto.WeaponType_WeaponTypeMace,
+					proto.WeaponType_WeaponTypeOffHand,
+					proto.WeaponType_WeaponTypeStaff,
+					proto.WeaponType_WeaponTypePolearm,
+				},
+				ArmorType: proto.ArmorType_ArmorTypeLeather,
+			},
+		},
+	}))
+}
+
+var StandardTalents = "113222"
+var StandardGlyphs = &proto.Glyphs{
+	Major1: int32(proto.DruidMajorGlyph_GlyphOfRebirth),
+	Major2: int32(proto.DruidMajorGlyph_GlyphOfStampede),
+}
+
+var FullConsumesSpec = &proto.ConsumesSpec{
+	FlaskId:  76085, // Flask of the Warm Sun
+	FoodId:   74650, // Mogu Fish Stew
+	PotId:    76093, // Potion of the Jade Serpent
+	PrepotId: 76093, // Potion of the Jade Serpent
+}
+
+var PlayerOptionsStandard = &proto.Player_RestorationDruid{
+	RestorationDruid: &proto.RestorationDruid{
+		Options: &proto.RestorationDruid_Options{
+			ClassOptions: &proto.DruidOptions{
+				InnervateTarget: &proto.UnitReference{Type: proto.UnitReference_Self},
+			},
+		},
+	},
+}
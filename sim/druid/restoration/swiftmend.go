@@ -0,0 +1,116 @@
+package restoration
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/druid"
+)
+
+const (
+	SwiftmendBonusCoeff     = 1.292
+	SwiftmendCoeff          = 12.59
+	EfflorescenceBonusCoeff = 0.116
+	EfflorescenceCoeff      = 1.16
+)
+
+func (resto *RestorationDruid) registerSwiftmendSpell() {
+	resto.registerEfflorescence()
+
+	resto.Swiftmend = resto.RegisterSpell(druid.Humanoid|druid.Tree, core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 18562},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellHealing,
+		ClassSpellMask:   druid.DruidSpellSwiftmend,
+		Flags:            core.SpellFlagHelpful | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   resto.DefaultCritMultiplier(),
+		BonusCoefficient: SwiftmendBonusCoeff,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 8.5,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    resto.NewTimer(),
+				Duration: time.Second * 15,
+			},
+		},
+
+		// Swiftmend requires Rejuvenation or Regrowth to be active on the target.
+		ExtraCastCondition: func(_ *core.Simulation, target *core.Unit) bool {
+			if target.IsOpponent(&resto.Unit) {
+				target = &resto.Unit
+			}
+			return resto.hasSwiftmendHot(target)
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if target.IsOpponent(&resto.Unit) {
+				target = &resto.Unit
+			}
+
+			baseHealing := SwiftmendCoeff * resto.ClassSpellScaling
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+			resto.Efflorescence.Cast(sim, target)
+		},
+	})
+}
+
+func (resto *RestorationDruid) hasSwiftmendHot(target *core.Unit) bool {
+	if hot := resto.Rejuvenation.Hot(target); hot != nil && hot.IsActive() {
+		return true
+	}
+	if hot := resto.Regrowth.Hot(target); hot != nil && hot.IsActive() {
+		return true
+	}
+	return false
+}
+
+// Swiftmend creates a patch of Efflorescence at the target, healing the 3 most
+// injured allies within it every second.
+func (resto *RestorationDruid) registerEfflorescence() {
+	baseTickHealing := EfflorescenceCoeff * resto.ClassSpellScaling
+	var center *core.Unit
+
+	resto.Efflorescence = resto.RegisterSpell(druid.Any, core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 81262},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellHealing,
+		Flags:            core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   resto.DefaultCritMultiplier(),
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Efflorescence",
+			},
+
+			SelfOnly:         true,
+			NumberOfTicks:    7,
+			TickLength:       time.Second,
+			BonusCoefficient: EfflorescenceBonusCoeff,
+
+			OnSnapshot: func(_ *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, baseTickHealing)
+			},
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, healTarget := range append([]*core.Unit{center}, resto.Env.Raid.SmartHealTargets(center, 2)...) {
+					dot.CalcAndDealPeriodicSnapshotHealing(sim, healTarget, dot.OutcomeSnapshotCrit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			center = target
+			spell.SelfHot().Apply(sim)
+		},
+	})
+}
@@ -0,0 +1,66 @@
+package restoration
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/druid"
+)
+
+/*
+Incarnation: Tree of Life
+Increases healing done by 15%, allows Lifebloom to be active on multiple targets,
+makes Regrowth instant and causes Wild Growth to affect 2 additional targets.
+*/
+func (resto *RestorationDruid) registerTreeOfLife() {
+	if !resto.Talents.Incarnation {
+		return
+	}
+
+	actionID := core.ActionID{SpellID: 33891}
+
+	resto.TreeOfLifeAura = resto.RegisterAura(core.Aura{
+		Label:    "Incarnation: Tree of Life",
+		ActionID: actionID,
+		Duration: time.Second * 30,
+		OnExpire: func(_ *core.Aura, sim *core.Simulation) {
+			// Only the most recently applied Lifebloom survives leaving the form.
+			for _, unit := range resto.Env.Raid.AllPlayerUnits {
+				if unit != resto.lifebloomTarget {
+					resto.Lifebloom.Hot(unit).Deactivate(sim)
+				}
+			}
+		},
+	}).AttachMultiplicativePseudoStatBuff(
+		&resto.PseudoStats.HealingDealtMultiplier, 1.15,
+	).AttachSpellMod(core.SpellModConfig{
+		ClassMask:  druid.DruidSpellRegrowth,
+		Kind:       core.SpellMod_CastTime_Pct,
+		FloatValue: -1,
+	})
+
+	resto.TreeOfLife = resto.RegisterSpell(druid.Any, core.SpellConfig{
+		ActionID:        actionID,
+		Flags:           core.SpellFlagAPL,
+		RelatedSelfBuff: resto.TreeOfLifeAura,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    resto.NewTimer(),
+				Duration: time.Minute * 3,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.RelatedSelfBuff.Activate(sim)
+		},
+	})
+
+	resto.AddMajorCooldown(core.MajorCooldown{
+		Spell: resto.TreeOfLife.Spell,
+		Type:  core.CooldownTypeDPS,
+	})
+}
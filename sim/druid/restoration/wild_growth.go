@@ -0,0 +1,97 @@
+package restoration
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/druid"
+)
+
+const (
+	WildGrowthBonusCoeff = 0.0989
+	WildGrowthCoeff      = 0.986
+
+	// Wild Growth front-loads its healing: the first tick heals for this much
+	// more than the average tick and the last one for this much less.
+	WildGrowthTickDecay = 0.15
+)
+
+func (resto *RestorationDruid) registerWildGrowthSpell() {
+	hasGlyph := resto.HasMajorGlyph(proto.DruidMajorGlyph_GlyphOfWildGrowth)
+	baseTickHealing := WildGrowthCoeff * resto.ClassSpellScaling
+
+	numTargets := 5
+	cooldown := time.Second * 8
+	if hasGlyph {
+		numTargets++
+		cooldown += time.Second * 2
+	}
+
+	resto.WildGrowth = resto.RegisterSpell(druid.Humanoid|druid.Tree, core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 48438},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellHealing,
+		ClassSpellMask:   druid.DruidSpellWildGrowth,
+		Flags:            core.SpellFlagHelpful | core.SpellFlagAPL,
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   resto.DefaultCritMultiplier(),
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 22.9,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    resto.NewTimer(),
+				Duration: cooldown,
+			},
+		},
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Wild Growth",
+			},
+
+			NumberOfTicks:       7,
+			TickLength:          time.Second,
+			AffectedByCastSpeed: true,
+			BonusCoefficient:    WildGrowthBonusCoeff,
+
+			OnSnapshot: func(_ *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, baseTickHealing)
+			},
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				result := dot.CalcSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+
+				totalTicks := dot.HastedTickCount()
+				if totalTicks > 1 {
+					progress := float64(dot.TickCount()-1) / float64(totalTicks-1)
+					result.Damage *= 1 + WildGrowthTickDecay*(1-2*progress)
+				}
+
+				dot.Spell.DealPeriodicHealing(sim, result)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if target.IsOpponent(&resto.Unit) {
+				target = &resto.Unit
+			}
+
+			count := numTargets
+			if resto.TreeOfLifeAura != nil && resto.TreeOfLifeAura.IsActive() {
+				count += 2
+			}
+
+			for _, healTarget := range append([]*core.Unit{target}, resto.Env.Raid.SmartHealTargets(target, count-1)...) {
+				spell.Hot(healTarget).Apply(sim)
+			}
+		},
+	})
+}
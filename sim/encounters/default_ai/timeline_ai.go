@@ -0,0 +1,468 @@
+package default_ai
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// How often health based phase triggers are checked.
+const timelineHealthCheckPeriod = time.Millisecond * 250
+
+// Generic TargetAI which runs the BossTimeline of its target config. Abilities
+// of the embedded DefaultAI are used in between timeline casts, so presets can
+// combine both.
+type TimelineAI struct {
+	DefaultAI
+
+	// Builds the timeline of presets from their target inputs. Only used when
+	// the target config has no timeline of its own.
+	BuildTimeline func(*proto.Target) *proto.BossTimeline
+
+	phases []*timelinePhase
+
+	tank       *core.Unit
+	secondTank *core.Unit
+
+	// Index of the running phase.
+	currentPhase int
+
+	// Actions of the running phase, cancelled when the next one starts.
+	phaseActions []*core.PendingAction
+
+	// Casts waiting for the boss to finish its current cast.
+	queuedCasts []*core.Spell
+}
+
+type timelinePhase struct {
+	config *proto.BossTimelinePhase
+	events []*timelineEvent
+}
+
+type timelineEvent struct {
+	config *proto.BossTimelineEvent
+
+	spell *core.Spell
+	auras []*core.Aura
+}
+
+func NewTimelineAI(abilities []TargetAbility) core.AIFactory {
+	return func() core.TargetAI {
+		return &TimelineAI{
+			DefaultAI: DefaultAI{
+				Abilities: abilities,
+			},
+		}
+	}
+}
+
+func NewPresetTimelineAI(buildTimeline func(*proto.Target) *proto.BossTimeline, abilities []TargetAbility) core.AIFactory {
+	return func() core.TargetAI {
+		return &TimelineAI{
+			DefaultAI: DefaultAI{
+				Abilities: abilities,
+			},
+			BuildTimeline: buildTimeline,
+		}
+	}
+}
+
+func (ai *TimelineAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.DefaultAI.Initialize(target, config)
+	ai.tank = target.CurrentTarget
+	ai.secondTank = target.SecondaryTarget
+
+	timeline := config.Timeline
+	if (timeline == nil) && (ai.BuildTimeline != nil) {
+		timeline = ai.BuildTimeline(config)
+	}
+	if timeline == nil {
+		return
+	}
+
+	// Tags keep casts with the same spell ID apart.
+	var numCasts int32
+	for phaseIdx, phaseConfig := range timeline.Phases {
+		phase := &timelinePhase{config: phaseConfig}
+		for eventIdx, eventConfig := range phaseConfig.Events {
+			event := &timelineEvent{config: eventConfig}
+			// Player auras need labels unique across all targets.
+			label := fmt.Sprintf("%s Timeline %d-%d", target.Label, phaseIdx+1, eventIdx+1)
+
+			switch action := eventConfig.Action.(type) {
+			case *proto.BossTimelineEvent_Cast:
+				numCasts++
+				event.spell = ai.registerCast(action.Cast, numCasts)
+			case *proto.BossTimelineEvent_DamageTakenModifier:
+				event.auras = ai.registerDamageTakenModifier(action.DamageTakenModifier, label)
+			case *proto.BossTimelineEvent_DamageDealtModifier:
+				event.auras = []*core.Aura{ai.registerDamageDealtModifier(action.DamageDealtModifier, label)}
+			}
+
+			phase.events = append(phase.events, event)
+		}
+		ai.phases = append(ai.phases, phase)
+	}
+}
+
+func (ai *TimelineAI) registerCast(config *proto.BossTimelineCast, tag int32) *core.Spell {
+	castTime := core.DurationFromSeconds(config.CastTime)
+
+	flags := core.SpellFlagNone
+	if config.Melee {
+		flags |= core.SpellFlagMeleeMetrics
+	}
+
+	return ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: config.SpellId, Tag: tag},
+		SpellSchool:      core.SpellSchoolFromProto(config.School),
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            flags,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      castTime,
+				CastTime: castTime,
+			},
+
+			ModifyCast: func(sim *core.Simulation, spell *core.Spell, curCast *core.Cast) {
+				if curCast.CastTime == 0 {
+					return
+				}
+
+				hastedCastTime := spell.Unit.ApplyCastSpeedForSpell(curCast.CastTime, spell).Round(time.Millisecond)
+				spell.Unit.AutoAttacks.StopMeleeUntil(sim, sim.CurrentTime+hastedCastTime)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			outcome := spell.OutcomeAlwaysHit
+			if config.Melee {
+				outcome = spell.OutcomeEnemyMeleeWhite
+			}
+
+			dealDamage := func(target *core.Unit) {
+				damageRoll := config.MinDamage * (1 + config.DamageSpread*sim.RandomFloat("Timeline Damage"))
+				spell.CalcAndDealDamage(sim, target, damageRoll, outcome)
+			}
+
+			// Random targets are picked once for all ticks.
+			if config.Target == proto.BossTimelineCast_RandomPlayer {
+				players := sim.Raid.AllPlayerUnits
+				target = players[int(sim.RandomFloat("Timeline Target")*float64(len(players)))]
+			}
+
+			tick := func(sim *core.Simulation) {
+				if config.Target == proto.BossTimelineCast_Raid {
+					for _, player := range sim.Raid.AllPlayerUnits {
+						dealDamage(player)
+					}
+				} else {
+					dealDamage(target)
+				}
+			}
+
+			tick(sim)
+			if config.NumTicks > 1 {
+				core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+					Period:   core.DurationFromSeconds(config.TickInterval),
+					NumTicks: int(config.NumTicks - 1),
+					Priority: core.ActionPriorityDOT,
+					OnAction: tick,
+				})
+			}
+		},
+	})
+}
+
+func (ai *TimelineAI) registerDamageTakenModifier(config *proto.BossTimelineDamageTakenModifier, label string) []*core.Aura {
+	duration := core.DurationFromSeconds(config.Duration)
+	if duration == 0 {
+		duration = core.NeverExpires
+	}
+
+	units := []*core.Unit{&ai.Target.Unit}
+	if config.Players {
+		units = ai.Target.Env.Raid.AllPlayerUnits
+	}
+
+	return core.MapSlice(units, func(unit *core.Unit) *core.Aura {
+		return unit.RegisterAura(core.Aura{
+			Label:    label + " Damage Taken",
+			Duration: duration,
+
+			OnGain: func(aura *core.Aura, _ *core.Simulation) {
+				aura.Unit.PseudoStats.DamageTakenMultiplier *= config.Multiplier
+			},
+
+			OnExpire: func(aura *core.Aura, _ *core.Simulation) {
+				aura.Unit.PseudoStats.DamageTakenMultiplier /= config.Multiplier
+			},
+		})
+	})
+}
+
+func (ai *TimelineAI) registerDamageDealtModifier(config *proto.BossTimelineDamageDealtModifier, label string) *core.Aura {
+	duration := core.DurationFromSeconds(config.Duration)
+	if duration == 0 {
+		duration = core.NeverExpires
+	}
+
+	return ai.Target.RegisterAura(core.Aura{
+		Label:     label + " Damage Dealt",
+		Duration:  duration,
+		MaxStacks: max(config.MaxStacks, 1),
+
+		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+			aura.Unit.PseudoStats.DamageDealtMultiplier *= math.Pow(config.Multiplier, float64(newStacks-oldStacks))
+
+			if config.SpeedMultiplier > 0 {
+				speedMultiplier := math.Pow(config.SpeedMultiplier, float64(newStacks-oldStacks))
+				aura.Unit.MultiplyAttackSpeed(sim, speedMultiplier)
+				aura.Unit.MultiplyCastSpeed(sim, speedMultiplier)
+			}
+		},
+	})
+}
+
+func (ai *TimelineAI) Reset(sim *core.Simulation) {
+	ai.DefaultAI.Reset(sim)
+	ai.Target.SecondaryTarget = ai.secondTank
+	ai.currentPhase = -1
+	ai.phaseActions = nil
+	ai.queuedCasts = ai.queuedCasts[:0]
+
+	if len(ai.phases) > 0 {
+		ai.startPhase(sim, 0)
+	}
+}
+
+func (ai *TimelineAI) startPhase(sim *core.Simulation, phaseIdx int) {
+	for _, pa := range ai.phaseActions {
+		pa.Cancel(sim)
+	}
+	ai.phaseActions = ai.phaseActions[:0]
+	ai.currentPhase = phaseIdx
+
+	if sim.Log != nil {
+		ai.Target.Log(sim, "Starting timeline phase %d %s", phaseIdx+1, ai.phases[phaseIdx].config.Name)
+	}
+
+	for _, event := range ai.phases[phaseIdx].events {
+		ai.scheduleEvent(sim, event)
+	}
+
+	if phaseIdx+1 >= len(ai.phases) {
+		return
+	}
+
+	nextPhase := ai.phases[phaseIdx+1].config
+	healthReached := func(sim *core.Simulation) bool {
+		return (nextPhase.StartHealthPercent > 0) && (sim.GetRemainingDurationPercent()*100 <= nextPhase.StartHealthPercent)
+	}
+
+	if healthReached(sim) || ((nextPhase.StartTime > 0) && (core.DurationFromSeconds(nextPhase.StartTime) <= sim.CurrentTime)) {
+		ai.startPhase(sim, phaseIdx+1)
+		return
+	}
+
+	if nextPhase.StartTime > 0 {
+		ai.phaseActions = append(ai.phaseActions, core.StartDelayedAction(sim, core.DelayedActionOptions{
+			DoAt:     core.DurationFromSeconds(nextPhase.StartTime),
+			Priority: core.ActionPriorityDOT,
+
+			OnAction: func(sim *core.Simulation) {
+				ai.startPhase(sim, phaseIdx+1)
+			},
+		}))
+	}
+
+	if nextPhase.StartHealthPercent > 0 {
+		ai.phaseActions = append(ai.phaseActions, core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+			Period:   timelineHealthCheckPeriod,
+			Priority: core.ActionPriorityDOT,
+
+			OnAction: func(sim *core.Simulation) {
+				if (ai.currentPhase == phaseIdx) && healthReached(sim) {
+					ai.startPhase(sim, phaseIdx+1)
+				}
+			},
+		}))
+	}
+}
+
+func (ai *TimelineAI) scheduleEvent(sim *core.Simulation, event *timelineEvent) {
+	config := event.config
+	period := core.DurationFromSeconds(config.Period)
+
+	ai.phaseActions = append(ai.phaseActions, core.StartDelayedAction(sim, core.DelayedActionOptions{
+		DoAt:     sim.CurrentTime + core.DurationFromSeconds(config.Offset),
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			phaseIdx := ai.currentPhase
+			ai.runEvent(sim, event)
+
+			if (period <= 0) || (config.Count == 1) || (ai.currentPhase != phaseIdx) {
+				return
+			}
+
+			ai.phaseActions = append(ai.phaseActions, core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:   period,
+				NumTicks: int(max(config.Count-1, 0)),
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					ai.runEvent(sim, event)
+				},
+			}))
+		},
+	}))
+}
+
+func (ai *TimelineAI) runEvent(sim *core.Simulation, event *timelineEvent) {
+	switch action := event.config.Action.(type) {
+	case *proto.BossTimelineEvent_Cast:
+		ai.cast(sim, event.spell)
+	case *proto.BossTimelineEvent_SetTargetEnabled:
+		ai.setTargetEnabled(sim, action.SetTargetEnabled)
+	case *proto.BossTimelineEvent_Movement:
+		ai.movePlayers(sim, action.Movement)
+	case *proto.BossTimelineEvent_DamageTakenModifier:
+		for _, aura := range event.auras {
+			aura.Activate(sim)
+		}
+	case *proto.BossTimelineEvent_TankSwap:
+		ai.swapTanks(sim)
+	case *proto.BossTimelineEvent_DamageDealtModifier:
+		aura := event.auras[0]
+		aura.Activate(sim)
+		aura.AddStack(sim)
+	case *proto.BossTimelineEvent_Reposition:
+		ai.reposition(sim, action.Reposition)
+	}
+}
+
+func (ai *TimelineAI) castTarget() *core.Unit {
+	if ai.Target.CurrentTarget != nil {
+		return ai.Target.CurrentTarget
+	}
+	return ai.Target.Env.Raid.AllPlayerUnits[0]
+}
+
+func (ai *TimelineAI) cast(sim *core.Simulation, spell *core.Spell) {
+	if !ai.Target.IsEnabled() {
+		return
+	}
+
+	if (len(ai.queuedCasts) == 0) && spell.CanCast(sim, ai.castTarget()) {
+		spell.Cast(sim, ai.castTarget())
+		return
+	}
+
+	ai.queuedCasts = append(ai.queuedCasts, spell)
+}
+
+func (ai *TimelineAI) setTargetEnabled(sim *core.Simulation, config *proto.BossTimelineSetTargetEnabled) {
+	targets := sim.Encounter.AllTargetUnits
+	if (config.TargetIndex < 0) || (int(config.TargetIndex) >= len(targets)) {
+		return
+	}
+
+	targetUnit := targets[config.TargetIndex]
+	if config.Enabled {
+		if !targetUnit.IsEnabled() {
+			sim.EnableTargetUnit(targetUnit)
+		}
+	} else if targetUnit.IsEnabled() && (len(sim.Encounter.ActiveTargetUnits) > 1) {
+		sim.DisableTargetUnit(targetUnit, true)
+	}
+}
+
+func (ai *TimelineAI) movePlayers(sim *core.Simulation, config *proto.BossTimelineMovement) {
+	duration := core.DurationFromSeconds(config.Duration)
+
+	for _, player := range sim.Raid.AllPlayerUnits {
+		if config.ExcludeTanks && ((player == ai.tank) || (player == ai.secondTank)) {
+			continue
+		}
+
+		// Players finish their current cast before moving.
+		if (player.Hardcast.Expires > sim.CurrentTime) && !player.Hardcast.CanMove {
+			core.StartDelayedAction(sim, core.DelayedActionOptions{
+				DoAt:     player.Hardcast.Expires,
+				Priority: core.ActionPriorityPrePull + 1,
+
+				OnAction: func(sim *core.Simulation) {
+					player.MoveDuration(duration, sim)
+				},
+			})
+		} else {
+			player.MoveDuration(duration, sim)
+		}
+	}
+}
+
+func (ai *TimelineAI) reposition(sim *core.Simulation, config *proto.BossTimelineReposition) {
+	boss := &ai.Target.Unit
+	destination := core.Vector2FromProto(config.Position)
+	offset := destination.Sub(boss.GetPosition(sim))
+
+	// Followers keep their spot relative to the boss, so melee stay in range
+	// and behind it.
+	var followers []*core.Unit
+	if config.MeleeFollow {
+		for _, player := range sim.Raid.AllUnits {
+			if (player.CurrentTarget == boss) && (player.DistanceTo(sim, boss) <= core.MaxMeleeRange) {
+				followers = append(followers, player)
+			}
+		}
+	}
+
+	if config.Instant {
+		boss.SetPosition(sim, destination)
+		for _, follower := range followers {
+			follower.SetPosition(sim, follower.GetPosition(sim).Add(offset))
+		}
+		return
+	}
+
+	boss.MoveToPosition(sim, destination)
+	for _, follower := range followers {
+		follower.MoveToPosition(sim, follower.GetPosition(sim).Add(offset))
+	}
+}
+
+func (ai *TimelineAI) swapTanks(sim *core.Simulation) {
+	nextTank := ai.Target.SecondaryTarget
+	if (nextTank == nil) || (ai.Target.CurrentTarget == nil) {
+		return
+	}
+
+	ai.Target.SecondaryTarget = ai.Target.CurrentTarget
+	ai.Target.CurrentTarget = nextTank
+	nextTank.CurrentTarget = &ai.Target.Unit
+
+	if sim.CombatLog != nil {
+		sim.CombatLog.AddTargetChange(sim, &ai.Target.Unit, nextTank)
+		sim.CombatLog.AddTargetChange(sim, nextTank, &ai.Target.Unit)
+	}
+}
+
+func (ai *TimelineAI) ExecuteCustomRotation(sim *core.Simulation) {
+	for len(ai.queuedCasts) > 0 {
+		spell := ai.queuedCasts[0]
+		if !spell.CanCast(sim, ai.castTarget()) {
+			return
+		}
+
+		ai.queuedCasts = ai.queuedCasts[1:]
+		spell.Cast(sim, ai.castTarget())
+	}
+
+	ai.DefaultAI.ExecuteCustomRotation(sim)
+}
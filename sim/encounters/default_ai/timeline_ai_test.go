@@ -0,0 +1,243 @@
+package default_ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+)
+
+func init() {
+	core.RegisterAgentFactory(
+		proto.Player_ElementalShaman{},
+		proto.Spec_SpecElementalShaman,
+		func(char *core.Character, _ *proto.Player) core.Agent {
+			return &timelineTestAgent{Character: *char}
+		},
+		func(player *proto.Player, spec interface{}) {
+			player.Spec = spec.(*proto.Player_ElementalShaman)
+		},
+	)
+	core.SetTimelineAI(NewTimelineAI(nil))
+}
+
+// Player which does nothing, so only the timeline affects the sim.
+type timelineTestAgent struct {
+	core.Character
+}
+
+func (agent *timelineTestAgent) GetCharacter() *core.Character {
+	return &agent.Character
+}
+
+func (agent *timelineTestAgent) Initialize()                         {}
+func (agent *timelineTestAgent) ApplyTalents()                       {}
+func (agent *timelineTestAgent) Reset(_ *core.Simulation)            {}
+func (agent *timelineTestAgent) OnEncounterStart(_ *core.Simulation) {}
+
+// Starts a 100s sim of a boss running the timeline, and returns the sim with
+// the boss's AI. Extra targets follow the boss, and the first two players tank.
+func setupTimelineSim(t *testing.T, timeline *proto.BossTimeline, numPlayers int, extraTargets ...*proto.Target) (*core.Simulation, *TimelineAI) {
+	t.Helper()
+
+	party := &proto.Party{Buffs: &proto.PartyBuffs{}}
+	for range numPlayers {
+		party.Players = append(party.Players, &proto.Player{
+			Name:      "Player",
+			Class:     proto.Class_ClassShaman,
+			Buffs:     &proto.IndividualBuffs{},
+			Spec:      &proto.Player_ElementalShaman{},
+			Equipment: &proto.EquipmentSpec{},
+		})
+	}
+
+	sim := core.NewSim(&proto.RaidSimRequest{
+		SimOptions: &proto.SimOptions{RandomSeed: 101},
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{party},
+			Tanks: []*proto.UnitReference{
+				{Type: proto.UnitReference_Player, Index: 0},
+				{Type: proto.UnitReference_Player, Index: 1},
+			},
+		},
+		Encounter: &proto.Encounter{
+			Duration: 100,
+			Targets: append([]*proto.Target{{
+				Name:            "Boss",
+				Level:           93,
+				MobType:         proto.MobType_MobTypeHumanoid,
+				SecondTankIndex: 1,
+				Timeline:        timeline,
+			}}, extraTargets...),
+		},
+	}, simsignals.CreateSignals())
+	sim.Reset()
+	sim.PrePull()
+
+	ai, ok := sim.Encounter.AllTargets[0].AI.(*TimelineAI)
+	if !ok {
+		t.Fatalf("Expected the boss to run a timeline AI")
+	}
+	return sim, ai
+}
+
+// Runs the sim until the given time in seconds.
+func runTimelineSimUntil(sim *core.Simulation, seconds float64) {
+	for sim.CurrentTime < core.DurationFromSeconds(seconds) {
+		if sim.Step() {
+			return
+		}
+	}
+}
+
+func expectTimelinePhase(t *testing.T, sim *core.Simulation, ai *TimelineAI, expected int) {
+	t.Helper()
+	if ai.currentPhase != expected {
+		t.Fatalf("Expected phase %d at %s, got phase %d", expected+1, sim.CurrentTime, ai.currentPhase+1)
+	}
+}
+
+func TestTimelinePhaseStartTime(t *testing.T) {
+	sim, ai := setupTimelineSim(t, &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "One"},
+			{Name: "Two", StartTime: 30},
+		},
+	}, 1)
+
+	runTimelineSimUntil(sim, 20)
+	expectTimelinePhase(t, sim, ai, 0)
+	runTimelineSimUntil(sim, 40)
+	expectTimelinePhase(t, sim, ai, 1)
+}
+
+func TestTimelinePhaseStartHealth(t *testing.T) {
+	sim, ai := setupTimelineSim(t, &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "One"},
+			{Name: "Two", StartHealthPercent: 50},
+		},
+	}, 1)
+
+	// Without a health based fight, the remaining duration stands in for
+	// the boss's health.
+	runTimelineSimUntil(sim, 40)
+	expectTimelinePhase(t, sim, ai, 0)
+	runTimelineSimUntil(sim, 60)
+	expectTimelinePhase(t, sim, ai, 1)
+}
+
+func TestTimelinePeriodicEventCounts(t *testing.T) {
+	stacking := &proto.BossTimelineDamageDealtModifier{Multiplier: 1.1, MaxStacks: 100}
+	limited := DamageDealtEvent(0, 5, stacking)
+	limited.Count = 3
+
+	sim, ai := setupTimelineSim(t, &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{
+				Name: "One",
+				Events: []*proto.BossTimelineEvent{
+					limited,
+					DamageDealtEvent(0, 5, stacking),
+				},
+			},
+			{Name: "Two", StartTime: 22},
+		},
+	}, 1)
+
+	runTimelineSimUntil(sim, 60)
+	events := ai.phases[0].events
+	if stacks := events[0].auras[0].GetStacks(); stacks != 3 {
+		t.Fatalf("Expected an event with a count of 3 to run 3 times, got %d", stacks)
+	}
+	// Runs at 0, 5, 10, 15 and 20s, then stops when phase two starts.
+	if stacks := events[1].auras[0].GetStacks(); stacks != 5 {
+		t.Fatalf("Expected an unlimited event to run until its phase ends, got %d runs", stacks)
+	}
+}
+
+func TestTimelineTankSwap(t *testing.T) {
+	sim, ai := setupTimelineSim(t, &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{
+				Name:   "One",
+				Events: []*proto.BossTimelineEvent{TankSwapEvent(10, 20)},
+			},
+		},
+	}, 2)
+
+	boss := ai.Target
+	firstTank := sim.Raid.AllPlayerUnits[0]
+	secondTank := sim.Raid.AllPlayerUnits[1]
+
+	runTimelineSimUntil(sim, 5)
+	if boss.CurrentTarget != firstTank || boss.SecondaryTarget != secondTank {
+		t.Fatalf("Expected the first tank to start tanking the boss")
+	}
+	runTimelineSimUntil(sim, 15)
+	if boss.CurrentTarget != secondTank || boss.SecondaryTarget != firstTank {
+		t.Fatalf("Expected the second tank to take the boss after the first swap")
+	}
+	if secondTank.CurrentTarget != &boss.Unit {
+		t.Fatalf("Expected the second tank to target the boss after taking it")
+	}
+	runTimelineSimUntil(sim, 35)
+	if boss.CurrentTarget != firstTank || boss.SecondaryTarget != secondTank {
+		t.Fatalf("Expected the first tank to take the boss back after the second swap")
+	}
+}
+
+func TestTimelineSetTargetEnabled(t *testing.T) {
+	sim, _ := setupTimelineSim(t, &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{
+				Name: "One",
+				Events: []*proto.BossTimelineEvent{
+					SetTargetEnabledEvent(10, 1, true),
+					SetTargetEnabledEvent(20, 1, false),
+					// Out of range indices are ignored.
+					SetTargetEnabledEvent(25, 5, true),
+				},
+			},
+		},
+	}, 1, &proto.Target{
+		Name:            "Add",
+		Level:           92,
+		MobType:         proto.MobType_MobTypeHumanoid,
+		DisabledAtStart: true,
+	})
+
+	add := sim.Encounter.AllTargetUnits[1]
+	expectEnabled := func(seconds float64, expected bool) {
+		t.Helper()
+		runTimelineSimUntil(sim, seconds)
+		if add.IsEnabled() != expected {
+			t.Fatalf("Expected the add to be enabled: %t at %s", expected, sim.CurrentTime)
+		}
+	}
+
+	expectEnabled(5, false)
+	expectEnabled(15, true)
+	expectEnabled(30, false)
+}
+
+func TestTimelineResetRestartsFirstPhase(t *testing.T) {
+	sim, ai := setupTimelineSim(t, &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "One"},
+			{Name: "Two", StartTime: 10},
+		},
+	}, 1)
+
+	runTimelineSimUntil(sim, 20)
+	expectTimelinePhase(t, sim, ai, 1)
+
+	sim.Cleanup()
+	sim.Reset()
+	if sim.CurrentTime != time.Duration(0) {
+		t.Fatalf("Expected the sim to restart at 0")
+	}
+	expectTimelinePhase(t, sim, ai, 0)
+}
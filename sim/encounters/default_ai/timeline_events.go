@@ -0,0 +1,93 @@
+package default_ai
+
+import (
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+// Shorthands for building preset timelines in Go. Times are in seconds, and a
+// period of 0 makes the event happen only once.
+
+func CastEvent(offset float64, period float64, cast *proto.BossTimelineCast) *proto.BossTimelineEvent {
+	return &proto.BossTimelineEvent{
+		Offset: offset,
+		Period: period,
+		Action: &proto.BossTimelineEvent_Cast{Cast: cast},
+	}
+}
+
+func SetTargetEnabledEvent(offset float64, targetIndex int32, enabled bool) *proto.BossTimelineEvent {
+	return &proto.BossTimelineEvent{
+		Offset: offset,
+		Action: &proto.BossTimelineEvent_SetTargetEnabled{
+			SetTargetEnabled: &proto.BossTimelineSetTargetEnabled{
+				TargetIndex: targetIndex,
+				Enabled:     enabled,
+			},
+		},
+	}
+}
+
+func MovementEvent(offset float64, period float64, duration float64) *proto.BossTimelineEvent {
+	return &proto.BossTimelineEvent{
+		Offset: offset,
+		Period: period,
+		Action: &proto.BossTimelineEvent_Movement{
+			Movement: &proto.BossTimelineMovement{
+				Duration:     duration,
+				ExcludeTanks: true,
+			},
+		},
+	}
+}
+
+func DamageTakenEvent(offset float64, period float64, modifier *proto.BossTimelineDamageTakenModifier) *proto.BossTimelineEvent {
+	return &proto.BossTimelineEvent{
+		Offset: offset,
+		Period: period,
+		Action: &proto.BossTimelineEvent_DamageTakenModifier{DamageTakenModifier: modifier},
+	}
+}
+
+func DamageDealtEvent(offset float64, period float64, modifier *proto.BossTimelineDamageDealtModifier) *proto.BossTimelineEvent {
+	return &proto.BossTimelineEvent{
+		Offset: offset,
+		Period: period,
+		Action: &proto.BossTimelineEvent_DamageDealtModifier{DamageDealtModifier: modifier},
+	}
+}
+
+func TankSwapEvent(offset float64, period float64) *proto.BossTimelineEvent {
+	return &proto.BossTimelineEvent{
+		Offset: offset,
+		Period: period,
+		Action: &proto.BossTimelineEvent_TankSwap{TankSwap: &proto.BossTimelineTankSwap{}},
+	}
+}
+
+func RepositionEvent(offset float64, x float64, y float64, meleeFollow bool) *proto.BossTimelineEvent {
+	return &proto.BossTimelineEvent{
+		Offset: offset,
+		Action: &proto.BossTimelineEvent_Reposition{
+			Reposition: &proto.BossTimelineReposition{
+				Position:    &proto.Position{X: x, Y: y},
+				MeleeFollow: meleeFollow,
+			},
+		},
+	}
+}
+
+// Returns the value of a number input, or the default for configs saved
+// before the input existed.
+func NumberInput(config *proto.Target, idx int, defaultValue float64) float64 {
+	if idx < len(config.TargetInputs) {
+		return config.TargetInputs[idx].NumberValue
+	}
+	return defaultValue
+}
+
+func BoolInput(config *proto.Target, idx int, defaultValue bool) bool {
+	if idx < len(config.TargetInputs) {
+		return config.TargetInputs[idx].BoolValue
+	}
+	return defaultValue
+}
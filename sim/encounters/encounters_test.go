@@ -0,0 +1,28 @@
+package encounters
+
+import (
+	"testing"
+
+	"github.com/wowsims/mop/sim/common"
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/warrior/protection"
+)
+
+func init() {
+	protection.RegisterProtectionWarrior()
+	common.RegisterAllEffects()
+}
+
+func TestPresetEncounters(t *testing.T) {
+	for _, raid := range []string{
+		"Mogu'shan Vaults",
+		"Heart of Fear",
+		"Terrace of Endless Spring",
+		"Throne of Thunder",
+		"Siege of Orgrimmar",
+	} {
+		t.Run(raid, func(t *testing.T) {
+			core.PresetEncounterTest(t, raid, "../../ui/warrior/protection/builds", "garajal_default")
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package hof
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// Garalon is followed by his four legs in the encounter targets.
+const garalonNumLegs = 4
+
+func addGaralon(raidPrefix string) {
+	createGaralonHeroicPreset(raidPrefix, 25, 990_000_000, 420_000, 24_000_000)
+}
+
+func createGaralonHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, legHealth float64) {
+	bossName := fmt.Sprintf("Garalon %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        62164,
+			Name:      bossName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeBeast,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  garalonTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(garalonTimeline, nil),
+	})
+
+	targetPathNames := []string{raidPrefix + "/" + bossName}
+
+	for legIdx := int32(1); legIdx <= garalonNumLegs; legIdx++ {
+		legName := fmt.Sprintf("Garalon's Leg %d H - %d", raidSize, legIdx)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:      63053*100 + legIdx, // hack to guarantee distinct IDs for each leg
+				Name:    legName,
+				Level:   93,
+				MobType: proto.MobType_MobTypeBeast,
+
+				Stats: stats.Stats{
+					stats.Health: legHealth,
+					stats.Armor:  24835,
+				}.ToProtoArray(),
+
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: true,
+			},
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+legName)
+	}
+
+	core.AddPresetEncounter(bossName, targetPathNames)
+}
+
+func garalonTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Leg kill interval",
+			Tooltip:     "Time (in seconds) between the melee switching to a leg. Set to 0 to ignore the legs.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 30,
+		},
+		{
+			Label:       "Leg kill time",
+			Tooltip:     "Time (in seconds) the melee take to kill a leg",
+			InputType:   proto.InputType_Number,
+			NumberValue: 10,
+		},
+	}
+}
+
+// The melee rotate through the legs, and each Broken Leg makes Garalon take
+// more damage until it regrows. Pheromones pulse the raid throughout, with
+// Crush hitting everyone and forcing the raid to spread.
+func garalonTimeline(config *proto.Target) *proto.BossTimeline {
+	legInterval := default_ai.NumberInput(config, 0, 30)
+	legKillTime := default_ai.NumberInput(config, 1, 10)
+
+	events := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(8, 9, &proto.BossTimelineCast{
+			SpellId:      122735, // Furious Swipe
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    330_000,
+			DamageSpread: 0.2,
+			CastTime:     2.5,
+			Melee:        true,
+		}),
+		default_ai.CastEvent(2, 2, &proto.BossTimelineCast{
+			SpellId:      123092, // Pheromones
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    14_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.CastEvent(37, 37, &proto.BossTimelineCast{
+			SpellId:      122082, // Crush
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    120_000,
+			DamageSpread: 0.1,
+			CastTime:     3,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.MovementEvent(35, 37, 2),
+	}
+
+	if legInterval > 0 {
+		for legIdx := int32(1); legIdx <= garalonNumLegs; legIdx++ {
+			firstKill := legInterval * float64(legIdx)
+			rotation := legInterval * garalonNumLegs
+
+			spawn := default_ai.SetTargetEnabledEvent(firstKill, legIdx, true)
+			spawn.Period = rotation
+			kill := default_ai.SetTargetEnabledEvent(firstKill+legKillTime, legIdx, false)
+			kill.Period = rotation
+			events = append(events, spawn, kill)
+		}
+
+		events = append(events, default_ai.DamageTakenEvent(legInterval+legKillTime, legInterval, &proto.BossTimelineDamageTakenModifier{
+			Multiplier: 1.1,
+			Duration:   legInterval,
+		}))
+	}
+
+	enrage := default_ai.DamageDealtEvent(0, 0, &proto.BossTimelineDamageDealtModifier{
+		Multiplier:      1.5,
+		SpeedMultiplier: 1.25,
+	})
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Garalon", Events: events},
+			{
+				Name:               "Enrage",
+				StartHealthPercent: 33,
+				Events:             append(slices.Clone(events), enrage),
+			},
+		},
+	}
+}
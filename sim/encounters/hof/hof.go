@@ -0,0 +1,17 @@
+// Package hof registers the 25H Heart of Fear presets.
+//
+// Every boss runs on a boss timeline which enables and disables the fight's
+// adds as extra targets: Zor'lok leaves an Echo behind on each platform,
+// Garalon's legs are broken and regrow, Mel'jarak's add groups die one by one,
+// the Amber Monstrosity spawns at 70% and Shek'zeer sends in her Royal Guards
+// when she retreats. Health pools and hit sizes are rough 25H figures that
+// haven't been checked against logs.
+package hof
+
+func Register() {
+	addZorlok("Heart of Fear")
+	addGaralon("Heart of Fear")
+	addMeljarak("Heart of Fear")
+	addUnsok("Heart of Fear")
+	addShekzeer("Heart of Fear")
+}
@@ -0,0 +1,163 @@
+package hof
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type meljarakAddGroup struct {
+	name   string
+	npcID  int32
+	health float64
+
+	// Index of the tank holding the group, or -1 if it is crowd controlled.
+	tankIndex int32
+}
+
+// In the default kill order.
+var meljarakAddGroups = []meljarakAddGroup{
+	{name: "Zar'thik Battle-Mender", npcID: 62408, health: 60_000_000, tankIndex: 1},
+	{name: "Sra'thik Amber-Trapper", npcID: 62405, health: 60_000_000, tankIndex: -1},
+	{name: "Kor'thik Elite Blademaster", npcID: 62402, health: 60_000_000, tankIndex: 1},
+}
+
+// The add groups follow Mel'jarak in the encounter targets.
+const meljarakFirstAddIdx = 1
+
+func addMeljarak(raidPrefix string) {
+	createMeljarakHeroicPreset(raidPrefix, 25, 560_000_000, 340_000)
+}
+
+func createMeljarakHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64) {
+	bossName := fmt.Sprintf("Wind Lord Mel'jarak %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        62397,
+			Name:      bossName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  meljarakTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(meljarakTimeline, nil),
+	})
+
+	targetPathNames := []string{raidPrefix + "/" + bossName}
+
+	for _, group := range meljarakAddGroups {
+		name := fmt.Sprintf("%s %d H", group.name, raidSize)
+
+		config := &proto.Target{
+			Id:      group.npcID,
+			Name:    name,
+			Level:   92,
+			MobType: proto.MobType_MobTypeHumanoid,
+
+			Stats: stats.Stats{
+				stats.Health: group.health,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs: []*proto.TargetInput{},
+		}
+
+		if group.tankIndex >= 0 {
+			config.TankIndex = group.tankIndex
+			config.SpellSchool = proto.SpellSchool_SpellSchoolPhysical
+			config.SwingSpeed = 2.0
+			config.MinBaseDamage = bossMinBaseDamage / 3
+			config.DamageSpread = 0.4
+		}
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+			Config:     config,
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(bossName, targetPathNames)
+}
+
+func meljarakTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Add group kill time",
+			Tooltip:     "Time (in seconds) the raid takes to kill each add group, in the order Battle-Menders, Amber-Trappers, Blademasters. Set to 0 to leave the adds up.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 45,
+		},
+	}
+}
+
+// All add groups are up at the pull. Each time a group dies Mel'jarak gains a
+// stack of Recklessness, and he keeps channeling Rain of Blades throughout.
+func meljarakTimeline(config *proto.Target) *proto.BossTimeline {
+	addKillTime := default_ai.NumberInput(config, 0, 45)
+
+	events := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(60, 60, &proto.BossTimelineCast{
+			SpellId:      122406, // Rain of Blades
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    50_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+			NumTicks:     12,
+			TickInterval: 0.5,
+		}),
+		default_ai.CastEvent(20, 45, &proto.BossTimelineCast{
+			SpellId:      121896, // Whirling Blade
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    180_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		}),
+		default_ai.CastEvent(12, 36, &proto.BossTimelineCast{
+			SpellId:      122064, // Corrosive Resin
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    30_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+			NumTicks:     5,
+			TickInterval: 1,
+		}),
+		// Players move out of the Corrosive Resin pools.
+		default_ai.MovementEvent(14, 36, 2),
+	}
+
+	if addKillTime > 0 {
+		for idx := range meljarakAddGroups {
+			killTime := addKillTime * float64(idx+1)
+			events = append(events,
+				default_ai.SetTargetEnabledEvent(killTime, int32(meljarakFirstAddIdx+idx), false),
+				default_ai.DamageDealtEvent(killTime, 0, &proto.BossTimelineDamageDealtModifier{
+					Multiplier: 1.1,
+				}),
+			)
+		}
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{{Name: "Mel'jarak", Events: events}},
+	}
+}
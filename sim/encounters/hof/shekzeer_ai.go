@@ -0,0 +1,192 @@
+package hof
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type shekzeerAdd struct {
+	name  string
+	npcID int32
+}
+
+// The Royal Guards follow Shek'zeer in the encounter targets.
+var shekzeerAdds = []shekzeerAdd{
+	{name: "Kor'thik Reaver", npcID: 63591},
+	{name: "Set'thik Windblade", npcID: 63589},
+}
+
+const shekzeerFirstAddIdx = 1
+
+// Shek'zeer retreats 150 seconds into the first phase.
+const shekzeerRetreatTime = 150
+
+func addShekzeer(raidPrefix string) {
+	createShekzeerHeroicPreset(raidPrefix, 25, 740_000_000, 360_000, 45_000_000)
+}
+
+func createShekzeerHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, addHealth float64) {
+	bossName := fmt.Sprintf("Grand Empress Shek'zeer %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              62837,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeHumanoid,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  shekzeerTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(shekzeerTimeline, nil),
+	})
+
+	targetPathNames := []string{raidPrefix + "/" + bossName}
+
+	for idx, add := range shekzeerAdds {
+		name := fmt.Sprintf("%s %d H", add.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        add.npcID,
+				Name:      name,
+				Level:     92,
+				MobType:   proto.MobType_MobTypeHumanoid,
+				TankIndex: int32(idx),
+
+				Stats: stats.Stats{
+					stats.Health: addHealth,
+					stats.Armor:  24835,
+				}.ToProtoArray(),
+
+				SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:      2.0,
+				MinBaseDamage:   bossMinBaseDamage / 3,
+				DamageSpread:    0.4,
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: true,
+			},
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(bossName, targetPathNames)
+}
+
+func shekzeerTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Retreat duration",
+			Tooltip:     "Time (in seconds) the raid takes to kill the Royal Guards while Shek'zeer is away",
+			InputType:   proto.InputType_Number,
+			NumberValue: 60,
+		},
+		{
+			Label:       "Tank swap stacks",
+			Tooltip:     "Stacks of Eyes of the Empress at which the tanks swap",
+			InputType:   proto.InputType_Number,
+			NumberValue: 3,
+		},
+	}
+}
+
+// Shek'zeer retreats after 150 seconds and sends in her Royal Guards, comes
+// back once they are dead, and enters the Sha phase at 30%.
+func shekzeerTimeline(config *proto.Target) *proto.BossTimeline {
+	retreatDuration := default_ai.NumberInput(config, 0, 60)
+	tankSwapStacks := default_ai.NumberInput(config, 1, 3)
+
+	empressEvents := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(15, 20, &proto.BossTimelineCast{
+			SpellId:      123735, // Dread Screech
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    70_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		}),
+		default_ai.CastEvent(25, 25, &proto.BossTimelineCast{
+			SpellId:      123788, // Cry of Terror
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    30_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+			NumTicks:     5,
+			TickInterval: 2,
+		}),
+		// Players leave the Dissonance Fields.
+		default_ai.MovementEvent(30, 65, 3),
+	}
+
+	// Eyes of the Empress stacks on every melee swing at a 2 second swing
+	// timer.
+	if tankSwapStacks > 0 {
+		empressEvents = append(empressEvents, default_ai.TankSwapEvent(2*tankSwapStacks, 2*tankSwapStacks))
+	}
+
+	royalGuardEvents := []*proto.BossTimelineEvent{}
+	for idx := range shekzeerAdds {
+		royalGuardEvents = append(royalGuardEvents, default_ai.SetTargetEnabledEvent(0, int32(shekzeerFirstAddIdx+idx), true))
+	}
+	royalGuardEvents = append(royalGuardEvents, default_ai.SetTargetEnabledEvent(0.1, 0, false))
+
+	returnEvents := []*proto.BossTimelineEvent{default_ai.SetTargetEnabledEvent(0, 0, true)}
+	for idx := range shekzeerAdds {
+		returnEvents = append(returnEvents, default_ai.SetTargetEnabledEvent(0.1, int32(shekzeerFirstAddIdx+idx), false))
+	}
+	returnEvents = append(returnEvents, empressEvents...)
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Empress", Events: empressEvents},
+			{Name: "Royal Guards", StartTime: shekzeerRetreatTime, Events: royalGuardEvents},
+			{Name: "Empress Return", StartTime: shekzeerRetreatTime + retreatDuration, Events: returnEvents},
+			{
+				Name:               "Sha of Fear",
+				StartHealthPercent: 30,
+				Events: []*proto.BossTimelineEvent{
+					default_ai.CastEvent(5, 10, &proto.BossTimelineCast{
+						SpellId:      124845, // Calamity
+						School:       proto.SpellSchool_SpellSchoolShadow,
+						MinDamage:    110_000,
+						DamageSpread: 0.1,
+						CastTime:     1.5,
+						Target:       proto.BossTimelineCast_Raid,
+					}),
+					default_ai.CastEvent(2, 2, &proto.BossTimelineCast{
+						SpellId:      124862, // Visions of Demise
+						School:       proto.SpellSchool_SpellSchoolShadow,
+						MinDamage:    40_000,
+						DamageSpread: 0.1,
+						Target:       proto.BossTimelineCast_RandomPlayer,
+					}),
+					// Amassing Darkness ramps up the longer the phase lasts.
+					default_ai.DamageDealtEvent(10, 10, &proto.BossTimelineDamageDealtModifier{
+						Multiplier: 1.05,
+						MaxStacks:  30,
+					}),
+				},
+			},
+		},
+	}
+}
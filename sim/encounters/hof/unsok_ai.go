@@ -0,0 +1,159 @@
+package hof
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// The Amber Monstrosity follows Un'sok in the encounter targets.
+const unsokMonstrosityIdx = 1
+
+func addUnsok(raidPrefix string) {
+	createUnsokHeroicPreset(raidPrefix, 25, 650_000_000, 330_000, 160_000_000)
+}
+
+func createUnsokHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, monstrosityHealth float64) {
+	bossName := fmt.Sprintf("Amber-Shaper Un'sok %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        62511,
+			Name:      bossName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  unsokTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(unsokTimeline, nil),
+	})
+
+	monstrosityName := fmt.Sprintf("Amber Monstrosity %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        62711,
+			Name:      monstrosityName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeElemental,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health: monstrosityHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:      2.0,
+			MinBaseDamage:   bossMinBaseDamage * 1.5,
+			DamageSpread:    0.4,
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + monstrosityName,
+	})
+}
+
+func unsokTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Monstrosity lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill the Amber Monstrosity",
+			InputType:   proto.InputType_Number,
+			NumberValue: 75,
+		},
+	}
+}
+
+// The Amber Monstrosity spawns at 70% and the raid ignores Un'sok until it
+// dies. From 30% Un'sok fixates on the raid with Amber Globules and
+// Concentrated Mutation.
+func unsokTimeline(config *proto.Target) *proto.BossTimeline {
+	monstrosityLifetime := default_ai.NumberInput(config, 0, 75)
+
+	amberScalpel := default_ai.CastEvent(10, 40, &proto.BossTimelineCast{
+		SpellId:      121994, // Amber Scalpel
+		School:       proto.SpellSchool_SpellSchoolNature,
+		MinDamage:    60_000,
+		DamageSpread: 0.1,
+		Target:       proto.BossTimelineCast_RandomPlayer,
+		NumTicks:     10,
+		TickInterval: 1,
+	})
+	parasiticGrowth := default_ai.CastEvent(25, 50, &proto.BossTimelineCast{
+		SpellId:      121949, // Parasitic Growth
+		School:       proto.SpellSchool_SpellSchoolNature,
+		MinDamage:    40_000,
+		DamageSpread: 0.1,
+		Target:       proto.BossTimelineCast_RandomPlayer,
+		NumTicks:     15,
+		TickInterval: 2,
+	})
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Un'sok", Events: []*proto.BossTimelineEvent{amberScalpel, parasiticGrowth}},
+			{
+				Name:               "Amber Monstrosity",
+				StartHealthPercent: 70,
+				Events: []*proto.BossTimelineEvent{
+					amberScalpel, parasiticGrowth,
+					default_ai.SetTargetEnabledEvent(0, unsokMonstrosityIdx, true),
+					default_ai.DamageTakenEvent(0, 0, &proto.BossTimelineDamageTakenModifier{
+						Multiplier: 0.01,
+						Duration:   monstrosityLifetime,
+					}),
+					default_ai.SetTargetEnabledEvent(monstrosityLifetime, unsokMonstrosityIdx, false),
+					default_ai.CastEvent(15, 20, &proto.BossTimelineCast{
+						SpellId:      122395, // Massive Stomp
+						School:       proto.SpellSchool_SpellSchoolPhysical,
+						MinDamage:    90_000,
+						DamageSpread: 0.1,
+						CastTime:     2,
+						Target:       proto.BossTimelineCast_Raid,
+					}),
+				},
+			},
+			{
+				Name:               "Concentrated Mutation",
+				StartHealthPercent: 30,
+				Events: []*proto.BossTimelineEvent{
+					amberScalpel, parasiticGrowth,
+					default_ai.CastEvent(5, 5, &proto.BossTimelineCast{
+						SpellId:      123059, // Amber Globule
+						School:       proto.SpellSchool_SpellSchoolNature,
+						MinDamage:    70_000,
+						DamageSpread: 0.1,
+						Target:       proto.BossTimelineCast_RandomPlayer,
+					}),
+					default_ai.DamageDealtEvent(0, 0, &proto.BossTimelineDamageDealtModifier{
+						Multiplier: 1.3,
+					}),
+				},
+			},
+		},
+	}
+}
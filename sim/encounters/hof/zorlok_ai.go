@@ -0,0 +1,158 @@
+package hof
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+func addZorlok(raidPrefix string) {
+	createZorlokHeroicPreset(raidPrefix, 25, 580_000_000, 310_000, 40_000_000)
+}
+
+func createZorlokHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, echoHealth float64) {
+	bossName := fmt.Sprintf("Imperial Vizier Zor'lok %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        62980,
+			Name:      bossName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  zorlokTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(zorlokTimeline, nil),
+	})
+
+	targetPathNames := []string{raidPrefix + "/" + bossName}
+
+	// On Heroic, Zor'lok leaves an Echo behind on each platform he leaves.
+	for _, echo := range []struct {
+		name  string
+		npcID int32
+	}{
+		{name: "Echo of Attenuation", npcID: 65173},
+		{name: "Echo of Force and Verve", npcID: 65174},
+	} {
+		echoName := fmt.Sprintf("%s %d H", echo.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        echo.npcID,
+				Name:      echoName,
+				Level:     92,
+				MobType:   proto.MobType_MobTypeHumanoid,
+				TankIndex: 1,
+
+				Stats: stats.Stats{
+					stats.Health: echoHealth,
+					stats.Armor:  24835,
+				}.ToProtoArray(),
+
+				SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:      2.0,
+				MinBaseDamage:   bossMinBaseDamage / 2,
+				DamageSpread:    0.4,
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: true,
+			},
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+echoName)
+	}
+
+	core.AddPresetEncounter(bossName, targetPathNames)
+}
+
+func zorlokTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Platform movement time",
+			Tooltip:     "Time (in seconds) players spend running to the next platform",
+			InputType:   proto.InputType_Number,
+			NumberValue: 6,
+		},
+		{
+			Label:       "Echo lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill each Echo",
+			InputType:   proto.InputType_Number,
+			NumberValue: 30,
+		},
+	}
+}
+
+// Zor'lok moves to a new platform at 80% and 60%, leaving an Echo behind, and
+// stays on the central platform from 40%.
+func zorlokTimeline(config *proto.Target) *proto.BossTimeline {
+	movementTime := default_ai.NumberInput(config, 0, 6)
+	echoLifetime := default_ai.NumberInput(config, 1, 30)
+
+	forceAndVerve := default_ai.CastEvent(25, 50, &proto.BossTimelineCast{
+		SpellId:      122713, // Force and Verve
+		School:       proto.SpellSchool_SpellSchoolPhysical,
+		MinDamage:    40_000,
+		DamageSpread: 0.1,
+		CastTime:     2,
+		Target:       proto.BossTimelineCast_Raid,
+		NumTicks:     10,
+		TickInterval: 1,
+	})
+	exhale := default_ai.CastEvent(10, 20, &proto.BossTimelineCast{
+		SpellId:      122761, // Exhale
+		School:       proto.SpellSchool_SpellSchoolPhysical,
+		MinDamage:    160_000,
+		DamageSpread: 0.1,
+		Target:       proto.BossTimelineCast_RandomPlayer,
+	})
+	// Players dodge the Attenuation rings.
+	attenuation := default_ai.MovementEvent(40, 50, 4)
+
+	platformPhase := func(name string, startHealthPercent float64, echoIdx int32) *proto.BossTimelinePhase {
+		events := []*proto.BossTimelineEvent{
+			default_ai.MovementEvent(0, 0, movementTime),
+			forceAndVerve, exhale, attenuation,
+		}
+
+		if echoIdx > 0 {
+			events = append(events,
+				default_ai.SetTargetEnabledEvent(0, echoIdx, true),
+				default_ai.SetTargetEnabledEvent(echoLifetime, echoIdx, false),
+			)
+		}
+
+		return &proto.BossTimelinePhase{
+			Name:               name,
+			StartHealthPercent: startHealthPercent,
+			Events:             events,
+		}
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "First Platform", Events: []*proto.BossTimelineEvent{forceAndVerve, exhale, attenuation}},
+			platformPhase("Second Platform", 80, 1),
+			platformPhase("Third Platform", 60, 2),
+			platformPhase("Central Platform", 40, 0),
+		},
+	}
+}
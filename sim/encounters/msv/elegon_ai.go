@@ -0,0 +1,235 @@
+package msv
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+const (
+	elegonBossID      int32 = 60410
+	elegonProtectorID int32 = 60793
+	elegonFocusID     int32 = 60776
+)
+
+// Indices in the encounter targets.
+const (
+	elegonBossIdx      int32 = 0
+	elegonProtectorIdx int32 = 1
+	elegonFocusIdx     int32 = 2
+)
+
+func addElegon(raidPrefix string) {
+	createElegonHeroicPreset(raidPrefix, 25, 840_000_000, 250_000, 62_000_000, 9_500_000)
+}
+
+func createElegonHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, protectorHealth float64, focusHealth float64) {
+	bossName := fmt.Sprintf("Elegon %d H", raidSize)
+	protectorName := fmt.Sprintf("Celestial Protector %d H", raidSize)
+	focusName := fmt.Sprintf("Empyreal Focus %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        elegonBossID,
+			Name:      bossName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeMechanical,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolArcane,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  elegonTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(elegonTimeline, nil),
+	})
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        elegonProtectorID,
+			Name:      protectorName,
+			Level:     92,
+			MobType:   proto.MobType_MobTypeMechanical,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health: protectorHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			SpellSchool:     proto.SpellSchool_SpellSchoolArcane,
+			SwingSpeed:      2.0,
+			MinBaseDamage:   bossMinBaseDamage / 2,
+			DamageSpread:    0.4,
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	// The six foci are modeled as one target.
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:      elegonFocusID,
+			Name:    focusName,
+			Level:   92,
+			MobType: proto.MobType_MobTypeMechanical,
+
+			Stats: stats.Stats{
+				stats.Health: 6 * focusHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + protectorName,
+		raidPrefix + "/" + focusName,
+	})
+}
+
+func elegonTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Celestial Protector interval",
+			Tooltip:     "Time (in seconds) between Celestial Protector spawns",
+			InputType:   proto.InputType_Number,
+			NumberValue: 35,
+		},
+		{
+			Label:       "Celestial Protector lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill each Celestial Protector",
+			InputType:   proto.InputType_Number,
+			NumberValue: 20,
+		},
+		{
+			Label:       "Energy Conduit duration",
+			Tooltip:     "Time (in seconds) the raid takes to kill the Empyreal Foci during each Energy Conduit phase",
+			InputType:   proto.InputType_Number,
+			NumberValue: 25,
+		},
+		{
+			Label:       "Draw Power stacks",
+			Tooltip:     "Number of Energy Charges reaching Elegon during each Energy Conduit phase, each increasing his damage by 10%",
+			InputType:   proto.InputType_Number,
+			NumberValue: 3,
+		},
+	}
+}
+
+// Protectors spawn and die while Elegon is tanked. At 85% and 50% Elegon leaves
+// for an Energy Conduit phase where the raid kills the Empyreal Foci, and gains
+// a stack of Draw Power for every charge they let through. After the second
+// conduit the floor is gone and Unstable Energy pulses the raid.
+func elegonTimeline(config *proto.Target) *proto.BossTimeline {
+	protectorInterval := default_ai.NumberInput(config, 0, 35)
+	protectorLifetime := default_ai.NumberInput(config, 1, 20)
+	conduitDuration := default_ai.NumberInput(config, 2, 25)
+	drawPowerStacks := int32(default_ai.NumberInput(config, 3, 3))
+
+	celestialBreath := default_ai.CastEvent(8, 18, &proto.BossTimelineCast{
+		SpellId:      117960, // Celestial Breath
+		School:       proto.SpellSchool_SpellSchoolArcane,
+		MinDamage:    240_000,
+		DamageSpread: 0.1,
+		CastTime:     1.5,
+		NumTicks:     3,
+		TickInterval: 1,
+	})
+
+	protectorEvents := func(offset float64) []*proto.BossTimelineEvent {
+		if protectorInterval <= 0 {
+			return nil
+		}
+
+		spawn := default_ai.SetTargetEnabledEvent(offset, elegonProtectorIdx, true)
+		spawn.Period = protectorInterval
+		despawn := default_ai.SetTargetEnabledEvent(offset+protectorLifetime, elegonProtectorIdx, false)
+		despawn.Period = protectorInterval
+
+		// Killing a protector near Elegon pulses the raid.
+		stabilityFlux := default_ai.CastEvent(offset+protectorLifetime, protectorInterval, &proto.BossTimelineCast{
+			SpellId:      117911, // Stability Flux
+			School:       proto.SpellSchool_SpellSchoolArcane,
+			MinDamage:    120_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		})
+
+		return []*proto.BossTimelineEvent{spawn, despawn, stabilityFlux}
+	}
+
+	conduitEvents := func() []*proto.BossTimelineEvent {
+		events := []*proto.BossTimelineEvent{
+			default_ai.SetTargetEnabledEvent(0, elegonProtectorIdx, false),
+			default_ai.SetTargetEnabledEvent(0, elegonFocusIdx, true),
+			default_ai.SetTargetEnabledEvent(0.1, elegonBossIdx, false),
+			default_ai.SetTargetEnabledEvent(conduitDuration, elegonBossIdx, true),
+			default_ai.SetTargetEnabledEvent(conduitDuration+0.1, elegonFocusIdx, false),
+		}
+
+		if drawPowerStacks > 0 {
+			drawPower := default_ai.DamageDealtEvent(conduitDuration, 0.1, &proto.BossTimelineDamageDealtModifier{
+				Multiplier: 1.1,
+				MaxStacks:  100,
+			})
+			drawPower.Count = drawPowerStacks
+			events = append(events, drawPower)
+		}
+
+		return events
+	}
+
+	// Elegon keeps the first phase abilities once back from a conduit.
+	resumedEvents := func(offset float64) []*proto.BossTimelineEvent {
+		breath := default_ai.CastEvent(offset+celestialBreath.Offset, celestialBreath.Period, celestialBreath.GetCast())
+		return append(protectorEvents(offset+10), breath)
+	}
+
+	unstableEnergy := default_ai.CastEvent(conduitDuration+1, 1, &proto.BossTimelineCast{
+		SpellId:      116994, // Unstable Energy
+		School:       proto.SpellSchool_SpellSchoolArcane,
+		MinDamage:    30_000,
+		DamageSpread: 0.1,
+		Target:       proto.BossTimelineCast_Raid,
+	})
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{
+				Name:   "Celestial Protectors",
+				Events: append(protectorEvents(10), celestialBreath),
+			},
+			{
+				Name:               "Energy Conduit",
+				StartHealthPercent: 85,
+				Events:             append(conduitEvents(), resumedEvents(conduitDuration)...),
+			},
+			{
+				Name:               "Energy Conduit and Unstable Floor",
+				StartHealthPercent: 50,
+				Events:             append(append(conduitEvents(), resumedEvents(conduitDuration)...), unstableEnergy),
+			},
+		},
+	}
+}
@@ -0,0 +1,169 @@
+package msv
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+const fengBossID int32 = 60009
+
+func addFeng(raidPrefix string) {
+	createFengHeroicPreset(raidPrefix, 25, 770_000_000, 330_000)
+}
+
+func createFengHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64) {
+	bossName := fmt.Sprintf("Feng the Accursed %d H", raidSize)
+
+	preset := &core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              fengBossID,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeHumanoid,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  fengTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(fengTimeline, nil),
+	}
+
+	core.AddPresetTarget(preset)
+	core.AddPresetEncounter(bossName, []string{preset.Path()})
+}
+
+func fengTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:     "Nullification Barrier",
+			Tooltip:   "Whether the raid uses Nullification Barrier to soak every other Epicenter, Draw Flame and Arcane Velocity",
+			InputType: proto.InputType_Bool,
+			BoolValue: true,
+		},
+		{
+			Label:       "Tank swap interval",
+			Tooltip:     "Time (in seconds) between tank swaps on Lightning Fists, Flaming Spear and Arcane Shock stacks",
+			InputType:   proto.InputType_Number,
+			NumberValue: 20,
+		},
+	}
+}
+
+// Each phase changes the school of the tank hits and raid channel, with the
+// raid optionally halving every other channel with Nullification Barrier.
+func fengTimeline(config *proto.Target) *proto.BossTimeline {
+	useNullification := default_ai.BoolInput(config, 0, true)
+	tankSwapInterval := default_ai.NumberInput(config, 1, 20)
+
+	phase := func(name string, startHealthPercent float64, school proto.SpellSchool, tankHitID int32, tankHitDamage float64, channel *proto.BossTimelineCast, channelPeriod float64, extraEvents ...*proto.BossTimelineEvent) *proto.BossTimelinePhase {
+		events := []*proto.BossTimelineEvent{
+			default_ai.CastEvent(6, 10, &proto.BossTimelineCast{
+				SpellId:      tankHitID,
+				School:       school,
+				MinDamage:    tankHitDamage,
+				DamageSpread: 0.2,
+				Melee:        true,
+			}),
+			default_ai.CastEvent(18, channelPeriod, channel),
+		}
+
+		if useNullification {
+			// Barrier lasts 6 seconds and has a 45 second cooldown.
+			events = append(events, default_ai.DamageTakenEvent(18, 2*channelPeriod, &proto.BossTimelineDamageTakenModifier{
+				Multiplier: 0.5,
+				Duration:   6,
+				Players:    true,
+			}))
+		}
+
+		if tankSwapInterval > 0 {
+			events = append(events, default_ai.TankSwapEvent(tankSwapInterval, tankSwapInterval))
+		}
+
+		return &proto.BossTimelinePhase{
+			Name:               name,
+			StartHealthPercent: startHealthPercent,
+			Events:             append(events, extraEvents...),
+		}
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			phase("Spirit of the Fist", 0, proto.SpellSchool_SpellSchoolNature, 131788, 260_000,
+				&proto.BossTimelineCast{
+					SpellId:      116018, // Epicenter
+					School:       proto.SpellSchool_SpellSchoolNature,
+					MinDamage:    55_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_Raid,
+					NumTicks:     10,
+					TickInterval: 1,
+				}, 30,
+				// Players run out of the Epicenter.
+				default_ai.MovementEvent(18, 30, 3),
+			),
+			phase("Spirit of the Spear", 66, proto.SpellSchool_SpellSchoolFire, 116942, 280_000,
+				&proto.BossTimelineCast{
+					SpellId:      116711, // Draw Flame
+					School:       proto.SpellSchool_SpellSchoolFire,
+					MinDamage:    48_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_Raid,
+					NumTicks:     6,
+					TickInterval: 1,
+				}, 35,
+				default_ai.CastEvent(10, 14, &proto.BossTimelineCast{
+					SpellId:      116784, // Wildfire Spark
+					School:       proto.SpellSchool_SpellSchoolFire,
+					MinDamage:    150_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+					NumTicks:     5,
+					TickInterval: 1,
+				}),
+			),
+			phase("Spirit of the Staff", 33, proto.SpellSchool_SpellSchoolArcane, 131790, 300_000,
+				&proto.BossTimelineCast{
+					SpellId:      116364, // Arcane Velocity
+					School:       proto.SpellSchool_SpellSchoolArcane,
+					MinDamage:    65_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_Raid,
+					NumTicks:     8,
+					TickInterval: 1,
+				}, 28,
+				default_ai.CastEvent(8, 15, &proto.BossTimelineCast{
+					SpellId:      116417, // Arcane Resonance
+					School:       proto.SpellSchool_SpellSchoolArcane,
+					MinDamage:    60_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+					NumTicks:     6,
+					TickInterval: 1,
+				}),
+				// Players stack on the boss before Arcane Velocity.
+				default_ai.MovementEvent(16, 28, 2),
+			),
+		},
+	}
+}
@@ -1,5 +1,18 @@
+// Package msv registers the 25H Mogu'shan Vaults presets.
+//
+// Gara'jal keeps its hand-written AI, which takes its spell damage from the
+// game's spell data. The other bosses run on boss timelines: The Stone Guard
+// and Feng stay on one target, only one of the Spirit Kings can be attacked at
+// a time, and Elegon's Celestial Protectors and the Will of the Emperor add
+// waves come and go as extra targets. Their health and hits are ballpark 25H
+// numbers which still need fitting against logs.
 package msv
 
 func Register() {
+	addStoneGuard("Mogu'shan Vaults")
+	addFeng("Mogu'shan Vaults")
 	addGarajal("Mogu'shan Vaults")
+	addSpiritKings("Mogu'shan Vaults")
+	addElegon("Mogu'shan Vaults")
+	addWillOfTheEmperor("Mogu'shan Vaults")
 }
@@ -0,0 +1,195 @@
+package msv
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type spiritKing struct {
+	name   string
+	npcID  int32
+	events func() []*proto.BossTimelineEvent
+}
+
+// In Heroic order of appearance.
+var spiritKings = []spiritKing{
+	{
+		name:  "Qiang the Merciless",
+		npcID: 60709,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(5, 5, &proto.BossTimelineCast{
+					SpellId:      117921, // Massive Attack
+					School:       proto.SpellSchool_SpellSchoolPhysical,
+					MinDamage:    320_000,
+					DamageSpread: 0.2,
+					Melee:        true,
+				}),
+				// Players dodge Annihilate.
+				default_ai.MovementEvent(20, 40, 2),
+			}
+		},
+	},
+	{
+		name:  "Subetai the Swift",
+		npcID: 60710,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(10, 40, &proto.BossTimelineCast{
+					SpellId:      118094, // Volley
+					School:       proto.SpellSchool_SpellSchoolPhysical,
+					MinDamage:    45_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_Raid,
+					NumTicks:     3,
+					TickInterval: 1,
+				}),
+				default_ai.CastEvent(25, 40, &proto.BossTimelineCast{
+					SpellId:      118047, // Pillage
+					School:       proto.SpellSchool_SpellSchoolPhysical,
+					MinDamage:    250_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+				}),
+			}
+		},
+	},
+	{
+		name:  "Zian of the Endless Shadow",
+		npcID: 60701,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(4, 6, &proto.BossTimelineCast{
+					SpellId:      117628, // Shadow Blast
+					School:       proto.SpellSchool_SpellSchoolShadow,
+					MinDamage:    130_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+				}),
+				default_ai.CastEvent(15, 30, &proto.BossTimelineCast{
+					SpellId:      117685, // Chaos Bolt
+					School:       proto.SpellSchool_SpellSchoolShadow,
+					MinDamage:    60_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_Raid,
+				}),
+			}
+		},
+	},
+	{
+		name:  "Meng the Demented",
+		npcID: 60708,
+		events: func() []*proto.BossTimelineEvent {
+			// Crazed builds up Meng's damage until he switches to Cowardice.
+			crazed := default_ai.DamageDealtEvent(3, 3, &proto.BossTimelineDamageDealtModifier{
+				Multiplier: 1.05,
+				MaxStacks:  20,
+			})
+
+			return []*proto.BossTimelineEvent{
+				crazed,
+				default_ai.CastEvent(12, 40, &proto.BossTimelineCast{
+					SpellId:      117708, // Maddening Shout
+					School:       proto.SpellSchool_SpellSchoolShadow,
+					MinDamage:    100_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_Raid,
+				}),
+			}
+		},
+	},
+}
+
+func addSpiritKings(raidPrefix string) {
+	createSpiritKingsHeroicPreset(raidPrefix, 25, 285_000_000, 290_000)
+}
+
+func createSpiritKingsHeroicPreset(raidPrefix string, raidSize int32, kingHealth float64, kingMinBaseDamage float64) {
+	var targetPathNames []string
+
+	for idx, king := range spiritKings {
+		name := fmt.Sprintf("%s %d H", king.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:              king.npcID,
+				Name:            name,
+				Level:           93,
+				MobType:         proto.MobType_MobTypeHumanoid,
+				TankIndex:       0,
+				SecondTankIndex: 1,
+
+				Stats: stats.Stats{
+					stats.Health:      kingHealth,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:      2.0,
+				MinBaseDamage:   kingMinBaseDamage,
+				DamageSpread:    0.4,
+				TargetInputs:    spiritKingsTargetInputs(),
+				DisabledAtStart: idx > 0,
+			},
+
+			AI: default_ai.NewPresetTimelineAI(makeSpiritKingTimeline(idx), nil),
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(fmt.Sprintf("The Spirit Kings %d H", raidSize), targetPathNames)
+}
+
+func spiritKingsTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Tank swap interval",
+			Tooltip:     "Time (in seconds) between tank swaps on this king",
+			InputType:   proto.InputType_Number,
+			NumberValue: 30,
+		},
+	}
+}
+
+// Only one king fights at a time, and the first king's timeline switches to
+// the next one each time the encounter loses a quarter of its health.
+func makeSpiritKingTimeline(kingIdx int) func(*proto.Target) *proto.BossTimeline {
+	return func(config *proto.Target) *proto.BossTimeline {
+		events := spiritKings[kingIdx].events()
+
+		tankSwapInterval := default_ai.NumberInput(config, 0, 30)
+		if tankSwapInterval > 0 {
+			events = append(events, default_ai.TankSwapEvent(tankSwapInterval, tankSwapInterval))
+		}
+
+		if kingIdx > 0 {
+			return &proto.BossTimeline{
+				Phases: []*proto.BossTimelinePhase{{Name: spiritKings[kingIdx].name, Events: events}},
+			}
+		}
+
+		phases := []*proto.BossTimelinePhase{{Name: spiritKings[0].name, Events: events}}
+		for idx := 1; idx < len(spiritKings); idx++ {
+			phases = append(phases, &proto.BossTimelinePhase{
+				Name:               spiritKings[idx].name,
+				StartHealthPercent: 100 * float64(len(spiritKings)-idx) / float64(len(spiritKings)),
+				Events: []*proto.BossTimelineEvent{
+					default_ai.SetTargetEnabledEvent(0, int32(idx), true),
+					default_ai.SetTargetEnabledEvent(0.1, int32(idx-1), false),
+				},
+			})
+		}
+
+		return &proto.BossTimeline{Phases: phases}
+	}
+}
@@ -0,0 +1,184 @@
+package msv
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+const stoneGuardMeleeDamageSpread = 0.4
+
+type stoneGuardian struct {
+	name       string
+	npcID      int32
+	overloadID int32
+	school     proto.SpellSchool
+}
+
+var stoneGuardians = []stoneGuardian{
+	{name: "Jasper Guardian", npcID: 59915, overloadID: 115843, school: proto.SpellSchool_SpellSchoolFire},
+	{name: "Jade Guardian", npcID: 60043, overloadID: 115842, school: proto.SpellSchool_SpellSchoolNature},
+	{name: "Cobalt Guardian", npcID: 60051, overloadID: 115840, school: proto.SpellSchool_SpellSchoolArcane},
+	{name: "Amethyst Guardian", npcID: 60047, overloadID: 115844, school: proto.SpellSchool_SpellSchoolShadow},
+}
+
+func addStoneGuard(raidPrefix string) {
+	createStoneGuardHeroicPreset(raidPrefix, 25, 155_000_000, 250_000, 310_000)
+}
+
+func createStoneGuardHeroicPreset(raidPrefix string, raidSize int32, guardianHealth float64, guardianMinBaseDamage float64, overloadDamage float64) {
+	var targetPathNames []string
+
+	for idx, guardian := range stoneGuardians {
+		name := fmt.Sprintf("%s %d H", guardian.name, raidSize)
+
+		// Guardians are tanked in pairs, with the tanks swapping pairs.
+		tankIndex := int32(idx / 2)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:              guardian.npcID,
+				Name:            name,
+				Level:           93,
+				MobType:         proto.MobType_MobTypeElemental,
+				TankIndex:       tankIndex,
+				SecondTankIndex: 1 - tankIndex,
+
+				Stats: stats.Stats{
+					stats.Health:      guardianHealth,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:    2.0,
+				MinBaseDamage: guardianMinBaseDamage,
+				DamageSpread:  stoneGuardMeleeDamageSpread,
+				TargetInputs:  stoneGuardTargetInputs(),
+			},
+
+			AI: makeStoneGuardAI(idx, overloadDamage),
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(fmt.Sprintf("The Stone Guard %d H", raidSize), targetPathNames)
+}
+
+func stoneGuardTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Overload interval",
+			Tooltip:     "Average time (in seconds) for this guardian to reach 100 Energy and Overload. Set to 0 to never Overload.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 60,
+		},
+		{
+			Label:       "Tank swap interval",
+			Tooltip:     "Time (in seconds) between tank swaps of the guardian pairs",
+			InputType:   proto.InputType_Number,
+			NumberValue: 30,
+		},
+	}
+}
+
+func makeStoneGuardAI(guardianIdx int, overloadDamage float64) core.AIFactory {
+	return func() core.TargetAI {
+		ai := &StoneGuardAI{
+			guardianIdx:    guardianIdx,
+			overloadDamage: overloadDamage,
+		}
+		ai.BuildTimeline = ai.buildTimeline
+		return ai
+	}
+}
+
+// Guardians gain Energy over time and Overload the raid for damage of their
+// school when reaching 100, while tank swaps are scripted by the timeline.
+type StoneGuardAI struct {
+	default_ai.TimelineAI
+
+	guardianIdx    int
+	overloadDamage float64
+
+	energyPerTick float64
+	energy        float64
+
+	Overload *core.Spell
+}
+
+const stoneGuardEnergyTick = core.BossGCD
+
+func (ai *StoneGuardAI) buildTimeline(config *proto.Target) *proto.BossTimeline {
+	tankSwapInterval := default_ai.NumberInput(config, 1, 30)
+	if tankSwapInterval <= 0 {
+		return nil
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{
+				Name:   "Guardians",
+				Events: []*proto.BossTimelineEvent{default_ai.TankSwapEvent(tankSwapInterval, tankSwapInterval)},
+			},
+		},
+	}
+}
+
+func (ai *StoneGuardAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.TimelineAI.Initialize(target, config)
+
+	if overloadInterval := default_ai.NumberInput(config, 0, 60); overloadInterval > 0 {
+		ai.energyPerTick = 100 * stoneGuardEnergyTick.Seconds() / overloadInterval
+	}
+
+	guardian := stoneGuardians[ai.guardianIdx]
+	ai.Overload = target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: guardian.overloadID},
+		SpellSchool:      core.SpellSchoolFromProto(guardian.school),
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreAttackerModifiers,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, player := range sim.Raid.AllPlayerUnits {
+				damageRoll := ai.overloadDamage * (0.9 + 0.2*sim.RandomFloat("Overload Damage"))
+				spell.CalcAndDealDamage(sim, player, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *StoneGuardAI) Reset(sim *core.Simulation) {
+	ai.TimelineAI.Reset(sim)
+
+	if ai.energyPerTick == 0 {
+		return
+	}
+
+	// Stagger the guardians so they don't all Overload at once.
+	ai.energy = 25 * float64(ai.guardianIdx)
+
+	core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+		Period:   stoneGuardEnergyTick,
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			if !ai.Target.IsEnabled() {
+				return
+			}
+
+			ai.energy += ai.energyPerTick * (0.5 + sim.RandomFloat("Stone Guard Energy"))
+			if ai.energy >= 100 {
+				ai.energy = 0
+				ai.Overload.Cast(sim, sim.Raid.AllPlayerUnits[0])
+			}
+		},
+	})
+}
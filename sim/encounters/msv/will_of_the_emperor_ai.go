@@ -0,0 +1,201 @@
+package msv
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type emperorAdd struct {
+	name   string
+	npcID  int32
+	health float64
+
+	// Seconds between waves.
+	interval float64
+}
+
+var emperorAdds = []emperorAdd{
+	{name: "Emperor's Rage", npcID: 60396, health: 9_000_000, interval: 15},
+	{name: "Emperor's Strength", npcID: 60397, health: 26_000_000, interval: 30},
+	{name: "Emperor's Courage", npcID: 60398, health: 26_000_000, interval: 45},
+}
+
+// The adds follow the two bosses in the encounter targets.
+const emperorFirstAddIdx = 2
+
+func addWillOfTheEmperor(raidPrefix string) {
+	createWillOfTheEmperorHeroicPreset(raidPrefix, 25, 440_000_000, 300_000)
+}
+
+func createWillOfTheEmperorHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64) {
+	var targetPathNames []string
+
+	bosses := []struct {
+		name  string
+		npcID int32
+	}{
+		{name: "Jan-xi", npcID: 60400},
+		{name: "Qin-xi", npcID: 60399},
+	}
+
+	for idx, boss := range bosses {
+		name := fmt.Sprintf("%s %d H", boss.name, raidSize)
+
+		var targetInputs []*proto.TargetInput
+		var ai core.AIFactory
+		if idx == 0 {
+			targetInputs = willOfTheEmperorTargetInputs()
+			ai = default_ai.NewPresetTimelineAI(willOfTheEmperorTimeline, nil)
+		} else {
+			ai = default_ai.NewPresetTimelineAI(emperorBossTimeline, nil)
+		}
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        boss.npcID,
+				Name:      name,
+				Level:     93,
+				MobType:   proto.MobType_MobTypeMechanical,
+				TankIndex: int32(idx),
+
+				Stats: stats.Stats{
+					stats.Health:      bossHealth,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:    2.0,
+				MinBaseDamage: bossMinBaseDamage,
+				DamageSpread:  0.4,
+				TargetInputs:  targetInputs,
+			},
+
+			AI: ai,
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	for _, add := range emperorAdds {
+		name := fmt.Sprintf("%s %d H", add.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        add.npcID,
+				Name:      name,
+				Level:     92,
+				MobType:   proto.MobType_MobTypeHumanoid,
+				TankIndex: 2,
+
+				Stats: stats.Stats{
+					stats.Health: add.health,
+					stats.Armor:  24835,
+				}.ToProtoArray(),
+
+				SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:      2.0,
+				MinBaseDamage:   bossMinBaseDamage / 4,
+				DamageSpread:    0.4,
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: true,
+			},
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(fmt.Sprintf("Will of the Emperor %d H", raidSize), targetPathNames)
+}
+
+func willOfTheEmperorTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Add lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill each wave of adds",
+			InputType:   proto.InputType_Number,
+			NumberValue: 12,
+		},
+		{
+			Label:       "Titan Gas interval",
+			Tooltip:     "Time (in seconds) between Titan Gas releases",
+			InputType:   proto.InputType_Number,
+			NumberValue: 225,
+		},
+	}
+}
+
+// Devastating Arc and Stomp of each boss.
+func emperorBossTimeline(_ *proto.Target) *proto.BossTimeline {
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{
+				Name: "Bosses",
+				Events: []*proto.BossTimelineEvent{
+					default_ai.CastEvent(12, 20, &proto.BossTimelineCast{
+						SpellId:      117006, // Devastating Arc
+						School:       proto.SpellSchool_SpellSchoolPhysical,
+						MinDamage:    380_000,
+						DamageSpread: 0.2,
+						CastTime:     2,
+						Melee:        true,
+					}),
+					default_ai.CastEvent(20, 30, &proto.BossTimelineCast{
+						SpellId:      116969, // Stomp
+						School:       proto.SpellSchool_SpellSchoolPhysical,
+						MinDamage:    70_000,
+						DamageSpread: 0.1,
+						CastTime:     1.5,
+						Target:       proto.BossTimelineCast_Raid,
+					}),
+				},
+			},
+		},
+	}
+}
+
+// Jan-xi also drives the add waves and Titan Gas, which pulses the raid for
+// 30 seconds while making Jan-xi hit harder.
+func willOfTheEmperorTimeline(config *proto.Target) *proto.BossTimeline {
+	addLifetime := default_ai.NumberInput(config, 0, 12)
+	titanGasInterval := default_ai.NumberInput(config, 1, 225)
+
+	timeline := emperorBossTimeline(config)
+	events := timeline.Phases[0].Events
+
+	for idx, add := range emperorAdds {
+		spawn := default_ai.SetTargetEnabledEvent(add.interval, int32(emperorFirstAddIdx+idx), true)
+		spawn.Period = add.interval
+		despawn := default_ai.SetTargetEnabledEvent(add.interval+addLifetime, int32(emperorFirstAddIdx+idx), false)
+		despawn.Period = add.interval
+		events = append(events, spawn, despawn)
+	}
+
+	if titanGasInterval > 0 {
+		titanGas := default_ai.CastEvent(titanGasInterval, titanGasInterval, &proto.BossTimelineCast{
+			SpellId:      116779, // Titan Gas
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    25_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+			NumTicks:     30,
+			TickInterval: 1,
+		})
+		energized := default_ai.DamageDealtEvent(titanGasInterval, titanGasInterval, &proto.BossTimelineDamageDealtModifier{
+			Multiplier: 1.25,
+			Duration:   30,
+		})
+		events = append(events, titanGas, energized)
+	}
+
+	timeline.Phases[0].Events = events
+	return timeline
+}
@@ -5,18 +5,28 @@ import (
 	"github.com/wowsims/mop/sim/core/proto"
 	"github.com/wowsims/mop/sim/core/stats"
 	"github.com/wowsims/mop/sim/encounters/bwd"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
 	"github.com/wowsims/mop/sim/encounters/dragonsoul"
 	"github.com/wowsims/mop/sim/encounters/firelands"
+	"github.com/wowsims/mop/sim/encounters/hof"
 	"github.com/wowsims/mop/sim/encounters/msv"
+	"github.com/wowsims/mop/sim/encounters/soo"
+	"github.com/wowsims/mop/sim/encounters/toes"
+	"github.com/wowsims/mop/sim/encounters/tot"
 )
 
 func init() {
+	core.SetTimelineAI(default_ai.NewTimelineAI(nil))
 	AddDefaultPresetEncounter()
 	addMovementAI()
 	bwd.Register()
 	firelands.Register()
 	dragonsoul.Register()
 	msv.Register()
+	hof.Register()
+	toes.Register()
+	tot.Register()
+	soo.Register()
 }
 
 func AddSingleTargetBossEncounter(presetTarget *core.PresetTarget) {
@@ -0,0 +1,203 @@
+package soo
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type blackfuseWeapon struct {
+	name  string
+	npcID int32
+}
+
+// The Automated Shredder and then the belt weapons follow Blackfuse in the
+// encounter targets. The belt sends the weapons in this order.
+var blackfuseWeapons = []blackfuseWeapon{
+	{name: "Deactivated Laser Turret", npcID: 71606},
+	{name: "Deactivated Missile Turret", npcID: 71638},
+	{name: "Deactivated Electromagnet", npcID: 71694},
+	{name: "Disassembled Crawler Mines", npcID: 71788},
+}
+
+const (
+	blackfuseShredderIdx    = 1
+	blackfuseFirstWeaponIdx = 2
+)
+
+// Seconds between two weapons coming down the belt.
+const blackfuseBeltInterval = 40
+
+func addBlackfuse(raidPrefix string) {
+	createBlackfuseHeroicPreset(raidPrefix, 25, 1_100_000_000, 420_000, 90_000_000, 14_000_000)
+}
+
+func createBlackfuseHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, shredderHealth float64, weaponHealth float64) {
+	bossName := fmt.Sprintf("Siegecrafter Blackfuse %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              71504,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeHumanoid,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  blackfuseTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(blackfuseTimeline, nil),
+	})
+
+	shredderName := fmt.Sprintf("Automated Shredder %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        71591,
+			Name:      shredderName,
+			Level:     92,
+			MobType:   proto.MobType_MobTypeMechanical,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health: shredderHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:      2.0,
+			MinBaseDamage:   bossMinBaseDamage * 0.75,
+			DamageSpread:    0.4,
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	targetPathNames := []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + shredderName,
+	}
+
+	for _, weapon := range blackfuseWeapons {
+		name := fmt.Sprintf("%s %d H", weapon.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:      weapon.npcID,
+				Name:    name,
+				Level:   92,
+				MobType: proto.MobType_MobTypeMechanical,
+
+				Stats: stats.Stats{
+					stats.Health: weaponHealth,
+					stats.Armor:  24835,
+				}.ToProtoArray(),
+
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: true,
+			},
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(bossName, targetPathNames).UseHealth = true
+}
+
+func blackfuseTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:     "Belt duty",
+			Tooltip:   "Whether this player hops on the conveyor belt to kill the weapons, or stays on the boss and Shredders",
+			InputType: proto.InputType_Bool,
+			BoolValue: true,
+		},
+		{
+			Label:       "Weapon lifetime",
+			Tooltip:     "Time (in seconds) the belt team takes to kill each weapon",
+			InputType:   proto.InputType_Number,
+			NumberValue: 15,
+		},
+		{
+			Label:       "Shredder lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill each Automated Shredder",
+			InputType:   proto.InputType_Number,
+			NumberValue: 25,
+		},
+	}
+}
+
+// An Automated Shredder joins every minute, and a new weapon comes down the
+// conveyor belt every 40 seconds. Belt duty moves the player onto the belt
+// for each weapon.
+func blackfuseTimeline(config *proto.Target) *proto.BossTimeline {
+	beltDuty := default_ai.BoolInput(config, 0, true)
+	weaponLifetime := default_ai.NumberInput(config, 1, 15)
+	shredderLifetime := default_ai.NumberInput(config, 2, 25)
+
+	events := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(10, 17, &proto.BossTimelineCast{
+			SpellId:      143385, // Electrostatic Charge
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    150_000,
+			DamageSpread: 0.1,
+			Melee:        true,
+		}),
+		default_ai.TankSwapEvent(45, 51),
+		default_ai.CastEvent(8, 11, &proto.BossTimelineCast{
+			SpellId:      143265, // Launch Sawblade
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    200_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		}),
+	}
+
+	if shredderLifetime > 0 {
+		spawn := default_ai.SetTargetEnabledEvent(35, blackfuseShredderIdx, true)
+		spawn.Period = 60
+		kill := default_ai.SetTargetEnabledEvent(35+shredderLifetime, blackfuseShredderIdx, false)
+		kill.Period = 60
+		events = append(events, spawn, kill)
+	}
+
+	if beltDuty && (weaponLifetime > 0) {
+		beltCycle := float64(blackfuseBeltInterval * len(blackfuseWeapons))
+
+		for idx := range blackfuseWeapons {
+			weaponTime := float64(blackfuseBeltInterval * (idx + 1))
+			targetIdx := int32(blackfuseFirstWeaponIdx + idx)
+
+			move := default_ai.MovementEvent(weaponTime-3, beltCycle, 3)
+			spawn := default_ai.SetTargetEnabledEvent(weaponTime, targetIdx, true)
+			spawn.Period = beltCycle
+			kill := default_ai.SetTargetEnabledEvent(weaponTime+weaponLifetime, targetIdx, false)
+			kill.Period = beltCycle
+			events = append(events, move, spawn, kill)
+		}
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{{Name: "Siegecrafter Blackfuse", Events: events}},
+	}
+}
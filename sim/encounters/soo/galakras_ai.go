@@ -0,0 +1,197 @@
+package soo
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type galakrasAdd struct {
+	name      string
+	npcID     int32
+	health    float64
+	tankIndex int32
+
+	// Fraction of Galakras' melee damage, 0 for adds that don't melee.
+	damageFraction float64
+}
+
+// The adds follow Galakras in the encounter targets.
+var galakrasAdds = []galakrasAdd{
+	{name: "Dragonmaw Bonecrusher", npcID: 72354, health: 45_000_000, tankIndex: 1, damageFraction: 0.4},
+	{name: "Dragonmaw Flameslinger", npcID: 72353, health: 12_000_000},
+	{name: "Kor'kron Demolisher", npcID: 72947, health: 10_000_000},
+}
+
+const (
+	galakrasFirstWaveIdx  = 1
+	galakrasDemolisherIdx = 3
+)
+
+func addGalakras(raidPrefix string) {
+	createGalakrasHeroicPreset(raidPrefix, 25, 900_000_000, 420_000)
+}
+
+func createGalakrasHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64) {
+	bossName := fmt.Sprintf("Galakras %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              72311,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeDragonkin,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  galakrasTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(galakrasTimeline, nil),
+	})
+
+	targetPathNames := []string{raidPrefix + "/" + bossName}
+
+	for _, add := range galakrasAdds {
+		name := fmt.Sprintf("%s %d H", add.name, raidSize)
+
+		config := &proto.Target{
+			Id:      add.npcID,
+			Name:    name,
+			Level:   92,
+			MobType: proto.MobType_MobTypeHumanoid,
+
+			Stats: stats.Stats{
+				stats.Health: add.health,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		}
+
+		if add.damageFraction > 0 {
+			config.TankIndex = add.tankIndex
+			config.SpellSchool = proto.SpellSchool_SpellSchoolPhysical
+			config.SwingSpeed = 2.0
+			config.MinBaseDamage = bossMinBaseDamage * add.damageFraction
+			config.DamageSpread = 0.4
+		}
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+			Config:     config,
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(bossName, targetPathNames).UseHealth = true
+}
+
+func galakrasTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Ground phase duration",
+			Tooltip:     "Time (in seconds) before Galakras is shot down and lands",
+			InputType:   proto.InputType_Number,
+			NumberValue: 270,
+		},
+		{
+			Label:       "Wave lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill each wave of Dragonmaw adds",
+			InputType:   proto.InputType_Number,
+			NumberValue: 25,
+		},
+		{
+			Label:     "Tower duty",
+			Tooltip:   "Whether this player is part of a tower team, leaving the adds to clear a tower twice during the ground phase",
+			InputType: proto.InputType_Bool,
+			BoolValue: false,
+		},
+	}
+}
+
+// Galakras flies overhead while the raid fights off waves of Dragonmaw adds
+// and the tower teams clear the South and North towers. He lands once the
+// tower teams have shot him down.
+func galakrasTimeline(config *proto.Target) *proto.BossTimeline {
+	groundDuration := default_ai.NumberInput(config, 0, 270)
+	waveLifetime := default_ai.NumberInput(config, 1, 25)
+	towerDuty := default_ai.BoolInput(config, 2, false)
+
+	groundEvents := []*proto.BossTimelineEvent{
+		default_ai.DamageTakenEvent(0, 0, &proto.BossTimelineDamageTakenModifier{
+			Multiplier: 0.01,
+			Duration:   groundDuration,
+		}),
+		default_ai.CastEvent(30, 40, &proto.BossTimelineCast{
+			SpellId:      146991, // Flames of Galakrond
+			School:       proto.SpellSchool_SpellSchoolFire,
+			MinDamage:    180_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		}),
+	}
+
+	for idx := galakrasFirstWaveIdx; idx < galakrasDemolisherIdx; idx++ {
+		spawn := default_ai.SetTargetEnabledEvent(5, int32(idx), true)
+		spawn.Period = 55
+		kill := default_ai.SetTargetEnabledEvent(5+waveLifetime, int32(idx), false)
+		kill.Period = 55
+		groundEvents = append(groundEvents, spawn, kill)
+	}
+
+	if towerDuty {
+		for _, towerTime := range []float64{60, 150} {
+			groundEvents = append(groundEvents,
+				default_ai.MovementEvent(towerTime, 0, 8),
+				default_ai.SetTargetEnabledEvent(towerTime+8, galakrasDemolisherIdx, true),
+				default_ai.SetTargetEnabledEvent(towerTime+30, galakrasDemolisherIdx, false),
+			)
+		}
+	}
+
+	landEvents := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(10, 20, &proto.BossTimelineCast{
+			SpellId:      147029, // Flames of Galakrond
+			School:       proto.SpellSchool_SpellSchoolFire,
+			MinDamage:    250_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.CastEvent(5, 5, &proto.BossTimelineCast{
+			SpellId:      147042, // Pulsing Flames
+			School:       proto.SpellSchool_SpellSchoolFire,
+			MinDamage:    60_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.TankSwapEvent(30, 30),
+	}
+	for idx := range galakrasAdds {
+		landEvents = append(landEvents, default_ai.SetTargetEnabledEvent(0, int32(galakrasFirstWaveIdx+idx), false))
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Ground Phase", Events: groundEvents},
+			{Name: "Galakras", StartTime: groundDuration, Events: landEvents},
+		},
+	}
+}
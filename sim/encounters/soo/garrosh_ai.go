@@ -0,0 +1,231 @@
+package soo
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// Indices of the adds that follow Garrosh in the encounter targets.
+const (
+	garroshWarbringerIdx = 1
+	garroshEmbodiedIdx   = 2
+)
+
+func addGarrosh(raidPrefix string) {
+	createGarroshHeroicPreset(raidPrefix, 25, 1_600_000_000, 500_000, 20_000_000, 60_000_000)
+}
+
+func createGarroshHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, warbringerHealth float64, embodiedHealth float64) {
+	bossName := fmt.Sprintf("Garrosh Hellscream %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              71865,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeHumanoid,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  garroshTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(garroshTimeline, nil),
+	})
+
+	warbringerName := fmt.Sprintf("Kor'kron Warbringer %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        71979,
+			Name:      warbringerName,
+			Level:     92,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health: warbringerHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:      2.0,
+			MinBaseDamage:   bossMinBaseDamage / 5,
+			DamageSpread:    0.4,
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	embodiedName := fmt.Sprintf("Embodied Despair %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:      72238,
+			Name:    embodiedName,
+			Level:   92,
+			MobType: proto.MobType_MobTypeDemon,
+
+			Stats: stats.Stats{
+				stats.Health: embodiedHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + warbringerName,
+		raidPrefix + "/" + embodiedName,
+	}).UseHealth = true
+}
+
+func garroshTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Transition duration",
+			Tooltip:     "Time (in seconds) Garrosh can't be attacked while he draws power from the heart at 70% and 10%",
+			InputType:   proto.InputType_Number,
+			NumberValue: 20,
+		},
+		{
+			Label:       "Realm of Y'Shaarj duration",
+			Tooltip:     "Time (in seconds) the raid spends in each Realm of Y'Shaarj intermission",
+			InputType:   proto.InputType_Number,
+			NumberValue: 60,
+		},
+		{
+			Label:       "Warbringer lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill each Kor'kron Warbringer",
+			InputType:   proto.InputType_Number,
+			NumberValue: 15,
+		},
+	}
+}
+
+// Garrosh draws on the heart of Y'Shaarj at 70% and 10%, and can't be
+// attacked during those transitions. In the second phase he regularly pulls
+// the raid into the Realm of Y'Shaarj, where the Embodied minions are the only
+// thing left to hit.
+func garroshTimeline(config *proto.Target) *proto.BossTimeline {
+	transitionDuration := default_ai.NumberInput(config, 0, 20)
+	realmDuration := default_ai.NumberInput(config, 1, 60)
+	warbringerLifetime := default_ai.NumberInput(config, 2, 15)
+
+	// Swaps Garrosh out for the Embodied minions, starting at offset and
+	// repeating with period.
+	downtime := func(offset float64, period float64, duration float64) []*proto.BossTimelineEvent {
+		events := []*proto.BossTimelineEvent{
+			default_ai.SetTargetEnabledEvent(offset, garroshEmbodiedIdx, true),
+			default_ai.SetTargetEnabledEvent(offset+0.1, 0, false),
+			default_ai.SetTargetEnabledEvent(offset+duration, 0, true),
+			default_ai.SetTargetEnabledEvent(offset+duration+0.1, garroshEmbodiedIdx, false),
+		}
+		for _, event := range events {
+			event.Period = period
+		}
+		return events
+	}
+
+	desecratedWeapon := default_ai.CastEvent(12, 36, &proto.BossTimelineCast{
+		SpellId:      144748, // Desecrated Weapon
+		School:       proto.SpellSchool_SpellSchoolShadow,
+		MinDamage:    120_000,
+		DamageSpread: 0.1,
+		Target:       proto.BossTimelineCast_RandomPlayer,
+	})
+	// Players leave the Desecration.
+	leaveDesecration := default_ai.MovementEvent(13, 36, 2)
+
+	firstPhase := []*proto.BossTimelineEvent{
+		desecratedWeapon, leaveDesecration,
+		default_ai.CastEvent(20, 42, &proto.BossTimelineCast{
+			SpellId:      144821, // Hellscream's Warsong
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    80_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.TankSwapEvent(30, 30),
+	}
+	if warbringerLifetime > 0 {
+		spawn := default_ai.SetTargetEnabledEvent(5, garroshWarbringerIdx, true)
+		spawn.Period = 45
+		kill := default_ai.SetTargetEnabledEvent(5+warbringerLifetime, garroshWarbringerIdx, false)
+		kill.Period = 45
+		firstPhase = append(firstPhase, spawn, kill)
+	}
+
+	secondPhase := append(downtime(0, 0, transitionDuration),
+		desecratedWeapon, leaveDesecration,
+		default_ai.CastEvent(transitionDuration+15, 50, &proto.BossTimelineCast{
+			SpellId:      144985, // Whirling Corruption
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    60_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+			NumTicks:     5,
+			TickInterval: 1,
+		}),
+		default_ai.TankSwapEvent(transitionDuration+30, 30),
+		default_ai.SetTargetEnabledEvent(0, garroshWarbringerIdx, false),
+	)
+	if realmDuration > 0 {
+		secondPhase = append(secondPhase, downtime(transitionDuration+120, 120+realmDuration, realmDuration)...)
+	}
+
+	thirdPhase := append(downtime(0, 0, transitionDuration),
+		default_ai.CastEvent(transitionDuration+10, 30, &proto.BossTimelineCast{
+			SpellId:      145037, // Empowered Whirling Corruption
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    90_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+			NumTicks:     5,
+			TickInterval: 1,
+		}),
+		default_ai.CastEvent(transitionDuration+5, 20, &proto.BossTimelineCast{
+			SpellId:      145183, // Gripping Despair
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    150_000,
+			DamageSpread: 0.1,
+			Melee:        true,
+		}),
+		default_ai.TankSwapEvent(transitionDuration+20, 20),
+		default_ai.DamageDealtEvent(transitionDuration, 10, &proto.BossTimelineDamageDealtModifier{
+			Multiplier: 1.05,
+			MaxStacks:  30,
+		}),
+	)
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Phase 1", Events: firstPhase},
+			{Name: "Phase 2", StartHealthPercent: 70, Events: secondPhase},
+			{Name: "Phase 3", StartHealthPercent: 10, Events: thirdPhase},
+		},
+	}
+}
@@ -0,0 +1,146 @@
+package soo
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// The Sha Puddles follow Immerseus in the encounter targets.
+const immerseusPuddleIdx = 1
+
+func addImmerseus(raidPrefix string) {
+	createImmerseusHeroicPreset(raidPrefix, 25, 1_200_000_000, 450_000, 30_000_000)
+}
+
+func createImmerseusHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, puddleHealth float64) {
+	bossName := fmt.Sprintf("Immerseus %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              71543,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeElemental,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  immerseusTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(immerseusTimeline, nil),
+	})
+
+	puddleName := fmt.Sprintf("Sha Puddle %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:      71603,
+			Name:    puddleName,
+			Level:   92,
+			MobType: proto.MobType_MobTypeElemental,
+
+			Stats: stats.Stats{
+				stats.Health: puddleHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + puddleName,
+	}).UseHealth = true
+}
+
+func immerseusTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Split interval",
+			Tooltip:     "Time (in seconds) between the starts of two Splits",
+			InputType:   proto.InputType_Number,
+			NumberValue: 90,
+		},
+		{
+			Label:       "Split duration",
+			Tooltip:     "Time (in seconds) the raid spends killing the puddles before Immerseus reforms",
+			InputType:   proto.InputType_Number,
+			NumberValue: 30,
+		},
+	}
+}
+
+// Immerseus regularly Splits into puddles that make their way back to the
+// pool. He can't be attacked until he reforms, so the Sha Puddles are the
+// only target during each Split.
+func immerseusTimeline(config *proto.Target) *proto.BossTimeline {
+	splitInterval := default_ai.NumberInput(config, 0, 90)
+	splitDuration := default_ai.NumberInput(config, 1, 30)
+
+	events := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(6, 35, &proto.BossTimelineCast{
+			SpellId:      143436, // Corrosive Blast
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    450_000,
+			DamageSpread: 0.1,
+			Melee:        true,
+		}),
+		default_ai.TankSwapEvent(7, 35),
+		default_ai.CastEvent(16, 20, &proto.BossTimelineCast{
+			SpellId:      143295, // Sha Bolt
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    90_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.CastEvent(20, 48, &proto.BossTimelineCast{
+			SpellId:      143309, // Swirl
+			School:       proto.SpellSchool_SpellSchoolFrost,
+			MinDamage:    100_000,
+			DamageSpread: 0.1,
+			CastTime:     3,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+			NumTicks:     13,
+			TickInterval: 1,
+		}),
+		// Players dodge the Swirl currents.
+		default_ai.MovementEvent(23, 48, 6),
+	}
+
+	if (splitInterval > 0) && (splitDuration > 0) {
+		split := []*proto.BossTimelineEvent{
+			default_ai.SetTargetEnabledEvent(splitInterval, immerseusPuddleIdx, true),
+			default_ai.SetTargetEnabledEvent(splitInterval+0.1, 0, false),
+			default_ai.SetTargetEnabledEvent(splitInterval+splitDuration, 0, true),
+			default_ai.SetTargetEnabledEvent(splitInterval+splitDuration+0.1, immerseusPuddleIdx, false),
+		}
+		for _, event := range split {
+			event.Period = splitInterval
+			events = append(events, event)
+		}
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{{Name: "Immerseus", Events: events}},
+	}
+}
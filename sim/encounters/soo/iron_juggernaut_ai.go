@@ -0,0 +1,175 @@
+package soo
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// The Crawler Mines follow the Iron Juggernaut in the encounter targets.
+const ironJuggernautMineIdx = 1
+
+// Assault Mode and Siege Mode alternate for the whole fight.
+const (
+	ironJuggernautAssaultDuration = 120
+	ironJuggernautSiegeDuration   = 60
+	ironJuggernautCycleDuration   = ironJuggernautAssaultDuration + ironJuggernautSiegeDuration
+)
+
+func addIronJuggernaut(raidPrefix string) {
+	createIronJuggernautHeroicPreset(raidPrefix, 25, 1_000_000_000, 480_000, 3_000_000)
+}
+
+func createIronJuggernautHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, mineHealth float64) {
+	bossName := fmt.Sprintf("Iron Juggernaut %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              71466,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeMechanical,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  ironJuggernautTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(ironJuggernautTimeline, nil),
+	})
+
+	mineName := fmt.Sprintf("Crawler Mine %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:      72050,
+			Name:    mineName,
+			Level:   92,
+			MobType: proto.MobType_MobTypeMechanical,
+
+			Stats: stats.Stats{
+				stats.Health: mineHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + mineName,
+	}).UseHealth = true
+}
+
+func ironJuggernautTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Crawler Mine lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill each set of Crawler Mines",
+			InputType:   proto.InputType_Number,
+			NumberValue: 6,
+		},
+	}
+}
+
+// In Assault Mode the Juggernaut fights the tanks and drops Crawler Mines. In
+// Siege Mode it stops meleeing, which is approximated by slowing its swings to
+// a crawl, and bombards the raid with Mortar Blasts, Cutter Lasers and Shock
+// Pulses.
+func ironJuggernautTimeline(config *proto.Target) *proto.BossTimeline {
+	mineLifetime := default_ai.NumberInput(config, 0, 6)
+
+	cycle := func(offset float64, event *proto.BossTimelineEvent) *proto.BossTimelineEvent {
+		event.Offset = offset
+		event.Period = ironJuggernautCycleDuration
+		return event
+	}
+
+	var events []*proto.BossTimelineEvent
+
+	// Assault Mode
+	for assaultTime := 10.0; assaultTime < ironJuggernautAssaultDuration; assaultTime += 30 {
+		events = append(events,
+			cycle(assaultTime, default_ai.CastEvent(0, 0, &proto.BossTimelineCast{
+				SpellId:      144467, // Ignite Armor
+				School:       proto.SpellSchool_SpellSchoolFire,
+				MinDamage:    120_000,
+				DamageSpread: 0.1,
+				NumTicks:     9,
+				TickInterval: 1,
+			})),
+			cycle(assaultTime+5, default_ai.TankSwapEvent(0, 0)),
+			cycle(assaultTime+8, default_ai.CastEvent(0, 0, &proto.BossTimelineCast{
+				SpellId:      144218, // Borer Drill
+				School:       proto.SpellSchool_SpellSchoolFire,
+				MinDamage:    70_000,
+				DamageSpread: 0.1,
+				Target:       proto.BossTimelineCast_RandomPlayer,
+			})),
+			// Players dodge the Borer Drills.
+			cycle(assaultTime+9, default_ai.MovementEvent(0, 0, 2)),
+		)
+	}
+
+	events = append(events,
+		// Siege Mode
+		cycle(ironJuggernautAssaultDuration, default_ai.DamageDealtEvent(0, 0, &proto.BossTimelineDamageDealtModifier{
+			Multiplier:      1,
+			SpeedMultiplier: 0.01,
+			Duration:        ironJuggernautSiegeDuration,
+		})),
+		cycle(ironJuggernautAssaultDuration+5, default_ai.CastEvent(0, 0, &proto.BossTimelineCast{
+			SpellId:      144316, // Mortar Blast
+			School:       proto.SpellSchool_SpellSchoolFire,
+			MinDamage:    120_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+			NumTicks:     int32(ironJuggernautSiegeDuration / 10),
+			TickInterval: 10,
+		})),
+		// Players run from the Cutter Lasers.
+		cycle(ironJuggernautAssaultDuration+10, default_ai.MovementEvent(0, 0, 6)),
+		cycle(ironJuggernautAssaultDuration+35, default_ai.MovementEvent(0, 0, 6)),
+		cycle(ironJuggernautAssaultDuration+15, default_ai.CastEvent(0, 0, &proto.BossTimelineCast{
+			SpellId:      144485, // Shock Pulse
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    90_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+			NumTicks:     3,
+			TickInterval: 15,
+		})),
+	)
+
+	if mineLifetime > 0 {
+		for _, mineTime := range []float64{30, 60, 90} {
+			events = append(events,
+				cycle(mineTime, default_ai.SetTargetEnabledEvent(0, ironJuggernautMineIdx, true)),
+				cycle(mineTime+mineLifetime, default_ai.SetTargetEnabledEvent(0, ironJuggernautMineIdx, false)),
+			)
+		}
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{{Name: "Iron Juggernaut", Events: events}},
+	}
+}
@@ -0,0 +1,152 @@
+package soo
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// The Manifestations of Corruption follow the Amalgam in the encounter
+// targets.
+const norushenManifestationIdx = 1
+
+func addNorushen(raidPrefix string) {
+	createNorushenHeroicPreset(raidPrefix, 25, 1_050_000_000, 450_000, 18_000_000)
+}
+
+func createNorushenHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, manifestationHealth float64) {
+	bossName := fmt.Sprintf("Amalgam of Corruption %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              72276,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeElemental,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolShadow,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  norushenTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(norushenTimeline, nil),
+	})
+
+	manifestationName := fmt.Sprintf("Manifestation of Corruption %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        71977,
+			Name:      manifestationName,
+			Level:     92,
+			MobType:   proto.MobType_MobTypeElemental,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health: manifestationHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			SpellSchool:     proto.SpellSchool_SpellSchoolShadow,
+			SwingSpeed:      2.0,
+			MinBaseDamage:   bossMinBaseDamage / 4,
+			DamageSpread:    0.4,
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(fmt.Sprintf("Norushen %d H", raidSize), []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + manifestationName,
+	}).UseHealth = true
+}
+
+func norushenTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Manifestation interval",
+			Tooltip:     "Time (in seconds) between Manifestations of Corruption released by the Test of Confidence before the Amalgam is Frayed",
+			InputType:   proto.InputType_Number,
+			NumberValue: 45,
+		},
+		{
+			Label:       "Manifestation lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to burst down each Manifestation of Corruption",
+			InputType:   proto.InputType_Number,
+			NumberValue: 8,
+		},
+	}
+}
+
+// The raid bursts a Manifestation of Corruption each time a Test of
+// Confidence succeeds. Once the Amalgam is Frayed at 50%, a Manifestation
+// breaks off every 10 seconds as the raid keeps damaging it.
+func norushenTimeline(config *proto.Target) *proto.BossTimeline {
+	manifestationInterval := default_ai.NumberInput(config, 0, 45)
+	manifestationLifetime := default_ai.NumberInput(config, 1, 8)
+
+	amalgamEvents := func() []*proto.BossTimelineEvent {
+		return []*proto.BossTimelineEvent{
+			default_ai.CastEvent(11, 11, &proto.BossTimelineCast{
+				SpellId:      145216, // Unleashed Anger
+				School:       proto.SpellSchool_SpellSchoolShadow,
+				MinDamage:    400_000,
+				DamageSpread: 0.1,
+				Melee:        true,
+				NumTicks:     3,
+				TickInterval: 2,
+			}),
+			default_ai.TankSwapEvent(18, 33),
+			default_ai.CastEvent(10, 10, &proto.BossTimelineCast{
+				SpellId:      144482, // Unchecked Corruption
+				School:       proto.SpellSchool_SpellSchoolShadow,
+				MinDamage:    50_000,
+				DamageSpread: 0.1,
+				Target:       proto.BossTimelineCast_Raid,
+			}),
+			// Players pick up the Residual Corruption pools.
+			default_ai.MovementEvent(30, 30, 2),
+		}
+	}
+
+	manifestations := func(interval float64) []*proto.BossTimelineEvent {
+		if (interval <= 0) || (manifestationLifetime <= 0) {
+			return nil
+		}
+
+		spawn := default_ai.SetTargetEnabledEvent(interval, norushenManifestationIdx, true)
+		spawn.Period = interval
+		kill := default_ai.SetTargetEnabledEvent(interval+min(manifestationLifetime, interval-0.5), norushenManifestationIdx, false)
+		kill.Period = interval
+		return []*proto.BossTimelineEvent{spawn, kill}
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Amalgam of Corruption", Events: append(amalgamEvents(), manifestations(manifestationInterval)...)},
+			{
+				Name:               "Frayed",
+				StartHealthPercent: 50,
+				Events:             append(amalgamEvents(), manifestations(10)...),
+			},
+		},
+	}
+}
@@ -0,0 +1,216 @@
+package soo
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type klaxxiParagon struct {
+	name  string
+	npcID int32
+	cast  *proto.BossTimelineCast
+
+	// Seconds between casts.
+	interval float64
+}
+
+// In order of activation. The last paragon drives the encounter, since it is
+// still around when every other one has died.
+var klaxxiParagons = []klaxxiParagon{
+	{
+		name:     "Kil'ruk the Wind-Reaver",
+		npcID:    71161,
+		interval: 15,
+		cast: &proto.BossTimelineCast{
+			SpellId:      142232, // Death from Above
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    150_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		},
+	},
+	{
+		name:     "Xaril the Poisoned Mind",
+		npcID:    71157,
+		interval: 20,
+		cast: &proto.BossTimelineCast{
+			SpellId:      142315, // Caustic Blood
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    40_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+			NumTicks:     6,
+			TickInterval: 2,
+		},
+	},
+	{
+		name:     "Kaz'tik the Manipulator",
+		npcID:    71156,
+		interval: 25,
+		cast: &proto.BossTimelineCast{
+			SpellId:      142651, // Mesmerize
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    60_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		},
+	},
+	{
+		name:     "Korven the Prime",
+		npcID:    71155,
+		interval: 30,
+		cast: &proto.BossTimelineCast{
+			SpellId:      142729, // Shield Bash
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    500_000,
+			DamageSpread: 0.1,
+			CastTime:     2,
+			Melee:        true,
+		},
+	},
+	{
+		name:     "Iyyokuk the Lucid",
+		npcID:    71160,
+		interval: 35,
+		cast: &proto.BossTimelineCast{
+			SpellId:      142416, // Insane Calculation: Fiery Edge
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    120_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		},
+	},
+	{
+		name:     "Ka'roz the Locust",
+		npcID:    71154,
+		interval: 40,
+		cast: &proto.BossTimelineCast{
+			SpellId:      143701, // Whirling
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    200_000,
+			DamageSpread: 0.1,
+			CastTime:     2,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		},
+	},
+	{
+		name:     "Skeer the Bloodseeker",
+		npcID:    71152,
+		interval: 25,
+		cast: &proto.BossTimelineCast{
+			SpellId:      143280, // Bloodletting
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    90_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		},
+	},
+	{
+		name:     "Rik'kal the Dissector",
+		npcID:    71158,
+		interval: 20,
+		cast: &proto.BossTimelineCast{
+			SpellId:      143339, // Injection
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    80_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		},
+	},
+	{
+		name:     "Hisek the Swarmkeeper",
+		npcID:    71153,
+		interval: 30,
+		cast: &proto.BossTimelineCast{
+			SpellId:      144839, // Multi-Shot
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    250_000,
+			DamageSpread: 0.1,
+			CastTime:     3,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		},
+	},
+}
+
+// Paragons fighting at the same time.
+const klaxxiNumActiveParagons = 3
+
+func addParagons(raidPrefix string) {
+	createParagonsHeroicPreset(raidPrefix, 25, 160_000_000, 330_000)
+}
+
+func createParagonsHeroicPreset(raidPrefix string, raidSize int32, paragonHealth float64, paragonMinBaseDamage float64) {
+	var targetPathNames []string
+
+	for idx, paragon := range klaxxiParagons {
+		name := fmt.Sprintf("%s %d H", paragon.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        paragon.npcID,
+				Name:      name,
+				Level:     93,
+				MobType:   proto.MobType_MobTypeHumanoid,
+				TankIndex: int32(idx % 2),
+
+				Stats: stats.Stats{
+					stats.Health:      paragonHealth,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:      2.0,
+				MinBaseDamage:   paragonMinBaseDamage,
+				DamageSpread:    0.4,
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: idx >= klaxxiNumActiveParagons,
+			},
+
+			AI: default_ai.NewPresetTimelineAI(makeParagonTimeline(idx), nil),
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(fmt.Sprintf("Paragons of the Klaxxi %d H", raidSize), targetPathNames).UseHealth = true
+}
+
+// Three paragons fight at a time, and each time the raid kills one the next
+// paragon in line joins. The raid kills them in order of activation, one for
+// each ninth of the encounter's health.
+func makeParagonTimeline(paragonIdx int) func(*proto.Target) *proto.BossTimeline {
+	return func(_ *proto.Target) *proto.BossTimeline {
+		paragon := klaxxiParagons[paragonIdx]
+		events := []*proto.BossTimelineEvent{default_ai.CastEvent(paragon.interval, paragon.interval, paragon.cast)}
+		phases := []*proto.BossTimelinePhase{{Name: paragon.name, Events: events}}
+
+		numParagons := len(klaxxiParagons)
+		if paragonIdx < numParagons-1 {
+			return &proto.BossTimeline{Phases: phases}
+		}
+
+		for deaths := 1; deaths < numParagons; deaths++ {
+			phaseEvents := append([]*proto.BossTimelineEvent{}, events...)
+
+			if joinIdx := deaths + klaxxiNumActiveParagons - 1; joinIdx < numParagons {
+				phaseEvents = append(phaseEvents, default_ai.SetTargetEnabledEvent(0, int32(joinIdx), true))
+			}
+			phaseEvents = append(phaseEvents, default_ai.SetTargetEnabledEvent(0.1, int32(deaths-1), false))
+
+			phases = append(phases, &proto.BossTimelinePhase{
+				Name:               fmt.Sprintf("%s Defeated", klaxxiParagons[deaths-1].name),
+				StartHealthPercent: 100 * float64(numParagons-deaths) / float64(numParagons),
+				Events:             phaseEvents,
+			})
+		}
+
+		return &proto.BossTimeline{Phases: phases}
+	}
+}
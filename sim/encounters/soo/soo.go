@@ -0,0 +1,21 @@
+// Package soo registers the 25H Siege of Orgrimmar presets.
+//
+// As in Throne of Thunder, each preset ends once the raid has dealt the
+// encounter's health. While the boss can't be attacked, as during Immerseus'
+// Splits, Garrosh's transitions and the Realm of Y'Shaarj, its timeline
+// disables it and leaves the adds as the only targets. Thok and the Iron
+// Juggernaut stand in for their phases without melee by slowing their swings.
+// The health and damage values are round guesses that were never fit against
+// logs.
+package soo
+
+func Register() {
+	addImmerseus("Siege of Orgrimmar")
+	addNorushen("Siege of Orgrimmar")
+	addGalakras("Siege of Orgrimmar")
+	addIronJuggernaut("Siege of Orgrimmar")
+	addThok("Siege of Orgrimmar")
+	addBlackfuse("Siege of Orgrimmar")
+	addParagons("Siege of Orgrimmar")
+	addGarrosh("Siege of Orgrimmar")
+}
@@ -0,0 +1,151 @@
+package soo
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// The hunt and Blood Frenzy phases are laid out up to this many seconds into
+// the fight, which covers any realistic kill.
+const thokTimelineHorizon = 1200
+
+// Seconds between Deafening Screeches, each of which adds a stack of
+// Acceleration.
+const thokScreechInterval = 8
+
+func addThok(raidPrefix string) {
+	createThokHeroicPreset(raidPrefix, 25, 1_300_000_000, 450_000)
+}
+
+func createThokHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64) {
+	bossName := fmt.Sprintf("Thok the Bloodthirsty %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              71529,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeBeast,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  thokTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(thokTimeline, nil),
+	})
+
+	core.AddPresetEncounter(bossName, []string{raidPrefix + "/" + bossName}).UseHealth = true
+}
+
+func thokTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Hunt duration",
+			Tooltip:     "Time (in seconds) Thok spends on the tanks before going into Blood Frenzy",
+			InputType:   proto.InputType_Number,
+			NumberValue: 120,
+		},
+		{
+			Label:       "Blood Frenzy duration",
+			Tooltip:     "Time (in seconds) the raid takes to free a prisoner and end Blood Frenzy",
+			InputType:   proto.InputType_Number,
+			NumberValue: 45,
+		},
+	}
+}
+
+// On the tanks, every Deafening Screech makes Thok faster. In Blood Frenzy he
+// chases a fixated player instead of meleeing the tanks, which is
+// approximated by slowing his swings to a crawl, until a freed prisoner
+// distracts him and the cycle starts over.
+func thokTimeline(config *proto.Target) *proto.BossTimeline {
+	huntDuration := max(default_ai.NumberInput(config, 0, 120), thokScreechInterval)
+	frenzyDuration := default_ai.NumberInput(config, 1, 45)
+
+	huntEvents := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(thokScreechInterval, thokScreechInterval, &proto.BossTimelineCast{
+			SpellId:      143343, // Deafening Screech
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    70_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		// Each stack refreshes the others, so Acceleration wears off shortly
+		// after the last Screech of the hunt.
+		{
+			Offset: thokScreechInterval,
+			Period: thokScreechInterval,
+			Count:  int32(huntDuration / thokScreechInterval),
+			Action: &proto.BossTimelineEvent_DamageDealtModifier{
+				DamageDealtModifier: &proto.BossTimelineDamageDealtModifier{
+					Multiplier:      1,
+					SpeedMultiplier: 1.05,
+					Duration:        thokScreechInterval,
+					MaxStacks:       int32(huntDuration / thokScreechInterval),
+				},
+			},
+		},
+		default_ai.CastEvent(12, 12, &proto.BossTimelineCast{
+			SpellId:      143426, // Fearsome Roar
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    250_000,
+			DamageSpread: 0.1,
+			Melee:        true,
+		}),
+		default_ai.TankSwapEvent(37, 36),
+	}
+
+	var frenzyEvents []*proto.BossTimelineEvent
+	if frenzyDuration > 0 {
+		frenzyEvents = []*proto.BossTimelineEvent{
+			default_ai.DamageDealtEvent(0, 0, &proto.BossTimelineDamageDealtModifier{
+				Multiplier:      1,
+				SpeedMultiplier: 0.01,
+				Duration:        frenzyDuration,
+			}),
+			default_ai.CastEvent(5, 10, &proto.BossTimelineCast{
+				SpellId:      143783, // Burning Blood
+				School:       proto.SpellSchool_SpellSchoolFire,
+				MinDamage:    120_000,
+				DamageSpread: 0.1,
+				Target:       proto.BossTimelineCast_RandomPlayer,
+			}),
+		}
+	}
+
+	var phases []*proto.BossTimelinePhase
+	for cycleStart, cycleIdx := 0.0, 1; cycleStart < thokTimelineHorizon; cycleStart, cycleIdx = cycleStart+huntDuration+frenzyDuration, cycleIdx+1 {
+		phases = append(phases, &proto.BossTimelinePhase{
+			Name:      fmt.Sprintf("Hunt %d", cycleIdx),
+			StartTime: cycleStart,
+			Events:    huntEvents,
+		})
+
+		if frenzyDuration > 0 {
+			phases = append(phases, &proto.BossTimelinePhase{
+				Name:      fmt.Sprintf("Blood Frenzy %d", cycleIdx),
+				StartTime: cycleStart + huntDuration,
+				Events:    frenzyEvents,
+			})
+		}
+	}
+
+	return &proto.BossTimeline{Phases: phases}
+}
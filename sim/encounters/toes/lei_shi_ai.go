@@ -0,0 +1,159 @@
+package toes
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// The Animated Protectors follow Lei Shi in the encounter targets.
+const leiShiProtectorIdx = 1
+
+func addLeiShi(raidPrefix string) {
+	createLeiShiHeroicPreset(raidPrefix, 25, 560_000_000, 320_000, 40_000_000)
+}
+
+func createLeiShiHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, protectorHealth float64) {
+	bossName := fmt.Sprintf("Lei Shi %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              62983,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeElemental,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolFrost,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  leiShiTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(leiShiTimeline, nil),
+	})
+
+	protectorName := fmt.Sprintf("Animated Protector %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        62995,
+			Name:      protectorName,
+			Level:     92,
+			MobType:   proto.MobType_MobTypeElemental,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health: protectorHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:      2.0,
+			MinBaseDamage:   bossMinBaseDamage / 2,
+			DamageSpread:    0.4,
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + protectorName,
+	})
+}
+
+func leiShiTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Hide duration",
+			Tooltip:     "Time (in seconds) the raid takes to find Lei Shi after she hides",
+			InputType:   proto.InputType_Number,
+			NumberValue: 8,
+		},
+		{
+			Label:       "Get Away! duration",
+			Tooltip:     "Time (in seconds) players spend pushing against Get Away!",
+			InputType:   proto.InputType_Number,
+			NumberValue: 12,
+		},
+		{
+			Label:       "Protector lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill the Animated Protectors",
+			InputType:   proto.InputType_Number,
+			NumberValue: 20,
+		},
+	}
+}
+
+// Lei Shi hides periodically, taking almost no damage until the raid finds
+// her. At every 20% she casts Get Away!, pushing the raid back, and wakes up
+// Animated Protectors.
+func leiShiTimeline(config *proto.Target) *proto.BossTimeline {
+	hideDuration := default_ai.NumberInput(config, 0, 8)
+	getAwayDuration := default_ai.NumberInput(config, 1, 12)
+	protectorLifetime := default_ai.NumberInput(config, 2, 20)
+
+	baseEvents := func() []*proto.BossTimelineEvent {
+		events := []*proto.BossTimelineEvent{
+			default_ai.CastEvent(4, 4, &proto.BossTimelineCast{
+				SpellId:      123121, // Spray
+				School:       proto.SpellSchool_SpellSchoolFrost,
+				MinDamage:    90_000,
+				DamageSpread: 0.1,
+				Melee:        true,
+			}),
+			default_ai.TankSwapEvent(30, 30),
+		}
+
+		if hideDuration > 0 {
+			events = append(events, default_ai.DamageTakenEvent(40, 45, &proto.BossTimelineDamageTakenModifier{
+				Multiplier: 0.01,
+				Duration:   hideDuration,
+			}))
+		}
+
+		return events
+	}
+
+	phases := []*proto.BossTimelinePhase{{Name: "Lei Shi", Events: baseEvents()}}
+	for healthPercent := 80.0; healthPercent > 0; healthPercent -= 20 {
+		events := append(baseEvents(),
+			default_ai.MovementEvent(0, 0, getAwayDuration),
+			default_ai.CastEvent(0, 0, &proto.BossTimelineCast{
+				SpellId:      123461, // Get Away!
+				School:       proto.SpellSchool_SpellSchoolFrost,
+				MinDamage:    20_000,
+				DamageSpread: 0.1,
+				Target:       proto.BossTimelineCast_Raid,
+				NumTicks:     int32(getAwayDuration),
+				TickInterval: 1,
+			}),
+			default_ai.SetTargetEnabledEvent(0, leiShiProtectorIdx, true),
+			default_ai.SetTargetEnabledEvent(protectorLifetime, leiShiProtectorIdx, false),
+		)
+
+		phases = append(phases, &proto.BossTimelinePhase{
+			Name:               fmt.Sprintf("Get Away! %.0f%%", healthPercent),
+			StartHealthPercent: healthPercent,
+			Events:             events,
+		})
+	}
+
+	return &proto.BossTimeline{Phases: phases}
+}
@@ -0,0 +1,174 @@
+package toes
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type endlessProtector struct {
+	name      string
+	npcID     int32
+	tankIndex int32
+	events    func() []*proto.BossTimelineEvent
+}
+
+// In reverse kill order, so that the last protector standing drives the
+// encounter.
+var endlessProtectors = []endlessProtector{
+	{
+		name:      "Protector Kaolan",
+		npcID:     60583,
+		tankIndex: 0,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(10, 10, &proto.BossTimelineCast{
+					SpellId:      117519, // Touch of Sha
+					School:       proto.SpellSchool_SpellSchoolShadow,
+					MinDamage:    25_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+					NumTicks:     10,
+					TickInterval: 3,
+				}),
+			}
+		},
+	},
+	{
+		name:      "Elder Regail",
+		npcID:     60585,
+		tankIndex: 1,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(5, 6, &proto.BossTimelineCast{
+					SpellId:      117187, // Lightning Bolt
+					School:       proto.SpellSchool_SpellSchoolNature,
+					MinDamage:    90_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+				}),
+				default_ai.CastEvent(20, 40, &proto.BossTimelineCast{
+					SpellId:      117986, // Lightning Prison
+					School:       proto.SpellSchool_SpellSchoolNature,
+					MinDamage:    60_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+				}),
+			}
+		},
+	},
+	{
+		name:      "Elder Asani",
+		npcID:     60586,
+		tankIndex: 1,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(4, 6, &proto.BossTimelineCast{
+					SpellId:      118312, // Water Bolt
+					School:       proto.SpellSchool_SpellSchoolFrost,
+					MinDamage:    80_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+				}),
+				// Players dodge the Cleansing Waters.
+				default_ai.MovementEvent(15, 32, 2),
+			}
+		},
+	},
+}
+
+func addProtectors(raidPrefix string) {
+	createProtectorsHeroicPreset(raidPrefix, 25, 230_000_000, 290_000)
+}
+
+func createProtectorsHeroicPreset(raidPrefix string, raidSize int32, protectorHealth float64, protectorMinBaseDamage float64) {
+	var targetPathNames []string
+
+	for idx, protector := range endlessProtectors {
+		name := fmt.Sprintf("%s %d H", protector.name, raidSize)
+
+		var targetInputs []*proto.TargetInput
+		if idx == 0 {
+			targetInputs = protectorsTargetInputs()
+		}
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        protector.npcID,
+				Name:      name,
+				Level:     93,
+				MobType:   proto.MobType_MobTypeHumanoid,
+				TankIndex: protector.tankIndex,
+
+				Stats: stats.Stats{
+					stats.Health:      protectorHealth,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:    2.0,
+				MinBaseDamage: protectorMinBaseDamage,
+				DamageSpread:  0.4,
+				TargetInputs:  targetInputs,
+			},
+
+			AI: default_ai.NewPresetTimelineAI(makeProtectorTimeline(idx), nil),
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(fmt.Sprintf("Protectors of the Endless %d H", raidSize), targetPathNames)
+}
+
+func protectorsTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Corrupted Essence multiplier",
+			Tooltip:     "Damage multiplier the surviving protectors gain each time one of them dies",
+			InputType:   proto.InputType_Number,
+			NumberValue: 1.3,
+		},
+	}
+}
+
+// The raid kills the last protector in the list at 66% and the next one at
+// 33%. Each time a protector dies the survivors get stronger, and Kaolan's
+// timeline takes the dead ones out of the fight.
+func makeProtectorTimeline(protectorIdx int) func(*proto.Target) *proto.BossTimeline {
+	return func(config *proto.Target) *proto.BossTimeline {
+		events := endlessProtectors[protectorIdx].events()
+		phases := []*proto.BossTimelinePhase{{Name: endlessProtectors[protectorIdx].name, Events: events}}
+
+		for deaths := 1; deaths < len(endlessProtectors); deaths++ {
+			deadIdx := len(endlessProtectors) - deaths
+			if protectorIdx >= deadIdx {
+				break
+			}
+
+			phaseEvents := append(endlessProtectors[protectorIdx].events(), default_ai.DamageDealtEvent(0, 0, &proto.BossTimelineDamageDealtModifier{
+				Multiplier: default_ai.NumberInput(config, 0, 1.3),
+			}))
+
+			if protectorIdx == 0 {
+				phaseEvents = append(phaseEvents, default_ai.SetTargetEnabledEvent(0, int32(deadIdx), false))
+			}
+
+			phases = append(phases, &proto.BossTimelinePhase{
+				Name:               fmt.Sprintf("%s Defeated", endlessProtectors[deadIdx].name),
+				StartHealthPercent: 100 * float64(len(endlessProtectors)-deaths) / float64(len(endlessProtectors)),
+				Events:             phaseEvents,
+			})
+		}
+
+		return &proto.BossTimeline{Phases: phases}
+	}
+}
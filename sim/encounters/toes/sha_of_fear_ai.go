@@ -0,0 +1,202 @@
+package toes
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// Indices of the adds that follow the Sha of Fear in the encounter targets.
+const (
+	shaOfFearGuardianIdx    = 1
+	shaOfFearTerrorSpawnIdx = 2
+)
+
+func addShaOfFear(raidPrefix string) {
+	createShaOfFearHeroicPreset(raidPrefix, 25, 1_100_000_000, 400_000, 50_000_000, 9_000_000)
+}
+
+func createShaOfFearHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, guardianHealth float64, terrorSpawnHealth float64) {
+	bossName := fmt.Sprintf("Sha of Fear %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        60999,
+			Name:      bossName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeElemental,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolShadow,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  shaOfFearTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(shaOfFearTimeline, nil),
+	})
+
+	// The platform guardian stands in for whichever of Yang Guoshi, Cheng
+	// Kang and Jinlun Kun the player is sent to.
+	guardianName := fmt.Sprintf("Platform Guardian %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        61038,
+			Name:      guardianName,
+			Level:     92,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health: guardianHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:      2.0,
+			MinBaseDamage:   bossMinBaseDamage / 2,
+			DamageSpread:    0.4,
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	terrorSpawnName := fmt.Sprintf("Terror Spawn %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:      61034,
+			Name:    terrorSpawnName,
+			Level:   92,
+			MobType: proto.MobType_MobTypeElemental,
+
+			Stats: stats.Stats{
+				stats.Health: terrorSpawnHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + guardianName,
+		raidPrefix + "/" + terrorSpawnName,
+	})
+}
+
+func shaOfFearTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Guardian kill time",
+			Tooltip:     "Time (in seconds) players sent out by Ominous Cackle spend on the platform before returning. Set to 0 to stay on the terrace.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 30,
+		},
+		{
+			Label:       "Dread Expanse travel time",
+			Tooltip:     "Time (in seconds) the raid spends moving to the Dread Expanse at 66%",
+			InputType:   proto.InputType_Number,
+			NumberValue: 10,
+		},
+	}
+}
+
+// On the terrace, Ominous Cackle sends players out to fight a platform
+// guardian. At 66% the whole raid moves to the Dread Expanse, where the Sha
+// submerges regularly and Terror Spawns keep coming.
+func shaOfFearTimeline(config *proto.Target) *proto.BossTimeline {
+	guardianKillTime := default_ai.NumberInput(config, 0, 30)
+	travelTime := default_ai.NumberInput(config, 1, 10)
+
+	thrash := default_ai.CastEvent(6, 6, &proto.BossTimelineCast{
+		SpellId:      131996, // Thrash
+		School:       proto.SpellSchool_SpellSchoolPhysical,
+		MinDamage:    450_000,
+		DamageSpread: 0.4,
+		Melee:        true,
+	})
+
+	terraceEvents := []*proto.BossTimelineEvent{
+		thrash,
+		default_ai.CastEvent(33, 33, &proto.BossTimelineCast{
+			SpellId:      119414, // Breath of Fear
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    150_000,
+			DamageSpread: 0.1,
+			CastTime:     1.5,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		// Players run into the Wall of Light to avoid Breath of Fear.
+		default_ai.MovementEvent(31, 33, 2),
+		default_ai.CastEvent(10, 25, &proto.BossTimelineCast{
+			SpellId:      119888, // Eerie Skull
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    110_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		}),
+	}
+
+	if guardianKillTime > 0 {
+		enable := default_ai.SetTargetEnabledEvent(45, shaOfFearGuardianIdx, true)
+		enable.Period = 90
+		disable := default_ai.SetTargetEnabledEvent(45+guardianKillTime, shaOfFearGuardianIdx, false)
+		disable.Period = 90
+		terraceEvents = append(terraceEvents, enable, disable)
+	}
+
+	spawnTerrors := default_ai.SetTargetEnabledEvent(travelTime+20, shaOfFearTerrorSpawnIdx, true)
+	spawnTerrors.Period = 40
+	killTerrors := default_ai.SetTargetEnabledEvent(travelTime+35, shaOfFearTerrorSpawnIdx, false)
+	killTerrors.Period = 40
+
+	expanseEvents := []*proto.BossTimelineEvent{
+		default_ai.MovementEvent(0, 0, travelTime),
+		default_ai.SetTargetEnabledEvent(0, shaOfFearGuardianIdx, false),
+		thrash,
+		spawnTerrors, killTerrors,
+		default_ai.CastEvent(travelTime+30, 52, &proto.BossTimelineCast{
+			SpellId:      120455, // Submerge
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    200_000,
+			DamageSpread: 0.1,
+			CastTime:     2,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.MovementEvent(travelTime+30, 52, 3),
+		default_ai.CastEvent(travelTime+15, 30, &proto.BossTimelineCast{
+			SpellId:      120629, // Huddle in Terror
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    160_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		}),
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Terrace of Endless Spring", Events: terraceEvents},
+			{Name: "Dread Expanse", StartHealthPercent: 66, Events: expanseEvents},
+		},
+	}
+}
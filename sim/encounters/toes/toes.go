@@ -0,0 +1,15 @@
+// Package toes registers the 25H Terrace of Endless Spring presets.
+//
+// The Protectors of the Endless die one at a time at set health thresholds,
+// Tsulong is replaced by the Day adds for half of every cycle, Lei Shi wakes
+// Animated Protectors at every 20%, and the Sha of Fear sends players out to
+// the platform guardians before the Dread Expanse. None of these numbers have
+// been fit against logs; they are round 25H values.
+package toes
+
+func Register() {
+	addProtectors("Terrace of Endless Spring")
+	addTsulong("Terrace of Endless Spring")
+	addLeiShi("Terrace of Endless Spring")
+	addShaOfFear("Terrace of Endless Spring")
+}
@@ -0,0 +1,158 @@
+package toes
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type tsulongAdd struct {
+	name   string
+	npcID  int32
+	health float64
+}
+
+// The Day adds follow Tsulong in the encounter targets.
+var tsulongAdds = []tsulongAdd{
+	{name: "Embodied Terror", npcID: 62969, health: 30_000_000},
+	{name: "Unstable Sha", npcID: 62919, health: 4_000_000},
+}
+
+const tsulongFirstAddIdx = 1
+
+// Night and Day each last half of the cycle.
+const tsulongCycleDuration = 242
+
+func addTsulong(raidPrefix string) {
+	createTsulongHeroicPreset(raidPrefix, 25, 520_000_000, 350_000)
+}
+
+func createTsulongHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64) {
+	bossName := fmt.Sprintf("Tsulong %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              62442,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeDragonkin,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  tsulongTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(tsulongTimeline, nil),
+	})
+
+	targetPathNames := []string{raidPrefix + "/" + bossName}
+
+	for _, add := range tsulongAdds {
+		name := fmt.Sprintf("%s %d H", add.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:      add.npcID,
+				Name:    name,
+				Level:   92,
+				MobType: proto.MobType_MobTypeDragonkin,
+
+				Stats: stats.Stats{
+					stats.Health: add.health,
+					stats.Armor:  24835,
+				}.ToProtoArray(),
+
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: true,
+			},
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(bossName, targetPathNames)
+}
+
+func tsulongTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Tank swap interval",
+			Tooltip:     "Time (in seconds) between tank swaps during the Night",
+			InputType:   proto.InputType_Number,
+			NumberValue: 30,
+		},
+	}
+}
+
+// Tsulong can only be attacked at Night. During the Day he is friendly and
+// the raid kills the Day adds instead. Night abilities that line up with the
+// Day are skipped since Tsulong is disabled.
+func tsulongTimeline(config *proto.Target) *proto.BossTimeline {
+	tankSwapInterval := default_ai.NumberInput(config, 0, 30)
+	halfCycle := float64(tsulongCycleDuration) / 2
+
+	events := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(10, 29, &proto.BossTimelineCast{
+			SpellId:      122752, // Shadow Breath
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    500_000,
+			DamageSpread: 0.1,
+			CastTime:     2,
+			Melee:        true,
+		}),
+		default_ai.CastEvent(15, 15, &proto.BossTimelineCast{
+			SpellId:      122770, // Nightmares
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    140_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		}),
+		default_ai.CastEvent(20, 2, &proto.BossTimelineCast{
+			SpellId:      122768, // Dread Shadows
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    4_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		// Players step into Sunbeam to clear Dread Shadows.
+		default_ai.MovementEvent(40, 40, 3),
+	}
+
+	if tankSwapInterval > 0 {
+		events = append(events, default_ai.TankSwapEvent(tankSwapInterval, tankSwapInterval))
+	}
+
+	var dawnEvents, duskEvents []*proto.BossTimelineEvent
+	for idx := range tsulongAdds {
+		dawnEvents = append(dawnEvents, default_ai.SetTargetEnabledEvent(halfCycle, int32(tsulongFirstAddIdx+idx), true))
+		duskEvents = append(duskEvents, default_ai.SetTargetEnabledEvent(tsulongCycleDuration+0.1, int32(tsulongFirstAddIdx+idx), false))
+	}
+	dawnEvents = append(dawnEvents, default_ai.SetTargetEnabledEvent(halfCycle+0.1, 0, false))
+	duskEvents = append(duskEvents, default_ai.SetTargetEnabledEvent(tsulongCycleDuration, 0, true))
+
+	for _, event := range append(dawnEvents, duskEvents...) {
+		event.Period = tsulongCycleDuration
+		events = append(events, event)
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{{Name: "Night and Day", Events: events}},
+	}
+}
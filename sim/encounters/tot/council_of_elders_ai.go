@@ -0,0 +1,218 @@
+package tot
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type councilElder struct {
+	name      string
+	npcID     int32
+	tankIndex int32
+	mobType   proto.MobType
+	events    func() []*proto.BossTimelineEvent
+}
+
+// In reverse kill order, so that the last elder standing drives the
+// encounter. Gara'jal possesses them in the same order.
+var councilElders = []councilElder{
+	{
+		name:      "Frost King Malakk",
+		npcID:     69131,
+		tankIndex: 0,
+		mobType:   proto.MobType_MobTypeHumanoid,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(10, 15, &proto.BossTimelineCast{
+					SpellId:      136190, // Frigid Assault
+					School:       proto.SpellSchool_SpellSchoolFrost,
+					MinDamage:    180_000,
+					DamageSpread: 0.1,
+					Melee:        true,
+				}),
+				default_ai.CastEvent(20, 30, &proto.BossTimelineCast{
+					SpellId:      136992, // Biting Cold
+					School:       proto.SpellSchool_SpellSchoolFrost,
+					MinDamage:    50_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+					NumTicks:     10,
+					TickInterval: 3,
+				}),
+			}
+		},
+	},
+	{
+		name:      "Kazra'jin",
+		npcID:     69134,
+		tankIndex: 1,
+		mobType:   proto.MobType_MobTypeHumanoid,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(15, 20, &proto.BossTimelineCast{
+					SpellId:      137122, // Reckless Charge
+					School:       proto.SpellSchool_SpellSchoolPhysical,
+					MinDamage:    90_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_Raid,
+				}),
+				// Players dodge the Reckless Charge landing.
+				default_ai.MovementEvent(14, 20, 2),
+			}
+		},
+	},
+	{
+		name:      "Sul the Sandcrawler",
+		npcID:     69078,
+		tankIndex: 1,
+		mobType:   proto.MobType_MobTypeHumanoid,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(5, 7, &proto.BossTimelineCast{
+					SpellId:      136895, // Sandstorm
+					School:       proto.SpellSchool_SpellSchoolNature,
+					MinDamage:    65_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+				}),
+				// Players leave the Quicksand.
+				default_ai.MovementEvent(30, 35, 3),
+			}
+		},
+	},
+	{
+		name:      "High Priestess Mar'li",
+		npcID:     69132,
+		tankIndex: 1,
+		mobType:   proto.MobType_MobTypeHumanoid,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(4, 6, &proto.BossTimelineCast{
+					SpellId:      137344, // Wrath of the Loa
+					School:       proto.SpellSchool_SpellSchoolShadow,
+					MinDamage:    90_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+				}),
+			}
+		},
+	},
+}
+
+func addCouncilOfElders(raidPrefix string) {
+	createCouncilOfEldersHeroicPreset(raidPrefix, 25, 330_000_000, 300_000)
+}
+
+func createCouncilOfEldersHeroicPreset(raidPrefix string, raidSize int32, elderHealth float64, elderMinBaseDamage float64) {
+	var targetPathNames []string
+
+	for idx, elder := range councilElders {
+		name := fmt.Sprintf("%s %d H", elder.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        elder.npcID,
+				Name:      name,
+				Level:     93,
+				MobType:   elder.mobType,
+				TankIndex: elder.tankIndex,
+
+				Stats: stats.Stats{
+					stats.Health:      elderHealth,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:    2.0,
+				MinBaseDamage: elderMinBaseDamage,
+				DamageSpread:  0.4,
+				TargetInputs:  councilOfEldersTargetInputs(),
+			},
+
+			AI: default_ai.NewPresetTimelineAI(makeCouncilElderTimeline(idx), nil),
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(fmt.Sprintf("Council of Elders %d H", raidSize), targetPathNames).UseHealth = true
+}
+
+func councilOfEldersTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Possession duration",
+			Tooltip:     "Time (in seconds) the raid takes to force Gara'jal's spirit out of each elder",
+			InputType:   proto.InputType_Number,
+			NumberValue: 40,
+		},
+	}
+}
+
+// Gara'jal's spirit possesses the living elders in turn, making them hit
+// harder and pulse Dark Power on the raid until the raid switches to them and
+// drives the spirit out. The raid kills the last elder in the list at 75%, and
+// so on, and every elder restarts the possession rotation when one dies.
+func makeCouncilElderTimeline(elderIdx int) func(*proto.Target) *proto.BossTimeline {
+	return func(config *proto.Target) *proto.BossTimeline {
+		possessionDuration := default_ai.NumberInput(config, 0, 40)
+		numElders := len(councilElders)
+
+		phaseEvents := func(numAlive int) []*proto.BossTimelineEvent {
+			events := councilElders[elderIdx].events()
+			if possessionDuration <= 0 {
+				return events
+			}
+
+			possessionStart := possessionDuration * float64(elderIdx)
+			possessionCycle := possessionDuration * float64(numAlive)
+
+			return append(events,
+				default_ai.DamageDealtEvent(possessionStart, possessionCycle, &proto.BossTimelineDamageDealtModifier{
+					Multiplier: 1.5,
+					Duration:   possessionDuration,
+				}),
+				default_ai.CastEvent(possessionStart, possessionCycle, &proto.BossTimelineCast{
+					SpellId:      136507, // Dark Power
+					School:       proto.SpellSchool_SpellSchoolShadow,
+					MinDamage:    20_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_Raid,
+					NumTicks:     int32(possessionDuration / 2),
+					TickInterval: 2,
+				}),
+			)
+		}
+
+		phases := []*proto.BossTimelinePhase{{Name: councilElders[elderIdx].name, Events: phaseEvents(numElders)}}
+
+		for deaths := 1; deaths < numElders; deaths++ {
+			deadIdx := numElders - deaths
+			if elderIdx >= deadIdx {
+				break
+			}
+
+			events := phaseEvents(deadIdx)
+			if elderIdx == 0 {
+				events = append(events, default_ai.SetTargetEnabledEvent(0, int32(deadIdx), false))
+			}
+
+			phases = append(phases, &proto.BossTimelinePhase{
+				Name:               fmt.Sprintf("%s Defeated", councilElders[deadIdx].name),
+				StartHealthPercent: 100 * float64(numElders-deaths) / float64(numElders),
+				Events:             events,
+			})
+		}
+
+		return &proto.BossTimeline{Phases: phases}
+	}
+}
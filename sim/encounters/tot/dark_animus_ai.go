@@ -0,0 +1,191 @@
+package tot
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type animaGolemTier struct {
+	name      string
+	npcID     int32
+	count     int
+	health    float64
+	tankIndex int32
+
+	// Fraction of the boss's melee damage, 0 for golems that don't melee.
+	damageFraction float64
+
+	// Default time (in seconds) the raid takes to kill the whole tier.
+	killTime float64
+}
+
+// The golems follow Dark Animus in the encounter targets, in the order the
+// raid kills them.
+var animaGolemTiers = []animaGolemTier{
+	{name: "Anima Golem", npcID: 69701, count: 8, health: 4_000_000, killTime: 20},
+	{name: "Large Anima Golem", npcID: 69700, count: 4, health: 40_000_000, tankIndex: 1, damageFraction: 0.5, killTime: 30},
+	{name: "Massive Anima Golem", npcID: 69699, count: 1, health: 150_000_000, tankIndex: 0, damageFraction: 1, killTime: 35},
+}
+
+func addDarkAnimus(raidPrefix string) {
+	createDarkAnimusHeroicPreset(raidPrefix, 25, 650_000_000, 400_000)
+}
+
+func createDarkAnimusHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64) {
+	bossName := fmt.Sprintf("Dark Animus %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        69427,
+			Name:      bossName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeElemental,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  darkAnimusTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(darkAnimusTimeline, nil),
+	})
+
+	targetPathNames := []string{raidPrefix + "/" + bossName}
+
+	for tierIdx, tier := range animaGolemTiers {
+		for golemIdx := 1; golemIdx <= tier.count; golemIdx++ {
+			name := fmt.Sprintf("%s %d H", tier.name, raidSize)
+			if tier.count > 1 {
+				name = fmt.Sprintf("%s - %d", name, golemIdx)
+			}
+
+			config := &proto.Target{
+				Id:      tier.npcID*100 + int32(golemIdx), // hack to guarantee distinct IDs for each golem
+				Name:    name,
+				Level:   92,
+				MobType: proto.MobType_MobTypeMechanical,
+
+				Stats: stats.Stats{
+					stats.Health: tier.health,
+					stats.Armor:  24835,
+				}.ToProtoArray(),
+
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: tierIdx > 0,
+			}
+
+			if tier.damageFraction > 0 {
+				config.TankIndex = tier.tankIndex
+				config.SpellSchool = proto.SpellSchool_SpellSchoolPhysical
+				config.SwingSpeed = 2.0
+				config.MinBaseDamage = bossMinBaseDamage * tier.damageFraction
+				config.DamageSpread = 0.4
+			}
+
+			core.AddPresetTarget(&core.PresetTarget{
+				PathPrefix: raidPrefix,
+				Config:     config,
+			})
+
+			targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+		}
+	}
+
+	core.AddPresetEncounter(bossName, targetPathNames).UseHealth = true
+}
+
+func darkAnimusTargetInputs() []*proto.TargetInput {
+	return core.MapSlice(animaGolemTiers, func(tier animaGolemTier) *proto.TargetInput {
+		return &proto.TargetInput{
+			Label:       tier.name + " kill time",
+			Tooltip:     fmt.Sprintf("Time (in seconds) the raid takes to kill every %s", tier.name),
+			InputType:   proto.InputType_Number,
+			NumberValue: tier.killTime,
+		}
+	})
+}
+
+// Dark Animus stays dormant while the raid works up through the golems, one
+// size at a time. Each tier dies one golem after the other over its kill
+// time, and the boss wakes up once the Massive Anima Golem is dead.
+func darkAnimusTimeline(config *proto.Target) *proto.BossTimeline {
+	var events []*proto.BossTimelineEvent
+	tierStart := 0.0
+	targetIdx := int32(1)
+
+	for tierIdx, tier := range animaGolemTiers {
+		killTime := default_ai.NumberInput(config, tierIdx, tier.killTime)
+
+		for golemIdx := 0; golemIdx < tier.count; golemIdx++ {
+			if tierIdx > 0 {
+				events = append(events, default_ai.SetTargetEnabledEvent(tierStart, targetIdx, true))
+			}
+
+			deathTime := tierStart + killTime*float64(golemIdx+1)/float64(tier.count)
+			events = append(events, default_ai.SetTargetEnabledEvent(deathTime, targetIdx, false))
+			targetIdx++
+		}
+
+		tierStart += killTime
+	}
+
+	events = append(events, default_ai.DamageTakenEvent(0, 0, &proto.BossTimelineDamageTakenModifier{
+		Multiplier: 0.01,
+		Duration:   tierStart,
+	}))
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Anima Golems", Events: events},
+			{
+				Name:      "Dark Animus",
+				StartTime: tierStart,
+				Events: []*proto.BossTimelineEvent{
+					default_ai.CastEvent(5, 25, &proto.BossTimelineCast{
+						SpellId:      138763, // Interrupting Jolt
+						School:       proto.SpellSchool_SpellSchoolNature,
+						MinDamage:    250_000,
+						DamageSpread: 0.1,
+						CastTime:     2.2,
+						Target:       proto.BossTimelineCast_Raid,
+					}),
+					default_ai.CastEvent(15, 30, &proto.BossTimelineCast{
+						SpellId:      138644, // Siphon Anima
+						School:       proto.SpellSchool_SpellSchoolShadow,
+						MinDamage:    60_000,
+						DamageSpread: 0.1,
+						Target:       proto.BossTimelineCast_Raid,
+					}),
+					default_ai.CastEvent(10, 20, &proto.BossTimelineCast{
+						SpellId:      138569, // Explosive Slam
+						School:       proto.SpellSchool_SpellSchoolPhysical,
+						MinDamage:    350_000,
+						DamageSpread: 0.1,
+						Melee:        true,
+					}),
+					// Players dodge the Anima Font orbs.
+					default_ai.MovementEvent(20, 30, 2),
+					// Full Power soft enrage once the boss reaches full energy.
+					default_ai.DamageDealtEvent(120, 10, &proto.BossTimelineDamageDealtModifier{
+						Multiplier: 1.2,
+						MaxStacks:  20,
+					}),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,178 @@
+package tot
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type durumuFog struct {
+	name  string
+	npcID int32
+}
+
+// The fogs revealed by Light Spectrum follow Durumu in the encounter targets.
+var durumuFogs = []durumuFog{
+	{name: "Crimson Fog", npcID: 69050},
+	{name: "Amber Fog", npcID: 69051},
+	{name: "Azure Fog", npcID: 69052},
+}
+
+const durumuFirstFogIdx = 1
+
+// Light Spectrum and Disintegration Beam each come up once per cycle.
+const (
+	durumuCycleDuration = 190
+	durumuSpectrumTime  = 40
+	durumuBeamTime      = 135
+	durumuBeamDuration  = 55
+)
+
+func addDurumu(raidPrefix string) {
+	createDurumuHeroicPreset(raidPrefix, 25, 900_000_000, 380_000, 9_000_000)
+}
+
+func createDurumuHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, fogHealth float64) {
+	bossName := fmt.Sprintf("Durumu the Forgotten %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        68036,
+			Name:      bossName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeUnknown,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolShadow,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  durumuTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(durumuTimeline, nil),
+	})
+
+	targetPathNames := []string{raidPrefix + "/" + bossName}
+
+	for _, fog := range durumuFogs {
+		name := fmt.Sprintf("%s %d H", fog.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:      fog.npcID,
+				Name:    name,
+				Level:   92,
+				MobType: proto.MobType_MobTypeElemental,
+
+				Stats: stats.Stats{
+					stats.Health: fogHealth,
+					stats.Armor:  24835,
+				}.ToProtoArray(),
+
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: true,
+			},
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(bossName, targetPathNames).UseHealth = true
+}
+
+func durumuTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Fog lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill the fogs revealed by Light Spectrum",
+			InputType:   proto.InputType_Number,
+			NumberValue: 25,
+		},
+		{
+			Label:       "Maze movement",
+			Tooltip:     "Fraction of the Disintegration Beam the player spends moving through the maze",
+			InputType:   proto.InputType_Number,
+			NumberValue: 0.6,
+		},
+	}
+}
+
+// Each cycle Light Spectrum reveals the fogs, and Disintegration Beam forces
+// the raid through the maze. Force of Will and Lingering Gaze add short
+// movement windows in between.
+func durumuTimeline(config *proto.Target) *proto.BossTimeline {
+	fogLifetime := default_ai.NumberInput(config, 0, 25)
+	mazeMovement := default_ai.NumberInput(config, 1, 0.6)
+
+	events := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(5, 12, &proto.BossTimelineCast{
+			SpellId:      133765, // Hard Stare
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    300_000,
+			DamageSpread: 0.1,
+			Melee:        true,
+		}),
+		default_ai.CastEvent(15, 20, &proto.BossTimelineCast{
+			SpellId:      136413, // Force of Will
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    100_000,
+			DamageSpread: 0.1,
+			CastTime:     2,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		}),
+		default_ai.MovementEvent(15, 20, 2),
+		default_ai.CastEvent(25, 45, &proto.BossTimelineCast{
+			SpellId:      138467, // Lingering Gaze
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    80_000,
+			DamageSpread: 0.1,
+			CastTime:     1.5,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.MovementEvent(26.5, 45, 3),
+	}
+
+	if fogLifetime > 0 {
+		for idx := range durumuFogs {
+			reveal := default_ai.SetTargetEnabledEvent(durumuSpectrumTime, int32(durumuFirstFogIdx+idx), true)
+			reveal.Period = durumuCycleDuration
+			kill := default_ai.SetTargetEnabledEvent(durumuSpectrumTime+fogLifetime, int32(durumuFirstFogIdx+idx), false)
+			kill.Period = durumuCycleDuration
+			events = append(events, reveal, kill)
+		}
+	}
+
+	events = append(events,
+		default_ai.CastEvent(durumuBeamTime, durumuCycleDuration, &proto.BossTimelineCast{
+			SpellId:      133776, // Disintegration Beam
+			School:       proto.SpellSchool_SpellSchoolShadow,
+			MinDamage:    30_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+			NumTicks:     durumuBeamDuration,
+			TickInterval: 1,
+		}),
+	)
+
+	if mazeMovement > 0 {
+		events = append(events, default_ai.MovementEvent(durumuBeamTime, durumuCycleDuration, durumuBeamDuration*min(mazeMovement, 1)))
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{{Name: "Durumu", Events: events}},
+	}
+}
@@ -0,0 +1,217 @@
+package tot
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type horridonDoor struct {
+	name   string
+	npcID  int32
+	health float64
+}
+
+// Each door stands in for the adds of its tribe, in the order the doors open.
+var horridonDoors = []horridonDoor{
+	{name: "Farraki Wastewalker", npcID: 69175, health: 7_000_000},
+	{name: "Gurubashi Venom Priest", npcID: 69164, health: 7_000_000},
+	{name: "Risen Drakkari Champion", npcID: 69184, health: 5_000_000},
+	{name: "Amani'shi Beast Shaman", npcID: 69176, health: 8_000_000},
+}
+
+// The door adds and War-God Jalak follow Horridon in the encounter targets.
+const (
+	horridonFirstDoorIdx = 1
+	horridonJalakIdx     = horridonFirstDoorIdx + 4
+)
+
+// Seconds between add waves while a door is open.
+const horridonWaveInterval = 19
+
+func addHorridon(raidPrefix string) {
+	createHorridonHeroicPreset(raidPrefix, 25, 1_100_000_000, 400_000, 80_000_000)
+}
+
+func createHorridonHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, jalakHealth float64) {
+	bossName := fmt.Sprintf("Horridon %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              68476,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeBeast,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  horridonTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(horridonTimeline, nil),
+	})
+
+	targetPathNames := []string{raidPrefix + "/" + bossName}
+
+	for _, door := range horridonDoors {
+		name := fmt.Sprintf("%s %d H", door.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:      door.npcID,
+				Name:    name,
+				Level:   92,
+				MobType: proto.MobType_MobTypeHumanoid,
+
+				Stats: stats.Stats{
+					stats.Health: door.health,
+					stats.Armor:  24835,
+				}.ToProtoArray(),
+
+				TargetInputs:    []*proto.TargetInput{},
+				DisabledAtStart: true,
+			},
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	jalakName := fmt.Sprintf("War-God Jalak %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        69374,
+			Name:      jalakName,
+			Level:     93,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health: jalakHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:      2.0,
+			MinBaseDamage:   bossMinBaseDamage / 2,
+			DamageSpread:    0.4,
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	targetPathNames = append(targetPathNames, raidPrefix+"/"+jalakName)
+
+	core.AddPresetEncounter(bossName, targetPathNames).UseHealth = true
+}
+
+func horridonTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Door duration",
+			Tooltip:     "Time (in seconds) each tribal door stays open",
+			InputType:   proto.InputType_Number,
+			NumberValue: 110,
+		},
+		{
+			Label:       "Add lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill each wave of door adds",
+			InputType:   proto.InputType_Number,
+			NumberValue: 12,
+		},
+		{
+			Label:       "Jalak lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill War-God Jalak after the last door",
+			InputType:   proto.InputType_Number,
+			NumberValue: 40,
+		},
+	}
+}
+
+// The tribal doors open one after the other, each sending a wave of adds
+// every 19 seconds. War-God Jalak jumps down after the last door, and Horridon
+// goes into Rampage once Jalak dies.
+func horridonTimeline(config *proto.Target) *proto.BossTimeline {
+	doorDuration := default_ai.NumberInput(config, 0, 110)
+	addLifetime := default_ai.NumberInput(config, 1, 12)
+	jalakLifetime := default_ai.NumberInput(config, 2, 40)
+
+	bossEvents := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(10, 11, &proto.BossTimelineCast{
+			SpellId:      136767, // Triple Puncture
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    450_000,
+			DamageSpread: 0.1,
+			Melee:        true,
+		}),
+		default_ai.CastEvent(17, 17, &proto.BossTimelineCast{
+			SpellId:      136741, // Double Swipe
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    350_000,
+			DamageSpread: 0.1,
+			CastTime:     1.5,
+			Melee:        true,
+		}),
+		default_ai.CastEvent(62, 62, &proto.BossTimelineCast{
+			SpellId:      137458, // Dire Call
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    120_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.TankSwapEvent(33, 33),
+	}
+
+	doorEvents := slices.Clone(bossEvents)
+	numWaves := max(int32(doorDuration/horridonWaveInterval), 1)
+	for idx := range horridonDoors {
+		doorStart := doorDuration * float64(idx)
+		targetIdx := int32(horridonFirstDoorIdx + idx)
+
+		spawn := default_ai.SetTargetEnabledEvent(doorStart, targetIdx, true)
+		spawn.Period = horridonWaveInterval
+		spawn.Count = numWaves
+		despawn := default_ai.SetTargetEnabledEvent(doorStart+addLifetime, targetIdx, false)
+		despawn.Period = horridonWaveInterval
+		despawn.Count = numWaves
+		doorEvents = append(doorEvents, spawn, despawn)
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Tribal Doors", Events: doorEvents},
+			{
+				Name:      "War-God Jalak",
+				StartTime: doorDuration * float64(len(horridonDoors)),
+				Events: append(bossEvents,
+					default_ai.SetTargetEnabledEvent(0, horridonJalakIdx, true),
+					default_ai.SetTargetEnabledEvent(jalakLifetime, horridonJalakIdx, false),
+					default_ai.DamageDealtEvent(jalakLifetime, 0, &proto.BossTimelineDamageDealtModifier{
+						Multiplier:      1.5,
+						SpeedMultiplier: 1.5,
+					}),
+				),
+			},
+		},
+	}
+}
@@ -0,0 +1,195 @@
+package tot
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+type ironQonTarget struct {
+	name    string
+	npcID   int32
+	mobType proto.MobType
+	events  func() []*proto.BossTimelineEvent
+}
+
+// Iron Qon rides each of his quilen in turn before fighting on foot.
+var ironQonTargets = []ironQonTarget{
+	{
+		name:    "Ro'shak",
+		npcID:   68079,
+		mobType: proto.MobType_MobTypeBeast,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(2, 2, &proto.BossTimelineCast{
+					SpellId:      134628, // Unleashed Flame
+					School:       proto.SpellSchool_SpellSchoolFire,
+					MinDamage:    30_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_Raid,
+				}),
+				// Players leave the Burning Cinders.
+				default_ai.MovementEvent(20, 20, 2),
+			}
+		},
+	},
+	{
+		name:    "Quet'zal",
+		npcID:   68080,
+		mobType: proto.MobType_MobTypeBeast,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(10, 20, &proto.BossTimelineCast{
+					SpellId:      136192, // Lightning Storm
+					School:       proto.SpellSchool_SpellSchoolNature,
+					MinDamage:    120_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+				}),
+				// Players spread out of the Windstorm.
+				default_ai.MovementEvent(50, 70, 10),
+			}
+		},
+	},
+	{
+		name:    "Dam'ren",
+		npcID:   68081,
+		mobType: proto.MobType_MobTypeBeast,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(10, 2, &proto.BossTimelineCast{
+					SpellId:      137669, // Arcing Lightning
+					School:       proto.SpellSchool_SpellSchoolNature,
+					MinDamage:    20_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_RandomPlayer,
+				}),
+				default_ai.CastEvent(30, 50, &proto.BossTimelineCast{
+					SpellId:      134926, // Freeze
+					School:       proto.SpellSchool_SpellSchoolFrost,
+					MinDamage:    150_000,
+					DamageSpread: 0.1,
+					Target:       proto.BossTimelineCast_Raid,
+				}),
+			}
+		},
+	},
+	{
+		name:    "Iron Qon",
+		npcID:   68078,
+		mobType: proto.MobType_MobTypeHumanoid,
+		events: func() []*proto.BossTimelineEvent {
+			return []*proto.BossTimelineEvent{
+				default_ai.CastEvent(20, 20, &proto.BossTimelineCast{
+					SpellId:      136147, // Fist Smash
+					School:       proto.SpellSchool_SpellSchoolPhysical,
+					MinDamage:    200_000,
+					DamageSpread: 0.1,
+					CastTime:     2,
+					Target:       proto.BossTimelineCast_Raid,
+					NumTicks:     4,
+					TickInterval: 1.5,
+				}),
+			}
+		},
+	},
+}
+
+func addIronQon(raidPrefix string) {
+	createIronQonHeroicPreset(raidPrefix, 25, 300_000_000, 400_000)
+}
+
+func createIronQonHeroicPreset(raidPrefix string, raidSize int32, health float64, minBaseDamage float64) {
+	var targetPathNames []string
+
+	for idx, target := range ironQonTargets {
+		name := fmt.Sprintf("%s %d H", target.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:              target.npcID,
+				Name:            name,
+				Level:           93,
+				MobType:         target.mobType,
+				TankIndex:       0,
+				SecondTankIndex: 1,
+
+				Stats: stats.Stats{
+					stats.Health:      health,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:     proto.SpellSchool_SpellSchoolPhysical,
+				SwingSpeed:      2.0,
+				MinBaseDamage:   minBaseDamage,
+				DamageSpread:    0.4,
+				TargetInputs:    ironQonTargetInputs(),
+				DisabledAtStart: idx > 0,
+			},
+
+			AI: default_ai.NewPresetTimelineAI(makeIronQonTimeline(idx), nil),
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(fmt.Sprintf("Iron Qon %d H", raidSize), targetPathNames).UseHealth = true
+}
+
+func ironQonTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Tank swap interval",
+			Tooltip:     "Time (in seconds) between tank swaps for Impale",
+			InputType:   proto.InputType_Number,
+			NumberValue: 20,
+		},
+	}
+}
+
+// Only the current mount can be attacked, and Ro'shak's timeline moves the
+// raid to the next one each time the encounter loses a quarter of its health.
+// Impale keeps hitting the tank whichever quilen Qon is riding.
+func makeIronQonTimeline(targetIdx int) func(*proto.Target) *proto.BossTimeline {
+	return func(config *proto.Target) *proto.BossTimeline {
+		events := append(ironQonTargets[targetIdx].events(), default_ai.CastEvent(15, 20, &proto.BossTimelineCast{
+			SpellId:      134691, // Impale
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    150_000,
+			DamageSpread: 0.1,
+			NumTicks:     20,
+			TickInterval: 1,
+		}))
+
+		tankSwapInterval := default_ai.NumberInput(config, 0, 20)
+		if tankSwapInterval > 0 {
+			events = append(events, default_ai.TankSwapEvent(tankSwapInterval, tankSwapInterval))
+		}
+
+		if targetIdx > 0 {
+			return &proto.BossTimeline{
+				Phases: []*proto.BossTimelinePhase{{Name: ironQonTargets[targetIdx].name, Events: events}},
+			}
+		}
+
+		phases := []*proto.BossTimelinePhase{{Name: ironQonTargets[0].name, Events: events}}
+		for idx := 1; idx < len(ironQonTargets); idx++ {
+			phases = append(phases, &proto.BossTimelinePhase{
+				Name:               ironQonTargets[idx].name,
+				StartHealthPercent: 100 * float64(len(ironQonTargets)-idx) / float64(len(ironQonTargets)),
+				Events: []*proto.BossTimelineEvent{
+					default_ai.SetTargetEnabledEvent(0, int32(idx), true),
+					default_ai.SetTargetEnabledEvent(0.1, int32(idx-1), false),
+				},
+			})
+		}
+
+		return &proto.BossTimeline{Phases: phases}
+	}
+}
@@ -0,0 +1,140 @@
+package tot
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// The hatchlings of the active nest follow Ji-Kun in the encounter targets.
+const jiKunHatchlingIdx = 1
+
+func addJiKun(raidPrefix string) {
+	createJiKunHeroicPreset(raidPrefix, 25, 900_000_000, 380_000, 6_000_000)
+}
+
+func createJiKunHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, hatchlingHealth float64) {
+	bossName := fmt.Sprintf("Ji-Kun %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              69712,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeBeast,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  jiKunTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(jiKunTimeline, nil),
+	})
+
+	hatchlingName := fmt.Sprintf("Hatchling %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:      68192,
+			Name:    hatchlingName,
+			Level:   92,
+			MobType: proto.MobType_MobTypeBeast,
+
+			Stats: stats.Stats{
+				stats.Health: hatchlingHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + hatchlingName,
+	}).UseHealth = true
+}
+
+func jiKunTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:     "Nest duty",
+			Tooltip:   "Whether this player is part of a nest team, flying off to kill the hatchlings each time a nest hatches",
+			InputType: proto.InputType_Bool,
+			BoolValue: false,
+		},
+		{
+			Label:       "Nest duration",
+			Tooltip:     "Time (in seconds) a nest team spends away from the platform",
+			InputType:   proto.InputType_Number,
+			NumberValue: 20,
+		},
+	}
+}
+
+// A nest hatches every 30 seconds. With nest duty enabled the flight out
+// moves the player and the hatchlings can be attacked until the team comes
+// back. Down Draft and Quills hit the platform regardless.
+func jiKunTimeline(config *proto.Target) *proto.BossTimeline {
+	nestDuty := default_ai.BoolInput(config, 0, false)
+	nestDuration := default_ai.NumberInput(config, 1, 20)
+
+	events := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(24, 30, &proto.BossTimelineCast{
+			SpellId:      134366, // Talon Rake
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    400_000,
+			DamageSpread: 0.1,
+			Melee:        true,
+		}),
+		default_ai.TankSwapEvent(25, 30),
+		default_ai.CastEvent(18, 18, &proto.BossTimelineCast{
+			SpellId:      138923, // Caw
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    90_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+		}),
+		default_ai.CastEvent(60, 60, &proto.BossTimelineCast{
+			SpellId:      134380, // Quills
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    60_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+			NumTicks:     10,
+			TickInterval: 1,
+		}),
+		// Players walk against Down Draft.
+		default_ai.MovementEvent(90, 97, 8),
+	}
+
+	if nestDuty && (nestDuration > 0) {
+		enterNest := default_ai.SetTargetEnabledEvent(30, jiKunHatchlingIdx, true)
+		enterNest.Period = 30
+		leaveNest := default_ai.SetTargetEnabledEvent(30+nestDuration, jiKunHatchlingIdx, false)
+		leaveNest.Period = 30
+		events = append(events, enterNest, leaveNest, default_ai.MovementEvent(28, 30, 3))
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{{Name: "Ji-Kun", Events: events}},
+	}
+}
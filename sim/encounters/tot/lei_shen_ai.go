@@ -0,0 +1,187 @@
+package tot
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// Lei Shen can't be attacked during the intermissions, where the Ball
+// Lightnings that follow him in the encounter targets are the only thing left
+// to hit.
+const leiShenBallLightningIdx = 1
+
+func addLeiShen(raidPrefix string) {
+	createLeiShenHeroicPreset(raidPrefix, 25, 1_500_000_000, 450_000, 5_000_000)
+}
+
+func createLeiShenHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, ballLightningHealth float64) {
+	bossName := fmt.Sprintf("Lei Shen %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              68397,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeHumanoid,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  leiShenTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(leiShenTimeline, nil),
+	})
+
+	ballLightningName := fmt.Sprintf("Ball Lightning %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:      69232,
+			Name:    ballLightningName,
+			Level:   92,
+			MobType: proto.MobType_MobTypeElemental,
+
+			Stats: stats.Stats{
+				stats.Health: ballLightningHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + ballLightningName,
+	}).UseHealth = true
+}
+
+func leiShenTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Intermission duration",
+			Tooltip:     "Time (in seconds) Lei Shen spends out of reach during each intermission",
+			InputType:   proto.InputType_Number,
+			NumberValue: 45,
+		},
+	}
+}
+
+// Lei Shen leaves for an intermission at 65% and 30%, and comes back with a
+// new set of abilities after each one.
+func leiShenTimeline(config *proto.Target) *proto.BossTimeline {
+	intermissionDuration := default_ai.NumberInput(config, 0, 45)
+
+	// Starts the phase with an intermission, delaying its abilities until
+	// Lei Shen is back.
+	afterIntermission := func(events ...*proto.BossTimelineEvent) []*proto.BossTimelineEvent {
+		for _, event := range events {
+			event.Offset += intermissionDuration
+		}
+
+		return append([]*proto.BossTimelineEvent{
+			default_ai.SetTargetEnabledEvent(0, leiShenBallLightningIdx, true),
+			default_ai.SetTargetEnabledEvent(0.1, 0, false),
+			default_ai.SetTargetEnabledEvent(intermissionDuration, 0, true),
+			default_ai.SetTargetEnabledEvent(intermissionDuration+0.1, leiShenBallLightningIdx, false),
+		}, events...)
+	}
+
+	thunderstruck := func() *proto.BossTimelineEvent {
+		return default_ai.CastEvent(25, 46, &proto.BossTimelineCast{
+			SpellId:      135095, // Thunderstruck
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    250_000,
+			DamageSpread: 0.1,
+			CastTime:     2,
+			Target:       proto.BossTimelineCast_Raid,
+		})
+	}
+	lightningWhip := func() []*proto.BossTimelineEvent {
+		return []*proto.BossTimelineEvent{
+			default_ai.CastEvent(30, 46, &proto.BossTimelineCast{
+				SpellId:      136850, // Lightning Whip
+				School:       proto.SpellSchool_SpellSchoolNature,
+				MinDamage:    300_000,
+				DamageSpread: 0.1,
+				CastTime:     2,
+				Target:       proto.BossTimelineCast_RandomPlayer,
+			}),
+			default_ai.MovementEvent(30, 46, 2),
+		}
+	}
+	summonBallLightning := func() *proto.BossTimelineEvent {
+		return default_ai.CastEvent(15, 46, &proto.BossTimelineCast{
+			SpellId:      136543, // Summon Ball Lightning
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    90_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		})
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{
+				Name: "Phase 1",
+				Events: []*proto.BossTimelineEvent{
+					default_ai.CastEvent(40, 50, &proto.BossTimelineCast{
+						SpellId:      134912, // Decapitate
+						School:       proto.SpellSchool_SpellSchoolNature,
+						MinDamage:    600_000,
+						DamageSpread: 0.1,
+						Melee:        true,
+					}),
+					default_ai.TankSwapEvent(41, 50),
+					thunderstruck(),
+					// Players leave the Crashing Thunder pools.
+					default_ai.MovementEvent(10, 30, 2),
+				},
+			},
+			{
+				Name:               "Phase 2",
+				StartHealthPercent: 65,
+				Events: afterIntermission(append(lightningWhip(),
+					default_ai.CastEvent(20, 42, &proto.BossTimelineCast{
+						SpellId:      135695, // Fusion Slash
+						School:       proto.SpellSchool_SpellSchoolNature,
+						MinDamage:    500_000,
+						DamageSpread: 0.1,
+						Melee:        true,
+					}),
+					default_ai.TankSwapEvent(21, 42),
+					summonBallLightning(),
+				)...),
+			},
+			{
+				Name:               "Phase 3",
+				StartHealthPercent: 30,
+				Events: afterIntermission(append(lightningWhip(),
+					thunderstruck(),
+					summonBallLightning(),
+					// Players push against the Violent Gale Winds.
+					default_ai.MovementEvent(20, 30, 4),
+				)...),
+			},
+		},
+	}
+}
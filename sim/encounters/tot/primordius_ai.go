@@ -0,0 +1,146 @@
+package tot
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// The Living Fluids follow Primordius in the encounter targets.
+const primordiusLivingFluidIdx = 1
+
+func addPrimordius(raidPrefix string) {
+	createPrimordiusHeroicPreset(raidPrefix, 25, 1_000_000_000, 420_000, 2_500_000)
+}
+
+func createPrimordiusHeroicPreset(raidPrefix string, raidSize int32, bossHealth float64, bossMinBaseDamage float64, livingFluidHealth float64) {
+	bossName := fmt.Sprintf("Primordius %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              69017,
+			Name:            bossName,
+			Level:           93,
+			MobType:         proto.MobType_MobTypeElemental,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       24835,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolNature,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  primordiusTargetInputs(),
+		},
+
+		AI: default_ai.NewPresetTimelineAI(primordiusTimeline, nil),
+	})
+
+	livingFluidName := fmt.Sprintf("Living Fluid %d H", raidSize)
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:      69069,
+			Name:    livingFluidName,
+			Level:   92,
+			MobType: proto.MobType_MobTypeElemental,
+
+			Stats: stats.Stats{
+				stats.Health: livingFluidHealth,
+				stats.Armor:  24835,
+			}.ToProtoArray(),
+
+			TargetInputs:    []*proto.TargetInput{},
+			DisabledAtStart: true,
+		},
+	})
+
+	core.AddPresetEncounter(bossName, []string{
+		raidPrefix + "/" + bossName,
+		raidPrefix + "/" + livingFluidName,
+	}).UseHealth = true
+}
+
+func primordiusTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Living Fluid lifetime",
+			Tooltip:     "Time (in seconds) the raid takes to kill each wave of Living Fluids",
+			InputType:   proto.InputType_Number,
+			NumberValue: 8,
+		},
+		{
+			Label:       "Tank swap stacks",
+			Tooltip:     "Stacks of Malformed Blood at which the tanks swap",
+			InputType:   proto.InputType_Number,
+			NumberValue: 6,
+		},
+	}
+}
+
+// Living Fluids pour out every 32 seconds. Primordius gains an Evolution each
+// time he reaches full energy, which the raid usually lets stack up a few
+// times before killing the fluids into Viscous Horror.
+func primordiusTimeline(config *proto.Target) *proto.BossTimeline {
+	livingFluidLifetime := default_ai.NumberInput(config, 0, 8)
+	tankSwapStacks := default_ai.NumberInput(config, 1, 6)
+
+	events := []*proto.BossTimelineEvent{
+		default_ai.CastEvent(19, 19, &proto.BossTimelineCast{
+			SpellId:      136037, // Primordial Strike
+			School:       proto.SpellSchool_SpellSchoolPhysical,
+			MinDamage:    400_000,
+			DamageSpread: 0.1,
+			Melee:        true,
+		}),
+		default_ai.CastEvent(26, 26, &proto.BossTimelineCast{
+			SpellId:      136216, // Caustic Gas
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    250_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_Raid,
+		}),
+		default_ai.CastEvent(28, 28, &proto.BossTimelineCast{
+			SpellId:      136228, // Volatile Pathogen
+			School:       proto.SpellSchool_SpellSchoolNature,
+			MinDamage:    45_000,
+			DamageSpread: 0.1,
+			Target:       proto.BossTimelineCast_RandomPlayer,
+			NumTicks:     10,
+			TickInterval: 2,
+		}),
+		default_ai.DamageDealtEvent(55, 55, &proto.BossTimelineDamageDealtModifier{
+			Multiplier: 1.1,
+			MaxStacks:  5,
+		}),
+	}
+
+	// Malformed Blood stacks on every Primordial Strike.
+	if tankSwapStacks > 0 {
+		events = append(events, default_ai.TankSwapEvent(19*tankSwapStacks, 19*tankSwapStacks))
+	}
+
+	if livingFluidLifetime > 0 {
+		spawn := default_ai.SetTargetEnabledEvent(10, primordiusLivingFluidIdx, true)
+		spawn.Period = 32
+		kill := default_ai.SetTargetEnabledEvent(10+livingFluidLifetime, primordiusLivingFluidIdx, false)
+		kill.Period = 32
+		events = append(events, spawn, kill)
+	}
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{{Name: "Primordius", Events: events}},
+	}
+}
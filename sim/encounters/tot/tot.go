@@ -0,0 +1,21 @@
+// Package tot registers the 25H Throne of Thunder presets.
+//
+// Every preset is health based: the fight lasts until the raid has dealt the
+// combined health of the boss and its adds rather than a set duration. The
+// Council of Elders, Iron Qon and Lei Shen change phase at health thresholds,
+// while Horridon's doors, Dark Animus' golems and the adds of the other fights
+// follow fixed timings. Health and damage values are round 25H estimates, so
+// kill times will drift until they are fit against logs.
+package tot
+
+func Register() {
+	addHorridon("Throne of Thunder")
+	addCouncilOfElders("Throne of Thunder")
+	addJiKun("Throne of Thunder")
+	addDurumu("Throne of Thunder")
+	addPrimordius("Throne of Thunder")
+	addDarkAnimus("Throne of Thunder")
+	addIronQon("Throne of Thunder")
+	addTwinConsorts("Throne of Thunder")
+	addLeiShen("Throne of Thunder")
+}
@@ -0,0 +1,182 @@
+package tot
+
+import (
+	"fmt"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/stats"
+	"github.com/wowsims/mop/sim/encounters/default_ai"
+)
+
+// Lu'lin follows Suen in the encounter targets.
+const twinConsortsLulinIdx = 1
+
+func addTwinConsorts(raidPrefix string) {
+	createTwinConsortsHeroicPreset(raidPrefix, 25, 520_000_000, 380_000)
+}
+
+func createTwinConsortsHeroicPreset(raidPrefix string, raidSize int32, consortHealth float64, consortMinBaseDamage float64) {
+	var targetPathNames []string
+
+	consorts := []struct {
+		name     string
+		npcID    int32
+		school   proto.SpellSchool
+		timeline func(*proto.Target) *proto.BossTimeline
+	}{
+		{name: "Suen", npcID: 68904, school: proto.SpellSchool_SpellSchoolFire, timeline: suenTimeline},
+		{name: "Lu'lin", npcID: 68905, school: proto.SpellSchool_SpellSchoolArcane, timeline: lulinTimeline},
+	}
+
+	for idx, consort := range consorts {
+		name := fmt.Sprintf("%s %d H", consort.name, raidSize)
+
+		core.AddPresetTarget(&core.PresetTarget{
+			PathPrefix: raidPrefix,
+
+			Config: &proto.Target{
+				Id:        consort.npcID,
+				Name:      name,
+				Level:     93,
+				MobType:   proto.MobType_MobTypeHumanoid,
+				TankIndex: int32(idx),
+
+				Stats: stats.Stats{
+					stats.Health:      consortHealth,
+					stats.Armor:       24835,
+					stats.AttackPower: 0,
+				}.ToProtoArray(),
+
+				SpellSchool:   consort.school,
+				SwingSpeed:    2.0,
+				MinBaseDamage: consortMinBaseDamage,
+				DamageSpread:  0.4,
+				TargetInputs:  twinConsortsTargetInputs(),
+			},
+
+			AI: default_ai.NewPresetTimelineAI(consort.timeline, nil),
+		})
+
+		targetPathNames = append(targetPathNames, raidPrefix+"/"+name)
+	}
+
+	core.AddPresetEncounter(fmt.Sprintf("Twin Consorts %d H", raidSize), targetPathNames).UseHealth = true
+}
+
+func twinConsortsTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Night duration",
+			Tooltip:     "Time (in seconds) at which Day begins",
+			InputType:   proto.InputType_Number,
+			NumberValue: 185,
+		},
+		{
+			Label:       "Day duration",
+			Tooltip:     "Time (in seconds) Day lasts before Dusk brings Lu'lin back",
+			InputType:   proto.InputType_Number,
+			NumberValue: 185,
+		},
+	}
+}
+
+// Both consorts follow the same Night, Day and Dusk schedule so that their
+// phases stay in sync.
+func twinConsortsTimeline(config *proto.Target, night []*proto.BossTimelineEvent, day []*proto.BossTimelineEvent, dusk []*proto.BossTimelineEvent) *proto.BossTimeline {
+	nightDuration := default_ai.NumberInput(config, 0, 185)
+	dayDuration := default_ai.NumberInput(config, 1, 185)
+
+	return &proto.BossTimeline{
+		Phases: []*proto.BossTimelinePhase{
+			{Name: "Night", Events: night},
+			{Name: "Day", StartTime: nightDuration, Events: day},
+			{Name: "Dusk", StartTime: nightDuration + dayDuration, Events: dusk},
+		},
+	}
+}
+
+// Suen is up throughout, and takes Lu'lin out of the fight during the Day.
+func suenTimeline(config *proto.Target) *proto.BossTimeline {
+	fanOfFlames := default_ai.CastEvent(12, 12, &proto.BossTimelineCast{
+		SpellId:      137408, // Fan of Flames
+		School:       proto.SpellSchool_SpellSchoolFire,
+		MinDamage:    250_000,
+		DamageSpread: 0.1,
+		Melee:        true,
+	})
+	tearsOfTheSun := default_ai.CastEvent(40, 40, &proto.BossTimelineCast{
+		SpellId:      137404, // Tears of the Sun
+		School:       proto.SpellSchool_SpellSchoolFire,
+		MinDamage:    40_000,
+		DamageSpread: 0.1,
+		Target:       proto.BossTimelineCast_Raid,
+		NumTicks:     10,
+		TickInterval: 1,
+	})
+	flamesOfPassion := default_ai.CastEvent(30, 30, &proto.BossTimelineCast{
+		SpellId:      137414, // Flames of Passion
+		School:       proto.SpellSchool_SpellSchoolFire,
+		MinDamage:    180_000,
+		DamageSpread: 0.1,
+		Target:       proto.BossTimelineCast_RandomPlayer,
+	})
+
+	return twinConsortsTimeline(config,
+		[]*proto.BossTimelineEvent{fanOfFlames, default_ai.TankSwapEvent(36, 36)},
+		[]*proto.BossTimelineEvent{
+			default_ai.SetTargetEnabledEvent(0, twinConsortsLulinIdx, false),
+			fanOfFlames, tearsOfTheSun, flamesOfPassion,
+		},
+		[]*proto.BossTimelineEvent{
+			default_ai.SetTargetEnabledEvent(0, twinConsortsLulinIdx, true),
+			fanOfFlames, tearsOfTheSun, flamesOfPassion,
+		},
+	)
+}
+
+// Lu'lin leads the Night, vanishes during the Day and returns at Dusk.
+func lulinTimeline(config *proto.Target) *proto.BossTimeline {
+	cosmicBarrage := default_ai.CastEvent(15, 20, &proto.BossTimelineCast{
+		SpellId:      136752, // Cosmic Barrage
+		School:       proto.SpellSchool_SpellSchoolArcane,
+		MinDamage:    90_000,
+		DamageSpread: 0.1,
+		Target:       proto.BossTimelineCast_RandomPlayer,
+	})
+	// Players dodge the Cosmic Barrage stars.
+	dodgeStars := default_ai.MovementEvent(15, 20, 2)
+
+	return twinConsortsTimeline(config,
+		[]*proto.BossTimelineEvent{
+			cosmicBarrage, dodgeStars,
+			default_ai.CastEvent(30, 30, &proto.BossTimelineCast{
+				SpellId:      137531, // Tidal Force
+				School:       proto.SpellSchool_SpellSchoolFrost,
+				MinDamage:    70_000,
+				DamageSpread: 0.1,
+				Target:       proto.BossTimelineCast_Raid,
+				NumTicks:     8,
+				TickInterval: 1,
+			}),
+			default_ai.CastEvent(50, 60, &proto.BossTimelineCast{
+				SpellId:      137375, // Beast of Nightmares
+				School:       proto.SpellSchool_SpellSchoolShadow,
+				MinDamage:    150_000,
+				DamageSpread: 0.1,
+				Melee:        true,
+			}),
+		},
+		[]*proto.BossTimelineEvent{},
+		[]*proto.BossTimelineEvent{
+			cosmicBarrage, dodgeStars,
+			default_ai.CastEvent(20, 20, &proto.BossTimelineCast{
+				SpellId:      137419, // Ice Comet
+				School:       proto.SpellSchool_SpellSchoolFrost,
+				MinDamage:    50_000,
+				DamageSpread: 0.1,
+				Target:       proto.BossTimelineCast_Raid,
+			}),
+		},
+	)
+}
@@ -35,6 +35,7 @@ type Hunter struct {
 	ExplosiveTrap        *core.Spell
 	ExplosiveShot        *core.Spell
 	ImprovedSerpentSting *core.Spell
+	Misdirection         *core.Spell
 
 	// Fake spells to encapsulate weaving logic.
 	HuntersMarkSpell *core.Spell
@@ -145,6 +146,7 @@ func (hunter *Hunter) RegisterSpells() {
 	hunter.RegisterDireBeastSpell()
 	hunter.RegisterStampedeSpell()
 	hunter.registerPowerShotSpell()
+	hunter.registerMisdirectionSpell()
 }
 
 func (hunter *Hunter) AddStatDependencies() {
@@ -247,6 +249,7 @@ const (
 	HunterSpellGlaiveToss
 	HunterSpellBarrage
 	HunterSpellPowershot
+	HunterSpellMisdirection
 	HunterSpellsTierTwelve = HunterSpellArcaneShot | HunterSpellKillCommand | HunterSpellChimeraShot | HunterSpellExplosiveShot |
 		HunterSpellMultiShot | HunterSpellAimedShot
 	HunterSpellsAll = HunterSpellSteadyShot | HunterSpellCobraShot |
@@ -0,0 +1,70 @@
+package hunter
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func (hunter *Hunter) registerMisdirectionSpell() {
+	actionID := core.ActionID{SpellID: 34477}
+	hasGlyph := hunter.HasMajorGlyph(proto.HunterMajorGlyph_GlyphOfMisdirection)
+
+	var mdTarget *core.Unit
+	if hunter.Options.MisdirectionTarget != nil {
+		mdTarget = hunter.GetUnit(hunter.Options.MisdirectionTarget)
+	}
+
+	var castTarget *core.Unit
+	threatTransferAura := hunter.RegisterAura(core.Aura{
+		ActionID: core.ActionID{SpellID: 35079},
+		Label:    "MisdirectionThreatTransfer",
+		Duration: time.Second * 4,
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			hunter.ThreatRedirectTarget = castTarget
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			hunter.ThreatRedirectTarget = nil
+		},
+	})
+
+	applicationAura := hunter.RegisterAura(core.Aura{
+		ActionID: actionID,
+		Label:    "MisdirectionApplication",
+		Duration: time.Second * 30,
+		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if result.Landed() && result.Damage > 0 {
+				threatTransferAura.Activate(sim)
+				aura.Deactivate(sim)
+			}
+		},
+	})
+
+	hunter.Misdirection = hunter.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		Flags:          core.SpellFlagAPL | core.SpellFlagHelpful,
+		ClassSpellMask: HunterSpellMisdirection,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    hunter.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return mdTarget != nil || target.Type != core.EnemyUnit
+		},
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			castTarget = target
+			if mdTarget != nil {
+				castTarget = mdTarget
+			}
+			applicationAura.Activate(sim)
+
+			if hasGlyph && hunter.Pet != nil && castTarget == &hunter.Pet.Unit {
+				spell.CD.Reset()
+			}
+		},
+	})
+}
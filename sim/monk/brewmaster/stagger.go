@@ -37,7 +37,7 @@ func (bm *BrewmasterMonk) registerStagger() {
 				}
 
 				damage := max(0, dot.SnapshotBaseDamage)
-				target.RemoveHealth(sim, damage)
+				target.RemoveHealthFromSpell(sim, damage, dot.Spell)
 
 				if sim.Log != nil && dot.Aura.IsActive() {
 					bm.Log(sim, "[DEBUG] Stagger ticked for %0.0f Damage", damage)
@@ -104,6 +104,7 @@ func (bm *BrewmasterMonk) registerStagger() {
 		staggerMultiplier := min(1, bm.GetMasteryBonus()) + shuffleMultiplier + fortifyingBrewMultiplier + avertHarmMultiplier + t15Brewmaster2P
 		staggeredDamage := result.Damage * staggerMultiplier
 		result.Damage -= staggeredDamage
+		result.Staggered += staggeredDamage
 
 		newOutstandingDamage := outstandingDamage + staggeredDamage
 		newTickCount := dot.BaseTickCount
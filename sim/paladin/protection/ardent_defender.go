@@ -73,6 +73,7 @@ func (prot *ProtectionPaladin) registerArdentDefender() {
 				// Incoming attack gets reduced so we end up at 15% hp
 				// TODO: Overkill counted as absorb but not as healing in logs
 				result.Damage = currentHealth - maxHealth*0.15
+				result.Absorbed += incomingDamage - result.Damage
 				if sim.Log != nil {
 					prot.Log(sim, "Ardent Defender absorbed %.1f damage", incomingDamage-result.Damage)
 				}
@@ -81,6 +82,7 @@ func (prot *ProtectionPaladin) registerArdentDefender() {
 				// Heal up to 15% hp
 				// TODO: Overkill counted as absorb but not as healing in logs
 				result.Damage = 0
+				result.Absorbed += incomingDamage
 				adHealAmount = maxHealth*0.15 - currentHealth
 				adHeal.Cast(sim, &prot.Unit)
 				if sim.Log != nil {
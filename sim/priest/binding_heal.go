@@ -0,0 +1,47 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const BindingHealScaleCoeff = 8.86
+const BindingHealVariance = 0.25
+const BindingHealSpellCoeff = 0.899
+
+func (priest *Priest) registerBindingHealSpell() {
+	priest.BindingHeal = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 32546},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellBindingHeal,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 5.4,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 0.5,
+		BonusCoefficient: BindingHealSpellCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = priest.healTarget(target)
+			if target != &priest.Unit {
+				targetHealing := priest.CalcAndRollDamageRange(sim, BindingHealScaleCoeff, BindingHealVariance)
+				spell.CalcAndDealHealing(sim, target, targetHealing, spell.OutcomeHealingCrit)
+			}
+
+			selfHealing := priest.CalcAndRollDamageRange(sim, BindingHealScaleCoeff, BindingHealVariance)
+			spell.CalcAndDealHealing(sim, &priest.Unit, selfHealing, spell.OutcomeHealingCrit)
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package discipline
+
+import (
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const AtonementHealPercent = 0.9
+
+// Damage dealt by Smite, Holy Fire and Penance heals the most injured ally for 90% of the damage.
+func (discPriest *DisciplinePriest) registerAtonement() {
+	atonement := discPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 81751},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagPassiveSpell,
+		ClassSpellMask: priest.PriestSpellAtonement,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+		CritMultiplier:   discPriest.DefaultCritMultiplier(),
+	})
+
+	core.MakeProcTriggerAura(&discPriest.Unit, core.ProcTrigger{
+		Name:           "Atonement",
+		ActionID:       core.ActionID{SpellID: 81749},
+		Callback:       core.CallbackOnSpellHitDealt,
+		ProcMask:       core.ProcMaskSpellDamage,
+		ClassSpellMask: priest.PriestSpellSmite | priest.PriestSpellHolyFire | priest.PriestSpellPenance,
+		Outcome:        core.OutcomeLanded,
+
+		Handler: func(sim *core.Simulation, _ *core.Spell, result *core.SpellResult) {
+			target := sim.Raid.GetLowestHealthAllyUnit()
+			if target == nil {
+				target = &discPriest.Unit
+			}
+			atonement.CalcAndDealHealing(sim, target, result.Damage*AtonementHealPercent, atonement.OutcomeHealingCrit)
+		},
+	})
+}
@@ -68,15 +68,17 @@ func (discPriest *DisciplinePriest) GetMainTarget() *core.Unit {
 func (discPriest *DisciplinePriest) Initialize() {
 	discPriest.CurrentTarget = discPriest.GetMainTarget()
 	discPriest.Priest.Initialize()
-	// discPriest.Priest.RegisterHealingSpells()
+	discPriest.Priest.RegisterHealingSpells()
 
-	// // discPriest.ApplyRapture(discPriest.Options.RapturesPerMinute)
-	// discPriest.RegisterHymnOfHopeCD()
+	discPriest.registerPenanceSpells()
+	discPriest.registerSpiritShell()
+	discPriest.registerDisciplinePassives()
 }
 
-func (holyPriest *DisciplinePriest) ApplyTalents() {
+func (discPriest *DisciplinePriest) ApplyTalents() {
+	discPriest.Priest.ApplyTalents()
 }
 
 func (discPriest *DisciplinePriest) Reset(sim *core.Simulation) {
-	//discPriest.Priest.Reset(sim)
+	discPriest.Priest.Reset(sim)
 }
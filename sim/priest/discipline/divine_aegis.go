@@ -0,0 +1,53 @@
+package discipline
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const (
+	DivineAegisAbsorbPercent = 0.5
+
+	// Divine Aegis stacks up to this fraction of the target's maximum health.
+	DivineAegisMaxHealthPercent = 0.4
+)
+
+// Critical heals, and every heal from Prayer of Healing, also leave an absorb
+// for half of the amount healed.
+func (discPriest *DisciplinePriest) registerDivineAegis() {
+	divineAegis := discPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 47753},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagPassiveSpell,
+		ClassSpellMask: priest.PriestSpellDivineAegis,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		Shield: core.ShieldConfig{
+			AbsorbDamage: true,
+			Aura: core.Aura{
+				Label:    "Divine Aegis",
+				Duration: time.Second * 15,
+			},
+		},
+	})
+
+	core.MakeProcTriggerAura(&discPriest.Unit, core.ProcTrigger{
+		Name:           "Divine Aegis Trigger",
+		ActionID:       core.ActionID{SpellID: 47515},
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: priest.PriestSpellDirectHeal,
+
+		ExtraCondition: func(_ *core.Simulation, spell *core.Spell, result *core.SpellResult) bool {
+			return result.DidCrit() || spell.Matches(priest.PriestSpellPrayerOfHealing)
+		},
+
+		Handler: func(sim *core.Simulation, _ *core.Spell, result *core.SpellResult) {
+			divineAegis.Shield(result.Target).ApplyAdditive(sim, result.Damage*DivineAegisAbsorbPercent, result.Target.MaxHealth()*DivineAegisMaxHealthPercent)
+		},
+	})
+}
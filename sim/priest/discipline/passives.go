@@ -0,0 +1,52 @@
+package discipline
+
+import (
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const (
+	// Absorbs that benefit from Mastery: Shield Discipline.
+	ShieldDisciplineSpells = priest.PriestSpellPowerWordShield | priest.PriestSpellDivineAegis | priest.PriestSpellSpiritShell
+)
+
+func (discPriest *DisciplinePriest) registerDisciplinePassives() {
+	discPriest.registerMeditation()
+	discPriest.registerMastery()
+	discPriest.registerAtonement()
+	discPriest.registerDivineAegis()
+}
+
+// Allows 50% of your mana regeneration from Spirit to continue while in combat.
+func (discPriest *DisciplinePriest) registerMeditation() {
+	discPriest.PseudoStats.SpiritRegenRateCombat = 0.5
+}
+
+// Increases the potency of all your damage absorption spells by (12.8 + <Mastery Rating> / 375)%.
+func (discPriest *DisciplinePriest) registerMastery() {
+	masteryMod := discPriest.AddDynamicMod(core.SpellModConfig{
+		ClassMask:  ShieldDisciplineSpells,
+		Kind:       core.SpellMod_DamageDone_Pct,
+		FloatValue: discPriest.getMasteryPercent(),
+	})
+
+	core.MakePermanent(discPriest.RegisterAura(core.Aura{
+		Label:    "Mastery: Shield Discipline",
+		ActionID: core.ActionID{SpellID: 77484},
+
+		OnGain: func(_ *core.Aura, _ *core.Simulation) {
+			masteryMod.Activate()
+		},
+		OnExpire: func(_ *core.Aura, _ *core.Simulation) {
+			masteryMod.Deactivate()
+		},
+	}))
+
+	discPriest.AddOnMasteryStatChanged(func(_ *core.Simulation, _ float64, _ float64) {
+		masteryMod.UpdateFloatValue(discPriest.getMasteryPercent())
+	})
+}
+
+func (discPriest *DisciplinePriest) getMasteryPercent() float64 {
+	return (8.0 + discPriest.GetMasteryPoints()) * 0.016
+}
@@ -0,0 +1,110 @@
+package discipline
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const PenanceDamageScaleCoeff = 1.26
+const PenanceDamageSpellCoeff = 0.838
+const PenanceHealScaleCoeff = 4.966
+const PenanceHealSpellCoeff = 0.838
+
+// Penance fires one bolt immediately and one more each second of the channel.
+func (discPriest *DisciplinePriest) registerPenanceSpells() {
+	cd := core.Cooldown{
+		Timer:    discPriest.NewTimer(),
+		Duration: time.Second * 9,
+	}
+
+	discPriest.Penance = discPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 47540},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellDamage,
+		Flags:          core.SpellFlagChanneled | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellPenance,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 3.1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: cd,
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   discPriest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: PenanceDamageSpellCoeff,
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Penance",
+			},
+			NumberOfTicks:       2,
+			TickLength:          time.Second,
+			AffectedByCastSpeed: true,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealDamage(sim, target, discPriest.CalcScalingSpellDmg(PenanceDamageScaleCoeff), dot.Spell.OutcomeMagicHitAndCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			dot := spell.Dot(target)
+			dot.Apply(sim)
+			dot.TickOnce(sim)
+		},
+	})
+
+	discPriest.PenanceHeal = discPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 47540}.WithTag(1),
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagChanneled | core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellPenance,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 3.1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: cd,
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   discPriest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: PenanceHealSpellCoeff,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Penance (Heal)",
+			},
+			NumberOfTicks:       2,
+			TickLength:          time.Second,
+			AffectedByCastSpeed: true,
+
+			// Each bolt is a separate direct heal rather than a periodic one.
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealHealing(sim, target, discPriest.CalcScalingSpellDmg(PenanceHealScaleCoeff), dot.Spell.OutcomeHealingCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if target.IsOpponent(&discPriest.Unit) {
+				target = &discPriest.Unit
+			}
+
+			hot := spell.Hot(target)
+			hot.Apply(sim)
+			hot.TickOnce(sim)
+		},
+	})
+}
@@ -0,0 +1,68 @@
+package discipline
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+// While Spirit Shell is active Heal, Flash Heal, Greater Heal and Prayer of
+// Healing grant absorbs for the amount they would have healed instead.
+func (discPriest *DisciplinePriest) registerSpiritShell() {
+	actionID := core.ActionID{SpellID: 109964}
+
+	discPriest.SpiritShellAbsorb = discPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 114908},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagPassiveSpell,
+		ClassSpellMask: priest.PriestSpellSpiritShell,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		Shield: core.ShieldConfig{
+			AbsorbDamage: true,
+			Aura: core.Aura{
+				Label:    "Spirit Shell Absorb",
+				Duration: time.Second * 15,
+			},
+		},
+	})
+
+	discPriest.SpiritShellAura = discPriest.RegisterAura(core.Aura{
+		Label:    "Spirit Shell",
+		ActionID: actionID,
+		Duration: time.Second * 15,
+	})
+
+	spiritShell := discPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolHoly,
+		Flags:          core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellSpiritShell,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 2.5,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    discPriest.NewTimer(),
+				Duration: time.Minute,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			discPriest.SpiritShellAura.Activate(sim)
+		},
+	})
+
+	discPriest.AddMajorCooldown(core.MajorCooldown{
+		Spell: spiritShell,
+		Type:  core.CooldownTypeDPS,
+	})
+}
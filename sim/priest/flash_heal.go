@@ -0,0 +1,41 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const FlashHealScaleCoeff = 11.07
+const FlashHealVariance = 0.15
+const FlashHealSpellCoeff = 1.314
+
+func (priest *Priest) registerFlashHealSpell() {
+	priest.FlashHeal = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 2061},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellFlashHeal,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 5.9,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: FlashHealSpellCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := priest.CalcAndRollDamageRange(sim, FlashHealScaleCoeff, FlashHealVariance)
+			priest.CalcAndDealHealing(sim, spell, priest.healTarget(target), baseHealing)
+		},
+	})
+}
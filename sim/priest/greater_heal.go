@@ -0,0 +1,41 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const GreaterHealScaleCoeff = 21.94
+const GreaterHealVariance = 0.15
+const GreaterHealSpellCoeff = 2.19
+
+func (priest *Priest) registerGreaterHealSpell() {
+	priest.GreaterHeal = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 2060},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellGreaterHeal,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 5.9,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: GreaterHealSpellCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := priest.CalcAndRollDamageRange(sim, GreaterHealScaleCoeff, GreaterHealVariance)
+			priest.CalcAndDealHealing(sim, spell, priest.healTarget(target), baseHealing)
+		},
+	})
+}
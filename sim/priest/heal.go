@@ -0,0 +1,41 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const HealScaleCoeff = 10.24
+const HealVariance = 0.15
+const HealSpellCoeff = 1.024
+
+func (priest *Priest) registerHealSpell() {
+	priest.Heal = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 2050},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellHeal,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 1.9,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: HealSpellCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := priest.CalcAndRollDamageRange(sim, HealScaleCoeff, HealVariance)
+			priest.CalcAndDealHealing(sim, spell, priest.healTarget(target), baseHealing)
+		},
+	})
+}
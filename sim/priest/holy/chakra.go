@@ -0,0 +1,100 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const chakraEffectCategory = "Chakra"
+
+const (
+	ChakraSerenitySpells = priest.PriestSpellHeal |
+		priest.PriestSpellFlashHeal |
+		priest.PriestSpellGreaterHeal |
+		priest.PriestSpellBindingHeal |
+		priest.PriestSpellHolyWordSerenity
+	ChakraSanctuarySpells = priest.PriestSpellPrayerOfHealing |
+		priest.PriestSpellCircleOfHealing |
+		priest.PriestSpellPrayerOfMending
+	ChakraChastiseSpells = priest.PriestSpellSmite | priest.PriestSpellHolyFire
+)
+
+func (holyPriest *HolyPriest) registerChakras() {
+	chakraCD := holyPriest.NewTimer()
+
+	// Increases single target healing by 25%, and those heals refresh Renew on the target.
+	holyPriest.ChakraSerenityAura = holyPriest.RegisterAura(core.Aura{
+		Label:    "Chakra: Serenity",
+		ActionID: core.ActionID{SpellID: 81208},
+		Duration: core.NeverExpires,
+
+		OnHealDealt: func(_ *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if !spell.Matches(ChakraSerenitySpells) {
+				return
+			}
+			if renew := holyPriest.Renew.Hot(result.Target); renew != nil && renew.IsActive() {
+				renew.Apply(sim)
+			}
+		},
+	}).AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_DamageDone_Pct,
+		ClassMask:  ChakraSerenitySpells,
+		FloatValue: 0.25,
+	})
+
+	// Increases area healing by 25% and reduces the cooldown of Circle of Healing by 2 sec.
+	holyPriest.ChakraSanctuaryAura = holyPriest.RegisterAura(core.Aura{
+		Label:    "Chakra: Sanctuary",
+		ActionID: core.ActionID{SpellID: 81206},
+		Duration: core.NeverExpires,
+	}).AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_DamageDone_Pct,
+		ClassMask:  ChakraSanctuarySpells,
+		FloatValue: 0.25,
+	}).AttachSpellMod(core.SpellModConfig{
+		Kind:      core.SpellMod_Cooldown_Flat,
+		ClassMask: priest.PriestSpellCircleOfHealing,
+		TimeValue: -time.Second * 2,
+	})
+
+	// Increases damage done by 50%.
+	holyPriest.ChakraChastiseAura = holyPriest.RegisterAura(core.Aura{
+		Label:    "Chakra: Chastise",
+		ActionID: core.ActionID{SpellID: 81209},
+		Duration: core.NeverExpires,
+	}).AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_DamageDone_Pct,
+		ClassMask:  ChakraChastiseSpells,
+		FloatValue: 0.5,
+	})
+
+	holyPriest.ChakraSerenity = holyPriest.makeChakraSpell(holyPriest.ChakraSerenityAura, chakraCD)
+	holyPriest.ChakraSanctuary = holyPriest.makeChakraSpell(holyPriest.ChakraSanctuaryAura, chakraCD)
+	holyPriest.ChakraChastise = holyPriest.makeChakraSpell(holyPriest.ChakraChastiseAura, chakraCD)
+}
+
+// Only one Chakra state can be active, entering one leaves the current one.
+func (holyPriest *HolyPriest) makeChakraSpell(aura *core.Aura, chakraCD *core.Timer) *core.Spell {
+	aura.NewExclusiveEffect(chakraEffectCategory, true, core.ExclusiveEffect{})
+
+	return holyPriest.RegisterSpell(core.SpellConfig{
+		ActionID: aura.ActionID,
+		Flags:    core.SpellFlagNoOnCastComplete | core.SpellFlagAPL,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    chakraCD,
+				Duration: time.Second * 30,
+			},
+		},
+		ExtraCastCondition: func(_ *core.Simulation, _ *core.Unit) bool {
+			return !aura.IsActive()
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			aura.Activate(sim)
+		},
+	})
+}
@@ -0,0 +1,55 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const CohScaleCoeff = 4.57
+const CohVariance = 0.1
+const CohSpellCoeff = 0.467
+
+func (holyPriest *HolyPriest) registerCircleOfHealingSpell() {
+	hasGlyph := holyPriest.HasMajorGlyph(proto.PriestMajorGlyph_GlyphOfCircleOfHealing)
+	numTargets := core.TernaryInt(hasGlyph, 6, 5)
+
+	holyPriest.CircleOfHealing = holyPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 34861},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellCircleOfHealing,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: core.TernaryFloat64(hasGlyph, 3.2*1.35, 3.2),
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    holyPriest.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holyPriest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: CohSpellCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if target.IsOpponent(&holyPriest.Unit) {
+				target = &holyPriest.Unit
+			}
+
+			for _, unit := range append([]*core.Unit{target}, holyPriest.Env.Raid.SmartHealTargets(target, numTargets-1)...) {
+				baseHealing := holyPriest.CalcAndRollDamageRange(sim, CohScaleCoeff, CohVariance)
+				spell.CalcAndDealHealing(sim, unit, baseHealing, spell.OutcomeHealingCrit)
+			}
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const EchoOfLightTicks = 6
+
+/*
+Your direct healing spells heal for an additional (10 + <Mastery Rating> / 480)% over 6 sec.
+A new Echo of Light adds the healing still outstanding on the target to its total.
+*/
+func (holyPriest *HolyPriest) registerEchoOfLight() {
+	echoOfLight := holyPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 77489},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagPassiveSpell | core.SpellFlagIgnoreModifiers | core.SpellFlagNoSpellMods,
+		ClassSpellMask: priest.PriestSpellEchoOfLight,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Echo of Light",
+			},
+			NumberOfTicks: EchoOfLightTicks,
+			TickLength:    time.Second,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicHealing(sim, target, dot.SnapshotBaseDamage, dot.Spell.OutcomeHealing)
+			},
+		},
+	})
+
+	core.MakePermanent(holyPriest.RegisterAura(core.Aura{
+		Label:    "Mastery: Echo of Light",
+		ActionID: core.ActionID{SpellID: 77485},
+
+		OnHealDealt: func(_ *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if !spell.Matches(priest.PriestSpellDirectHeal) || result.Damage <= 0 {
+				return
+			}
+
+			hot := echoOfLight.Hot(result.Target)
+			if hot == nil {
+				return
+			}
+			totalHealing := hot.OutstandingDmg() + result.Damage*holyPriest.getMasteryPercent()
+			hot.SnapshotBaseDamage = totalHealing / EchoOfLightTicks
+			hot.Apply(sim)
+		},
+	}))
+}
+
+func (holyPriest *HolyPriest) getMasteryPercent() float64 {
+	return (8.0 + holyPriest.GetMasteryPoints()) * 0.0125
+}
@@ -41,6 +41,15 @@ func NewHolyPriest(character *core.Character, options *proto.Player) *HolyPriest
 
 type HolyPriest struct {
 	*priest.Priest
+
+	HolyWordSerenity *core.Spell
+
+	ChakraSerenity      *core.Spell
+	ChakraSanctuary     *core.Spell
+	ChakraChastise      *core.Spell
+	ChakraSerenityAura  *core.Aura
+	ChakraSanctuaryAura *core.Aura
+	ChakraChastiseAura  *core.Aura
 }
 
 func (holyPriest *HolyPriest) GetPriest() *priest.Priest {
@@ -49,16 +58,18 @@ func (holyPriest *HolyPriest) GetPriest() *priest.Priest {
 
 func (holyPriest *HolyPriest) Initialize() {
 	holyPriest.Priest.Initialize()
+	holyPriest.Priest.RegisterHealingSpells()
 
-	// holyPriest.RegisterHolyFireSpell()
-	// holyPriest.RegisterSmiteSpell()
-	// holyPriest.RegisterPenanceSpell()
-	// holyPriest.RegisterHymnOfHopeCD()
+	holyPriest.registerCircleOfHealingSpell()
+	holyPriest.registerChakras()
+	holyPriest.registerHolyWordSerenitySpell()
+	holyPriest.registerHolyPassives()
 }
 
 func (holyPriest *HolyPriest) ApplyTalents() {
+	holyPriest.Priest.ApplyTalents()
 }
 
 func (holyPriest *HolyPriest) Reset(sim *core.Simulation) {
-	//holyPriest.Priest.Reset(sim)
+	holyPriest.Priest.Reset(sim)
 }
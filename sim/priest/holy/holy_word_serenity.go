@@ -0,0 +1,53 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const SerenityScaleCoeff = 12.36
+const SerenityVariance = 0.16
+const SerenitySpellCoeff = 1.3
+
+// Holy Word: Chastise turns into Holy Word: Serenity while in Chakra: Serenity.
+func (holyPriest *HolyPriest) registerHolyWordSerenitySpell() {
+	holyPriest.HolyWordSerenity = holyPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 88684},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: priest.PriestSpellHolyWordSerenity,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 2,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    holyPriest.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+		ExtraCastCondition: func(_ *core.Simulation, _ *core.Unit) bool {
+			return holyPriest.ChakraSerenityAura.IsActive()
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   holyPriest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: SerenitySpellCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if target.IsOpponent(&holyPriest.Unit) {
+				target = &holyPriest.Unit
+			}
+
+			baseHealing := holyPriest.CalcAndRollDamageRange(sim, SerenityScaleCoeff, SerenityVariance)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+		},
+	})
+}
@@ -0,0 +1,12 @@
+package holy
+
+func (holyPriest *HolyPriest) registerHolyPassives() {
+	holyPriest.registerMeditation()
+	holyPriest.registerEchoOfLight()
+	holyPriest.registerSerendipity()
+}
+
+// Allows 50% of your mana regeneration from Spirit to continue while in combat.
+func (holyPriest *HolyPriest) registerMeditation() {
+	holyPriest.PseudoStats.SpiritRegenRateCombat = 0.5
+}
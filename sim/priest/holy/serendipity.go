@@ -0,0 +1,73 @@
+package holy
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/priest"
+)
+
+const (
+	SerendipityTriggerSpells  = priest.PriestSpellFlashHeal | priest.PriestSpellBindingHeal
+	SerendipityAffectedSpells = priest.PriestSpellGreaterHeal | priest.PriestSpellPrayerOfHealing
+)
+
+/*
+When you heal with Binding Heal or Flash Heal, the cast time of your next Greater Heal or Prayer of Healing spell
+is reduced by 50% and mana cost reduced by 20%.
+Stacks up to 2 times. Lasts 20 sec.
+*/
+func (holyPriest *HolyPriest) registerSerendipity() {
+	castTimePerStack := []float64{0, -0.5, -1}
+	castTimeMod := holyPriest.AddDynamicMod(core.SpellModConfig{
+		Kind:       core.SpellMod_CastTime_Pct,
+		ClassMask:  SerendipityAffectedSpells,
+		FloatValue: castTimePerStack[0],
+	})
+
+	costPerStack := []float64{0, -0.2, -0.4}
+	costMod := holyPriest.AddDynamicMod(core.SpellModConfig{
+		Kind:       core.SpellMod_PowerCost_Pct,
+		ClassMask:  SerendipityAffectedSpells,
+		FloatValue: costPerStack[0],
+	})
+
+	serendipityAura := holyPriest.RegisterAura(core.Aura{
+		Label:     "Serendipity",
+		ActionID:  core.ActionID{SpellID: 63735},
+		Duration:  time.Second * 20,
+		MaxStacks: 2,
+		OnGain: func(_ *core.Aura, _ *core.Simulation) {
+			castTimeMod.Activate()
+			costMod.Activate()
+		},
+		OnStacksChange: func(_ *core.Aura, _ *core.Simulation, _ int32, newStacks int32) {
+			castTimeMod.UpdateFloatValue(castTimePerStack[newStacks])
+			costMod.UpdateFloatValue(costPerStack[newStacks])
+		},
+		OnExpire: func(_ *core.Aura, _ *core.Simulation) {
+			castTimeMod.Deactivate()
+			costMod.Deactivate()
+		},
+	})
+	serendipityAura.AttachProcTrigger(core.ProcTrigger{
+		Callback:       core.CallbackOnCastComplete,
+		ClassSpellMask: SerendipityAffectedSpells,
+
+		Handler: func(sim *core.Simulation, _ *core.Spell, _ *core.SpellResult) {
+			serendipityAura.Deactivate(sim)
+		},
+	})
+
+	core.MakeProcTriggerAura(&holyPriest.Unit, core.ProcTrigger{
+		Name:           "Serendipity Trigger",
+		ActionID:       core.ActionID{SpellID: 63733},
+		Callback:       core.CallbackOnCastComplete,
+		ClassSpellMask: SerendipityTriggerSpells,
+
+		Handler: func(sim *core.Simulation, _ *core.Spell, _ *core.SpellResult) {
+			serendipityAura.Activate(sim)
+			serendipityAura.AddStack(sim)
+		},
+	})
+}
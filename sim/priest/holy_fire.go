@@ -0,0 +1,66 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const HolyFireScaleCoeff = 1.08
+const HolyFireVariance = 0.238
+const HolyFireSpellCoeff = 1.11
+const HolyFireDotScaleCoeff = 0.0312
+const HolyFireDotSpellCoeff = 0.0312
+
+func (priest *Priest) registerHolyFireSpell() {
+	priest.HolyFire = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 14914},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellDamage,
+		Flags:          core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellHolyFire,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 1.8,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: HolyFireSpellCoeff,
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "HolyFire",
+			},
+			NumberOfTicks:    7,
+			TickLength:       time.Second,
+			BonusCoefficient: HolyFireDotSpellCoeff,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.Snapshot(target, priest.CalcScalingSpellDmg(HolyFireDotScaleCoeff))
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotDamage(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseDamage := priest.CalcAndRollDamageRange(sim, HolyFireScaleCoeff, HolyFireVariance)
+			result := spell.CalcDamage(sim, target, baseDamage, spell.OutcomeMagicHitAndCrit)
+			if result.Landed() {
+				spell.Dot(target).Apply(sim)
+			}
+			spell.DealDamage(sim, result)
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const PwsScaleCoeff = 18.515
+const PwsSpellCoeff = 1.871
+
+// Glyph of Power Word: Shield converts this share of the absorb into an instant heal.
+const PwsGlyphHealPercent = 0.2
+
+func (priest *Priest) registerPowerWordShieldSpell() {
+	hasGlyph := priest.HasMajorGlyph(proto.PriestMajorGlyph_GlyphOfPowerWordShield)
+
+	priest.WeakenedSouls = priest.NewAllyAuraArray(func(target *core.Unit) *core.Aura {
+		return target.GetOrRegisterAura(core.Aura{
+			Label:    "Weakened Soul",
+			ActionID: core.ActionID{SpellID: 6788},
+			Duration: time.Second * 15,
+		})
+	})
+
+	var glyphHeal *core.Spell
+	if hasGlyph {
+		glyphHeal = priest.RegisterSpell(core.SpellConfig{
+			ActionID:    core.ActionID{SpellID: 56160},
+			SpellSchool: core.SpellSchoolHoly,
+			ProcMask:    core.ProcMaskSpellHealing,
+			Flags:       core.SpellFlagHelpful | core.SpellFlagPassiveSpell,
+
+			DamageMultiplier: 1,
+			CritMultiplier:   priest.DefaultCritMultiplier(),
+			ThreatMultiplier: 1,
+		})
+	}
+
+	priest.PowerWordShield = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 17},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellPowerWordShield,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 6.1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 6,
+			},
+		},
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return !priest.WeakenedSouls.Get(priest.healTarget(target)).IsActive()
+		},
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		Shield: core.ShieldConfig{
+			AbsorbDamage: true,
+			Aura: core.Aura{
+				Label:    "Power Word Shield",
+				Duration: time.Second * 15,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			target = priest.healTarget(target)
+			absorb := priest.CalcScalingSpellDmg(PwsScaleCoeff) + PwsSpellCoeff*spell.HealingPower(target)
+
+			if glyphHeal != nil {
+				glyphHeal.CalcAndDealHealing(sim, target, absorb*PwsGlyphHealPercent, glyphHeal.OutcomeHealingCrit)
+				absorb *= 1 - PwsGlyphHealPercent
+			}
+
+			spell.Shield(target).Apply(sim, absorb)
+			priest.WeakenedSouls.Get(target).Activate(sim)
+		},
+	})
+}
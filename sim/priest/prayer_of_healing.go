@@ -0,0 +1,59 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const PohScaleCoeff = 8.309
+const PohVariance = 0.055
+const PohSpellCoeff = 0.838
+
+func (priest *Priest) registerPrayerOfHealingSpell() {
+	priest.PrayerOfHealing = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 596},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellPrayerOfHealing,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 4.5,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: PohSpellCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			for _, unit := range priest.partyMembers(priest.healTarget(target)) {
+				baseHealing := priest.CalcAndRollDamageRange(sim, PohScaleCoeff, PohVariance)
+				priest.CalcAndDealHealing(sim, spell, unit, baseHealing)
+			}
+		},
+	})
+}
+
+// Returns the enabled players in the same party as target.
+func (priest *Priest) partyMembers(target *core.Unit) []*core.Unit {
+	party := priest.Party
+	if player := priest.Env.Raid.GetPlayerFromUnit(target); player != nil {
+		party = player.GetCharacter().Party
+	}
+
+	members := make([]*core.Unit, 0, len(party.Players))
+	for _, player := range party.Players {
+		if unit := &player.GetCharacter().Unit; unit.IsEnabled() {
+			members = append(members, unit)
+		}
+	}
+	return members
+}
@@ -0,0 +1,125 @@
+package priest
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const PomScaleCoeff = 5.89
+const PomSpellCoeff = 0.571
+const PomMaxCharges = 5
+
+// Without an incoming damage model the buff is assumed to be hit this long after it lands.
+const PomAssumedProcDelay = time.Second * 5
+
+func (priest *Priest) registerPrayerOfMendingSpell() {
+	pomHeal := priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 33110},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagPassiveSpell,
+		ClassSpellMask: PriestSpellPrayerOfMending,
+
+		DamageMultiplier: 1,
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: PomSpellCoeff,
+	})
+
+	pomAuras := make([]*core.Aura, len(priest.Env.AllUnits))
+
+	priest.ProcPrayerOfMending = func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+		aura := pomAuras[target.UnitIndex]
+		charges := aura.GetStacks()
+		aura.Deactivate(sim)
+
+		pomHeal.CalcAndDealHealing(sim, target, priest.CalcScalingSpellDmg(PomScaleCoeff), pomHeal.OutcomeHealingCrit)
+
+		if charges <= 1 {
+			return
+		}
+		// Prayer of Mending jumps to the most injured other raid member.
+		if next := priest.Env.Raid.SmartHealTargets(target, 1); len(next) > 0 {
+			pomAuras[next[0].UnitIndex].Activate(sim)
+			pomAuras[next[0].UnitIndex].SetStacks(sim, charges-1)
+		}
+	}
+
+	for _, unit := range priest.Env.AllUnits {
+		if !priest.IsOpponent(unit) {
+			pomAuras[unit.UnitIndex] = priest.makePrayerOfMendingAura(unit)
+		}
+	}
+
+	priest.PrayerOfMending = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 33076},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellPrayerOfMending,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 3.5,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+			// Only one Prayer of Mending per priest can be active in the raid.
+			for _, aura := range pomAuras {
+				if aura != nil {
+					aura.Deactivate(sim)
+				}
+			}
+
+			aura := pomAuras[priest.healTarget(target).UnitIndex]
+			aura.Activate(sim)
+			aura.SetStacks(sim, PomMaxCharges)
+		},
+	})
+}
+
+func (priest *Priest) makePrayerOfMendingAura(target *core.Unit) *core.Aura {
+	var assumedProc *core.PendingAction
+
+	return target.RegisterAura(core.Aura{
+		Label:     "PrayerOfMending" + strconv.Itoa(int(priest.Index)),
+		ActionID:  core.ActionID{SpellID: 41635},
+		Duration:  time.Second * 30,
+		MaxStacks: PomMaxCharges,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			if aura.Unit.HasHealthBar() {
+				return
+			}
+			assumedProc = core.StartDelayedAction(sim, core.DelayedActionOptions{
+				DoAt: sim.CurrentTime + PomAssumedProcDelay,
+				OnAction: func(sim *core.Simulation) {
+					priest.ProcPrayerOfMending(sim, aura.Unit, priest.PrayerOfMending)
+				},
+			})
+		},
+		OnExpire: func(_ *core.Aura, sim *core.Simulation) {
+			if assumedProc != nil {
+				assumedProc.Cancel(sim)
+				assumedProc = nil
+			}
+		},
+		OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, _ *core.Spell, result *core.SpellResult) {
+			if result.Landed() && result.Damage > 0 {
+				priest.ProcPrayerOfMending(sim, aura.Unit, priest.PrayerOfMending)
+			}
+		},
+	})
+}
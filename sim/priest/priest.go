@@ -35,6 +35,7 @@ type Priest struct {
 	CircleOfHealing   *core.Spell
 	FlashHeal         *core.Spell
 	GreaterHeal       *core.Spell
+	Heal              *core.Spell
 	Penance           *core.Spell
 	PenanceHeal       *core.Spell
 	PowerWordShield   *core.Spell
@@ -53,6 +54,10 @@ type Priest struct {
 
 	WeakenedSouls core.AuraArray
 
+	// Set by Discipline, heals cast while it is active become absorbs instead.
+	SpiritShellAura   *core.Aura
+	SpiritShellAbsorb *core.Spell
+
 	ProcPrayerOfMending core.ApplySpellResults
 }
 
@@ -93,6 +98,43 @@ func (priest *Priest) Initialize() {
 	priest.ApplyGlyphs()
 }
 
+// Registers the class healing kit shared by Discipline and Holy.
+func (priest *Priest) RegisterHealingSpells() {
+	priest.registerSmiteSpell()
+	priest.registerHolyFireSpell()
+	priest.registerPowerWordShieldSpell()
+	priest.registerRenewSpell()
+	priest.registerFlashHealSpell()
+	priest.registerGreaterHealSpell()
+	priest.registerHealSpell()
+	priest.registerBindingHealSpell()
+	priest.registerPrayerOfHealingSpell()
+	priest.registerPrayerOfMendingSpell()
+}
+
+// Spirit Shell absorbs stack up to this fraction of the target's maximum health.
+const SpiritShellMaxHealthPercent = 0.6
+
+// Deals the healing of a direct heal, or grants it as a Spirit Shell absorb
+// instead while Spirit Shell is active.
+func (priest *Priest) CalcAndDealHealing(sim *core.Simulation, spell *core.Spell, target *core.Unit, baseHealing float64) *core.SpellResult {
+	if !priest.SpiritShellAura.IsActive() || !spell.Matches(PriestSpellSpiritShellable) {
+		return spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+	}
+
+	result := spell.CalcHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+	priest.SpiritShellAbsorb.Shield(target).ApplyAdditive(sim, result.Damage, target.MaxHealth()*SpiritShellMaxHealthPercent)
+	return result
+}
+
+// Heals aimed at an enemy, e.g. from a damage APL target, land on the priest instead.
+func (priest *Priest) healTarget(target *core.Unit) *core.Unit {
+	if target.IsOpponent(&priest.Unit) {
+		return &priest.Unit
+	}
+	return target
+}
+
 func (priest *Priest) AddHolyEvanglismStack(sim *core.Simulation) {
 	if priest.HolyEvangelismProcAura != nil {
 		priest.HolyEvangelismProcAura.Activate(sim)
@@ -150,6 +192,7 @@ func (priest *Priest) HasMinorGlyph(glyph proto.PriestMinorGlyph) bool {
 const (
 	PriestSpellFlagNone  int64 = 0
 	PriestSpellArchangel int64 = 1 << iota
+	PriestSpellAtonement
 	PriestSpellDarkArchangel
 	PriestSpellBindingHeal
 	PriestSpellCascade
@@ -161,12 +204,14 @@ const (
 	PriestSpellDivineAegis
 	PriestSpellDivineHymn
 	PriestSpellDivineStar
+	PriestSpellEchoOfLight
 	PriestSpellEmpoweredRenew
 	PriestSpellFade
 	PriestSpellFlashHeal
 	PriestSpellGreaterHeal
 	PriestSpellGuardianSpirit
 	PriestSpellHalo
+	PriestSpellHeal
 	PriestSpellHolyFire
 	PriestSpellHolyNova
 	PriestSpellHolyWordChastise
@@ -200,6 +245,7 @@ const (
 	PriestSpellShadowFiend
 	PriestSpellShadowyApparation
 	PriestSpellSmite
+	PriestSpellSpiritShell
 	PriestSpellVampiricEmbrace
 	PriestSpellVampiricTouch
 
@@ -225,6 +271,19 @@ const (
 		PriestSpellShadowWordDeath |
 		PriestSpellShadowWordPain |
 		PriestSpellVampiricEmbrace
+	PriestSpellDirectHeal = PriestSpellBindingHeal |
+		PriestSpellCircleOfHealing |
+		PriestSpellFlashHeal |
+		PriestSpellGreaterHeal |
+		PriestSpellHeal |
+		PriestSpellHolyWordSerenity |
+		PriestSpellPenance |
+		PriestSpellPrayerOfHealing |
+		PriestSpellPrayerOfMending
+	PriestSpellSpiritShellable = PriestSpellFlashHeal |
+		PriestSpellGreaterHeal |
+		PriestSpellHeal |
+		PriestSpellPrayerOfHealing
 	PriestShadowSpells = PriestSpellImprovedDevouringPlague |
 		PriestSpellDevouringPlague |
 		PriestSpellShadowWordDeath |
@@ -0,0 +1,57 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+const RenewScaleCoeff = 2.05
+const RenewSpellCoeff = 0.207
+
+func (priest *Priest) registerRenewSpell() {
+	hasGlyph := priest.HasMajorGlyph(proto.PriestMajorGlyph_GlyphOfRenew)
+
+	priest.Renew = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 139},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellRenew,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 2.6,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+
+		// Glyph of Renew: one tick shorter, but each tick heals for 33% more.
+		DamageMultiplier: core.TernaryFloat64(hasGlyph, 1.33, 1),
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Renew",
+			},
+			NumberOfTicks:    core.TernaryInt32(hasGlyph, 3, 4),
+			TickLength:       time.Second * 3,
+			BonusCoefficient: RenewSpellCoeff,
+
+			OnSnapshot: func(_ *core.Simulation, target *core.Unit, dot *core.Dot, _ bool) {
+				dot.SnapshotHeal(target, priest.CalcScalingSpellDmg(RenewScaleCoeff))
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.Hot(priest.healTarget(target)).Apply(sim)
+		},
+	})
+}
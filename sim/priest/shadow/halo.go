@@ -43,10 +43,17 @@ func (shadow *ShadowPriest) registerHalo() {
 		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
 			spell.WaitTravelTime(sim, func(s *core.Simulation) {
 				baseDamage := shadow.CalcAndRollDamageRange(sim, haloScale, haloVariance)
-				distMod := calcHaloMod(shadow.DistanceFromTarget)
-				spell.DamageMultiplier *= distMod
-				spell.CalcAndDealAoeDamage(sim, baseDamage, spell.OutcomeMagicHitAndCrit)
-				spell.DamageMultiplier /= distMod
+				for _, aoeTarget := range sim.Encounter.ActiveTargetUnits {
+					distance := shadow.DistanceTo(sim, aoeTarget)
+					if distance > spell.MaxRange {
+						continue
+					}
+
+					distMod := calcHaloMod(distance)
+					spell.DamageMultiplier *= distMod
+					spell.CalcAndDealDamage(sim, aoeTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
+					spell.DamageMultiplier /= distMod
+				}
 			})
 		},
 	})
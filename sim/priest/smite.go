@@ -0,0 +1,41 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/mop/sim/core"
+)
+
+const SmiteScaleCoeff = 2.232
+const SmiteVariance = 0.115
+const SmiteSpellCoeff = 0.856
+
+func (priest *Priest) registerSmiteSpell() {
+	priest.Smite = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 585},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellDamage,
+		Flags:          core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellSmite,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCostPercent: 1.5,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   priest.DefaultCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: SmiteSpellCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseDamage := priest.CalcAndRollDamageRange(sim, SmiteScaleCoeff, SmiteVariance)
+			spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeMagicHitAndCrit)
+		},
+	})
+}
@@ -0,0 +1,95 @@
+package sim
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wowsims/mop/sim/core"
+	"github.com/wowsims/mop/sim/core/proto"
+)
+
+func raidCompBalanceDruid() *proto.Player {
+	return &proto.Player{
+		Class:         proto.Class_ClassDruid,
+		Race:          proto.Race_RaceTroll,
+		Equipment:     core.GetGearSet("../ui/druid/balance/gear_sets", "preraid").GearSet,
+		TalentsString: "113222",
+		Spec: &proto.Player_BalanceDruid{
+			BalanceDruid: &proto.BalanceDruid{
+				Options: &proto.BalanceDruid_Options{
+					ClassOptions: &proto.DruidOptions{},
+				},
+			},
+		},
+		Rotation: core.GetAplRotation("../ui/druid/balance/apls", "standard").Rotation,
+	}
+}
+
+func raidCompElementalShaman() *proto.Player {
+	return &proto.Player{
+		Class:         proto.Class_ClassShaman,
+		Race:          proto.Race_RaceTroll,
+		Equipment:     core.GetGearSet("../ui/shaman/elemental/gear_sets", "preraid").GearSet,
+		TalentsString: "313233",
+		Spec: &proto.Player_ElementalShaman{
+			ElementalShaman: &proto.ElementalShaman{
+				Options: &proto.ElementalShaman_Options{
+					ClassOptions: &proto.ShamanOptions{
+						Shield: proto.ShamanShield_LightningShield,
+					},
+				},
+			},
+		},
+		Rotation: core.GetAplRotation("../ui/shaman/elemental/apls", "default").Rotation,
+	}
+}
+
+// Sims a comp of exactly the given players.
+func raidCompDps(t *testing.T, players []*proto.Player) *proto.RaidCompOptimizeComp {
+	t.Helper()
+
+	request := &proto.RaidCompOptimizeRequest{
+		Players:   players,
+		RaidSize:  10,
+		Encounter: STEncounter,
+		SimOptions: &proto.SimOptions{
+			Iterations: 20,
+			RandomSeed: 101,
+			IsTest:     true,
+		},
+		MaxSims:             1,
+		ScreeningIterations: 1,
+	}
+	for i := range players {
+		request.RequiredPlayers = append(request.RequiredPlayers, int32(i))
+	}
+
+	result := core.RaidCompOptimize(request)
+	if result.Error != nil {
+		t.Fatalf("raid comp optimize failed: %s", result.Error.Message)
+	}
+	if len(result.Comps) != 1 {
+		t.Fatalf("expected 1 comp, got %d", len(result.Comps))
+	}
+	return result.Comps[0]
+}
+
+func TestRaidCompBloodlust(t *testing.T) {
+	var druids []*proto.Player
+	for range 10 {
+		druids = append(druids, raidCompBalanceDruid())
+	}
+	withoutBloodlust := raidCompDps(t, druids)
+	if !slices.Contains(withoutBloodlust.Missing, "Bloodlust") {
+		t.Fatalf("expected Bloodlust to be missing, got %v", withoutBloodlust.Missing)
+	}
+
+	withBloodlust := raidCompDps(t, append(druids[:9:9], raidCompElementalShaman()))
+	if !slices.Contains(withBloodlust.Covered, "Bloodlust") {
+		t.Fatalf("expected Bloodlust to be covered, got %v", withBloodlust.Covered)
+	}
+
+	if withoutBloodlust.RaidDps >= withBloodlust.RaidDps {
+		t.Fatalf("expected the comp without a Bloodlust provider to lose DPS, got %0.2f vs %0.2f", withoutBloodlust.RaidDps, withBloodlust.RaidDps)
+	}
+}
@@ -36,6 +36,8 @@ func main() {
 	js.Global().Set("statWeightsAsync", js.FuncOf(statWeightsAsync))
 	js.Global().Set("statWeightRequests", js.FuncOf(statWeightRequests))
 	js.Global().Set("statWeightCompute", js.FuncOf(statWeightCompute))
+	js.Global().Set("statScalingAsync", js.FuncOf(statScalingAsync))
+	js.Global().Set("raidCompOptimizeAsync", js.FuncOf(raidCompOptimizeAsync))
 	js.Global().Set("bulkSimAsync", js.FuncOf(bulkSimAsync))
 	js.Global().Set("abortById", js.FuncOf(abortById))
 	js.Global().Set("bulkSimCombos", js.FuncOf(bulkSimCombos))
@@ -222,6 +224,42 @@ func statWeightsAsync(this js.Value, args []js.Value) interface{} {
 	return js.Undefined()
 }
 
+func statScalingAsync(this js.Value, args []js.Value) interface{} {
+	ssr := &proto.StatScalingRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), ssr); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+
+	requestId := args[2].String()
+	if strings.HasPrefix(requestId, "<T") {
+		requestId = "" // Make it return the error for an empty id
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	go core.StatScalingAsync(ssr, reporter, requestId)
+	go processAsyncProgress(args[1], reporter)
+	return js.Undefined()
+}
+
+func raidCompOptimizeAsync(this js.Value, args []js.Value) interface{} {
+	rcr := &proto.RaidCompOptimizeRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), rcr); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+
+	requestId := args[2].String()
+	if strings.HasPrefix(requestId, "<T") {
+		requestId = "" // Make it return the error for an empty id
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	go core.RaidCompOptimizeAsync(rcr, reporter, requestId)
+	go processAsyncProgress(args[1], reporter)
+	return js.Undefined()
+}
+
 func statWeightRequests(this js.Value, args []js.Value) interface{} {
 	req := &proto.StatWeightsRequest{}
 	if err := googleProto.Unmarshal(getArgsBinary(args[0]), req); err != nil {
@@ -408,7 +446,7 @@ func processAsyncProgress(progFunc js.Value, reporter chan *proto.ProgressMetric
 			js.CopyBytesToJS(outArray, outbytes)
 			progFunc.Invoke(outArray)
 
-			if progMetric.FinalWeightResult != nil || progMetric.FinalRaidResult != nil || progMetric.FinalBulkResult != nil {
+			if progMetric.FinalWeightResult != nil || progMetric.FinalRaidResult != nil || progMetric.FinalBulkResult != nil || progMetric.FinalScalingResult != nil || progMetric.FinalRaidCompResult != nil {
 				return
 			}
 		}
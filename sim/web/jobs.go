@@ -0,0 +1,383 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	proto "github.com/wowsims/mop/sim/core/proto"
+	"github.com/wowsims/mop/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultQueueSize      = 64
+	defaultMaxRunningJobs = 4
+
+	// How long finished jobs stay visible in the job listing.
+	jobRetention = time.Minute * 10
+)
+
+var errQueueFull = errors.New("job queue is full")
+
+type jobState string
+
+const (
+	jobQueued   jobState = "queued"
+	jobRunning  jobState = "running"
+	jobDone     jobState = "done"
+	jobCanceled jobState = "canceled"
+)
+
+// job is a single async API call waiting for, or running on, the job manager.
+type job struct {
+	id        string
+	requestId string
+	endpoint  string
+	handler   asyncAPIHandler
+	msg       googleProto.Message
+	cacheKey  string
+	progress  *asyncProgress
+
+	// If set, every progress message is also sent here, and it is closed once
+	// the job finishes.
+	stream chan *proto.ProgressMetrics
+
+	mu          sync.Mutex
+	state       jobState
+	canceled    bool
+	cached      bool
+	submittedAt time.Time
+	startedAt   time.Time
+	finishedAt  time.Time
+}
+
+type jobStatus struct {
+	Id                  string    `json:"id"`
+	RequestId           string    `json:"requestId"`
+	Endpoint            string    `json:"endpoint"`
+	State               jobState  `json:"state"`
+	Cached              bool      `json:"cached"`
+	SubmittedAt         time.Time `json:"submittedAt"`
+	StartedAt           time.Time `json:"startedAt,omitempty"`
+	FinishedAt          time.Time `json:"finishedAt,omitempty"`
+	CompletedIterations int32     `json:"completedIterations"`
+	TotalIterations     int32     `json:"totalIterations"`
+	CompletedSims       int32     `json:"completedSims"`
+	TotalSims           int32     `json:"totalSims"`
+}
+
+func (j *job) status() jobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	latest := j.progress.latestProgress.Load().(*proto.ProgressMetrics)
+	return jobStatus{
+		Id:                  j.id,
+		RequestId:           j.requestId,
+		Endpoint:            j.endpoint,
+		State:               j.state,
+		Cached:              j.cached,
+		SubmittedAt:         j.submittedAt,
+		StartedAt:           j.startedAt,
+		FinishedAt:          j.finishedAt,
+		CompletedIterations: latest.CompletedIterations,
+		TotalIterations:     latest.TotalIterations,
+		CompletedSims:       latest.CompletedSims,
+		TotalSims:           latest.TotalSims,
+	}
+}
+
+func (j *job) finish(state jobState) {
+	j.mu.Lock()
+	j.state = state
+	j.finishedAt = time.Now()
+	j.mu.Unlock()
+}
+
+// jobManager runs async API calls from a bounded queue, with at most
+// maxRunning of them in flight. The CPU budget shared by running jobs is set
+// separately through core.SetWorkerBudget.
+type jobManager struct {
+	queue chan *job
+	cache *resultCache
+
+	mu   sync.RWMutex
+	jobs map[string]*job
+}
+
+func newJobManager(queueSize int, maxRunning int, cache *resultCache) *jobManager {
+	jm := &jobManager{
+		queue: make(chan *job, queueSize),
+		cache: cache,
+		jobs:  map[string]*job{},
+	}
+	for i := 0; i < maxRunning; i++ {
+		go func() {
+			for j := range jm.queue {
+				jm.run(j)
+			}
+		}()
+	}
+	return jm
+}
+
+// submit queues a job, or completes it immediately on a cache hit.
+func (jm *jobManager) submit(j *job) error {
+	j.state = jobQueued
+	j.submittedAt = time.Now()
+
+	if jm.cache != nil && j.cacheKey != "" {
+		if cached := jm.cache.get(j.cacheKey); cached != nil {
+			j.progress.latestProgress.Store(cached)
+			j.cached = true
+			j.startedAt = j.submittedAt
+			j.finishedAt = j.submittedAt
+			j.state = jobDone
+			jm.track(j)
+			jm.scheduleRemoval(j)
+			if j.stream != nil {
+				j.stream <- cached
+				close(j.stream)
+			}
+			return nil
+		}
+	}
+
+	select {
+	case jm.queue <- j:
+		jm.track(j)
+		return nil
+	default:
+		return errQueueFull
+	}
+}
+
+func (jm *jobManager) track(j *job) {
+	jm.mu.Lock()
+	jm.jobs[j.id] = j
+	jm.mu.Unlock()
+}
+
+func (jm *jobManager) scheduleRemoval(j *job) {
+	time.AfterFunc(jobRetention, func() {
+		jm.mu.Lock()
+		delete(jm.jobs, j.id)
+		jm.mu.Unlock()
+	})
+}
+
+func (jm *jobManager) run(j *job) {
+	j.mu.Lock()
+	j.state = jobRunning
+	j.startedAt = time.Now()
+	j.mu.Unlock()
+
+	// reporter channel is handed into the core simulation.
+	//  as the simulation advances it will push changes to the channel
+	//  these changes are pushed into the async progress cache so the asyncProgress endpoint can fetch the results.
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	j.handler.handle(j.msg, reporter, j.requestId)
+
+	// A cancel may have arrived before the run registered its abort signal.
+	j.mu.Lock()
+	canceled := j.canceled
+	j.mu.Unlock()
+	if canceled {
+		simsignals.AbortById(j.requestId)
+	}
+
+	defer jm.scheduleRemoval(j)
+	if j.stream != nil {
+		defer close(j.stream)
+	}
+
+	for {
+		select {
+		case <-time.After(time.Minute * 10):
+			// if we get no progress after 10 minutes, give up on the job.
+			simsignals.AbortById(j.requestId)
+			j.finish(jobCanceled)
+			return
+		case progMetric := <-reporter:
+			if progMetric == nil {
+				j.finish(jobDone)
+				return
+			}
+			j.progress.latestProgress.Store(progMetric)
+			if j.stream != nil {
+				j.stream <- progMetric
+			}
+			if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil || progMetric.FinalScalingResult != nil || progMetric.FinalRaidCompResult != nil {
+				j.mu.Lock()
+				canceled = j.canceled
+				j.mu.Unlock()
+
+				if canceled {
+					j.finish(jobCanceled)
+					return
+				}
+				if jm.cache != nil && j.cacheKey != "" && progMetric.FinalRaidResult != nil && progMetric.FinalRaidResult.Error == nil {
+					jm.cache.put(j.cacheKey, progMetric)
+				}
+				j.finish(jobDone)
+				return
+			}
+		}
+	}
+}
+
+// cancel aborts a running job, or marks a queued one so it aborts as soon as
+// it starts. Returns false if the job is unknown or already finished.
+func (jm *jobManager) cancel(id string) bool {
+	jm.mu.RLock()
+	j, ok := jm.jobs[id]
+	jm.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.state == jobDone || j.state == jobCanceled {
+		return false
+	}
+	j.canceled = true
+	if j.state == jobRunning {
+		simsignals.AbortById(j.requestId)
+	}
+	return true
+}
+
+func (jm *jobManager) get(id string) (*job, bool) {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+	j, ok := jm.jobs[id]
+	return j, ok
+}
+
+func (jm *jobManager) list() []jobStatus {
+	jm.mu.RLock()
+	jobs := make([]*job, 0, len(jm.jobs))
+	for _, j := range jm.jobs {
+		jobs = append(jobs, j)
+	}
+	jm.mu.RUnlock()
+
+	statuses := make([]jobStatus, len(jobs))
+	for i, j := range jobs {
+		statuses[i] = j.status()
+	}
+	return statuses
+}
+
+// raidSimCacheKey returns the content address of a raid sim request, or ""
+// if the request is not reproducible (no fixed seed) and must not be cached.
+func raidSimCacheKey(rsr *proto.RaidSimRequest) string {
+	if rsr.SimOptions == nil || rsr.SimOptions.RandomSeed == 0 {
+		return ""
+	}
+
+	// The request id is unique per call and does not affect the result.
+	canonical := googleProto.Clone(rsr).(*proto.RaidSimRequest)
+	canonical.RequestId = ""
+
+	data, err := googleProto.MarshalOptions{Deterministic: true}.Marshal(canonical)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// resultCache is a fixed size LRU of final sim results.
+type resultCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+type resultCacheEntry struct {
+	key    string
+	result *proto.ProgressMetrics
+}
+
+func newResultCache(capacity int) *resultCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &resultCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[string]*list.Element{},
+	}
+}
+
+func (c *resultCache) get(key string) *proto.ProgressMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*resultCacheEntry).result
+}
+
+func (c *resultCache) put(key string, result *proto.ProgressMetrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*resultCacheEntry).result = result
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&resultCacheEntry{key: key, result: result})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resultCacheEntry).key)
+	}
+}
+
+func (s *server) setupJobServer() {
+	// Lists all queued, running and recently finished jobs.
+	http.Handle("/jobs", corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.jobs.list())
+	})))
+
+	http.Handle("/jobs/status", corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		j, ok := s.jobs.get(r.URL.Query().Get("id"))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		writeJSON(w, j.status())
+	})))
+
+	http.Handle("/jobs/cancel", corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		writeJSON(w, struct {
+			Id       string `json:"id"`
+			Canceled bool   `json:"canceled"`
+		}{id, s.jobs.cancel(id)})
+	})))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(out)
+}
@@ -11,6 +11,7 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"runtime"
 	"runtime/pprof"
 	"strings"
 	"sync"
@@ -48,6 +49,10 @@ func main() {
 	var host = flag.String("host", "localhost:3333", "URL to host the interface on.")
 	var launch = flag.Bool("launch", true, "auto launch browser")
 	var skipVersionCheck = flag.Bool("nvc", false, "set true to skip version check")
+	var queueSize = flag.Int("queue", defaultQueueSize, "Maximum number of async sims waiting to run.")
+	var maxJobs = flag.Int("jobs", defaultMaxRunningJobs, "Maximum number of async sims running at once.")
+	var workers = flag.Int("workers", runtime.NumCPU(), "Maximum number of sim threads across all requests, 0 for no limit.")
+	var cacheSize = flag.Int("cache", 0, "Number of seeded raid sim results to cache, 0 to disable.")
 
 	flag.Parse()
 
@@ -82,9 +87,12 @@ func main() {
 		}()
 	}
 
+	core.SetWorkerBudget(*workers)
+
 	s := &server{
 		progMut:         sync.RWMutex{},
 		asyncProgresses: map[string]*asyncProgress{},
+		jobs:            newJobManager(*queueSize, *maxJobs, newResultCache(*cacheSize)),
 	}
 	s.runServer(*useFS, *host, *launch, *simName, *wasm, bufio.NewReader(os.Stdin))
 }
@@ -106,6 +114,15 @@ var handlers = map[string]apiHandler{
 	"/computeStats": {msg: func() googleProto.Message { return &proto.ComputeStatsRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.ComputeStats(msg.(*proto.ComputeStatsRequest))
 	}},
+	"/statScaling": {msg: func() googleProto.Message { return &proto.StatScalingRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.StatScaling(msg.(*proto.StatScalingRequest))
+	}},
+	"/raidCompOptimize": {msg: func() googleProto.Message { return &proto.RaidCompOptimizeRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.RaidCompOptimize(msg.(*proto.RaidCompOptimizeRequest))
+	}},
+	"/optimizeReforges": {msg: func() googleProto.Message { return &proto.ReforgeOptimizeRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.OptimizeReforges(msg.(*proto.ReforgeOptimizeRequest))
+	}},
 	"/abortById": {msg: func() googleProto.Message { return &proto.AbortRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		requestId := msg.(*proto.AbortRequest).RequestId
 		triggered := simsignals.AbortById(requestId)
@@ -123,6 +140,12 @@ var asyncAPIHandlers = map[string]asyncAPIHandler{
 	"/statWeightsAsync": {msg: func() googleProto.Message { return &proto.StatWeightsRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.StatWeightsAsync(msg.(*proto.StatWeightsRequest), reporter, requestId)
 	}},
+	"/statScalingAsync": {msg: func() googleProto.Message { return &proto.StatScalingRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.StatScalingAsync(msg.(*proto.StatScalingRequest), reporter, requestId)
+	}},
+	"/raidCompOptimizeAsync": {msg: func() googleProto.Message { return &proto.RaidCompOptimizeRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		core.RaidCompOptimizeAsync(msg.(*proto.RaidCompOptimizeRequest), reporter, requestId)
+	}},
 	"/bulkSimAsync": {msg: func() googleProto.Message { return &proto.BulkSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.RunBulkSimAsync(msg.(*proto.BulkSimRequest), reporter, requestId)
 	}},
@@ -131,6 +154,7 @@ var asyncAPIHandlers = map[string]asyncAPIHandler{
 type server struct {
 	progMut         sync.RWMutex
 	asyncProgresses map[string]*asyncProgress
+	jobs            *jobManager
 }
 
 type apiHandler struct {
@@ -181,40 +205,17 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// reporter channel is handed into the core simulation.
-	//  as the simulation advances it will push changes to the channel
-	//  these changes will be consumed by the goroutine below so the asyncProgress endpoint can fetch the results.
-	reporter := make(chan *proto.ProgressMetrics, 100)
-	handler.handle(msg, reporter, r.URL.Query().Get("requestId"))
-
 	// Generate a new async simulation
-	simProgress := s.addNewSim()
+	newJob := s.newJob(endpoint, handler, msg, r.URL.Query().Get("requestId"))
 
-	// Now launch a background process that pulls progress reports off the reporter channel
-	// and pushes it into the async progress cache.
-	go func() {
-		for {
-			select {
-			case <-time.After(time.Minute * 10):
-				// if we get no progress after 10 minutes, delete the pending sim and exit.
-				s.progMut.Lock()
-				delete(s.asyncProgresses, simProgress.id)
-				s.progMut.Unlock()
-				return
-			case progMetric := <-reporter:
-				if progMetric == nil {
-					return
-				}
-				simProgress.latestProgress.Store(progMetric)
-				if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil {
-					return
-				}
-			}
-		}
-	}()
+	// The job manager pushes progress into the async progress cache so the asyncProgress endpoint can fetch the results.
+	if err := s.submitJob(newJob); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
 
 	protoResult := &proto.AsyncAPIResult{
-		ProgressId: simProgress.id,
+		ProgressId: newJob.id,
 	}
 
 	outbytes, err := googleProto.Marshal(protoResult)
@@ -228,12 +229,56 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 	w.Write(outbytes)
 }
 
+// Creates a job for an async API call, with its own async progress so it can
+// be polled through the asyncProgress endpoint.
+func (s *server) newJob(endpoint string, handler asyncAPIHandler, msg googleProto.Message, requestId string) *job {
+	simProgress := s.addNewSim()
+	if requestId == "" {
+		// Needed so the job can be canceled.
+		requestId = simProgress.id
+	}
+
+	newJob := &job{
+		id:        simProgress.id,
+		requestId: requestId,
+		endpoint:  endpoint,
+		handler:   handler,
+		msg:       msg,
+		progress:  simProgress,
+	}
+	if rsr, ok := msg.(*proto.RaidSimRequest); ok {
+		newJob.cacheKey = raidSimCacheKey(rsr)
+	}
+	return newJob
+}
+
+func (s *server) submitJob(newJob *job) error {
+	if err := s.jobs.submit(newJob); err != nil {
+		log.Printf("Failed to queue request: %s", err.Error())
+		s.progMut.Lock()
+		delete(s.asyncProgresses, newJob.id)
+		s.progMut.Unlock()
+		return err
+	}
+	return nil
+}
+
 func (s *server) setupAsyncServer() {
+	if s.jobs == nil {
+		s.jobs = newJobManager(defaultQueueSize, defaultMaxRunningJobs, nil)
+	}
+	s.setupJobServer()
+
 	// All async handlers here will call the addNewSim, generating a new UUID and cached progress state.
 	for route := range asyncAPIHandlers {
 		http.Handle(route, corsMiddleware(http.HandlerFunc(s.handleAsyncAPI)))
 	}
 
+	// Stream handlers push every progress update to the client instead of requiring it to poll.
+	for route := range asyncAPIHandlers {
+		http.Handle(streamRoute(route), corsMiddleware(http.HandlerFunc(s.handleStreamAPI)))
+	}
+
 	// asyncProgress will fetch the current progress of a simulation by its UUID.
 	http.Handle("/asyncProgress", corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		body, err := io.ReadAll(r.Body)
@@ -264,7 +309,7 @@ func (s *server) setupAsyncServer() {
 		}
 
 		// If this was the last result, delete the cache for this simulation.
-		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil || latest.FinalBulkResult != nil {
+		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil || latest.FinalBulkResult != nil || latest.FinalScalingResult != nil || latest.FinalRaidCompResult != nil {
 			s.progMut.Lock()
 			delete(s.asyncProgresses, msg.ProgressId)
 			s.progMut.Unlock()
@@ -412,10 +457,14 @@ func (s *server) runServer(useFS bool, host string, launchBrowser bool, simName
 				fmt.Printf("Process: %s (%d sims)\n\t  Progress: %d/%d\n", v.id, latest.TotalSims, latest.CompletedIterations, latest.TotalIterations)
 			}
 			s.progMut.RUnlock()
+		case "jobs":
+			for _, status := range s.jobs.list() {
+				fmt.Printf("Job: %s (%s) %s\n\t  Progress: %d/%d\n", status.Id, status.Endpoint, status.State, status.CompletedIterations, status.TotalIterations)
+			}
 		case "quit":
 			os.Exit(1)
 		case "?":
-			fmt.Printf("Commands:\n\tsims - Lists all active async sims running currently.\n\tprofile - start a CPU profile for debugging performance\n\theap_profile - capture a memory snapshot for debugging performance\n\tquit - exits\n\n")
+			fmt.Printf("Commands:\n\tsims - Lists all active async sims running currently.\n\tjobs - Lists queued, running and recently finished jobs.\n\tprofile - start a CPU profile for debugging performance\n\theap_profile - capture a memory snapshot for debugging performance\n\tquit - exits\n\n")
 		case "":
 			// nothing.
 		default:
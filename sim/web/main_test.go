@@ -13,6 +13,7 @@ import (
 	_ "github.com/wowsims/mop/sim/common"
 	"github.com/wowsims/mop/sim/core"
 	"github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
 	googleProto "google.golang.org/protobuf/proto"
 )
 
@@ -118,3 +119,243 @@ func TestIndividualSim(t *testing.T) {
 
 	log.Printf("RESULT: %#v", rsr)
 }
+
+// streamRaidSim posts a gearless raid sim to the stream endpoint and returns
+// the response, checking the content type of the requested format.
+func streamRaidSim(t *testing.T, format string, contentType string) *http.Response {
+	req := &proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(
+			&proto.Player{
+				Race:      proto.Race_RaceTroll,
+				Class:     proto.Class_ClassShaman,
+				Equipment: &proto.EquipmentSpec{},
+				Spec:      basicSpec,
+			},
+			&proto.PartyBuffs{},
+			&proto.RaidBuffs{},
+			&proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 120,
+			Targets: []*proto.Target{
+				{},
+			},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: 500,
+			RandomSeed: 1,
+		},
+	}
+
+	msgBytes, err := googleProto.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %s", err.Error())
+	}
+
+	r, err := http.Post("http://localhost:3339/raidSimStream?format="+format, "application/x-protobuf", bytes.NewReader(msgBytes))
+	if err != nil {
+		t.Fatalf("Failed to POST request: %s", err.Error())
+	}
+	if r.StatusCode != http.StatusOK {
+		r.Body.Close()
+		t.Fatalf("Expected status 200, got %d", r.StatusCode)
+	}
+	if got := r.Header.Get("Content-Type"); got != contentType {
+		r.Body.Close()
+		t.Fatalf("Expected content type %s, got %s", contentType, got)
+	}
+	return r
+}
+
+// checkFinalRaidResult fails unless the message is a successful final result
+// covering every iteration.
+func checkFinalRaidResult(t *testing.T, progress *proto.ProgressMetrics) {
+	result := progress.FinalRaidResult
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+	if result.IterationsDone != 500 {
+		t.Fatalf("Expected 500 iterations in the final result, got %d", result.IterationsDone)
+	}
+	if len(result.RaidMetrics.GetParties()) != 1 {
+		t.Fatalf("Expected the final result to have raid metrics")
+	}
+}
+
+func TestRaidSimStream(t *testing.T) {
+	r := streamRaidSim(t, streamFormatNDJSON, "application/x-ndjson")
+	defer r.Body.Close()
+
+	// Every line is one complete ProgressMetrics and the stream ends right
+	// after the final result.
+	var final *proto.ProgressMetrics
+	numProgress := 0
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if final != nil {
+			t.Fatalf("Unexpected line after the final result: %s", scanner.Text())
+		}
+		progress := &proto.ProgressMetrics{}
+		if err := protojson.Unmarshal(scanner.Bytes(), progress); err != nil {
+			t.Fatalf("Failed to parse progress line %q: %s", scanner.Text(), err.Error())
+		}
+		if progress.FinalRaidResult != nil {
+			final = progress
+		} else {
+			numProgress++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Failed to read stream: %s", err.Error())
+	}
+	if final == nil {
+		t.Fatalf("Stream ended without a final result after %d progress messages", numProgress)
+	}
+	checkFinalRaidResult(t, final)
+}
+
+func TestRaidSimStreamSSE(t *testing.T) {
+	r := streamRaidSim(t, streamFormatSSE, "text/event-stream")
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("Failed to read stream: %s", err.Error())
+	}
+	if !bytes.HasSuffix(body, []byte("\n\n")) {
+		t.Fatalf("Expected the stream to end with a blank line after the last event")
+	}
+
+	// Events are an event line and a data line, separated by blank lines,
+	// and only the last one is the result.
+	events := bytes.Split(bytes.TrimSuffix(body, []byte("\n\n")), []byte("\n\n"))
+	for i, event := range events {
+		lines := bytes.Split(event, []byte("\n"))
+		if len(lines) != 2 || !bytes.HasPrefix(lines[0], []byte("event: ")) || !bytes.HasPrefix(lines[1], []byte("data: ")) {
+			t.Fatalf("Malformed event %d: %q", i, event)
+		}
+
+		isLast := i == len(events)-1
+		eventName := string(bytes.TrimPrefix(lines[0], []byte("event: ")))
+		if isLast && eventName != "result" || !isLast && eventName != "progress" {
+			t.Fatalf("Unexpected event %q at %d of %d", eventName, i+1, len(events))
+		}
+
+		progress := &proto.ProgressMetrics{}
+		if err := protojson.Unmarshal(bytes.TrimPrefix(lines[1], []byte("data: ")), progress); err != nil {
+			t.Fatalf("Failed to parse event %d data: %s", i, err.Error())
+		}
+		if isLast != (progress.FinalRaidResult != nil) {
+			t.Fatalf("Expected only the result event to have the final raid result")
+		}
+		if isLast {
+			checkFinalRaidResult(t, progress)
+		}
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	cache := newResultCache(2)
+
+	rsr := &proto.RaidSimRequest{
+		RequestId:  "a",
+		SimOptions: &proto.SimOptions{Iterations: 10, RandomSeed: 5},
+	}
+	key := raidSimCacheKey(rsr)
+	rsr.RequestId = "b"
+	if raidSimCacheKey(rsr) != key {
+		t.Fatalf("Cache key should not depend on the request id")
+	}
+	rsr.SimOptions.RandomSeed = 0
+	if raidSimCacheKey(rsr) != "" {
+		t.Fatalf("Unseeded requests should not be cached")
+	}
+
+	cache.put("1", &proto.ProgressMetrics{CompletedIterations: 1})
+	cache.put("2", &proto.ProgressMetrics{CompletedIterations: 2})
+	cache.get("1")
+	cache.put("3", &proto.ProgressMetrics{CompletedIterations: 3})
+	if cache.get("2") != nil {
+		t.Fatalf("Least recently used entry should have been evicted")
+	}
+	if cache.get("1") == nil || cache.get("3") == nil {
+		t.Fatalf("Recent entries should still be cached")
+	}
+}
+
+func TestJobManagerStreamsQueuedJobs(t *testing.T) {
+	release := make(chan struct{})
+	handler := asyncAPIHandler{
+		msg: func() googleProto.Message { return &proto.RaidSimRequest{} },
+		handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+			go func() {
+				<-release
+				reporter <- &proto.ProgressMetrics{CompletedIterations: 1}
+				reporter <- &proto.ProgressMetrics{CompletedIterations: 2, FinalRaidResult: &proto.RaidSimResult{IterationsDone: 2}}
+			}()
+		},
+	}
+	newStreamJob := func(id string) *job {
+		progress := &asyncProgress{id: id}
+		progress.latestProgress.Store(&proto.ProgressMetrics{})
+		return &job{
+			id:        id,
+			requestId: id,
+			endpoint:  "/raidSimAsync",
+			handler:   handler,
+			msg:       &proto.RaidSimRequest{},
+			cacheKey:  "seeded",
+			progress:  progress,
+			stream:    make(chan *proto.ProgressMetrics, 100),
+		}
+	}
+	readStream := func(j *job) []*proto.ProgressMetrics {
+		var received []*proto.ProgressMetrics
+		for progMetric := range j.stream {
+			received = append(received, progMetric)
+		}
+		return received
+	}
+
+	jm := newJobManager(4, 1, newResultCache(4))
+	first, second := newStreamJob("first"), newStreamJob("second")
+	if err := jm.submit(first); err != nil {
+		t.Fatalf("Failed to submit job: %s", err.Error())
+	}
+	if err := jm.submit(second); err != nil {
+		t.Fatalf("Failed to submit job: %s", err.Error())
+	}
+
+	// Only one job runs at a time, so the second stream waits in the queue.
+	for deadline := time.Now().Add(time.Second); first.status().State != jobRunning; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the first job to start running")
+		}
+	}
+	if state := second.status().State; state != jobQueued {
+		t.Fatalf("Expected the second job to be queued, got %s", state)
+	}
+
+	close(release)
+	for _, j := range []*job{first, second} {
+		received := readStream(j)
+		if len(received) != 2 || received[0].CompletedIterations != 1 || received[1].FinalRaidResult == nil {
+			t.Fatalf("Expected %s to stream its progress and final result, got %v", j.id, received)
+		}
+		if state := j.status().State; state != jobDone {
+			t.Fatalf("Expected %s to be done once its stream is closed, got %s", j.id, state)
+		}
+	}
+
+	// A cache hit streams the cached result right away.
+	cached := newStreamJob("cached")
+	if err := jm.submit(cached); err != nil {
+		t.Fatalf("Failed to submit job: %s", err.Error())
+	}
+	if received := readStream(cached); len(received) != 1 || received[0].FinalRaidResult.GetIterationsDone() != 2 {
+		t.Fatalf("Expected the cached result to be streamed, got %v", received)
+	}
+	if status := cached.status(); !status.Cached || status.State != jobDone {
+		t.Fatalf("Expected the cached job to be done from the cache")
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	proto "github.com/wowsims/mop/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Streaming variants of the async handlers, e.g. /raidSimAsync -> /raidSimStream.
+func streamRoute(asyncRoute string) string {
+	return strings.TrimSuffix(asyncRoute, "Async") + "Stream"
+}
+
+const (
+	streamFormatSSE    = "sse"
+	streamFormatNDJSON = "ndjson"
+)
+
+// handleStreamAPI runs one of the asyncAPIHandlers and pushes every
+// ProgressMetrics message to the client as it is produced, either as
+// Server-Sent Events (default) or as newline delimited JSON when
+// ?format=ndjson is given. The request body may be binary proto or, with a
+// JSON content type, protojson. If the client disconnects before the final
+// result the job is canceled.
+func (s *server) handleStreamAPI(w http.ResponseWriter, r *http.Request) {
+	asyncRoute := strings.TrimSuffix(r.URL.Path, "Stream") + "Async"
+	handler, ok := asyncAPIHandlers[asyncRoute]
+	if !ok {
+		log.Printf("Invalid Endpoint: %s", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Printf("Streaming unsupported by response writer")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return
+	}
+
+	msg := handler.msg()
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, msg)
+	} else {
+		err = googleProto.Unmarshal(body, msg)
+	}
+	if err != nil {
+		log.Printf("Failed to parse request: %s", err.Error())
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	streamFormat := r.URL.Query().Get("format")
+	if streamFormat == "" {
+		streamFormat = streamFormatSSE
+	}
+	if streamFormat != streamFormatSSE && streamFormat != streamFormatNDJSON {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Streams go through the job manager like any other async call, so they
+	// count towards its queue and running limits.
+	streamJob := s.newJob(asyncRoute, handler, msg, r.URL.Query().Get("requestId"))
+	streamJob.stream = make(chan *proto.ProgressMetrics, 100)
+	if err := s.submitJob(streamJob); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	if streamFormat == streamFormatSSE {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	out := bufio.NewWriter(w)
+	marshaler := protojson.MarshalOptions{}
+	for {
+		select {
+		case <-r.Context().Done():
+			s.abortStream(streamJob)
+			return
+		case progMetric, ok := <-streamJob.stream:
+			if !ok {
+				return
+			}
+
+			data, err := marshaler.Marshal(progMetric)
+			if err != nil {
+				log.Printf("[ERROR] Failed to marshal progress: %s", err.Error())
+				s.abortStream(streamJob)
+				return
+			}
+
+			isFinal := progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil || progMetric.FinalScalingResult != nil || progMetric.FinalRaidCompResult != nil
+			if streamFormat == streamFormatSSE {
+				event := "progress"
+				if isFinal {
+					event = "result"
+				}
+				fmt.Fprintf(out, "event: %s\ndata: %s\n\n", event, data)
+			} else {
+				out.Write(data)
+				out.WriteByte('\n')
+			}
+
+			if err := out.Flush(); err != nil {
+				s.abortStream(streamJob)
+				return
+			}
+			flusher.Flush()
+
+			if isFinal {
+				return
+			}
+		}
+	}
+}
+
+// abortStream cancels the job of a stream the client is gone from, and
+// consumes its remaining progress in the background so the job manager never
+// blocks on a full channel.
+func (s *server) abortStream(streamJob *job) {
+	s.jobs.cancel(streamJob.id)
+	go func() {
+		for range streamJob.stream {
+		}
+	}()
+}
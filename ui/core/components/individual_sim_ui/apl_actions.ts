@@ -25,6 +25,7 @@ import {
 	APLActionResetSequence,
 	APLActionSchedule,
 	APLActionSequence,
+	APLActionSetVariable,
 	APLActionStrictMultidot,
 	APLActionStrictSequence,
 	APLActionTriggerICD,
@@ -688,6 +689,16 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 			}),
 		],
 	}),
+	['setVariable']: inputBuilder({
+		label: 'Set Variable',
+		submenu: ['Misc'],
+		shortDescription: 'Sets a variable defined in the rotation to a new value.',
+		fullDescription: `
+			<p>The action is only ready when it would change the value of the variable.</p>
+		`,
+		newValue: () => APLActionSetVariable.create(),
+		fields: [AplHelpers.stringFieldConfig('name'), AplValues.valueFieldConfig('value')],
+	}),
 	['customRotation']: inputBuilder({
 		label: 'Custom Rotation',
 		//submenu: ['Misc'],
@@ -70,11 +70,13 @@ import {
 	APLValueMin,
 	APLValueMonkCurrentChi,
 	APLValueMonkMaxChi,
+	APLValueNamedValue,
 	APLValueNextRuneCooldown,
 	APLValueNot,
 	APLValueNumberTargets,
 	APLValueNumEquippedStatProcTrinkets,
 	APLValueNumStatBuffCooldowns,
+	APLValueNumTargetsInRange,
 	APLValueOr,
 	APLValueProtectionPaladinDamageTakenLastGlobal,
 	APLValueRemainingTime,
@@ -101,7 +103,9 @@ import {
 	APLValueTrinketProcsMaxRemainingICD,
 	APLValueTrinketProcsMinRemainingTime,
 	APLValueUnitDistance,
+	APLValueUnitInFrontArc,
 	APLValueUnitIsMoving,
+	APLValueVariable,
 	APLValueWarlockHandOfGuldanInFlight,
 	APLValueWarlockHauntInFlight,
 } from '../../proto/apl.js';
@@ -675,9 +679,42 @@ const valueKindFactories: { [f in ValidAPLValueKind]: ValueKindConfig<APLValueIm
 	unitDistance: inputBuilder({
 		label: 'Distance',
 		submenu: ['Unit'],
-		shortDescription: 'Returns the distance to the specified unit.',
+		shortDescription: 'Returns the distance between the source unit and the target unit, which defaults to the current target of the source unit.',
 		newValue: APLValueUnitDistance.create,
-		fields: [AplHelpers.unitFieldConfig('sourceUnit', 'aura_sources')],
+		fields: [AplHelpers.unitFieldConfig('sourceUnit', 'aura_sources'), AplHelpers.unitFieldConfig('targetUnit', 'targets')],
+	}),
+	numTargetsInRange: inputBuilder({
+		label: 'Number of Targets in Range',
+		submenu: ['Unit'],
+		shortDescription: 'Count of active targets within the given number of yards of the unit, e.g. for cleaves and point blank AoE.',
+		newValue: () =>
+			APLValueNumTargetsInRange.create({
+				range: 8,
+			}),
+		fields: [
+			AplHelpers.unitFieldConfig('sourceUnit', 'aura_sources'),
+			AplHelpers.numberFieldConfig('range', true, {
+				label: 'Range',
+				labelTooltip: 'Radius in yards.',
+			}),
+		],
+	}),
+	unitInFrontArc: inputBuilder({
+		label: 'In Front Arc',
+		submenu: ['Unit'],
+		shortDescription: '<b>True</b> if the target unit stands inside the cone in front of the source unit.',
+		newValue: () =>
+			APLValueUnitInFrontArc.create({
+				arcDegrees: 180,
+			}),
+		fields: [
+			AplHelpers.unitFieldConfig('sourceUnit', 'aura_sources'),
+			AplHelpers.unitFieldConfig('targetUnit', 'targets'),
+			AplHelpers.numberFieldConfig('arcDegrees', true, {
+				label: 'Arc',
+				labelTooltip: 'Width of the cone in degrees.',
+			}),
+		],
 	}),
 
 	// Resources
@@ -1379,6 +1416,25 @@ const valueKindFactories: { [f in ValidAPLValueKind]: ValueKindConfig<APLValueIm
 		fields: [AplHelpers.stringFieldConfig('sequenceName')],
 	}),
 
+	// Variables
+	variable: inputBuilder({
+		label: 'Variable',
+		submenu: ['Variables'],
+		shortDescription: 'Returns the current value of a variable defined in the rotation.',
+		fullDescription: `
+			<p>Variables are declared at the root of the rotation, and are changed with the <b>Set Variable</b> action.</p>
+		`,
+		newValue: APLValueVariable.create,
+		fields: [AplHelpers.stringFieldConfig('name')],
+	}),
+	namedValue: inputBuilder({
+		label: 'Named Value',
+		submenu: ['Variables'],
+		shortDescription: 'Evaluates a value defined once at the root of the rotation, referenced by its name.',
+		newValue: APLValueNamedValue.create,
+		fields: [AplHelpers.stringFieldConfig('name')],
+	}),
+
 	// Class/spec specific values
 	totemRemainingTime: inputBuilder({
 		label: 'Totem Remaining Time',
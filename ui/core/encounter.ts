@@ -123,8 +123,13 @@ export class Encounter {
 	}
 
 	applyPreset(eventID: EventID, preset: PresetEncounter) {
-		this.targets = preset.targets.map(presetTarget => presetTarget.target || TargetProto.create());
-		this.targetsChangeEmitter.emit(eventID);
+		TypedEvent.freezeAllAndDo(() => {
+			this.targets = preset.targets.map(presetTarget => presetTarget.target || TargetProto.create());
+			this.targetsChangeEmitter.emit(eventID);
+			if (preset.useHealth) {
+				this.setUseHealth(eventID, true);
+			}
+		});
 	}
 
 	applyPresetTarget(eventID: EventID, preset: PresetTarget, index: number) {
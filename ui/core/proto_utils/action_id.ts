@@ -147,6 +147,10 @@ export class ActionId {
 				baseName = 'Encounter Start';
 				iconUrl = 'https://wow.zamimg.com/images/wow/icons/medium/achievement_faction_elders.jpg';
 				break;
+			case OtherAction.OtherActionRaidDamage:
+				baseName = 'Raid Damage';
+				iconUrl = 'https://wow.zamimg.com/images/wow/icons/medium/spell_shadow_shadowfury.jpg';
+				break;
 		}
 		this.baseName = baseName ?? '';
 		this.name = (name || baseName) ?? '';